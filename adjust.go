@@ -11,38 +11,63 @@ package excelize
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 type adjustDirection bool
 
+// referencePartRe defined the regular expression to split a cell reference,
+// column reference or row reference into the absolute markers, column name
+// and row number.
+var referencePartRe = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})?(\$?)([0-9]+)?$`)
+
 const (
 	columns adjustDirection = false
 	rows    adjustDirection = true
 )
 
 // adjustHelper provides a function to adjust rows and columns dimensions,
-// hyperlinks, merged cells and auto filter when inserting or deleting rows or
-// columns.
+// hyperlinks, formulas, defined names, data validations, merged cells and
+// auto filter when inserting or deleting rows or columns.
 //
 // sheet: Worksheet name that we're editing
 // column: Index number of the column we're inserting/deleting before
 // row: Index number of the row we're inserting/deleting before
 // offset: Number of rows/column to insert/delete negative values indicate deletion
 //
-// TODO: adjustPageBreaks, adjustComments, adjustDataValidations, adjustProtectedCells
+// TODO: adjustPageBreaks, adjustComments, adjustProtectedCells
 //
 func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int) error {
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
+	// Read all worksheets and check the references before adjusting, so that
+	// the worksheet won't be partially adjusted on error.
+	worksheets := make(map[string]*xlsxWorksheet)
+	for _, name := range f.GetSheetMap() {
+		if f.isChartSheet(name) {
+			continue
+		}
+		if worksheets[name], err = f.workSheetReader(name); err != nil {
+			return err
+		}
+	}
+	if err = f.checkAdjustReferences(xlsx); err != nil {
+		return err
+	}
 	if dir == rows {
 		f.adjustRowDimensions(xlsx, num, offset)
 	} else {
 		f.adjustColDimensions(xlsx, num, offset)
 	}
 	f.adjustHyperlinks(xlsx, sheet, dir, num, offset)
+	f.adjustFormulas(worksheets, sheet, dir, num, offset)
+	f.adjustDefinedNames(sheet, dir, num, offset)
+	f.adjustDataValidations(xlsx, sheet, dir, num, offset)
 	if err = f.adjustMergeCells(xlsx, dir, num, offset); err != nil {
 		return err
 	}
@@ -141,6 +166,330 @@ func (f *File) adjustHyperlinks(xlsx *xlsxWorksheet, sheet string, dir adjustDir
 	}
 }
 
+// checkAdjustReferences provides a function to check the references of the
+// merged cells, auto filter and calculation chain which will be updated when
+// inserting or deleting rows or columns.
+func (f *File) checkAdjustReferences(xlsx *xlsxWorksheet) error {
+	if xlsx.MergeCells != nil {
+		for _, cell := range xlsx.MergeCells.Cells {
+			if _, err := f.areaRefToCoordinates(cell.Ref); err != nil {
+				return err
+			}
+		}
+	}
+	if xlsx.AutoFilter != nil {
+		if _, err := f.areaRefToCoordinates(xlsx.AutoFilter.Ref); err != nil {
+			return err
+		}
+	}
+	if f.CalcChain != nil {
+		for _, c := range f.CalcChain.C {
+			if _, _, err := CellNameToCoordinates(c.R); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// adjustFormulas provides a function to update the references in the cell
+// formulas of the given worksheets which refer to the given worksheet when
+// inserting or deleting rows or columns.
+func (f *File) adjustFormulas(worksheets map[string]*xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) {
+	for name, xlsx := range worksheets {
+		for rowIdx := range xlsx.SheetData.Row {
+			for colIdx := range xlsx.SheetData.Row[rowIdx].C {
+				cell := &xlsx.SheetData.Row[rowIdx].C[colIdx]
+				if cell.F == nil {
+					continue
+				}
+				cell.F.Content = adjustFormula(cell.F.Content, name, sheet, dir, num, offset)
+				if cell.F.Ref != "" && strings.EqualFold(name, sheet) {
+					if cell.F.Ref = adjustReference(cell.F.Ref, dir, num, offset); cell.F.Ref == "#REF!" {
+						cell.F.Ref = cell.R
+					}
+				}
+			}
+		}
+	}
+}
+
+// adjustDefinedNames provides a function to update the references to the
+// given worksheet in the defined names when inserting or deleting rows or
+// columns.
+func (f *File) adjustDefinedNames(sheet string, dir adjustDirection, num, offset int) {
+	wb := f.workbookReader()
+	if wb == nil || wb.DefinedNames == nil {
+		return
+	}
+	for idx, dn := range wb.DefinedNames.DefinedName {
+		wb.DefinedNames.DefinedName[idx].Data = adjustFormula(dn.Data, "", sheet, dir, num, offset)
+	}
+}
+
+// adjustDataValidations provides a function to update the sequence of
+// references and formulas of the data validations when inserting or
+// deleting rows or columns.
+func (f *File) adjustDataValidations(xlsx *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) {
+	if xlsx.DataValidations == nil {
+		return
+	}
+	re := regexp.MustCompile(`(<formula[12]>)(.*?)(</formula[12]>)`)
+	adjustInnerXML := func(innerXML string) string {
+		return re.ReplaceAllStringFunc(innerXML, func(s string) string {
+			match := re.FindStringSubmatch(s)
+			return match[1] + adjustFormula(match[2], sheet, sheet, dir, num, offset) + match[3]
+		})
+	}
+	for _, dv := range xlsx.DataValidations.DataValidation {
+		var sqref []string
+		for _, ref := range strings.Fields(dv.Sqref) {
+			if ref = adjustReference(ref, dir, num, offset); ref != "#REF!" {
+				sqref = append(sqref, ref)
+			}
+		}
+		dv.Sqref = strings.Join(sqref, " ")
+		dv.Formula1 = adjustInnerXML(dv.Formula1)
+		dv.Formula2 = adjustInnerXML(dv.Formula2)
+	}
+	for idx := 0; idx < len(xlsx.DataValidations.DataValidation); idx++ {
+		if xlsx.DataValidations.DataValidation[idx].Sqref == "" {
+			xlsx.DataValidations.DataValidation = append(xlsx.DataValidations.DataValidation[:idx], xlsx.DataValidations.DataValidation[idx+1:]...)
+			idx--
+		}
+	}
+	xlsx.DataValidations.Count = len(xlsx.DataValidations.DataValidation)
+	if xlsx.DataValidations.Count == 0 {
+		xlsx.DataValidations = nil
+	}
+}
+
+// adjustFormula provides a function to update the references to the given
+// worksheet in the formula by given the worksheet name where the formula
+// located, the string literals in the formula will be kept as is. The
+// references without sheet name will be treated as the references to the
+// worksheet where the formula located, and which will be ignored if the
+// formula doesn't belong to any worksheet, such as defined names.
+func adjustFormula(formula, formulaSheet, sheet string, dir adjustDirection, num, offset int) string {
+	return replaceFormulaReferences(formula, func(word string) string {
+		refSheet, ref := formulaSheet, word
+		if idx := strings.LastIndex(word, "!"); idx != -1 {
+			refSheet, ref = word[:idx], word[idx+1:]
+			if strings.HasPrefix(refSheet, "'") && strings.HasSuffix(refSheet, "'") && len(refSheet) > 1 {
+				refSheet = strings.Replace(refSheet[1:len(refSheet)-1], "''", "'", -1)
+			}
+		}
+		if refSheet == "" || !strings.EqualFold(refSheet, sheet) {
+			return word
+		}
+		return word[:len(word)-len(ref)] + adjustReference(ref, dir, num, offset)
+	})
+}
+
+// replaceFormulaReferences provides a function to replace the cell
+// references, range references, whole columns or whole rows references with
+// an optional sheet name prefix in the formula by given replace function. The
+// string literals, numbers and function names in the formula will be kept as
+// is.
+func replaceFormulaReferences(formula string, fn func(ref string) string) string {
+	var result strings.Builder
+	r := []rune(formula)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for ; j < len(r); j++ {
+				if r[j] == '"' {
+					if j+1 < len(r) && r[j+1] == '"' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j < len(r) {
+				j++
+			}
+			result.WriteString(string(r[i:j]))
+			i = j
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '$' || r[j] == ':') {
+				j++
+			}
+			if word := string(r[i:j]); strings.Contains(word, ":") && isFormulaReference(word) &&
+				(j >= len(r) || !isFormulaWordRune(r[j])) {
+				result.WriteString(fn(word))
+				i = j
+				break
+			}
+			// Skip the number and the exponent part of the number.
+			j = i
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '.') {
+				j++
+			}
+			if j < len(r) && (r[j] == 'e' || r[j] == 'E') {
+				j++
+				if j < len(r) && (r[j] == '+' || r[j] == '-') {
+					j++
+				}
+				for j < len(r) && unicode.IsDigit(r[j]) {
+					j++
+				}
+			}
+			result.WriteString(string(r[i:j]))
+			i = j
+		case c == '\'' || isFormulaWordRune(c):
+			j := i
+			if c == '\'' {
+				for j++; j < len(r); j++ {
+					if r[j] == '\'' {
+						if j+1 < len(r) && r[j+1] == '\'' {
+							j++
+							continue
+						}
+						break
+					}
+				}
+				if j < len(r) {
+					j++
+				}
+			}
+			for j < len(r) && isFormulaWordRune(r[j]) {
+				j++
+			}
+			word := string(r[i:j])
+			if (j >= len(r) || r[j] != '(') && isFormulaReference(word) {
+				word = fn(word)
+			}
+			result.WriteString(word)
+			i = j
+		default:
+			result.WriteRune(c)
+			i++
+		}
+	}
+	return result.String()
+}
+
+// shiftFormula provides a function to shift the relative references in the
+// formula by given column and row offset, which is used to copy the formula
+// to another cell. The absolute references will be kept as is, and the
+// references which are out of the worksheet will be turned into #REF!.
+func shiftFormula(formula string, colOffset, rowOffset int) string {
+	return replaceFormulaReferences(formula, func(word string) string {
+		ref := word
+		if idx := strings.LastIndex(word, "!"); idx != -1 {
+			ref = word[idx+1:]
+		}
+		parts := strings.Split(ref, ":")
+		for idx, part := range parts {
+			match := referencePartRe.FindStringSubmatch(part)
+			if match == nil {
+				return word
+			}
+			if match[2] != "" && match[1] == "" {
+				col, _ := ColumnNameToNumber(match[2])
+				if col += colOffset; col < 1 || col > TotalColumns {
+					return word[:len(word)-len(ref)] + "#REF!"
+				}
+				match[2], _ = ColumnNumberToName(col)
+			}
+			if match[4] != "" && match[3] == "" {
+				row, _ := strconv.Atoi(match[4])
+				if row += rowOffset; row < 1 || row > TotalRows {
+					return word[:len(word)-len(ref)] + "#REF!"
+				}
+				match[4] = strconv.Itoa(row)
+			}
+			parts[idx] = strings.Join(match[1:], "")
+		}
+		return word[:len(word)-len(ref)] + strings.Join(parts, ":")
+	})
+}
+
+// adjustReference provides a function to update the cell reference, range
+// reference, whole columns or whole rows reference by given adjust direction,
+// operation axis and offset. The absolute or relative reference marker will
+// be kept as is. The range reference will be shrunk if a part of the range
+// is deleted, and #REF! will be returned if the whole reference is deleted.
+func adjustReference(ref string, dir adjustDirection, num, offset int) string {
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 {
+		return ref
+	}
+	matches := make([][]string, len(parts))
+	axis := make([]int, len(parts))
+	for idx, part := range parts {
+		if matches[idx] = referencePartRe.FindStringSubmatch(part); matches[idx] == nil {
+			return ref
+		}
+		var err error
+		if dir == columns {
+			if matches[idx][2] == "" {
+				return ref
+			}
+			axis[idx], err = ColumnNameToNumber(matches[idx][2])
+		} else {
+			if matches[idx][4] == "" {
+				return ref
+			}
+			axis[idx], err = strconv.Atoi(matches[idx][4])
+		}
+		if err != nil {
+			return ref
+		}
+	}
+	first, last := 0, len(axis)-1
+	if axis[first] > axis[last] {
+		first, last = last, first
+	}
+	start, end, ok := adjustReferenceAxis(axis[first], axis[last], num, offset)
+	if !ok {
+		return "#REF!"
+	}
+	axis[first], axis[last] = start, end
+	for idx, match := range matches {
+		if dir == columns {
+			match[2], _ = ColumnNumberToName(axis[idx])
+		} else {
+			match[4] = strconv.Itoa(axis[idx])
+		}
+		parts[idx] = strings.Join(match[1:], "")
+	}
+	return strings.Join(parts, ":")
+}
+
+// adjustReferenceAxis provides a function to calculate the new start and end
+// row number or column number of the reference by given operation axis and
+// offset. The negative offset indicates deleting rows or columns from the
+// operation axis, and false will be returned if the whole reference is
+// deleted.
+func adjustReferenceAxis(start, end, num, offset int) (int, int, bool) {
+	if offset > 0 {
+		if start >= num {
+			start += offset
+		}
+		if end >= num {
+			end += offset
+		}
+		return start, end, true
+	}
+	last := num - offset - 1
+	if start > last {
+		start += offset
+	} else if start >= num {
+		start = num
+	}
+	if end > last {
+		end += offset
+	} else if end >= num {
+		end = num - 1
+	}
+	return start, end, start <= end
+}
+
 // adjustAutoFilter provides a function to update the auto filter when
 // inserting or deleting rows or columns.
 func (f *File) adjustAutoFilter(xlsx *xlsxWorksheet, dir adjustDirection, num, offset int) error {
@@ -246,7 +595,9 @@ func (f *File) coordinatesToAreaRef(coordinates []int) (string, error) {
 }
 
 // adjustMergeCells provides a function to update merged cells when inserting
-// or deleting rows or columns.
+// or deleting rows or columns. The merged cells will be shrunk if a part of
+// the merged area is deleted, and will be removed if the whole merged area is
+// deleted or only one cell remains.
 func (f *File) adjustMergeCells(xlsx *xlsxWorksheet, dir adjustDirection, num, offset int) error {
 	if xlsx.MergeCells == nil {
 		return nil
@@ -258,25 +609,18 @@ func (f *File) adjustMergeCells(xlsx *xlsxWorksheet, dir adjustDirection, num, o
 		if err != nil {
 			return err
 		}
+		_ = sortCoordinates(coordinates)
 		x1, y1, x2, y2 := coordinates[0], coordinates[1], coordinates[2], coordinates[3]
+		var ok bool
 		if dir == rows {
-			if y1 == num && y2 == num && offset < 0 {
-				f.deleteMergeCell(xlsx, i)
-				i--
-			}
-			y1 = f.adjustMergeCellsHelper(y1, num, offset)
-			y2 = f.adjustMergeCellsHelper(y2, num, offset)
+			y1, y2, ok = adjustReferenceAxis(y1, y2, num, offset)
 		} else {
-			if x1 == num && x2 == num && offset < 0 {
-				f.deleteMergeCell(xlsx, i)
-				i--
-			}
-			x1 = f.adjustMergeCellsHelper(x1, num, offset)
-			x2 = f.adjustMergeCellsHelper(x2, num, offset)
+			x1, x2, ok = adjustReferenceAxis(x1, x2, num, offset)
 		}
-		if x1 == x2 && y1 == y2 {
+		if !ok || (x1 == x2 && y1 == y2) {
 			f.deleteMergeCell(xlsx, i)
 			i--
+			continue
 		}
 		if areaData.Ref, err = f.coordinatesToAreaRef([]int{x1, y1, x2, y2}); err != nil {
 			return err
@@ -285,20 +629,6 @@ func (f *File) adjustMergeCells(xlsx *xlsxWorksheet, dir adjustDirection, num, o
 	return nil
 }
 
-// adjustMergeCellsHelper provides a function for adjusting merge cells to
-// compare and calculate cell axis by the given pivot, operation axis and
-// offset.
-func (f *File) adjustMergeCellsHelper(pivot, num, offset int) int {
-	if pivot >= num {
-		pivot += offset
-		if pivot < 1 {
-			return 1
-		}
-		return pivot
-	}
-	return pivot
-}
-
 // deleteMergeCell provides a function to delete merged cell by given index.
 func (f *File) deleteMergeCell(sheet *xlsxWorksheet, idx int) {
 	if len(sheet.MergeCells.Cells) > idx {
@@ -86,6 +86,29 @@ func TestAdjustHelper(t *testing.T) {
 	assert.EqualError(t, f.adjustHelper("SheetN", rows, 0, 0), "sheet SheetN is not exist")
 }
 
+func TestAdjustHelperChartSheet(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 1))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "A2*2"))
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"name":"Sheet1!$A$2","values":"Sheet1!$A$2:$B$2"}]}`))
+	assert.NoError(t, f.InsertRow("Sheet1", 1))
+	assert.NoError(t, f.InsertCol("Sheet1", "A"))
+	formula, err := f.GetCellFormula("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "B3*2", formula)
+	assert.NoError(t, f.RemoveRow("Sheet1", 1))
+	assert.NoError(t, f.RemoveCol("Sheet1", "A"))
+
+	// Test the worksheet won't be adjusted with invalid references.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.AutoFilter = &xlsxAutoFilter{Ref: "A1:B"}
+	assert.EqualError(t, f.InsertRow("Sheet1", 1), `cannot convert cell "B" to coordinates: invalid cell name "B"`)
+	formula, err = f.GetCellFormula("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "A2*2", formula)
+}
+
 func TestAdjustCalcChain(t *testing.T) {
 	f := NewFile()
 	f.CalcChain = &xlsxCalcChain{
@@ -118,3 +141,45 @@ func TestCoordinatesToAreaRef(t *testing.T) {
 func TestSortCoordinates(t *testing.T) {
 	assert.EqualError(t, sortCoordinates(make([]int, 3)), "coordinates length must be 4")
 }
+
+func TestAdjustFormula(t *testing.T) {
+	for _, c := range []struct {
+		formula, formulaSheet string
+		dir                   adjustDirection
+		num, offset           int
+		expected              string
+	}{
+		{"SUM(A1:A5)+$B$3*C2", "Sheet1", rows, 3, 2, "SUM(A1:A7)+$B$5*C2"},
+		{"SUM(A:A)+SUM(2:$4)", "Sheet1", rows, 3, 2, "SUM(A:A)+SUM(2:$6)"},
+		{"SUM(A:C)+SUM(2:4)+B$3", "Sheet1", columns, 2, 1, "SUM(A:D)+SUM(2:4)+C$3"},
+		{`"A5"&A5&'Sheet 2'!A5&Sheet1!A5`, "Sheet1", rows, 5, 1, `"A5"&A6&'Sheet 2'!A5&Sheet1!A6`},
+		{"Sheet1!A5+'Sheet1'!B5+A5", "Sheet2", rows, 5, 1, "Sheet1!A6+'Sheet1'!B6+A5"},
+		{"LOG10(A10)+1E5+1.5e-3+A10", "Sheet1", rows, 5, 1, "LOG10(A11)+1E5+1.5e-3+A11"},
+		{"Sheet1!$A$1:$A$10", "", rows, 5, 1, "Sheet1!$A$1:$A$11"},
+		{"$A$1:$A$10", "", rows, 5, 1, "$A$1:$A$10"},
+		{"A4+A5+A6+SUM(A1:A5)+SUM(A5:A9)+SUM(A5:A5)", "Sheet1", rows, 5, -1, "A4+#REF!+A5+SUM(A1:A4)+SUM(A5:A8)+SUM(#REF!)"},
+		{"Sheet1!$C$1+'Sheet1'!B1:C1+SUM(C:E)+SUM(C:C)", "Sheet2", columns, 3, -1, "Sheet1!#REF!+'Sheet1'!B1:B1+SUM(C:E)+SUM(C:C)"},
+		{"SUM(A9:A1)", "Sheet1", rows, 9, -1, "SUM(A8:A1)"},
+	} {
+		assert.Equal(t, c.expected, adjustFormula(c.formula, c.formulaSheet, "Sheet1", c.dir, c.num, c.offset), c.formula)
+	}
+}
+
+func TestAdjustReferenceAxis(t *testing.T) {
+	for _, c := range []struct {
+		start, end, num, offset int
+		expected                []int
+		ok                      bool
+	}{
+		{2, 4, 3, 2, []int{2, 6}, true},
+		{2, 4, 2, -1, []int{2, 3}, true},
+		{2, 4, 4, -1, []int{2, 3}, true},
+		{2, 4, 1, -1, []int{1, 3}, true},
+		{2, 4, 2, -3, []int{2, 1}, false},
+		{2, 4, 3, -5, []int{2, 2}, true},
+	} {
+		start, end, ok := adjustReferenceAxis(c.start, c.end, c.num, c.offset)
+		assert.Equal(t, c.expected, []int{start, end})
+		assert.Equal(t, c.ok, ok)
+	}
+}
@@ -0,0 +1,2004 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Excel formula errors
+const (
+	formulaErrorDIV   = "#DIV/0!"
+	formulaErrorNAME  = "#NAME?"
+	formulaErrorNA    = "#N/A"
+	formulaErrorNUM   = "#NUM!"
+	formulaErrorVALUE = "#VALUE!"
+	formulaErrorREF   = "#REF!"
+	formulaErrorNULL  = "#NULL!"
+	formulaErrorSPILL = "#SPILL!"
+	formulaErrorCALC  = "#CALC!"
+)
+
+// formulaErrors defined the list of error values could be used in formula.
+var formulaErrors = []string{
+	formulaErrorDIV,
+	formulaErrorNAME,
+	formulaErrorNA,
+	formulaErrorNUM,
+	formulaErrorVALUE,
+	formulaErrorREF,
+	formulaErrorNULL,
+	formulaErrorSPILL,
+	formulaErrorCALC,
+}
+
+// ArgType is the type of formula argument.
+type ArgType byte
+
+// Formula argument types enumeration.
+const (
+	ArgUnknown ArgType = iota
+	ArgNumber
+	ArgString
+	ArgBool
+	ArgError
+	ArgEmpty
+	ArgMatrix
+)
+
+// FormulaArg is the argument of a formula or function. The Matrix field
+// holds the cell values of a range reference by rows.
+type FormulaArg struct {
+	Type    ArgType
+	Number  float64
+	String  string
+	Boolean bool
+	Matrix  [][]FormulaArg
+}
+
+// newNumberFormulaArg constructs a number formula argument.
+func newNumberFormulaArg(n float64) FormulaArg {
+	return FormulaArg{Type: ArgNumber, Number: n}
+}
+
+// newStringFormulaArg constructs a string formula argument.
+func newStringFormulaArg(s string) FormulaArg {
+	return FormulaArg{Type: ArgString, String: s}
+}
+
+// newBoolFormulaArg constructs a boolean formula argument.
+func newBoolFormulaArg(b bool) FormulaArg {
+	return FormulaArg{Type: ArgBool, Boolean: b}
+}
+
+// newErrorFormulaArg constructs an error formula argument.
+func newErrorFormulaArg(e string) FormulaArg {
+	return FormulaArg{Type: ArgError, String: e}
+}
+
+// newEmptyFormulaArg constructs an empty formula argument.
+func newEmptyFormulaArg() FormulaArg {
+	return FormulaArg{Type: ArgEmpty}
+}
+
+// newMatrixFormulaArg constructs a matrix formula argument.
+func newMatrixFormulaArg(m [][]FormulaArg) FormulaArg {
+	return FormulaArg{Type: ArgMatrix, Matrix: m}
+}
+
+// Value returns a string data type of the formula argument.
+func (fa FormulaArg) Value() string {
+	switch fa.Type {
+	case ArgNumber:
+		return formatFormulaNumber(fa.Number)
+	case ArgString, ArgError:
+		return fa.String
+	case ArgBool:
+		return strings.ToUpper(strconv.FormatBool(fa.Boolean))
+	case ArgMatrix:
+		return fa.scalar().Value()
+	}
+	return ""
+}
+
+// scalar returns the top-left value of a matrix formula argument, or the
+// argument itself for other types.
+func (fa FormulaArg) scalar() FormulaArg {
+	if fa.Type != ArgMatrix {
+		return fa
+	}
+	if len(fa.Matrix) == 0 || len(fa.Matrix[0]) == 0 {
+		return newEmptyFormulaArg()
+	}
+	return fa.Matrix[0][0]
+}
+
+// flatten returns all values of the formula argument by rows.
+func (fa FormulaArg) flatten() []FormulaArg {
+	if fa.Type != ArgMatrix {
+		return []FormulaArg{fa}
+	}
+	var args []FormulaArg
+	for _, row := range fa.Matrix {
+		args = append(args, row...)
+	}
+	return args
+}
+
+// toNumber converts the formula argument to a number formula argument, a
+// #VALUE! error will be returned if the value is not numeric.
+func (fa FormulaArg) toNumber() FormulaArg {
+	switch fa.Type {
+	case ArgNumber, ArgError:
+		return fa
+	case ArgString:
+		n, err := strconv.ParseFloat(strings.TrimSpace(fa.String), 64)
+		if err != nil {
+			return newErrorFormulaArg(formulaErrorVALUE)
+		}
+		return newNumberFormulaArg(n)
+	case ArgBool:
+		if fa.Boolean {
+			return newNumberFormulaArg(1)
+		}
+	case ArgMatrix:
+		return fa.scalar().toNumber()
+	}
+	return newNumberFormulaArg(0)
+}
+
+// toBool converts the formula argument to a boolean formula argument, a
+// #VALUE! error will be returned if the value can't be converted.
+func (fa FormulaArg) toBool() FormulaArg {
+	switch fa.Type {
+	case ArgBool, ArgError:
+		return fa
+	case ArgNumber:
+		return newBoolFormulaArg(fa.Number != 0)
+	case ArgString:
+		switch strings.ToUpper(fa.String) {
+		case "TRUE":
+			return newBoolFormulaArg(true)
+		case "FALSE":
+			return newBoolFormulaArg(false)
+		}
+		return newErrorFormulaArg(formulaErrorVALUE)
+	case ArgMatrix:
+		return fa.scalar().toBool()
+	}
+	return newBoolFormulaArg(false)
+}
+
+// formatFormulaNumber provides a function to convert the number to string
+// with 15 significant digits like Excel.
+func formatFormulaNumber(n float64) string {
+	if v, err := strconv.ParseFloat(strconv.FormatFloat(n, 'g', 15, 64), 64); err == nil {
+		n = v
+	}
+	if n == 0 {
+		n = 0 // drop the sign of negative zero
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// formulaTokenType is the type of lexical formula token.
+type formulaTokenType byte
+
+// Formula token types enumeration.
+const (
+	tokenNumber formulaTokenType = iota
+	tokenString
+	tokenBool
+	tokenError
+	tokenRef
+	tokenName
+	tokenFunc
+	tokenOperator
+	tokenOpen
+	tokenClose
+	tokenSep
+	tokenArrayOpen
+	tokenArrayClose
+	tokenArrayRowSep
+)
+
+// formulaToken directly maps a lexical token of the formula.
+type formulaToken struct {
+	Type  formulaTokenType
+	Value string
+}
+
+var (
+	formulaCellRe   = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[0-9]+$`)
+	formulaColumnRe = regexp.MustCompile(`^\$?[A-Za-z]{1,3}$`)
+	formulaRowRe    = regexp.MustCompile(`^\$?[0-9]+$`)
+)
+
+// isFormulaReference checks if the given word is a cell reference, a range
+// reference, a whole columns reference or a whole rows reference with an
+// optional sheet name prefix, for example: Sheet1!$A$1:$B$2.
+func isFormulaReference(word string) bool {
+	if i := strings.LastIndex(word, "!"); i != -1 {
+		word = word[i+1:]
+	}
+	parts := strings.Split(word, ":")
+	switch len(parts) {
+	case 1:
+		return formulaCellRe.MatchString(parts[0])
+	case 2:
+		for _, re := range []*regexp.Regexp{formulaCellRe, formulaColumnRe, formulaRowRe} {
+			if re.MatchString(parts[0]) && re.MatchString(parts[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isFormulaWordRune checks if the rune could be a part of function names,
+// defined names or references.
+func isFormulaWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.$!:\\", r)
+}
+
+// lexFormula provides a function to split the formula into lexical tokens.
+func lexFormula(formula string) ([]formulaToken, error) {
+	var tokens []formulaToken
+	r := []rune(strings.TrimPrefix(strings.TrimSpace(formula), "="))
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			var str strings.Builder
+			j := i + 1
+			for ; j < len(r); j++ {
+				if r[j] == '"' {
+					if j+1 < len(r) && r[j+1] == '"' {
+						str.WriteRune('"')
+						j++
+						continue
+					}
+					break
+				}
+				str.WriteRune(r[j])
+			}
+			if j >= len(r) {
+				return tokens, fmt.Errorf("formula string not terminated: %s", formula)
+			}
+			tokens = append(tokens, formulaToken{Type: tokenString, Value: str.String()})
+			i = j + 1
+		case c == '#':
+			rest := strings.ToUpper(string(r[i:]))
+			var matched string
+			for _, e := range formulaErrors {
+				if strings.HasPrefix(rest, e) {
+					matched = e
+					break
+				}
+			}
+			if matched == "" {
+				return tokens, fmt.Errorf("invalid formula error value: %s", formula)
+			}
+			tokens = append(tokens, formulaToken{Type: tokenError, Value: matched})
+			i += len([]rune(matched))
+		case c == '(':
+			tokens = append(tokens, formulaToken{Type: tokenOpen, Value: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, formulaToken{Type: tokenClose, Value: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, formulaToken{Type: tokenSep, Value: ","})
+			i++
+		case c == '{':
+			tokens = append(tokens, formulaToken{Type: tokenArrayOpen, Value: "{"})
+			i++
+		case c == '}':
+			tokens = append(tokens, formulaToken{Type: tokenArrayClose, Value: "}"})
+			i++
+		case c == ';':
+			tokens = append(tokens, formulaToken{Type: tokenArrayRowSep, Value: ";"})
+			i++
+		case strings.ContainsRune("+-*/^&%", c):
+			tokens = append(tokens, formulaToken{Type: tokenOperator, Value: string(c)})
+			i++
+		case strings.ContainsRune("=<>", c):
+			op := string(c)
+			if i+1 < len(r) && (c == '<' && (r[i+1] == '=' || r[i+1] == '>') || c == '>' && r[i+1] == '=') {
+				op += string(r[i+1])
+			}
+			tokens = append(tokens, formulaToken{Type: tokenOperator, Value: op})
+			i += len(op)
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '$' || r[j] == ':') {
+				j++
+			}
+			if word := string(r[i:j]); strings.Contains(word, ":") && isFormulaReference(word) {
+				tokens = append(tokens, formulaToken{Type: tokenRef, Value: word})
+				i = j
+				break
+			}
+			j = i
+			for j < len(r) && (unicode.IsDigit(r[j]) || r[j] == '.') {
+				j++
+			}
+			if j < len(r) && (r[j] == 'e' || r[j] == 'E') {
+				k := j + 1
+				if k < len(r) && (r[k] == '+' || r[k] == '-') {
+					k++
+				}
+				if k < len(r) && unicode.IsDigit(r[k]) {
+					for k < len(r) && unicode.IsDigit(r[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			if _, err := strconv.ParseFloat(string(r[i:j]), 64); err != nil {
+				return tokens, fmt.Errorf("invalid number %s in formula: %s", string(r[i:j]), formula)
+			}
+			tokens = append(tokens, formulaToken{Type: tokenNumber, Value: string(r[i:j])})
+			i = j
+		case c == '\'' || isFormulaWordRune(c):
+			j := i
+			if c == '\'' {
+				for j++; j < len(r); j++ {
+					if r[j] == '\'' {
+						if j+1 < len(r) && r[j+1] == '\'' {
+							j++
+							continue
+						}
+						break
+					}
+				}
+				if j+1 >= len(r) || r[j+1] != '!' {
+					return tokens, fmt.Errorf("invalid sheet reference in formula: %s", formula)
+				}
+				j++
+			}
+			for j < len(r) && isFormulaWordRune(r[j]) {
+				j++
+			}
+			word := string(r[i:j])
+			switch {
+			case j < len(r) && r[j] == '(':
+				tokens = append(tokens, formulaToken{Type: tokenFunc, Value: strings.ToUpper(word)})
+			case strings.EqualFold(word, "TRUE") || strings.EqualFold(word, "FALSE"):
+				tokens = append(tokens, formulaToken{Type: tokenBool, Value: strings.ToUpper(word)})
+			case isFormulaReference(word):
+				tokens = append(tokens, formulaToken{Type: tokenRef, Value: word})
+			default:
+				tokens = append(tokens, formulaToken{Type: tokenName, Value: word})
+			}
+			i = j
+		default:
+			return tokens, fmt.Errorf("unexpected character %q in formula: %s", c, formula)
+		}
+	}
+	return tokens, nil
+}
+
+// formulaNodeType is the type of formula syntax tree node.
+type formulaNodeType byte
+
+// Formula syntax tree node types enumeration.
+const (
+	nodeLiteral formulaNodeType = iota
+	nodeRef
+	nodeName
+	nodeFunc
+	nodeBinary
+	nodeUnary
+	nodePercent
+	nodeEmpty
+)
+
+// formulaNode directly maps a node of the formula syntax tree.
+type formulaNode struct {
+	Type  formulaNodeType
+	Value string
+	Arg   FormulaArg
+	Args  []*formulaNode
+}
+
+// formulaParser is a recursive descent parser for the formula tokens.
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+}
+
+// parseFormula provides a function to parse the formula into a syntax tree.
+func parseFormula(formula string) (*formulaNode, error) {
+	tokens, err := lexFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("formula not valid")
+	}
+	p := &formulaParser{tokens: tokens}
+	node, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %s in formula: %s", p.tokens[p.pos].Value, formula)
+	}
+	return node, nil
+}
+
+// peek returns the current token, or nil at the end of the tokens.
+func (p *formulaParser) peek() *formulaToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// peekOperator returns the current operator token if it is one of the
+// given operators.
+func (p *formulaParser) peekOperator(ops ...string) string {
+	if t := p.peek(); t != nil && t.Type == tokenOperator {
+		for _, op := range ops {
+			if t.Value == op {
+				return op
+			}
+		}
+	}
+	return ""
+}
+
+// parseBinary parses left associative binary operators of the same
+// precedence.
+func (p *formulaParser) parseBinary(next func() (*formulaNode, error), ops ...string) (*formulaNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peekOperator(ops...); op != ""; op = p.peekOperator(ops...) {
+		p.pos++
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaNode{Type: nodeBinary, Value: op, Args: []*formulaNode{left, right}}
+	}
+	return left, nil
+}
+
+func (p *formulaParser) parseComparison() (*formulaNode, error) {
+	return p.parseBinary(p.parseConcat, "=", "<>", "<", ">", "<=", ">=")
+}
+
+func (p *formulaParser) parseConcat() (*formulaNode, error) {
+	return p.parseBinary(p.parseAdditive, "&")
+}
+
+func (p *formulaParser) parseAdditive() (*formulaNode, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *formulaParser) parseMultiplicative() (*formulaNode, error) {
+	return p.parseBinary(p.parsePower, "*", "/")
+}
+
+func (p *formulaParser) parsePower() (*formulaNode, error) {
+	return p.parseBinary(p.parsePercent, "^")
+}
+
+func (p *formulaParser) parsePercent() (*formulaNode, error) {
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("%") != "" {
+		p.pos++
+		node = &formulaNode{Type: nodePercent, Args: []*formulaNode{node}}
+	}
+	return node, nil
+}
+
+func (p *formulaParser) parseUnary() (*formulaNode, error) {
+	if op := p.peekOperator("+", "-"); op != "" {
+		p.pos++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &formulaNode{Type: nodeUnary, Value: op, Args: []*formulaNode{node}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (*formulaNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("formula not valid")
+	}
+	p.pos++
+	switch t.Type {
+	case tokenNumber:
+		n, _ := strconv.ParseFloat(t.Value, 64)
+		return &formulaNode{Type: nodeLiteral, Arg: newNumberFormulaArg(n)}, nil
+	case tokenString:
+		return &formulaNode{Type: nodeLiteral, Arg: newStringFormulaArg(t.Value)}, nil
+	case tokenBool:
+		return &formulaNode{Type: nodeLiteral, Arg: newBoolFormulaArg(t.Value == "TRUE")}, nil
+	case tokenError:
+		return &formulaNode{Type: nodeLiteral, Arg: newErrorFormulaArg(t.Value)}, nil
+	case tokenRef:
+		return &formulaNode{Type: nodeRef, Value: t.Value}, nil
+	case tokenName:
+		return &formulaNode{Type: nodeName, Value: t.Value}, nil
+	case tokenFunc:
+		return p.parseFunction(t.Value)
+	case tokenOpen:
+		node, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.Type != tokenClose {
+			return nil, errors.New("formula not valid: missing close parenthesis")
+		}
+		p.pos++
+		return node, nil
+	case tokenArrayOpen:
+		return p.parseArray()
+	}
+	return nil, fmt.Errorf("unexpected token %s in formula", t.Value)
+}
+
+// parseFunction parses the arguments of the function, an omitted argument
+// will be parsed as an empty node.
+func (p *formulaParser) parseFunction(name string) (*formulaNode, error) {
+	node := &formulaNode{Type: nodeFunc, Value: name}
+	p.pos++ // skip the open parenthesis
+	if t := p.peek(); t != nil && t.Type == tokenClose {
+		p.pos++
+		return node, nil
+	}
+	for {
+		t := p.peek()
+		if t == nil {
+			return nil, fmt.Errorf("formula not valid: function %s not closed", name)
+		}
+		if t.Type == tokenSep || t.Type == tokenClose {
+			node.Args = append(node.Args, &formulaNode{Type: nodeEmpty})
+		} else {
+			arg, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			node.Args = append(node.Args, arg)
+		}
+		if t = p.peek(); t == nil {
+			return nil, fmt.Errorf("formula not valid: function %s not closed", name)
+		}
+		p.pos++
+		if t.Type == tokenClose {
+			return node, nil
+		}
+		if t.Type != tokenSep {
+			return nil, fmt.Errorf("unexpected token %s in function %s", t.Value, name)
+		}
+	}
+}
+
+// parseArray parses an array constant such as {1,2;3,4}.
+func (p *formulaParser) parseArray() (*formulaNode, error) {
+	matrix := [][]FormulaArg{{}}
+	for {
+		t := p.peek()
+		if t == nil {
+			return nil, errors.New("formula not valid: array constant not closed")
+		}
+		p.pos++
+		switch t.Type {
+		case tokenArrayClose:
+			for _, row := range matrix {
+				if len(row) != len(matrix[0]) {
+					return nil, errors.New("formula not valid: array constant rows must have same size")
+				}
+			}
+			return &formulaNode{Type: nodeLiteral, Arg: newMatrixFormulaArg(matrix)}, nil
+		case tokenSep:
+		case tokenArrayRowSep:
+			matrix = append(matrix, []FormulaArg{})
+		default:
+			p.pos--
+			node, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			if node.Type == nodeUnary && node.Args[0].Type == nodeLiteral && node.Args[0].Arg.Type == ArgNumber {
+				node = &formulaNode{Type: nodeLiteral, Arg: calcUnary(node.Value, node.Args[0].Arg)}
+			}
+			if node.Type != nodeLiteral || node.Arg.Type == ArgMatrix {
+				return nil, errors.New("formula not valid: array constant must contain constant values")
+			}
+			matrix[len(matrix)-1] = append(matrix[len(matrix)-1], node.Arg)
+		}
+	}
+}
+
+// calcContext holds the state of a calculation, the entry map records the
+// cells currently being calculated to detect circular references, and the
+// cache map records the calculated results of the cells. The circular
+// references are allowed in the iterative calculation, and the cached value
+// of the cell will be used in that case.
+type calcContext struct {
+	f       *File
+	entry   map[string]bool
+	cache   map[string]FormulaArg
+	iterate bool
+	err     error
+}
+
+// newCalcContext provides a function to create a calculation context.
+func (f *File) newCalcContext() *calcContext {
+	return &calcContext{f: f, entry: make(map[string]bool), cache: make(map[string]FormulaArg)}
+}
+
+// CalcError directly maps the error of calculating the formula in the cell,
+// the Err field holds the Excel error value such as #DIV/0!, or the error
+// which prevents the formula being calculated such as circular reference.
+type CalcError struct {
+	Sheet string
+	Cell  string
+	Err   error
+}
+
+func (err CalcError) Error() string {
+	return fmt.Sprintf("%s!%s: %v", err.Sheet, err.Cell, err.Err)
+}
+
+// CalcErrors defined the errors of calculating the formulas in the workbook.
+type CalcErrors []CalcError
+
+func (errs CalcErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// CalcWorkbook provides a function to calculate all formulas in the workbook
+// and store the results as the cached values of the formula cells, so the
+// calculated values can be shown without recalculating by the spreadsheet
+// application. The dependent formulas will be calculated first. The
+// circular references will be reported as errors, unless the iterative
+// calculation is enabled in the calculation properties of the workbook, in
+// which case the formulas will be calculated repeatedly until the maximum
+// change is less than the iterate delta or the iterate count is reached. The
+// errors of all cells will be returned together as CalcErrors after all
+// formulas calculated. For example:
+//
+//    if err := f.CalcWorkbook(); err != nil {
+//        if errs, ok := err.(excelize.CalcErrors); ok {
+//            for _, e := range errs {
+//                fmt.Println(e.Sheet, e.Cell, e.Err)
+//            }
+//        }
+//    }
+//
+func (f *File) CalcWorkbook() error {
+	wb := f.workbookReader()
+	iterate, count, delta := false, 1, 0.001
+	if wb.CalcPr != nil && wb.CalcPr.Iterate {
+		iterate, count = true, 100
+		if wb.CalcPr.IterateCount > 0 {
+			count = wb.CalcPr.IterateCount
+		}
+		if wb.CalcPr.IterateDelta > 0 {
+			delta = wb.CalcPr.IterateDelta
+		}
+	}
+	var errs CalcErrors
+	for i := 0; i < count; i++ {
+		ctx := f.newCalcContext()
+		ctx.iterate = iterate
+		errs = nil
+		var change float64
+		for _, sheet := range wb.Sheets.Sheet {
+			if f.isChartSheet(sheet.Name) {
+				continue
+			}
+			xlsx, err := f.workSheetReader(sheet.Name)
+			if err != nil {
+				return err
+			}
+			for r := range xlsx.SheetData.Row {
+				for c := range xlsx.SheetData.Row[r].C {
+					cell := &xlsx.SheetData.Row[r].C[c]
+					if cell.F == nil {
+						continue
+					}
+					formula, err := f.GetCellFormula(sheet.Name, cell.R)
+					if err != nil || formula == "" {
+						continue
+					}
+					ctx.err = nil
+					arg := ctx.calcFormula(sheet.Name, cell.R, formula)
+					if ctx.err != nil {
+						errs = append(errs, CalcError{Sheet: sheet.Name, Cell: cell.R, Err: ctx.err})
+						continue
+					}
+					if d := calcChange(f.cellFormulaArg(cell), arg.scalar()); d > change {
+						change = d
+					}
+					if arg.scalar().Type == ArgError {
+						errs = append(errs, CalcError{Sheet: sheet.Name, Cell: cell.R, Err: errors.New(arg.scalar().String)})
+					}
+					f.setArrayFormulaValue(xlsx, cell, arg)
+				}
+			}
+		}
+		if change < delta {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// calcChange returns the absolute change between the previous and the
+// current calculated value, the change of non-numeric value will be treated
+// as infinity.
+func calcChange(prev, current FormulaArg) float64 {
+	if prev.Type == ArgNumber && current.Type == ArgNumber {
+		return math.Abs(current.Number - prev.Number)
+	}
+	if prev.Type == current.Type && prev.Value() == current.Value() {
+		return 0
+	}
+	return math.Inf(1)
+}
+
+// setArrayFormulaValue provides a function to store the calculated result as
+// the cached value of the formula cell. The result of array formula will be
+// stored into the cells in the reference of the formula, and the #N/A error
+// will be stored for the cells out of the result.
+func (f *File) setArrayFormulaValue(xlsx *xlsxWorksheet, cell *xlsxC, arg FormulaArg) {
+	if cell.F.T != STCellFormulaTypeArray || !strings.Contains(cell.F.Ref, ":") {
+		cell.setFormulaArgValue(arg.scalar())
+		return
+	}
+	area, err := f.areaRefToCoordinates(cell.F.Ref)
+	if err != nil {
+		cell.setFormulaArgValue(arg.scalar())
+		return
+	}
+	_ = sortCoordinates(area)
+	for r := area[1]; r <= area[3]; r++ {
+		prepareSheetXML(xlsx, area[2], r)
+	}
+	for r := area[1]; r <= area[3]; r++ {
+		for c := area[0]; c <= area[2]; c++ {
+			xlsx.SheetData.Row[r-1].C[c-1].setFormulaArgValue(arg.matrixElement(r-area[1], c-area[0]).scalar())
+		}
+	}
+}
+
+// CalcCellValue provides a function to get calculated cell value. This
+// feature is currently in working processing. Array formula, table formula
+// and some other formulas are not supported currently. The formulas of the
+// referenced cells will be calculated recursively. If the formula results in
+// an error value such as #DIV/0!, the error value will be returned as the
+// result and the error. The top-left value will be returned if the formula
+// results in an array, use CalcCellValues to get all values of the array.
+// For example, calculate the formula of cell Sheet1!C1:
+//
+//    result, err := f.CalcCellValue("Sheet1", "C1")
+//
+// Supported formula functions:
+//
+//    DATE
+//    DATEDIF
+//    EOMONTH
+//    FILTER
+//    MMULT
+//    SUM
+//    SUMIFS
+//    TEXT
+//    TEXTJOIN
+//    TRANSPOSE
+//
+func (f *File) CalcCellValue(sheet, cell string) (result string, err error) {
+	var formula string
+	if formula, err = f.GetCellFormula(sheet, cell); err != nil {
+		return
+	}
+	if formula == "" {
+		return f.GetCellValue(sheet, cell)
+	}
+	ctx := f.newCalcContext()
+	arg := ctx.calcFormula(sheet, cell, formula).scalar()
+	if ctx.err != nil {
+		return "", ctx.err
+	}
+	result = arg.Value()
+	if arg.Type == ArgError {
+		err = errors.New(result)
+	}
+	return
+}
+
+// CalcCellValues provides a function to calculate the formula of the cell as
+// a dynamic array formula and returns all values of the result by rows. The
+// result will spill into the neighbouring cells starting from the formula
+// cell: the formula cell will be set as an array formula with the reference
+// of the spill range, and the values will be stored as the cached values of
+// the cells in the range. The #SPILL! error will be returned if any cell in
+// the spill range is not empty. For example, calculate the formula
+// =TRANSPOSE(A1:A3) of cell Sheet1!C1 and spill the result into the range
+// Sheet1!C1:E1:
+//
+//    result, err := f.CalcCellValues("Sheet1", "C1")
+//
+func (f *File) CalcCellValues(sheet, cell string) ([][]string, error) {
+	formula, err := f.GetCellFormula(sheet, cell)
+	if err != nil {
+		return nil, err
+	}
+	if formula == "" {
+		value, err := f.GetCellValue(sheet, cell)
+		return [][]string{{value}}, err
+	}
+	xlsx, _ := f.workSheetReader(sheet)
+	if cell, err = f.mergeCellsParser(xlsx, cell); err != nil {
+		return nil, err
+	}
+	col, row, _ := CellNameToCoordinates(cell)
+	ctx := f.newCalcContext()
+	arg := ctx.calcFormula(sheet, cell, formula)
+	if ctx.err != nil {
+		return nil, ctx.err
+	}
+	if arg.Type != ArgMatrix {
+		arg = newMatrixFormulaArg([][]FormulaArg{{arg}})
+	}
+	rows, cols := arg.matrixSize()
+	if rows == 0 || cols == 0 {
+		arg, rows, cols = newMatrixFormulaArg([][]FormulaArg{{newEmptyFormulaArg()}}), 1, 1
+	}
+	area := []int{col, row, col + cols - 1, row + rows - 1}
+	if !f.checkSpillArea(xlsx, area) {
+		return nil, errors.New(formulaErrorSPILL)
+	}
+	for r := row; r <= area[3]; r++ {
+		prepareSheetXML(xlsx, area[2], r)
+	}
+	formulaCell := &xlsx.SheetData.Row[row-1].C[col-1]
+	if formulaCell.F.T == STCellFormulaTypeArray && strings.Contains(formulaCell.F.Ref, ":") {
+		if prev, err := f.areaRefToCoordinates(formulaCell.F.Ref); err == nil {
+			for r := prev[1]; r <= prev[3] && r <= len(xlsx.SheetData.Row); r++ {
+				for c := prev[0]; c <= prev[2] && c <= len(xlsx.SheetData.Row[r-1].C); c++ {
+					if !cellInRef([]int{c, r}, area) {
+						xlsx.SheetData.Row[r-1].C[c-1].setFormulaArgValue(newEmptyFormulaArg())
+					}
+				}
+			}
+		}
+		formulaCell.F.T, formulaCell.F.Ref = "", ""
+	}
+	if rows*cols > 1 {
+		ref, _ := f.coordinatesToAreaRef(area)
+		formulaCell.F.T, formulaCell.F.Ref = STCellFormulaTypeArray, ref
+	}
+	result := make([][]string, rows)
+	for i := range result {
+		result[i] = make([]string, cols)
+		for j := range result[i] {
+			value := arg.Matrix[i][j].scalar()
+			xlsx.SheetData.Row[row-1+i].C[col-1+j].setFormulaArgValue(value)
+			result[i][j] = value.Value()
+		}
+	}
+	if rows*cols == 1 && arg.Matrix[0][0].Type == ArgError {
+		err = errors.New(result[0][0])
+	}
+	return result, err
+}
+
+// checkSpillArea checks if all cells in the spill area are empty except the
+// formula cell at the top-left and the cells spilled by the formula cell
+// before.
+func (f *File) checkSpillArea(xlsx *xlsxWorksheet, area []int) bool {
+	if area[2] > TotalColumns || area[3] > TotalRows {
+		return false
+	}
+	prev := []int{area[0], area[1], area[0], area[1]}
+	formulaCell := &xlsx.SheetData.Row[area[1]-1].C[area[0]-1]
+	if formulaCell.F.T == STCellFormulaTypeArray && strings.Contains(formulaCell.F.Ref, ":") {
+		if coordinates, err := f.areaRefToCoordinates(formulaCell.F.Ref); err == nil {
+			prev = coordinates
+		}
+	}
+	for r := area[1]; r <= area[3] && r <= len(xlsx.SheetData.Row); r++ {
+		for c := area[0]; c <= area[2] && c <= len(xlsx.SheetData.Row[r-1].C); c++ {
+			if cellInRef([]int{c, r}, prev) {
+				continue
+			}
+			if cell := xlsx.SheetData.Row[r-1].C[c-1]; cell.V != "" || cell.F != nil || cell.IS != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// setFormulaArgValue provides a function to store the formula argument as the
+// cached value of the cell.
+func (c *xlsxC) setFormulaArgValue(arg FormulaArg) {
+	c.T, c.V, c.IS = "", arg.Value(), nil
+	switch arg.Type {
+	case ArgString:
+		c.T = "str"
+	case ArgBool:
+		c.T, c.V = "b", "0"
+		if arg.Boolean {
+			c.V = "1"
+		}
+	case ArgError:
+		c.T = "e"
+	}
+}
+
+// calcFormula provides a function to calculate the formula of the given cell.
+func (ctx *calcContext) calcFormula(sheet, cell, formula string) FormulaArg {
+	key := sheet + "!" + cell
+	if arg, ok := ctx.cache[key]; ok {
+		return arg
+	}
+	if ctx.entry[key] {
+		if ctx.iterate {
+			return ctx.cachedCellArg(sheet, cell)
+		}
+		if ctx.err == nil {
+			ctx.err = fmt.Errorf("circular reference detected in cell %s", key)
+		}
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	node, err := parseFormula(formula)
+	if err != nil {
+		if ctx.err == nil {
+			ctx.err = err
+		}
+		return newErrorFormulaArg(formulaErrorNAME)
+	}
+	ctx.entry[key] = true
+	defer delete(ctx.entry, key)
+	arg := ctx.evalNode(sheet, node)
+	if ctx.err == nil {
+		ctx.cache[key] = arg
+	}
+	return arg
+}
+
+// cachedCellArg provides a function to get the cached value of the formula
+// cell as a formula argument.
+func (ctx *calcContext) cachedCellArg(sheet, cell string) FormulaArg {
+	xlsx, err := ctx.f.workSheetReader(sheet)
+	if err != nil {
+		return newEmptyFormulaArg()
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil || row > len(xlsx.SheetData.Row) || col > len(xlsx.SheetData.Row[row-1].C) {
+		return newEmptyFormulaArg()
+	}
+	return ctx.f.cellFormulaArg(&xlsx.SheetData.Row[row-1].C[col-1])
+}
+
+// evalNode provides a function to evaluate the syntax tree node in the given
+// worksheet.
+func (ctx *calcContext) evalNode(sheet string, node *formulaNode) FormulaArg {
+	switch node.Type {
+	case nodeLiteral:
+		return node.Arg
+	case nodeRef:
+		return ctx.rangeArg(sheet, node.Value)
+	case nodeName:
+		return ctx.nameArg(sheet, node.Value)
+	case nodeFunc:
+		name := strings.TrimPrefix(node.Value, "_XLFN.")
+		fn, ok := formulaFuncs[name]
+		dateFn, isDateFn := formulaDateFuncs[name]
+		custom, registered := ctx.f.functions[name]
+		if !ok && !isDateFn && !registered {
+			return newErrorFormulaArg(formulaErrorNAME)
+		}
+		args := make([]FormulaArg, 0, len(node.Args))
+		for _, arg := range node.Args {
+			args = append(args, ctx.evalNode(sheet, arg))
+		}
+		if ok {
+			return fn(args)
+		}
+		if isDateFn {
+			return dateFn(args, ctx.f.isDate1904())
+		}
+		result, err := custom(args)
+		if err != nil {
+			if ctx.err == nil {
+				ctx.err = fmt.Errorf("function %s: %v", name, err)
+			}
+			return newErrorFormulaArg(formulaErrorVALUE)
+		}
+		return result
+	case nodeBinary:
+		return calcBinary(node.Value, ctx.evalNode(sheet, node.Args[0]), ctx.evalNode(sheet, node.Args[1]))
+	case nodeUnary:
+		return calcUnary(node.Value, ctx.evalNode(sheet, node.Args[0]))
+	case nodePercent:
+		return calcUnary("%", ctx.evalNode(sheet, node.Args[0]))
+	}
+	return newEmptyFormulaArg()
+}
+
+// nameArg provides a function to evaluate the defined name, the worksheet
+// scope defined name takes precedence over the workbook scope one.
+func (ctx *calcContext) nameArg(sheet, name string) FormulaArg {
+	var refersTo string
+	for _, dn := range ctx.f.GetDefinedName() {
+		if !strings.EqualFold(dn.Name, name) {
+			continue
+		}
+		if dn.Scope == sheet {
+			refersTo = dn.RefersTo
+			break
+		}
+		if dn.Scope == "Workbook" {
+			refersTo = dn.RefersTo
+		}
+	}
+	if refersTo == "" {
+		return newErrorFormulaArg(formulaErrorNAME)
+	}
+	return ctx.calcFormula(sheet, "#"+name, refersTo)
+}
+
+// rangeArg provides a function to get the values of cells in the reference
+// as a matrix formula argument. Whole columns and rows references are
+// limited to the used range of the worksheet.
+func (ctx *calcContext) rangeArg(sheet, ref string) FormulaArg {
+	if i := strings.LastIndex(ref, "!"); i != -1 {
+		sheet = ref[:i]
+		if strings.HasPrefix(sheet, "'") {
+			sheet = strings.Replace(strings.Trim(sheet, "'"), "''", "'", -1)
+		}
+		ref = ref[i+1:]
+	}
+	xlsx, err := ctx.f.workSheetReader(sheet)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorREF)
+	}
+	maxCol, maxRow := 0, len(xlsx.SheetData.Row)
+	for _, row := range xlsx.SheetData.Row {
+		if len(row.C) > maxCol {
+			maxCol = len(row.C)
+		}
+	}
+	parts := strings.Split(strings.Replace(ref, "$", "", -1), ":")
+	coordinates := make([]int, 0, 4)
+	for _, part := range parts {
+		var col, row int
+		switch {
+		case formulaColumnRe.MatchString(part):
+			if col, err = ColumnNameToNumber(part); err != nil {
+				return newErrorFormulaArg(formulaErrorREF)
+			}
+			row = 1
+			if len(coordinates) > 0 {
+				row = maxRow
+			}
+		case formulaRowRe.MatchString(part):
+			if row, err = strconv.Atoi(part); err != nil {
+				return newErrorFormulaArg(formulaErrorREF)
+			}
+			col = 1
+			if len(coordinates) > 0 {
+				col = maxCol
+			}
+		default:
+			if col, row, err = CellNameToCoordinates(part); err != nil {
+				return newErrorFormulaArg(formulaErrorREF)
+			}
+		}
+		coordinates = append(coordinates, col, row)
+	}
+	if len(coordinates) == 2 {
+		coordinates = append(coordinates, coordinates...)
+	}
+	_ = sortCoordinates(coordinates)
+	matrix := [][]FormulaArg{}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		var cells []FormulaArg
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			cells = append(cells, ctx.cellArg(sheet, xlsx, col, row))
+		}
+		matrix = append(matrix, cells)
+	}
+	return newMatrixFormulaArg(matrix)
+}
+
+// cellArg provides a function to get the value of cell as a formula
+// argument, the formula of the cell will be calculated if exists.
+func (ctx *calcContext) cellArg(sheet string, xlsx *xlsxWorksheet, col, row int) FormulaArg {
+	if row > len(xlsx.SheetData.Row) || col > len(xlsx.SheetData.Row[row-1].C) {
+		return newEmptyFormulaArg()
+	}
+	c := &xlsx.SheetData.Row[row-1].C[col-1]
+	if c.F != nil {
+		cell, _ := CoordinatesToCellName(col, row)
+		if formula, err := ctx.f.GetCellFormula(sheet, cell); err == nil && formula != "" {
+			return ctx.calcFormula(sheet, cell, formula).scalar()
+		}
+	}
+	return ctx.f.cellFormulaArg(c)
+}
+
+// cellFormulaArg provides a function to convert the raw value of cell to a
+// formula argument by given cell data type.
+func (f *File) cellFormulaArg(c *xlsxC) FormulaArg {
+	switch c.T {
+	case "s":
+		idx, err := strconv.Atoi(c.V)
+		if d := f.sharedStringsReader(); err == nil && idx >= 0 && idx < len(d.SI) {
+			return newStringFormulaArg(d.SI[idx].String())
+		}
+		return newStringFormulaArg(c.V)
+	case "str":
+		return newStringFormulaArg(c.V)
+	case "inlineStr":
+		if c.IS != nil {
+			return newStringFormulaArg(c.IS.String())
+		}
+		return newStringFormulaArg(c.V)
+	case "b":
+		return newBoolFormulaArg(c.V == "1")
+	case "e":
+		return newErrorFormulaArg(c.V)
+	}
+	if c.V == "" {
+		return newEmptyFormulaArg()
+	}
+	n, err := strconv.ParseFloat(c.V, 64)
+	if err != nil {
+		return newStringFormulaArg(c.V)
+	}
+	return newNumberFormulaArg(n)
+}
+
+// calcUnary provides a function to calculate the unary operator, the percent
+// operator is also calculated here. The operator will be applied to each
+// element of the matrix argument.
+func calcUnary(op string, arg FormulaArg) FormulaArg {
+	if arg.Type == ArgMatrix {
+		return calcMatrix(arg, arg, func(value, _ FormulaArg) FormulaArg {
+			return calcUnary(op, value)
+		})
+	}
+	n := arg.toNumber()
+	if n.Type == ArgError || op == "+" {
+		return n
+	}
+	if op == "%" {
+		return newNumberFormulaArg(n.Number / 100)
+	}
+	return newNumberFormulaArg(-n.Number)
+}
+
+// matrixSize returns the number of rows and columns of the formula argument,
+// the scalar argument will be treated as a 1 by 1 matrix.
+func (fa FormulaArg) matrixSize() (rows, cols int) {
+	if fa.Type != ArgMatrix {
+		return 1, 1
+	}
+	if rows = len(fa.Matrix); rows > 0 {
+		cols = len(fa.Matrix[0])
+	}
+	return
+}
+
+// matrixElement returns the element of the formula argument by given row and
+// column index. The scalar argument, single row or single column matrix will
+// be expanded like Excel, and #N/A error will be returned for the index out
+// of range.
+func (fa FormulaArg) matrixElement(row, col int) FormulaArg {
+	rows, cols := fa.matrixSize()
+	if fa.Type != ArgMatrix {
+		return fa
+	}
+	if rows == 1 {
+		row = 0
+	}
+	if cols == 1 {
+		col = 0
+	}
+	if row >= rows || col >= cols {
+		return newErrorFormulaArg(formulaErrorNA)
+	}
+	return fa.Matrix[row][col]
+}
+
+// calcMatrix provides a function to apply the function to each pair of
+// elements of the arguments, and returns the result matrix.
+func calcMatrix(lhs, rhs FormulaArg, fn func(l, r FormulaArg) FormulaArg) FormulaArg {
+	lRows, lCols := lhs.matrixSize()
+	rRows, rCols := rhs.matrixSize()
+	rows, cols := lRows, lCols
+	if rRows > rows {
+		rows = rRows
+	}
+	if rCols > cols {
+		cols = rCols
+	}
+	matrix := make([][]FormulaArg, rows)
+	for i := range matrix {
+		matrix[i] = make([]FormulaArg, cols)
+		for j := range matrix[i] {
+			matrix[i][j] = fn(lhs.matrixElement(i, j), rhs.matrixElement(i, j))
+		}
+	}
+	return newMatrixFormulaArg(matrix)
+}
+
+// calcBinary provides a function to calculate the binary operator. The
+// operator will be applied to each pair of elements if any of the arguments
+// is a matrix.
+func calcBinary(op string, lhs, rhs FormulaArg) FormulaArg {
+	if lhs.Type == ArgMatrix || rhs.Type == ArgMatrix {
+		return calcMatrix(lhs, rhs, func(l, r FormulaArg) FormulaArg {
+			return calcBinary(op, l, r)
+		})
+	}
+	if lhs.Type == ArgError {
+		return lhs
+	}
+	if rhs.Type == ArgError {
+		return rhs
+	}
+	switch op {
+	case "&":
+		return newStringFormulaArg(lhs.Value() + rhs.Value())
+	case "=":
+		return newBoolFormulaArg(compareFormulaArg(lhs, rhs) == 0)
+	case "<>":
+		return newBoolFormulaArg(compareFormulaArg(lhs, rhs) != 0)
+	case "<":
+		return newBoolFormulaArg(compareFormulaArg(lhs, rhs) < 0)
+	case ">":
+		return newBoolFormulaArg(compareFormulaArg(lhs, rhs) > 0)
+	case "<=":
+		return newBoolFormulaArg(compareFormulaArg(lhs, rhs) <= 0)
+	case ">=":
+		return newBoolFormulaArg(compareFormulaArg(lhs, rhs) >= 0)
+	}
+	l, r := lhs.toNumber(), rhs.toNumber()
+	if l.Type == ArgError {
+		return l
+	}
+	if r.Type == ArgError {
+		return r
+	}
+	var result float64
+	switch op {
+	case "+":
+		result = l.Number + r.Number
+	case "-":
+		result = l.Number - r.Number
+	case "*":
+		result = l.Number * r.Number
+	case "/":
+		if r.Number == 0 {
+			return newErrorFormulaArg(formulaErrorDIV)
+		}
+		result = l.Number / r.Number
+	case "^":
+		if l.Number == 0 && r.Number <= 0 {
+			return newErrorFormulaArg(formulaErrorNUM)
+		}
+		result = math.Pow(l.Number, r.Number)
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	return newNumberFormulaArg(result)
+}
+
+// compareFormulaArg compares two scalar formula arguments like Excel, numbers
+// are less than strings and strings are less than booleans, the string
+// comparison is case-insensitive. The empty value is treated as zero, empty
+// string or FALSE by the type of the other argument.
+func compareFormulaArg(lhs, rhs FormulaArg) int {
+	lhs, rhs = lhs.scalar(), rhs.scalar()
+	empty := func(other FormulaArg) FormulaArg {
+		switch other.Type {
+		case ArgString:
+			return newStringFormulaArg("")
+		case ArgBool:
+			return newBoolFormulaArg(false)
+		}
+		return newNumberFormulaArg(0)
+	}
+	if lhs.Type == ArgEmpty {
+		lhs = empty(rhs)
+	}
+	if rhs.Type == ArgEmpty {
+		rhs = empty(lhs)
+	}
+	rank := map[ArgType]int{ArgNumber: 0, ArgString: 1, ArgBool: 2, ArgError: 3}
+	if rank[lhs.Type] != rank[rhs.Type] {
+		if rank[lhs.Type] < rank[rhs.Type] {
+			return -1
+		}
+		return 1
+	}
+	switch lhs.Type {
+	case ArgNumber:
+		if lhs.Number < rhs.Number {
+			return -1
+		}
+		if lhs.Number > rhs.Number {
+			return 1
+		}
+	case ArgString, ArgError:
+		return strings.Compare(strings.ToLower(lhs.String), strings.ToLower(rhs.String))
+	case ArgBool:
+		if lhs.Boolean != rhs.Boolean {
+			if rhs.Boolean {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// formulaCriteria directly maps the criteria of the conditional functions
+// such as SUMIFS, for example: ">=10", "<>apple" or "a*".
+type formulaCriteria struct {
+	Type      string
+	Condition FormulaArg
+}
+
+// newFormulaCriteria provides a function to parse the criteria argument.
+func newFormulaCriteria(arg FormulaArg) *formulaCriteria {
+	arg = arg.scalar()
+	criteria := &formulaCriteria{Type: "=", Condition: arg}
+	switch arg.Type {
+	case ArgNumber, ArgBool:
+		return criteria
+	case ArgEmpty:
+		criteria.Condition = newNumberFormulaArg(0)
+		return criteria
+	}
+	s := arg.Value()
+	for _, op := range []string{"<=", ">=", "<>", "<", ">", "="} {
+		if strings.HasPrefix(s, op) {
+			criteria.Type, s = op, s[len(op):]
+			break
+		}
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		criteria.Condition = newNumberFormulaArg(n)
+		return criteria
+	}
+	if b := newStringFormulaArg(s).toBool(); b.Type == ArgBool {
+		criteria.Condition = b
+		return criteria
+	}
+	criteria.Condition = newStringFormulaArg(s)
+	return criteria
+}
+
+// match checks if the cell value matches the criteria.
+func (c *formulaCriteria) match(cell FormulaArg) bool {
+	cell = cell.scalar()
+	switch c.Type {
+	case "=":
+		return c.equal(cell)
+	case "<>":
+		return !c.equal(cell)
+	}
+	if cell.Type != c.Condition.Type {
+		return false
+	}
+	result := compareFormulaArg(cell, c.Condition)
+	switch c.Type {
+	case "<":
+		return result < 0
+	case ">":
+		return result > 0
+	case "<=":
+		return result <= 0
+	}
+	return result >= 0
+}
+
+// equal checks if the cell value equals to the criteria condition, the
+// wildcard characters * and ? could be used in the string condition.
+func (c *formulaCriteria) equal(cell FormulaArg) bool {
+	switch c.Condition.Type {
+	case ArgNumber:
+		n := cell
+		if cell.Type == ArgString {
+			n = cell.toNumber()
+		}
+		return n.Type == ArgNumber && n.Number == c.Condition.Number
+	case ArgBool:
+		return cell.Type == ArgBool && cell.Boolean == c.Condition.Boolean
+	}
+	if c.Condition.String == "" {
+		return cell.Type == ArgEmpty || cell.Type == ArgString && cell.String == ""
+	}
+	return cell.Type == ArgString && matchFormulaWildcard(c.Condition.String, cell.String)
+}
+
+// matchFormulaWildcard checks if the string matches the pattern
+// case-insensitively. The question mark matches any single character, the
+// asterisk matches any sequence of characters and the tilde escapes them.
+func matchFormulaWildcard(pattern, s string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '~':
+			if i+1 < len(runes) {
+				i++
+			}
+			expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+// dateToExcelSerial provides a function to convert the date to the Excel
+// serial number in the 1900 or 1904 date system, the fictitious 29th
+// February 1900 is taken into account in the 1900 date system.
+func dateToExcelSerial(t time.Time, date1904 bool) float64 {
+	serial, _ := timeToExcelTime(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), date1904)
+	return math.Floor(serial)
+}
+
+// excelSerialToDate provides a function to convert the integer part of the
+// Excel serial number in the 1900 or 1904 date system to the date.
+func excelSerialToDate(serial float64, date1904 bool) time.Time {
+	return timeFromExcelTime(math.Floor(serial), date1904).Round(24 * time.Hour)
+}
+
+// maxExcelSerial provides a function to get the serial number of the last
+// date 9999-12-31 supported by Excel in the 1900 or 1904 date system.
+func maxExcelSerial(date1904 bool) float64 {
+	if date1904 {
+		return 2957003
+	}
+	return 2958465
+}
+
+// formulaDateLayouts defined the date string layouts could be converted to
+// the date serial number in the formula.
+var formulaDateLayouts = []string{
+	"2006-01-02",
+	"2006/1/2",
+	"1/2/2006",
+	"January 2, 2006",
+	"2 January 2006",
+	"2-Jan-2006",
+}
+
+// toDateSerial converts the formula argument to the date serial number, the
+// date string in common layouts is also accepted.
+func (fa FormulaArg) toDateSerial(date1904 bool) FormulaArg {
+	fa = fa.scalar()
+	n := fa.toNumber()
+	if fa.Type != ArgString || n.Type == ArgNumber {
+		return n
+	}
+	for _, layout := range formulaDateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(fa.String)); err == nil {
+			return newNumberFormulaArg(dateToExcelSerial(t, date1904))
+		}
+	}
+	return newErrorFormulaArg(formulaErrorVALUE)
+}
+
+// RegisterFunction provides a function to register the custom formula
+// function by given function name, the custom function will be called when
+// the formula calculated by CalcCellValue, CalcCellValues or CalcWorkbook
+// uses the name. The name is case-insensitive and will be converted to
+// uppercase, and it could not shadow the built-in functions. The arguments
+// of the function will be passed as formula arguments, the range reference
+// argument will be passed as a matrix. An Excel error value could be
+// returned as the result with ArgError type, and the returned error will be
+// reported as the error of the calculation. For example, register a custom
+// function SUMSQ to return the sum of the squares of the arguments:
+//
+//    err := f.RegisterFunction("SUMSQ", func(args []excelize.FormulaArg) (excelize.FormulaArg, error) {
+//        var sum float64
+//        for _, arg := range args {
+//            values := []excelize.FormulaArg{arg}
+//            if arg.Type == excelize.ArgMatrix {
+//                values = nil
+//                for _, row := range arg.Matrix {
+//                    values = append(values, row...)
+//                }
+//            }
+//            for _, value := range values {
+//                if value.Type == excelize.ArgNumber {
+//                    sum += value.Number * value.Number
+//                }
+//            }
+//        }
+//        return excelize.FormulaArg{Type: excelize.ArgNumber, Number: sum}, nil
+//    })
+//
+func (f *File) RegisterFunction(name string, fn func(args []FormulaArg) (FormulaArg, error)) error {
+	name = strings.ToUpper(name)
+	if !formulaFuncNameRe.MatchString(name) {
+		return fmt.Errorf("invalid function name %q", name)
+	}
+	_, ok := formulaFuncs[name]
+	if _, isDateFn := formulaDateFuncs[name]; ok || isDateFn {
+		return fmt.Errorf("function %s is a built-in function", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("function %s could not be nil", name)
+	}
+	if f.functions == nil {
+		f.functions = make(map[string]func(args []FormulaArg) (FormulaArg, error))
+	}
+	f.functions[name] = fn
+	return nil
+}
+
+// formulaFuncNameRe defined the pattern of valid custom function name.
+var formulaFuncNameRe = regexp.MustCompile(`^[A-Z_][A-Z0-9_.]*$`)
+
+// formulaFuncs defined the built-in functions of the formula calculation
+// engine.
+var formulaFuncs = map[string]func(args []FormulaArg) FormulaArg{
+	"FILTER":    fnFILTER,
+	"MMULT":     fnMMULT,
+	"SUM":       fnSUM,
+	"SUMIFS":    fnSUMIFS,
+	"TEXTJOIN":  fnTEXTJOIN,
+	"TRANSPOSE": fnTRANSPOSE,
+}
+
+// formulaDateFuncs defined the built-in functions of the formula calculation
+// engine which depend on the date system of the workbook.
+var formulaDateFuncs = map[string]func(args []FormulaArg, date1904 bool) FormulaArg{
+	"DATE":    fnDATE,
+	"DATEDIF": fnDATEDIF,
+	"EOMONTH": fnEOMONTH,
+	"TEXT":    fnTEXT,
+}
+
+// fnSUM function adds all the numbers in a range of cells and returns the
+// result. The syntax of the function is:
+//
+//    SUM(number1,[number2],...)
+//
+func fnSUM(args []FormulaArg) FormulaArg {
+	var sum float64
+	for _, arg := range args {
+		if arg.Type == ArgMatrix {
+			for _, cell := range arg.flatten() {
+				if cell.Type == ArgError {
+					return cell
+				}
+				if cell.Type == ArgNumber {
+					sum += cell.Number
+				}
+			}
+			continue
+		}
+		n := arg.toNumber()
+		if n.Type == ArgError {
+			return n
+		}
+		sum += n.Number
+	}
+	return newNumberFormulaArg(sum)
+}
+
+// fnSUMIFS function finds values in one or more supplied arrays, that
+// satisfy a set of criteria, and returns the sum of the corresponding values
+// in a further supplied array. The syntax of the function is:
+//
+//    SUMIFS(sum_range,criteria_range1,criteria1,[criteria_range2,criteria2],...)
+//
+func fnSUMIFS(args []FormulaArg) FormulaArg {
+	if len(args) < 3 || len(args)%2 == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	sumRange := args[0]
+	if sumRange.Type != ArgMatrix {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	cells := sumRange.flatten()
+	matched := make([]bool, len(cells))
+	for i := range matched {
+		matched[i] = true
+	}
+	for i := 1; i < len(args); i += 2 {
+		criteriaRange := args[i]
+		if criteriaRange.Type != ArgMatrix || len(criteriaRange.Matrix) != len(sumRange.Matrix) ||
+			len(criteriaRange.Matrix) > 0 && len(criteriaRange.Matrix[0]) != len(sumRange.Matrix[0]) {
+			return newErrorFormulaArg(formulaErrorVALUE)
+		}
+		if arg := args[i+1].scalar(); arg.Type == ArgError {
+			return arg
+		}
+		criteria := newFormulaCriteria(args[i+1])
+		for j, cell := range criteriaRange.flatten() {
+			matched[j] = matched[j] && criteria.match(cell)
+		}
+	}
+	var sum float64
+	for i, cell := range cells {
+		if !matched[i] {
+			continue
+		}
+		if cell.Type == ArgError {
+			return cell
+		}
+		if cell.Type == ArgNumber {
+			sum += cell.Number
+		}
+	}
+	return newNumberFormulaArg(sum)
+}
+
+// fnDATE returns a date, from a user-supplied year, month and day. The month
+// and day out of range will be carried over to the adjacent period. The
+// syntax of the function is:
+//
+//    DATE(year,month,day)
+//
+func fnDATE(args []FormulaArg, date1904 bool) FormulaArg {
+	if len(args) != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	var parts [3]int
+	for i, arg := range args {
+		n := arg.toNumber()
+		if n.Type == ArgError {
+			return n
+		}
+		parts[i] = int(n.Number)
+	}
+	year, month, day := parts[0], parts[1], parts[2]
+	if year < 0 || year > 9999 {
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	if year < 1900 {
+		year += 1900
+	}
+	date, minTime := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC), excelMinTime1900
+	if date1904 {
+		minTime = excelMinTime1904
+	}
+	serial := dateToExcelSerial(date, date1904)
+	if date.Before(minTime) || serial > maxExcelSerial(date1904) {
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	return newNumberFormulaArg(serial)
+}
+
+// fnDATEDIF function calculates the number of days, months, or years
+// between two dates. The unit could be one of Y, M, D, MD, YM and YD. The
+// syntax of the function is:
+//
+//    DATEDIF(start_date,end_date,unit)
+//
+func fnDATEDIF(args []FormulaArg, date1904 bool) FormulaArg {
+	if len(args) != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	start, end := args[0].toDateSerial(date1904), args[1].toDateSerial(date1904)
+	if start.Type == ArgError {
+		return start
+	}
+	if end.Type == ArgError {
+		return end
+	}
+	if start.Number < 0 || end.Number < 0 || start.Number > end.Number {
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	unit := args[2].scalar()
+	if unit.Type == ArgError {
+		return unit
+	}
+	startDate, endDate := excelSerialToDate(start.Number, date1904), excelSerialToDate(end.Number, date1904)
+	y1, m1, d1 := startDate.Date()
+	y2, m2, d2 := endDate.Date()
+	months := (y2-y1)*12 + int(m2) - int(m1)
+	if d2 < d1 {
+		months--
+	}
+	var diff float64
+	switch strings.ToUpper(unit.Value()) {
+	case "D":
+		diff = math.Floor(end.Number) - math.Floor(start.Number)
+	case "M":
+		diff = float64(months)
+	case "Y":
+		diff = float64(months / 12)
+	case "YM":
+		diff = float64(months % 12)
+	case "MD":
+		diff = float64(d2 - d1)
+		if d2 < d1 {
+			diff += float64(time.Date(y2, m2, 0, 0, 0, 0, 0, time.UTC).Day())
+		}
+	case "YD":
+		anniversary := time.Date(y2, m1, d1, 0, 0, 0, 0, time.UTC)
+		if anniversary.After(endDate) {
+			anniversary = time.Date(y2-1, m1, d1, 0, 0, 0, 0, time.UTC)
+		}
+		diff = math.Floor(endDate.Sub(anniversary).Hours() / 24)
+	default:
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	return newNumberFormulaArg(diff)
+}
+
+// fnEOMONTH function returns the last day of the month, that is a specified
+// number of months before or after an initial supplied start date. The
+// syntax of the function is:
+//
+//    EOMONTH(start_date,months)
+//
+func fnEOMONTH(args []FormulaArg, date1904 bool) FormulaArg {
+	if len(args) != 2 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	start := args[0].toDateSerial(date1904)
+	if start.Type == ArgError {
+		return start
+	}
+	months := args[1].toNumber()
+	if months.Type == ArgError {
+		return months
+	}
+	if start.Number < 0 {
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	date := excelSerialToDate(start.Number, date1904)
+	serial := dateToExcelSerial(time.Date(date.Year(), date.Month()+time.Month(months.Number)+1, 0, 0, 0, 0, 0, time.UTC), date1904)
+	if serial < 0 || serial > maxExcelSerial(date1904) {
+		return newErrorFormulaArg(formulaErrorNUM)
+	}
+	return newNumberFormulaArg(serial)
+}
+
+// fnTEXT function converts a supplied numeric value into text, in a
+// user-specified format. The syntax of the function is:
+//
+//    TEXT(value,format_text)
+//
+func fnTEXT(args []FormulaArg, date1904 bool) FormulaArg {
+	if len(args) != 2 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	value, format := args[0].scalar(), args[1].scalar()
+	if value.Type == ArgError {
+		return value
+	}
+	if format.Type == ArgError {
+		return format
+	}
+	switch value.Type {
+	case ArgBool:
+		return newStringFormulaArg(value.Value())
+	case ArgString:
+		n := value.toDateSerial(date1904)
+		if n.Type == ArgError {
+			if sections := splitNumFmtCode(format.Value()); len(sections) > 3 {
+				return newStringFormulaArg(strings.Replace(strings.Replace(sections[3], "\"", "", -1), "@", value.String, -1))
+			}
+			return value
+		}
+		value = n
+	}
+	return newStringFormulaArg(applyNumFmtCode(value.toNumber().Number, format.Value(), date1904))
+}
+
+// fnTEXTJOIN function joins together a series of supplied text strings into
+// one combined text string. The user can specify a delimiter to add between
+// the individual text items, as well as whether or not blank cells should be
+// ignored. The syntax of the function is:
+//
+//    TEXTJOIN(delimiter,ignore_empty,text1,[text2],...)
+//
+func fnTEXTJOIN(args []FormulaArg) FormulaArg {
+	if len(args) < 3 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	var delimiters []string
+	for _, d := range args[0].flatten() {
+		if d.Type == ArgError {
+			return d
+		}
+		delimiters = append(delimiters, d.Value())
+	}
+	if len(delimiters) == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	ignoreEmpty := args[1].toBool()
+	if ignoreEmpty.Type == ArgError {
+		return ignoreEmpty
+	}
+	var texts []string
+	for _, arg := range args[2:] {
+		for _, cell := range arg.flatten() {
+			if cell.Type == ArgError {
+				return cell
+			}
+			if text := cell.Value(); text != "" || !ignoreEmpty.Boolean {
+				texts = append(texts, text)
+			}
+		}
+	}
+	var result strings.Builder
+	for i, text := range texts {
+		if i > 0 {
+			result.WriteString(delimiters[(i-1)%len(delimiters)])
+		}
+		result.WriteString(text)
+	}
+	if result.Len() > 32767 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	return newStringFormulaArg(result.String())
+}
+
+// fnTRANSPOSE function transforms a vertical range of cells into a
+// horizontal range, or a horizontal range into a vertical range. The syntax
+// of the function is:
+//
+//    TRANSPOSE(array)
+//
+func fnTRANSPOSE(args []FormulaArg) FormulaArg {
+	if len(args) != 1 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	if args[0].Type != ArgMatrix {
+		return args[0]
+	}
+	rows, cols := args[0].matrixSize()
+	matrix := make([][]FormulaArg, cols)
+	for i := range matrix {
+		matrix[i] = make([]FormulaArg, rows)
+		for j := range matrix[i] {
+			matrix[i][j] = args[0].Matrix[j][i]
+		}
+	}
+	return newMatrixFormulaArg(matrix)
+}
+
+// fnMMULT function calculates the matrix product of two arrays, the number
+// of columns of array1 must be the same as the number of rows of array2. The
+// syntax of the function is:
+//
+//    MMULT(array1,array2)
+//
+func fnMMULT(args []FormulaArg) FormulaArg {
+	if len(args) != 2 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	lhs, rhs := args[0], args[1]
+	lRows, lCols := lhs.matrixSize()
+	rRows, rCols := rhs.matrixSize()
+	if lCols != rRows || lRows == 0 || rCols == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	for _, arg := range append(lhs.flatten(), rhs.flatten()...) {
+		if arg.Type == ArgError {
+			return arg
+		}
+		if arg.Type != ArgNumber {
+			return newErrorFormulaArg(formulaErrorVALUE)
+		}
+	}
+	matrix := make([][]FormulaArg, lRows)
+	for i := range matrix {
+		matrix[i] = make([]FormulaArg, rCols)
+		for j := range matrix[i] {
+			var sum float64
+			for k := 0; k < lCols; k++ {
+				sum += lhs.matrixElement(i, k).Number * rhs.matrixElement(k, j).Number
+			}
+			matrix[i][j] = newNumberFormulaArg(sum)
+		}
+	}
+	return newMatrixFormulaArg(matrix)
+}
+
+// fnFILTER function filters a range of data based on supplied criteria. The
+// include argument should be a single column with the same number of rows
+// as the array, or a single row with the same number of columns as the
+// array. The #CALC! error will be returned if no data matched and the
+// if_empty argument is omitted. The syntax of the function is:
+//
+//    FILTER(array,include,[if_empty])
+//
+func fnFILTER(args []FormulaArg) FormulaArg {
+	if len(args) < 2 || len(args) > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	array, include := args[0], args[1]
+	rows, cols := array.matrixSize()
+	iRows, iCols := include.matrixSize()
+	byRow := iCols == 1 && iRows == rows
+	if !byRow && (iRows != 1 || iCols != cols) {
+		return newErrorFormulaArg(formulaErrorVALUE)
+	}
+	var matrix [][]FormulaArg
+	for i, cond := range include.flatten() {
+		if cond.Type == ArgString {
+			return newErrorFormulaArg(formulaErrorVALUE)
+		}
+		if cond = cond.toBool(); cond.Type == ArgError {
+			return cond
+		}
+		if !cond.Boolean {
+			continue
+		}
+		if byRow {
+			var row []FormulaArg
+			for j := 0; j < cols; j++ {
+				row = append(row, array.matrixElement(i, j))
+			}
+			matrix = append(matrix, row)
+			continue
+		}
+		for j := 0; j < rows; j++ {
+			if len(matrix) <= j {
+				matrix = append(matrix, []FormulaArg{})
+			}
+			matrix[j] = append(matrix[j], array.matrixElement(j, i))
+		}
+	}
+	if len(matrix) == 0 {
+		if len(args) == 3 && args[2].Type != ArgEmpty {
+			return args[2]
+		}
+		return newErrorFormulaArg(formulaErrorCALC)
+	}
+	return newMatrixFormulaArg(matrix)
+}
@@ -0,0 +1,381 @@
+package excelize
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcCellValue(t *testing.T) {
+	prepareData := func() *File {
+		f := NewFile()
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"apple", 10, 1}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"banana", 20, 2}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"apple", 30, 3}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"cherry", 40, 4}))
+		assert.NoError(t, f.SetCellValue("Sheet1", "E1", "a"))
+		assert.NoError(t, f.SetCellValue("Sheet1", "E3", 1))
+		return f
+	}
+
+	mathCalc := map[string]string{
+		"=1+2*3":        "7",
+		"=-2^2":         "4",
+		"=(1+2)*3%":     "0.09",
+		"=\"a\"&1":      "a1",
+		"=1<2":          "TRUE",
+		"=\"A\"=\"a\"":  "TRUE",
+		"=SUM(B1:B4,1)": "101",
+		"=SUM(B1,C1)":   "11",
+		"=SUM(E1,1)":    "1",
+		// DATE
+		"=DATE(2020,10,21)": "44125",
+		"=DATE(1900,1,1)":   "1",
+		"=DATE(99,1,1)":     "36161",
+		"=DATE(2020,14,1)":  "44228",
+		"=DATE(2020,1,-1)":  "43829",
+		// DATEDIF
+		"=DATEDIF(DATE(2020,1,31),DATE(2021,3,1),\"Y\")":   "1",
+		"=DATEDIF(DATE(2020,1,31),DATE(2021,3,1),\"M\")":   "13",
+		"=DATEDIF(DATE(2020,1,31),DATE(2021,3,1),\"D\")":   "395",
+		"=DATEDIF(DATE(2020,1,31),DATE(2021,3,1),\"YM\")":  "1",
+		"=DATEDIF(DATE(2020,1,15),DATE(2020,3,10),\"MD\")": "24",
+		"=DATEDIF(DATE(2019,6,15),DATE(2021,3,1),\"yd\")":  "259",
+		"=DATEDIF(\"2020-01-01\",\"2020-03-01\",\"D\")":    "60",
+		// EOMONTH
+		"=EOMONTH(DATE(2020,1,15),1)":  "43890",
+		"=EOMONTH(DATE(2020,1,15),-2)": "43799",
+		"=EOMONTH(\"2020-01-15\",0)":   "43861",
+		// TEXT
+		"=TEXT(1234.567,\"#,##0.00\")":                 "1,234.57",
+		"=TEXT(1234.5678,\"$#,##0.00\")":               "$1,234.57",
+		"=TEXT(0.285,\"0.0%\")":                        "28.5%",
+		"=TEXT(-5,\"0;(0)\")":                          "(5)",
+		"=TEXT(-5,\"0.0\")":                            "-5.0",
+		"=TEXT(5,\"000\")":                             "005",
+		"=TEXT(0,\"#\")":                               "",
+		"=TEXT(12345678,\"#,##0,\")":                   "12,346",
+		"=TEXT(1234.5,\"0.00E+00\")":                   "1.23E+03",
+		"=TEXT(0.00012,\"0.0E+00\")":                   "1.2E-04",
+		"=TEXT(DATE(2020,10,21),\"yyyy-mm-dd\")":       "2020-10-21",
+		"=TEXT(DATE(2020,10,21),\"YYYY/MM/DD\")":       "2020/10/21",
+		"=TEXT(DATE(2020,1,1)+0.75,\"hh:mm\")":         "18:00",
+		"=TEXT(\"abc\",\"0\")":                         "abc",
+		"=TEXT(\"abc\",\"0;0;0;\"\"[\"\"@\"\"]\"\"\")": "[abc]",
+		"=TEXT(TRUE,\"0\")":                            "TRUE",
+		"=TEXT(\"12.5\",\"0.00\")":                     "12.50",
+		// TEXTJOIN
+		"=TEXTJOIN(\",\",TRUE,E1:E3,\"x\")":  "a,1,x",
+		"=TEXTJOIN(\",\",FALSE,E1:E3,\"x\")": "a,,1,x",
+		"=_xlfn.TEXTJOIN(\"-\",1,A1:A2)":     "apple-banana",
+		// SUMIFS
+		"=SUMIFS(B1:B4,A1:A4,\"apple\")":              "40",
+		"=SUMIFS(B1:B4,A1:A4,\"?PPLE\")":              "40",
+		"=SUMIFS(B1:B4,A1:A4,\"a*\",C1:C4,\">1\")":    "30",
+		"=SUMIFS(B1:B4,C1:C4,\"<>2\")":                "80",
+		"=SUMIFS(B1:B4,C1:C4,\">=3\")":                "70",
+		"=SUMIFS(B1:B4,C1:C4,3)":                      "30",
+		"=SUMIFS(B1:B4,D1:D4,\"\")":                   "100",
+		"=SUMIFS(B1:B4,D1:D4,\"x\")":                  "0",
+		"=SUMIFS(B:B,A:A,\"banana\")":                 "20",
+		"=SUMIFS(Sheet1!B1:B4,'Sheet1'!A1:A4,\"b*\")": "20",
+		// Array formulas
+		"=SUM(B1:B4*C1:C4)":               "300",
+		"=SUM(B1:B4*{1;0;1;0})":           "40",
+		"=SUM(-B1:B2%)":                   "-0.3",
+		"=TRANSPOSE(B1:C2)":               "10",
+		"=MMULT({1,2;3,4},{5;6})":         "17",
+		"=FILTER(A1:B4,C1:C4>2)":          "apple",
+		"=FILTER(B1:C4,{0,1})":            "1",
+		"=FILTER(B1:B4,C1:C4>4,\"none\")": "none",
+	}
+	for formula, expected := range mathCalc {
+		f := prepareData()
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, err := f.CalcCellValue("Sheet1", "F1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+
+	mathCalcError := map[string]string{
+		"=1/0":                           "#DIV/0!",
+		"=\"a\"+1":                       "#VALUE!",
+		"=UNKNOWN(1)":                    "#NAME?",
+		"=UNKNOWN_NAME":                  "#NAME?",
+		"=SUM(1,#N/A)":                   "#N/A",
+		"=DATE(-1,1,1)":                  "#NUM!",
+		"=DATE(10000,1,1)":               "#NUM!",
+		"=DATE(1900,1,-1)":               "#NUM!",
+		"=DATE(\"a\",1,1)":               "#VALUE!",
+		"=DATE(2020,1)":                  "#VALUE!",
+		"=DATEDIF(-1,1,\"D\")":           "#NUM!",
+		"=DATEDIF(10,1,\"D\")":           "#NUM!",
+		"=DATEDIF(1,10,\"X\")":           "#NUM!",
+		"=DATEDIF(\"a\",10,\"D\")":       "#VALUE!",
+		"=DATEDIF(1,2)":                  "#VALUE!",
+		"=EOMONTH(-1,0)":                 "#NUM!",
+		"=EOMONTH(1,\"a\")":              "#VALUE!",
+		"=EOMONTH(1)":                    "#VALUE!",
+		"=TEXT(1/0,\"0\")":               "#DIV/0!",
+		"=TEXT(1)":                       "#VALUE!",
+		"=TEXTJOIN(\"-\",TRUE)":          "#VALUE!",
+		"=TEXTJOIN(\"-\",\"x\",1)":       "#VALUE!",
+		"=TEXTJOIN(\"-\",TRUE,1/0)":      "#DIV/0!",
+		"=SUMIFS(B1:B4,A1:A3,\"apple\")": "#VALUE!",
+		"=SUMIFS(B1:B4,A1:A4)":           "#VALUE!",
+		"=SUMIFS(1,A1:A4,\"apple\")":     "#VALUE!",
+		"=SUMIFS(B1:B4,A1:A4,1/0)":       "#DIV/0!",
+		"=SUM(B1:B4+{1;2})":              "#N/A",
+		"=TRANSPOSE(1,2)":                "#VALUE!",
+		"=MMULT({1,2},{3,4})":            "#VALUE!",
+		"=MMULT(A1:A2,B1:B2)":            "#VALUE!",
+		"=MMULT({1;2},{#N/A,2})":         "#N/A",
+		"=MMULT(1)":                      "#VALUE!",
+		"=FILTER(B1:B4,C1:C4>4)":         "#CALC!",
+		"=FILTER(B1:B4,C1:C2>4)":         "#VALUE!",
+		"=FILTER(B1:B4,A1:A4)":           "#VALUE!",
+		"=FILTER(B1:B4,{1;1;1;#N/A})":    "#N/A",
+		"=FILTER(B1:B4)":                 "#VALUE!",
+	}
+	for formula, expected := range mathCalcError {
+		f := prepareData()
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, err := f.CalcCellValue("Sheet1", "F1")
+		assert.EqualError(t, err, expected, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	// Test TEXTJOIN with an empty delimiter array.
+	assert.Equal(t, newErrorFormulaArg(formulaErrorVALUE), fnTEXTJOIN([]FormulaArg{newMatrixFormulaArg(nil), newBoolFormulaArg(true), newStringFormulaArg("a"), newStringFormulaArg("b")}))
+
+	f := prepareData()
+	// Test calculate the formulas of referenced cells recursively.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "=SUM(B1:B2)"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F2", "=F1*2"))
+	result, err := f.CalcCellValue("Sheet1", "F2")
+	assert.NoError(t, err)
+	assert.Equal(t, "60", result)
+	// Test calculate the cell without formula.
+	result, err = f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", result)
+	// Test calculate with defined name.
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$B$1:$B$4"}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F3", "=SUM(Amount)"))
+	result, err = f.CalcCellValue("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", result)
+	// Test calculate with circular reference.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "=G2+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G2", "=G1+1"))
+	_, err = f.CalcCellValue("Sheet1", "G1")
+	assert.EqualError(t, err, "circular reference detected in cell Sheet1!G1")
+	// Test calculate with invalid formula.
+	for _, formula := range []string{"=SUM(1", "=1+", "=\"a", "=(1", "=1 2", "=#ABC", "={1,2;3}"} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "G3", formula))
+		_, err = f.CalcCellValue("Sheet1", "G3")
+		assert.Error(t, err, formula)
+	}
+	// Test calculate with not exist worksheet.
+	_, err = f.CalcCellValue("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G4", "=SUM(SheetN!A1)"))
+	_, err = f.CalcCellValue("Sheet1", "G4")
+	assert.EqualError(t, err, "#REF!")
+}
+
+func TestCalcCellValues(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2, "a"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{3, 4, true}))
+	// Test spill the result into the neighbouring cells.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "TRANSPOSE(A1:C2)"))
+	result, err := f.CalcCellValues("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "3"}, {"2", "4"}, {"a", "TRUE"}}, result)
+	for cell, expected := range map[string]string{"E1": "1", "F1": "3", "E2": "2", "F3": "1", "E3": "a"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "TRANSPOSE(A1:C2)", formula)
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxF{Content: "TRANSPOSE(A1:C2)", T: STCellFormulaTypeArray, Ref: "E1:F3"}, xlsx.SheetData.Row[0].C[4].F)
+	// Test recalculate with smaller result, the previous spilled values
+	// outside the new spill range should be cleared.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "TRANSPOSE(A1:B1)"))
+	result, err = f.CalcCellValues("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1"}, {"2"}}, result)
+	value, err := f.GetCellValue("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", value)
+	assert.Equal(t, "E1:E2", xlsx.SheetData.Row[0].C[4].F.Ref)
+	// Test spill collision.
+	assert.NoError(t, f.SetCellValue("Sheet1", "F1", "x"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "A1:B2"))
+	_, err = f.CalcCellValues("Sheet1", "E1")
+	assert.EqualError(t, err, formulaErrorSPILL)
+	// Test calculate with scalar result.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "1/0"))
+	result, err = f.CalcCellValues("Sheet1", "E1")
+	assert.EqualError(t, err, formulaErrorDIV)
+	assert.Equal(t, [][]string{{formulaErrorDIV}}, result)
+	assert.Equal(t, &xlsxF{Content: "1/0"}, xlsx.SheetData.Row[0].C[4].F)
+	// Test calculate the cell without formula.
+	result, err = f.CalcCellValues("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1"}}, result)
+	// Test calculate with invalid formula and not exist worksheet.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "SUM("))
+	_, err = f.CalcCellValues("Sheet1", "E1")
+	assert.Error(t, err)
+	_, err = f.CalcCellValues("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestCalcWorkbook(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2, 3}))
+	// The formula cells depend on the formula cells in the later positions
+	// and other worksheet.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "Sheet2!A1*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "SUM(Sheet1!A1:C1)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A2", "TEXT(Sheet1!D1,\"0.00\")"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A3", "A1>5"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "B1", "TRANSPOSE(Sheet1!A1:C1)", FormulaOpts{Type: stringPtr(STCellFormulaTypeArray), Ref: stringPtr("B1:B4")}))
+	assert.NoError(t, f.CalcWorkbook())
+	for _, c := range []struct{ sheet, cell, value string }{
+		{"Sheet1", "D1", "12"},
+		{"Sheet2", "A1", "6"},
+		{"Sheet2", "A2", "12.00"},
+		{"Sheet2", "A3", "1"},
+		{"Sheet2", "B1", "1"},
+		{"Sheet2", "B3", "3"},
+		{"Sheet2", "B4", "#N/A"},
+	} {
+		value, err := f.GetCellValue(c.sheet, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.value, value, c.cell)
+	}
+	xlsx, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", xlsx.SheetData.Row[2].C[0].T)
+	assert.Equal(t, "str", xlsx.SheetData.Row[1].C[0].T)
+
+	// Test calculate with errors.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "1/0"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "F2+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F2", "F1+1"))
+	err = f.CalcWorkbook()
+	assert.EqualError(t, err, "Sheet1!E1: #DIV/0!; Sheet1!F1: circular reference detected in cell Sheet1!F1; Sheet1!F2: circular reference detected in cell Sheet1!F2")
+	errs, ok := err.(CalcErrors)
+	assert.True(t, ok)
+	assert.Len(t, errs, 3)
+	assert.Equal(t, CalcError{Sheet: "Sheet1", Cell: "E1", Err: errs[0].Err}, errs[0])
+	value, err := f.GetCellValue("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "#DIV/0!", value)
+
+	// Test iterative calculation with circular references.
+	f = NewFile()
+	f.workbookReader().CalcPr = &xlsxCalcPr{Iterate: true, IterateCount: 50, IterateDelta: 0.0001}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "A1/2+1"))
+	assert.NoError(t, f.CalcWorkbook())
+	result, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.99993896484375", result)
+	f.workbookReader().CalcPr = &xlsxCalcPr{Iterate: true, IterateCount: 3}
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 0))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "A1/2+1"))
+	assert.NoError(t, f.CalcWorkbook())
+	result, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.75", result)
+
+	// Test calculate the workbook contains chart sheet.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "SUM(1,2)"))
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"name":"Sheet1!$A$1","values":"Sheet1!$A$1:$B$1"}]}`))
+	assert.NoError(t, f.CalcWorkbook())
+	result, err = f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result)
+
+	// Test calculate with not exist worksheet.
+	f.workbookReader().Sheets.Sheet = append(f.workbookReader().Sheets.Sheet, xlsxSheet{Name: "SheetN"})
+	assert.EqualError(t, f.CalcWorkbook(), "sheet SheetN is not exist")
+}
+
+func TestRegisterFunction(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2, "a"}))
+	assert.NoError(t, f.RegisterFunction("twice", func(args []FormulaArg) (FormulaArg, error) {
+		if len(args) != 1 {
+			return FormulaArg{Type: ArgError, String: formulaErrorVALUE}, nil
+		}
+		if args[0].Type == ArgString {
+			return FormulaArg{}, errors.New("number expected")
+		}
+		n := args[0].toNumber()
+		return FormulaArg{Type: ArgNumber, Number: n.Number * 2}, nil
+	}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "TWICE(B1)+Twice(3)"))
+	result, err := f.CalcCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10", result)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "TWICE(1,2)"))
+	result, err = f.CalcCellValue("Sheet1", "D1")
+	assert.EqualError(t, err, formulaErrorVALUE)
+	assert.Equal(t, formulaErrorVALUE, result)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "TWICE(\"a\")"))
+	_, err = f.CalcCellValue("Sheet1", "D1")
+	assert.EqualError(t, err, "function TWICE: number expected")
+
+	assert.EqualError(t, f.RegisterFunction("SUM", func(args []FormulaArg) (FormulaArg, error) { return args[0], nil }), "function SUM is a built-in function")
+	assert.EqualError(t, f.RegisterFunction("1A", func(args []FormulaArg) (FormulaArg, error) { return args[0], nil }), `invalid function name "1A"`)
+	assert.EqualError(t, f.RegisterFunction("", func(args []FormulaArg) (FormulaArg, error) { return args[0], nil }), `invalid function name ""`)
+	assert.EqualError(t, f.RegisterFunction("NONE", nil), "function NONE could not be nil")
+}
+
+func ExampleFile_RegisterFunction() {
+	f := NewFile()
+	if err := f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2, 3}); err != nil {
+		fmt.Println(err)
+	}
+	// Register a custom aggregate function SUMSQ to return the sum of the
+	// squares of the numbers.
+	if err := f.RegisterFunction("SUMSQ", func(args []FormulaArg) (FormulaArg, error) {
+		var sum float64
+		for _, arg := range args {
+			values := []FormulaArg{arg}
+			if arg.Type == ArgMatrix {
+				values = nil
+				for _, row := range arg.Matrix {
+					values = append(values, row...)
+				}
+			}
+			for _, value := range values {
+				if value.Type == ArgNumber {
+					sum += value.Number * value.Number
+				}
+			}
+		}
+		return FormulaArg{Type: ArgNumber, Number: sum}, nil
+	}); err != nil {
+		fmt.Println(err)
+	}
+	if err := f.SetCellFormula("Sheet1", "D1", "SUMSQ(A1:C1,4)"); err != nil {
+		fmt.Println(err)
+	}
+	result, err := f.CalcCellValue("Sheet1", "D1")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(result)
+	// Output: 30
+}
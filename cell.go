@@ -17,6 +17,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/mohae/deepcopy"
 )
 
 const (
@@ -44,6 +46,30 @@ func (f *File) GetCellValue(sheet, axis string) (string, error) {
 	})
 }
 
+// GetCellValueWithColor provides a function to get formatted value and the
+// color of the number format section which applied to the cell by given
+// worksheet name and axis in XLSX file. The color is the RGB color code such
+// as "#FF0000" for the number format code "#,##0.00;[Red]-#,##0.00" with a
+// negative value, and the empty string will be returned if the number format
+// doesn't specify the color. For example:
+//
+//    value, color, err := f.GetCellValueWithColor("Sheet1", "A1")
+//
+func (f *File) GetCellValueWithColor(sheet, axis string) (string, string, error) {
+	var color string
+	val, err := f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+		val, err := c.getValueFrom(f, f.sharedStringsReader())
+		if err != nil {
+			return val, false, err
+		}
+		if c.T == "" || c.T == "n" {
+			color = f.formattedValueColor(c.S, c.V)
+		}
+		return val, true, err
+	})
+	return val, color, err
+}
+
 // SetCellValue provides a function to set value of a cell. The specified
 // coordinates should not be in the first row of the table. The following
 // shows the supported data types:
@@ -65,10 +91,18 @@ func (f *File) GetCellValue(sheet, axis string) (string, error) {
 //    time.Duration
 //    time.Time
 //    bool
+//    CellError
 //    nil
 //
+// The CellError value must be one of the error values supported by Excel, for
+// example, set the error value #N/A to the cell A1 on Sheet1:
+//
+//    err := f.SetCellValue("Sheet1", "A1", excelize.CellError("#N/A"))
+//
 // Note that default date format is m/d/yy h:mm of time.Time type value. You can
-// set numbers format by SetCellStyle() method.
+// set numbers format by SetCellStyle() method. Setting the value to nil will
+// clear the value and formula of the cell but keep its style, use ClearCell()
+// to remove the cell completely.
 func (f *File) SetCellValue(sheet, axis string, value interface{}) error {
 	var err error
 	switch v := value.(type) {
@@ -93,14 +127,70 @@ func (f *File) SetCellValue(sheet, axis string, value interface{}) error {
 		err = f.setCellTimeFunc(sheet, axis, v)
 	case bool:
 		err = f.SetCellBool(sheet, axis, v)
+	case CellError:
+		err = f.setCellErrorFunc(sheet, axis, v)
 	case nil:
-		err = f.SetCellStr(sheet, axis, "")
+		err = f.setCellBlank(sheet, axis)
 	default:
 		err = f.SetCellStr(sheet, axis, fmt.Sprint(value))
 	}
 	return err
 }
 
+// SetCellValues provides a function to set the values of multiple cells by
+// given worksheet name and the map of the cell coordinates and values. The
+// supported value types are the same as the function SetCellValue. All the
+// cell coordinates will be validated before setting any value, and the
+// worksheet will be read only once, which is faster than calling
+// SetCellValue for each cell. The order of setting the values is irrelevant
+// since each cell is set only once. For example:
+//
+//    err := f.SetCellValues("Sheet1", map[string]interface{}{"A1": 1, "B2": "x"})
+//
+func (f *File) SetCellValues(sheet string, values map[string]interface{}) error {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	for axis, value := range values {
+		if _, _, err = CellNameToCoordinates(axis); err != nil {
+			return err
+		}
+		if v, ok := value.(CellError); ok {
+			if _, _, err = setCellError(v); err != nil {
+				return err
+			}
+		}
+	}
+	for axis, value := range values {
+		switch value.(type) {
+		case time.Duration, time.Time, nil:
+			// These values need to set the default styles or update the
+			// calculation chain.
+			if err = f.SetCellValue(sheet, axis, value); err != nil {
+				return err
+			}
+			continue
+		}
+		cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
+		if err != nil {
+			return err
+		}
+		cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
+		switch v := value.(type) {
+		case float32:
+			cellData.T, cellData.V = setCellFloat(f.roundSignificantDigits(float64(v), 32), -1, 32)
+			cellData.XMLSpace = xml.Attr{}
+		case float64:
+			cellData.T, cellData.V = setCellFloat(f.roundSignificantDigits(v, 64), -1, 64)
+			cellData.XMLSpace = xml.Attr{}
+		default:
+			_ = setCellValFunc(cellData, value, f.isDate1904())
+		}
+	}
+	return err
+}
+
 // setCellIntFunc is a wrapper of SetCellInt.
 func (f *File) setCellIntFunc(sheet, axis string, value interface{}) error {
 	var err error
@@ -136,14 +226,14 @@ func (f *File) setCellTimeFunc(sheet, axis string, value time.Time) error {
 	if err != nil {
 		return err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
-	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
 
 	var isNum bool
-	cellData.T, cellData.V, isNum, err = setCellTime(value)
+	cellData.T, cellData.V, isNum, err = setCellTime(value, f.isDate1904())
 	if err != nil {
 		return err
 	}
@@ -156,9 +246,9 @@ func (f *File) setCellTimeFunc(sheet, axis string, value time.Time) error {
 	return err
 }
 
-func setCellTime(value time.Time) (t string, b string, isNum bool, err error) {
+func setCellTime(value time.Time, date1904 bool) (t string, b string, isNum bool, err error) {
 	var excelTime float64
-	excelTime, err = timeToExcelTime(value)
+	excelTime, err = timeToExcelTime(value, date1904)
 	if err != nil {
 		return
 	}
@@ -183,11 +273,11 @@ func (f *File) SetCellInt(sheet, axis string, value int) error {
 	if err != nil {
 		return err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
-	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
 	cellData.T, cellData.V = setCellInt(value)
 	return err
 }
@@ -204,11 +294,11 @@ func (f *File) SetCellBool(sheet, axis string, value bool) error {
 	if err != nil {
 		return err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
-	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
 	cellData.T, cellData.V = setCellBool(value)
 	return err
 }
@@ -223,6 +313,41 @@ func setCellBool(value bool) (t string, v string) {
 	return
 }
 
+// CellError directly maps the error value of a cell, such as #N/A and
+// #DIV/0!. It can be used as a value of SetCellValue and the SetRow of the
+// stream writer to write an error value instead of a string.
+type CellError string
+
+// setCellErrorFunc provides a function to set the error value of a cell by
+// given worksheet name, cell name and error value.
+func (f *File) setCellErrorFunc(sheet, axis string, value CellError) error {
+	t, v, err := setCellError(value)
+	if err != nil {
+		return err
+	}
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
+	if err != nil {
+		return err
+	}
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
+	cellData.T, cellData.V, cellData.XMLSpace = t, v, xml.Attr{}
+	return err
+}
+
+// setCellError provides a function to get the cell type and value of the
+// given error value, the error value must be one of the supported formula
+// errors.
+func setCellError(value CellError) (t string, v string, err error) {
+	if inStrSlice(formulaErrors, string(value)) == -1 {
+		return t, v, fmt.Errorf("unsupported cell error value %s", value)
+	}
+	return "e", string(value), err
+}
+
 // SetCellFloat sets a floating point value into a cell. The prec parameter
 // specifies how many places after the decimal will be shown while -1 is a
 // special value that will use as many decimal places as necessary to
@@ -237,11 +362,14 @@ func (f *File) SetCellFloat(sheet, axis string, value float64, prec, bitSize int
 	if err != nil {
 		return err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
-	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
+	if prec < 0 {
+		value = f.roundSignificantDigits(value, bitSize)
+	}
 	cellData.T, cellData.V = setCellFloat(value, prec, bitSize)
 	return err
 }
@@ -251,28 +379,145 @@ func setCellFloat(value float64, prec, bitSize int) (t string, v string) {
 	return
 }
 
+// roundSignificantDigits provides a function to round the float number to
+// the significant digits of the workbook by given value and bit size.
+func (f *File) roundSignificantDigits(value float64, bitSize int) float64 {
+	if f.digits == 0 {
+		return value
+	}
+	if v, err := strconv.ParseFloat(strconv.FormatFloat(value, 'g', f.digits, bitSize), bitSize); err == nil {
+		value = v
+	}
+	if value == 0 {
+		value = 0 // drop the sign of negative zero
+	}
+	return value
+}
+
 // SetCellStr provides a function to set string type value of a cell. Total
-// number of characters that a cell can contain 32767 characters.
+// number of characters that a cell can contain 32767 characters. The value
+// is always stored as text without type inference, so the value such as the
+// UUID or long digit strings will be read back exactly, and use the style
+// with the text number format "@" (number format ID 49) to keep them as text
+// while editing in Excel.
 func (f *File) SetCellStr(sheet, axis, value string) error {
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
-	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
 	cellData.T, cellData.V, cellData.XMLSpace = setCellStr(value)
 	return err
 }
 
+// setCellBlank provides a function to clear the value, type and formula of a
+// cell by given worksheet name and cell coordinates, the style of the cell
+// will be kept.
+func (f *File) setCellBlank(sheet, axis string) error {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
+	if err != nil {
+		return err
+	}
+	if cellData.F != nil {
+		cell, _ := CoordinatesToCellName(col, row)
+		f.deleteCalcChain(f.GetSheetIndex(sheet), cell)
+	}
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
+	cellData.T, cellData.V, cellData.F, cellData.IS, cellData.XMLSpace = "", "", nil, nil, xml.Attr{}
+	return err
+}
+
+// ClearCell provides a function to remove a cell completely by given
+// worksheet name and cell coordinates, the value, formula and style of the
+// cell will be removed. The value of the merged cell will be removed if the
+// given cell is in the merged range. For example, remove the cell A1 in
+// Sheet1:
+//
+//    err := f.ClearCell("Sheet1", "A1")
+//
+func (f *File) ClearCell(sheet, axis string) error {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if axis, err = f.mergeCellsParser(xlsx, axis); err != nil {
+		return err
+	}
+	col, row, err := CellNameToCoordinates(axis)
+	if err != nil {
+		return err
+	}
+	if row > len(xlsx.SheetData.Row) || col > len(xlsx.SheetData.Row[row-1].C) {
+		return err
+	}
+	cellData := &xlsx.SheetData.Row[row-1].C[col-1]
+	if cellData.F != nil {
+		cell, _ := CoordinatesToCellName(col, row)
+		f.deleteCalcChain(f.GetSheetIndex(sheet), cell)
+	}
+	*cellData = xlsxC{R: cellData.R}
+	return err
+}
+
+// EnsureCells provides a function to create the empty cells in the given
+// range of the worksheet, the existing cells will not be changed. The empty
+// cells are required by some applications to render the conditional formats
+// and data bars over the range consistently. Each row of the range is filled
+// in memory from the column A to the last column of the range, and an error
+// will be returned if the number of these cells exceeds 1048576.
+//
+// Note that the empty cells are only written when saving the File which
+// created them. They will be dropped if the saved spreadsheet is opened and
+// saved again, so call EnsureCells again before that save. For example,
+// create the empty cells in the range A1:A100 on Sheet1:
+//
+//    err := f.EnsureCells("Sheet1", "A1:A100")
+//
+func (f *File) EnsureCells(sheet, rangeRef string) error {
+	cells := strings.Split(rangeRef, ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	if len(cells) != 2 {
+		return fmt.Errorf("invalid area %q", rangeRef)
+	}
+	coordinates, err := areaRangeToCoordinates(cells[0], cells[1])
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	if count := coordinates[2] * (coordinates[3] - coordinates[1] + 1); count > maxEnsureCells {
+		return fmt.Errorf("the number of cells in range %s exceeds the limit %d", rangeRef, maxEnsureCells)
+	}
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		prepareSheetXML(xlsx, coordinates[2], row)
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			xlsx.SheetData.Row[row-1].C[col-1].ensured = true
+		}
+	}
+	return err
+}
+
 func setCellStr(value string) (t string, v string, ns xml.Attr) {
 	if len(value) > 32767 {
 		value = value[0:32767]
 	}
-	// Leading and ending space(s) character detection.
-	if len(value) > 0 && (value[0] == 32 || value[len(value)-1] == 32) {
+	value = bstrMarshal(value)
+	// Leading and ending whitespace character detection, the xml:space
+	// attribute is only required for the significant whitespace.
+	if len(value) > 0 && (isXMLSpace(value[0]) || isXMLSpace(value[len(value)-1])) {
 		ns = xml.Attr{
 			Name:  xml.Name{Space: NameSpaceXML, Local: "space"},
 			Value: "preserve",
@@ -283,6 +528,13 @@ func setCellStr(value string) (t string, v string, ns xml.Attr) {
 	return
 }
 
+// isXMLSpace provides a function to check if the given character is the
+// whitespace character in XML, such as space, tab, carriage return and line
+// feed.
+func isXMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
 // SetCellDefault provides a function to set string type value of a cell as
 // default format without escaping the cell.
 func (f *File) SetCellDefault(sheet, axis, value string) error {
@@ -290,11 +542,11 @@ func (f *File) SetCellDefault(sheet, axis, value string) error {
 	if err != nil {
 		return err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
-	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.S = f.prepareCellStyle(xlsx, col, row, cellData.S)
 	cellData.T, cellData.V = setCellDefault(value)
 	return err
 }
@@ -312,10 +564,36 @@ func (f *File) GetCellFormula(sheet, axis string) (string, error) {
 			return "", false, nil
 		}
 		if c.F.T == STCellFormulaTypeShared {
-			return getSharedForumula(x, c.F.Si), true, nil
+			return getSharedForumula(x, c.F.Si, c.R), true, nil
+		}
+		return c.F.Content, true, nil
+	})
+}
+
+// GetCellFormulaWithResult provides a function to get the formula and the
+// cached result of the formula by given worksheet name and axis. The shared
+// formula will be expanded from the master formula for the given cell. The
+// cached result is the raw value of the cell which was calculated last time,
+// without applying the number format, and it will be empty if the cell has
+// no cached result. For example:
+//
+//    formula, cached, err := f.GetCellFormulaWithResult("Sheet1", "A3")
+//
+func (f *File) GetCellFormulaWithResult(sheet, axis string) (string, string, error) {
+	var cached string
+	formula, err := f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+		if c.F == nil {
+			return "", false, nil
+		}
+		if cached = c.V; c.T == "str" {
+			cached = bstrUnmarshal(c.V)
+		}
+		if c.F.T == STCellFormulaTypeShared {
+			return getSharedForumula(x, c.F.Si, c.R), true, nil
 		}
 		return c.F.Content, true, nil
 	})
+	return formula, cached, err
 }
 
 // FormulaOpts can be passed to SetCellFormula to use other formula types.
@@ -360,6 +638,93 @@ func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts)
 	return err
 }
 
+// SetSharedFormula provides a function to set the shared formula for the
+// cell range by given worksheet name, cell range and the formula of the top
+// left cell of the range. The relative references in the formula will be
+// adjusted for other cells in the range when they are calculated. For
+// example, set the shared formula for the range C1:C10 on Sheet1, the formula
+// of C2 will be "A2+B2":
+//
+//    err := f.SetSharedFormula("Sheet1", "C1:C10", "A1+B1")
+//
+func (f *File) SetSharedFormula(sheet, ref, masterFormula string) error {
+	formula := strings.TrimPrefix(strings.TrimSpace(masterFormula), "=")
+	if formula == "" {
+		return errors.New("the master formula can not be empty")
+	}
+	if _, err := parseFormula(formula); err != nil {
+		return err
+	}
+	cells := strings.Split(ref, ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	if len(cells) != 2 {
+		return fmt.Errorf("invalid area %q", ref)
+	}
+	coordinates, err := areaRangeToCoordinates(cells[0], cells[1])
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	if ref, err = f.coordinatesToAreaRef(coordinates); err != nil {
+		return err
+	}
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		prepareSheetXML(xlsx, coordinates[2], row)
+	}
+	si := 0
+	for rowIdx := range xlsx.SheetData.Row {
+		for colIdx := range xlsx.SheetData.Row[rowIdx].C {
+			c := &xlsx.SheetData.Row[rowIdx].C[colIdx]
+			if c.F == nil || c.F.T != STCellFormulaTypeShared {
+				continue
+			}
+			if idx, err := strconv.Atoi(c.F.Si); err == nil && idx >= si {
+				si = idx + 1
+			}
+			if c.F.Ref != "" && cellInRef([]int{colIdx + 1, rowIdx + 1}, coordinates) {
+				// Convert the members out of the range to the normal formula
+				// before overwriting the master cell of the shared formula.
+				f.unshareFormula(xlsx, c.F.Si, coordinates)
+			}
+		}
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			c := &xlsx.SheetData.Row[row-1].C[col-1]
+			c.T, c.V = "", ""
+			c.F = &xlsxF{T: STCellFormulaTypeShared, Si: strconv.Itoa(si)}
+		}
+	}
+	master := xlsx.SheetData.Row[coordinates[1]-1].C[coordinates[0]-1].F
+	master.Content, master.Ref = formula, ref
+	return err
+}
+
+// unshareFormula provides a function to convert the member cells of the
+// shared formula which are out of the given area to the normal formula by
+// given shared formula index.
+func (f *File) unshareFormula(xlsx *xlsxWorksheet, si string, area []int) {
+	for rowIdx := range xlsx.SheetData.Row {
+		for colIdx := range xlsx.SheetData.Row[rowIdx].C {
+			c := &xlsx.SheetData.Row[rowIdx].C[colIdx]
+			if c.F == nil || c.F.T != STCellFormulaTypeShared || c.F.Ref != "" || c.F.Si != si {
+				continue
+			}
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil || cellInRef([]int{col, row}, area) {
+				continue
+			}
+			c.F = &xlsxF{Content: getSharedForumula(xlsx, si, c.R)}
+		}
+	}
+}
+
 // GetCellHyperLink provides a function to get cell hyperlink by given
 // worksheet name and axis. Boolean type value link will be ture if the cell
 // has a hyperlink and the target is the address of the hyperlink. Otherwise,
@@ -490,6 +855,118 @@ func (f *File) SetSheetRow(sheet, axis string, slice interface{}) error {
 	return err
 }
 
+// CopyRange provides a function to copy the cell values, formulas, styles
+// and merged cells of the cell range to another location in the same
+// worksheet by given worksheet name, source cell range and the top left cell
+// of the destination. The existing content in the destination will be
+// overwritten, and the relative references in the formulas will be adjusted
+// for the new position. For example, copy the range A1:C3 to E5 in Sheet1:
+//
+//    err := f.CopyRange("Sheet1", "A1:C3", "E5")
+//
+func (f *File) CopyRange(sheet, srcRange, destTopLeft string) error {
+	return f.CopyRangeToSheet(sheet, srcRange, sheet, destTopLeft)
+}
+
+// CopyRangeToSheet provides a function to copy the cell values, formulas,
+// styles and merged cells of the cell range to another worksheet by given
+// source worksheet name, source cell range, destination worksheet name and
+// the top left cell of the destination. For example, copy the range A1:C3 in
+// Sheet1 to E5 in Sheet2:
+//
+//    err := f.CopyRangeToSheet("Sheet1", "A1:C3", "Sheet2", "E5")
+//
+func (f *File) CopyRangeToSheet(srcSheet, srcRange, destSheet, destTopLeft string) error {
+	cells := strings.Split(srcRange, ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	if len(cells) != 2 {
+		return fmt.Errorf("invalid area %q", srcRange)
+	}
+	coordinates, err := areaRangeToCoordinates(cells[0], cells[1])
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	destCol, destRow, err := CellNameToCoordinates(destTopLeft)
+	if err != nil {
+		return err
+	}
+	cols, rows := coordinates[2]-coordinates[0]+1, coordinates[3]-coordinates[1]+1
+	if destCol+cols-1 > TotalColumns || destRow+rows-1 > TotalRows {
+		return errors.New("the destination range exceeds the worksheet limits")
+	}
+	src, err := f.workSheetReader(srcSheet)
+	if err != nil {
+		return err
+	}
+	dest, err := f.workSheetReader(destSheet)
+	if err != nil {
+		return err
+	}
+	colOffset, rowOffset := destCol-coordinates[0], destRow-coordinates[1]
+	// Copy the source cells and merged cells into the buffer first, which
+	// allows the source and destination areas overlap.
+	buffer := make([][]xlsxC, rows)
+	for r := range buffer {
+		buffer[r] = make([]xlsxC, cols)
+		row := coordinates[1] + r
+		for c := range buffer[r] {
+			if row <= len(src.SheetData.Row) && coordinates[0]+c <= len(src.SheetData.Row[row-1].C) {
+				buffer[r][c] = deepcopy.Copy(src.SheetData.Row[row-1].C[coordinates[0]+c-1]).(xlsxC)
+				f.copyCellFormula(src, &buffer[r][c], colOffset, rowOffset)
+			}
+			buffer[r][c].R, _ = CoordinatesToCellName(destCol+c, destRow+r)
+		}
+	}
+	var mergeCells []*xlsxMergeCell
+	if src.MergeCells != nil {
+		for _, mergeCell := range src.MergeCells.Cells {
+			rect, err := f.areaRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(rect)
+			if rect[0] >= coordinates[0] && rect[2] <= coordinates[2] && rect[1] >= coordinates[1] && rect[3] <= coordinates[3] {
+				ref, _ := f.coordinatesToAreaRef([]int{rect[0] + colOffset, rect[1] + rowOffset, rect[2] + colOffset, rect[3] + rowOffset})
+				mergeCells = append(mergeCells, &xlsxMergeCell{Ref: ref})
+			}
+		}
+	}
+	for r := range buffer {
+		prepareSheetXML(dest, destCol+cols-1, destRow+r)
+		for c := range buffer[r] {
+			dest.SheetData.Row[destRow+r-1].C[destCol+c-1] = buffer[r][c]
+		}
+	}
+	if dest.MergeCells != nil {
+		// Delete the merged cells which overlap the destination area.
+		for i := 0; i < len(dest.MergeCells.Cells); i++ {
+			rect, err := f.areaRefToCoordinates(dest.MergeCells.Cells[i].Ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(rect)
+			if rect[0] <= destCol+cols-1 && destCol <= rect[2] && rect[1] <= destRow+rows-1 && destRow <= rect[3] {
+				f.deleteMergeCell(dest, i)
+				i--
+			}
+		}
+	}
+	if len(mergeCells) > 0 {
+		if dest.MergeCells == nil {
+			dest.MergeCells = &xlsxMergeCells{}
+		}
+		dest.MergeCells.Cells = append(dest.MergeCells.Cells, mergeCells...)
+		dest.MergeCells.Count = len(dest.MergeCells.Cells)
+	}
+	if dest.MergeCells != nil && len(dest.MergeCells.Cells) == 0 {
+		dest.MergeCells = nil
+	}
+	return nil
+}
+
 // getCellInfo does common preparation for all SetCell* methods.
 func (f *File) prepareCell(xlsx *xlsxWorksheet, sheet, cell string) (*xlsxC, int, int, error) {
 	var err error
@@ -563,16 +1040,88 @@ func (f *File) formattedValue(s int, v string) string {
 		return v
 	}
 	styleSheet := f.stylesReader()
-	ok := builtInNumFmtFunc[styleSheet.CellXfs.Xf[s].NumFmtID]
+	numFmtID := styleSheet.CellXfs.Xf[s].NumFmtID
+	date1904 := f.isDate1904()
+	ok := builtInNumFmtFunc[numFmtID]
 	if ok != nil {
-		return ok(styleSheet.CellXfs.Xf[s].NumFmtID, v)
+		return ok(numFmtID, v, date1904)
+	}
+	if styleSheet.NumFmts == nil {
+		return v
+	}
+	for _, numFmt := range styleSheet.NumFmts.NumFmt {
+		if numFmt.NumFmtID != numFmtID {
+			continue
+		}
+		if val, err := strconv.ParseFloat(v, 64); err == nil {
+			return applyNumFmtCode(val, numFmt.FormatCode, date1904)
+		}
+		return formatNumFmtText(numFmt.FormatCode, v)
 	}
 	return v
 }
 
+// formattedText provides a function to returns the value of the text cell
+// after formatted. The numeric and date number formats will not be applied to
+// the text, even if the text looks like a number, such as UUID or long digit
+// strings, only the text section of the custom number format will be applied.
+func (f *File) formattedText(s int, v string) string {
+	if s == 0 {
+		return v
+	}
+	styleSheet := f.stylesReader()
+	if styleSheet.CellXfs == nil || s >= len(styleSheet.CellXfs.Xf) || styleSheet.NumFmts == nil {
+		return v
+	}
+	numFmtID := styleSheet.CellXfs.Xf[s].NumFmtID
+	for _, numFmt := range styleSheet.NumFmts.NumFmt {
+		if numFmt.NumFmtID == numFmtID {
+			return formatNumFmtText(numFmt.FormatCode, v)
+		}
+	}
+	return v
+}
+
+// formatNumFmtText provides a function to apply the text section of the
+// number format code to the text value, the text will be returned as is if
+// the number format code doesn't have the text section.
+func formatNumFmtText(code, v string) string {
+	if sections := splitNumFmtCode(code); len(sections) > 3 {
+		return strings.Replace(formatNumFmtLiteral(numFmtColorRe.ReplaceAllString(sections[3], "")), "@", v, -1)
+	}
+	return v
+}
+
+// formattedValueColor provides a function to get the color of the number
+// format section which applied to the cell value by given style index and
+// cell value.
+func (f *File) formattedValueColor(s int, v string) string {
+	val, err := strconv.ParseFloat(v, 64)
+	if s == 0 || err != nil {
+		return ""
+	}
+	styleSheet := f.stylesReader()
+	numFmtID := styleSheet.CellXfs.Xf[s].NumFmtID
+	if code, ok := builtInNumFmt[numFmtID]; ok {
+		return getNumFmtColor(val, code)
+	}
+	if styleSheet.NumFmts != nil {
+		for _, numFmt := range styleSheet.NumFmts.NumFmt {
+			if numFmt.NumFmtID == numFmtID {
+				return getNumFmtColor(val, numFmt.FormatCode)
+			}
+		}
+	}
+	return ""
+}
+
 // prepareCellStyle provides a function to prepare style index of cell in
-// worksheet by given column index and style index.
-func (f *File) prepareCellStyle(xlsx *xlsxWorksheet, col, style int) int {
+// worksheet by given column index, row number and style index. The style of
+// the row with custom formatting takes precedence over the column style.
+func (f *File) prepareCellStyle(xlsx *xlsxWorksheet, col, row, style int) int {
+	if style == 0 && row > 0 && row <= len(xlsx.SheetData.Row) && xlsx.SheetData.Row[row-1].CustomFormat {
+		return xlsx.SheetData.Row[row-1].S
+	}
 	if xlsx.Cols != nil && style == 0 {
 		for _, c := range xlsx.Cols.Col {
 			if c.Min <= col && col <= c.Max {
@@ -645,13 +1194,22 @@ func isOverlap(rect1, rect2 []int) bool {
 // considered to be the same when their respective representations in
 // R1C1-reference notation, are the same.
 //
-// Note that this function not validate ref tag to check the cell if or not in
-// allow area, and always return origin shared formula.
-func getSharedForumula(xlsx *xlsxWorksheet, si string) string {
+// The relative references in the formula of the master cell will be
+// translated to the position of the given cell, and the absolute references
+// will be kept.
+func getSharedForumula(xlsx *xlsxWorksheet, si, axis string) string {
 	for _, r := range xlsx.SheetData.Row {
 		for _, c := range r.C {
 			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si == si {
-				return c.F.Content
+				col, row, err := CellNameToCoordinates(axis)
+				if err != nil {
+					return c.F.Content
+				}
+				masterCol, masterRow, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					return c.F.Content
+				}
+				return shiftFormula(c.F.Content, col-masterCol, row-masterRow)
 			}
 		}
 	}
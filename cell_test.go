@@ -3,6 +3,7 @@ package excelize
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -83,17 +84,339 @@ func TestSetCellFloat(t *testing.T) {
 	assert.EqualError(t, f.SetCellFloat(sheet, "A", 123.42, -1, 64), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
+func TestSignificantDigits(t *testing.T) {
+	f, a, b := NewFile(), 0.1, 0.2
+	// Test the shortest representation of the float numbers by default.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", a+b))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 1e15))
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.30000000000000004", val)
+	val, err = f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1000000000000000", val)
+
+	assert.Equal(t, f, f.SignificantDigits(15))
+	for value, expected := range map[float64]string{
+		a + b:                     "0.3",
+		1e15:                      "1000000000000000",
+		1e16:                      "10000000000000000",
+		123456789012345:           "123456789012345",
+		1234567890123456:          "1234567890123460",
+		99999999999999.99:         "100000000000000",
+		999999999999999.9:         "1000000000000000",
+		1234567890123456789:       "1234567890123460000",
+		0.00000123456789012345678: "0.00000123456789012346",
+		-a - b:                    "-0.3",
+		-a * 1e-320 * 1e-10:       "0",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", "B1", value))
+		val, err = f.GetCellValue("Sheet1", "B1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val, value)
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", float32(1.0/3)))
+	val, err = f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.33333334", val)
+	// Test the precision of SetCellFloat will not be affected.
+	assert.NoError(t, f.SetCellFloat("Sheet1", "B3", 1234567890123456, 2, 64))
+	val, err = f.GetCellValue("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567890123456.00", val)
+
+	// Test set the significant digits in the stream writer.
+	sw, err := f.SignificantDigits(3).NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{float32(1.0 / 3), 2.0 / 3, 12345.0}))
+	assert.NoError(t, sw.Flush())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0.333", "0.667", "12300"}}, rows)
+
+	// Test set the significant digits out of range.
+	f.SignificantDigits(18)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", a+b))
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.30000000000000004", val)
+}
+
 func TestSetCellValue(t *testing.T) {
 	f := NewFile()
 	assert.EqualError(t, f.SetCellValue("Sheet1", "A", time.Now().UTC()), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 	assert.EqualError(t, f.SetCellValue("Sheet1", "A", time.Duration(1e13)), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
+func TestSetCellValues(t *testing.T) {
+	f := NewFile()
+	values := map[string]interface{}{
+		"A1": 1, "B1": uint8(2), "C1": float32(1.325), "D1": 0.1 + 0.2, "E1": " text ",
+		"A2": []byte("bytes"), "B2": true, "C2": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		"D2": time.Hour * 6, "E2": nil, "F2": struct{}{},
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E2", "A1+B1"))
+	assert.NoError(t, f.SetCellValues("Sheet1", values))
+	// Test set the same values by SetCellValue and compare the results.
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellFormula("Sheet2", "E2", "A1+B1"))
+	for axis, value := range values {
+		assert.NoError(t, f.SetCellValue("Sheet2", axis, value))
+	}
+	for axis := range values {
+		expected, err := f.GetCellValue("Sheet2", axis)
+		assert.NoError(t, err)
+		result, err := f.GetCellValue("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, axis)
+	}
+	for _, axis := range []string{"A1", "C1", "D1", "E1", "E2"} {
+		result, err := f.GetCellValue("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A1": "1", "C1": "1.325", "D1": "0.3", "E1": " text ", "E2": ""}[axis], result, axis)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "E2")
+	assert.NoError(t, err)
+	assert.Equal(t, "", formula)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCellValues.xlsx")))
+
+	// Test set cell values with invalid cell coordinates.
+	assert.EqualError(t, f.SetCellValues("Sheet1", map[string]interface{}{"A": 1}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test set cell values on not exists worksheet.
+	assert.EqualError(t, f.SetCellValues("SheetN", map[string]interface{}{"A1": 1}), "sheet SheetN is not exist")
+}
+
+func TestClearCell(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(`{"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "A1+1"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", style))
+	f.CalcChain = &xlsxCalcChain{C: []xlsxCalcChainC{{R: "B1", I: 1}, {R: "C1", I: 1}}}
+	// Test set nil to clear the value and formula of the cells but keep the style.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", nil))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", nil))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, xlsxC{R: "A1", S: style}, ws.SheetData.Row[0].C[0])
+	assert.Equal(t, xlsxC{R: "B1", S: style}, ws.SheetData.Row[0].C[1])
+	assert.Equal(t, []xlsxCalcChainC{{R: "C1", I: 1}}, f.CalcChain.C)
+	// Test remove the cells completely.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "A1+2"))
+	assert.NoError(t, f.ClearCell("Sheet1", "A1"))
+	assert.NoError(t, f.ClearCell("Sheet1", "C1"))
+	assert.Equal(t, xlsxC{R: "A1"}, ws.SheetData.Row[0].C[0])
+	assert.Equal(t, xlsxC{R: "C1"}, ws.SheetData.Row[0].C[2])
+	assert.Nil(t, f.CalcChain)
+	assert.Equal(t, []xlsxC{{R: "B1", S: style}}, trimCell(ws.SheetData.Row[0].C))
+	// Test clear the cell in the merged range.
+	assert.NoError(t, f.SetCellValue("Sheet1", "D2", "Merged"))
+	assert.NoError(t, f.MergeCell("Sheet1", "D2", "E3"))
+	assert.NoError(t, f.ClearCell("Sheet1", "E3"))
+	val, err := f.GetCellValue("Sheet1", "D2")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	// Test clear the cell outside the worksheet data.
+	rows := len(ws.SheetData.Row)
+	assert.NoError(t, f.ClearCell("Sheet1", "Z100"))
+	assert.Len(t, ws.SheetData.Row, rows)
+	// Test clear the cell with invalid cell coordinates.
+	assert.EqualError(t, f.ClearCell("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A", nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test clear the cell on not exists worksheet.
+	assert.EqualError(t, f.ClearCell("SheetN", "A1"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.SetCellValue("SheetN", "A1", nil), "sheet SheetN is not exist")
+}
+
+func TestEnsureCells(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D1", 2))
+	assert.NoError(t, f.EnsureCells("Sheet1", "C3:B1"))
+	assert.NoError(t, f.EnsureCells("Sheet1", "A5"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	sheetXML := string(f.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, `<row r="1"><c r="B1"></c><c r="C1"></c><c r="D1"><v>2</v></c></row>`)
+	assert.Contains(t, sheetXML, `<row r="2"><c r="B2"><v>1</v></c><c r="C2"></c></row>`)
+	assert.Contains(t, sheetXML, `<row r="3"><c r="B3"></c><c r="C3"></c></row>`)
+	assert.Contains(t, sheetXML, `<row r="5"><c r="A5"></c></row>`)
+	// Test ensure cells with invalid range.
+	assert.EqualError(t, f.EnsureCells("Sheet1", "A1:B2:C3"), `invalid area "A1:B2:C3"`)
+	assert.EqualError(t, f.EnsureCells("Sheet1", "A:B2"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.EnsureCells("Sheet1", "A1:B1048576"), "the number of cells in range A1:B1048576 exceeds the limit 1048576")
+	// Test the cells on the left of the range are counted in the limit.
+	assert.EqualError(t, f.EnsureCells("Sheet1", "XFD1:XFD100"), "the number of cells in range XFD1:XFD100 exceeds the limit 1048576")
+	assert.NoError(t, f.EnsureCells("Sheet1", "XFD1:XFD64"))
+	assert.EqualError(t, f.EnsureCells("SheetN", "A1"), "sheet SheetN is not exist")
+}
+
+func TestSetCellStr(t *testing.T) {
+	for value, expected := range map[string]string{
+		"Hello":       "",
+		"Hello World": "",
+		" Hello":      "preserve",
+		"Hello ":      "preserve",
+		"\tHello":     "preserve",
+		"Hello\n":     "preserve",
+		"":            "",
+	} {
+		_, v, ns := setCellStr(value)
+		assert.Equal(t, value, v)
+		assert.Equal(t, expected, ns.Value, value)
+	}
+	f := NewFile()
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", "Hello"))
+	assert.NoError(t, f.SetCellStr("Sheet1", "A2", " Hello "))
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, xlsx.SheetData.Row[0].C[0].XMLSpace.Value)
+	assert.Equal(t, "preserve", xlsx.SheetData.Row[1].C[0].XMLSpace.Value)
+
+	// Test set and get cell value with the control characters and illegal
+	// XML runes.
+	for cell, value := range map[string][]string{
+		"B1": {"a\x00b", "a\x00b"},
+		"B2": {"a\x0bb", "a\x0bb"},
+		"B3": {"a\xed\xa0\x80b", "ab"},
+		"B4": {"_x000D_", "_x000D_"},
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value[0]))
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, value[1], val)
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "a\x0bb", val)
+}
+
+func TestSetCellTextValue(t *testing.T) {
+	f := NewFile()
+	values := []string{
+		"550e8400-e29b-41d4-a716-446655440000",
+		"{550E8400-E29B-41D4-A716-446655440000}",
+		"12345678901234567890",
+		"1E5",
+		"2020-01-01",
+		"00123",
+		"44000",
+	}
+	var styles []int
+	for _, style := range []string{
+		`{"number_format":49}`,
+		`{"number_format":14}`,
+		`{"number_format":11}`,
+		`{"custom_number_format":"0.00"}`,
+		`{"custom_number_format":"yyyy-mm-dd"}`,
+	} {
+		styleID, err := f.NewStyle(style)
+		assert.NoError(t, err)
+		styles = append(styles, styleID)
+	}
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for r, val := range values {
+		row := []interface{}{val}
+		for _, styleID := range styles {
+			row = append(row, Cell{StyleID: styleID, Value: val})
+		}
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", r+1), row))
+	}
+	assert.NoError(t, sw.Flush())
+	f.NewSheet("Sheet2")
+	for r, val := range values {
+		for c := 0; c <= len(styles); c++ {
+			cell, err := CoordinatesToCellName(c+1, r+1)
+			assert.NoError(t, err)
+			assert.NoError(t, f.SetCellValue("Sheet2", cell, val))
+			if c > 0 {
+				assert.NoError(t, f.SetCellStyle("Sheet2", cell, cell, styles[c-1]))
+			}
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for _, sheet := range []string{"Sheet1", "Sheet2"} {
+		rows, err := f.GetRows(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, rows, len(values))
+		for r, row := range rows {
+			assert.Len(t, row, len(styles)+1)
+			for _, val := range row {
+				assert.Equal(t, values[r], val)
+			}
+		}
+	}
+	// Test get the text value with the text section of the number format.
+	styleID, err := f.NewStyle(`{"custom_number_format":"0;-0;0;\"ID: \"@"}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet2", "A1", "A1", styleID))
+	val, err := f.GetCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: "+values[0], val)
+}
+
+func TestGetCellValueInlineString(t *testing.T) {
+	f := NewFile()
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="1"><c r="A1" t="inlineStr"><is><t>Inline</t></is></c>` +
+		`<c r="B1" t="inlineStr"><is><r><rPr><b/></rPr><t>Rich </t></r><r><t xml:space="preserve">inline </t></r><r><t>text</t></r></is></c>` +
+		`<c r="C1" t="inlineStr"><is><t>Prefix </t><r><t>run</t></r></is></c>` +
+		`<c r="D1" t="inlineStr"><is><t>漢字</t><rPh sb="0" eb="2"><t>カンジ</t></rPh></is></c>` +
+		`<c r="E1" t="inlineStr"><is><t>Line_x000D_</t></is></c>` +
+		`<c r="F1" t="inlineStr"><v>Value</v></c></row></sheetData></worksheet>`)
+	expected := []string{"Inline", "Rich inline text", "Prefix run", "漢字", "Line\r", "Value"}
+	for i, exp := range expected {
+		cell, err := CoordinatesToCellName(i+1, 1)
+		assert.NoError(t, err)
+		val, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, val)
+	}
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{expected}, rows)
+	val, err := f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Rich inline text", val)
+}
+
 func TestSetCellBool(t *testing.T) {
 	f := NewFile()
 	assert.EqualError(t, f.SetCellBool("Sheet1", "A", true), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
+func TestSetCellError(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", CellError("#N/A")))
+	assert.NoError(t, f.SetCellValues("Sheet1", map[string]interface{}{"B1": CellError("#DIV/0!")}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "e", ws.SheetData.Row[0].C[0].T)
+	assert.Equal(t, "#N/A", ws.SheetData.Row[0].C[0].V)
+	assert.Equal(t, "e", ws.SheetData.Row[0].C[1].T)
+	val, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "#DIV/0!", val)
+	// Test set cell with unsupported error value.
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A2", CellError("#ERR")), "unsupported cell error value #ERR")
+	assert.EqualError(t, f.SetCellValues("Sheet1", map[string]interface{}{"A2": CellError("N/A")}), "unsupported cell error value N/A")
+	assert.EqualError(t, f.SetCellValue("Sheet1", "A", CellError("#N/A")), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellValue("SheetN", "A1", CellError("#N/A")), "sheet SheetN is not exist")
+	assert.Len(t, ws.SheetData.Row, 1)
+}
+
 func TestGetCellFormula(t *testing.T) {
 	// Test get cell formula on not exist worksheet.
 	f := NewFile()
@@ -104,6 +427,111 @@ func TestGetCellFormula(t *testing.T) {
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", true))
 	_, err = f.GetCellFormula("Sheet1", "A1")
 	assert.NoError(t, err)
+
+	// Test get cell formula on the shared formula cells.
+	f = NewFile()
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c><c r="C1"><f t="shared" ref="C1:C3" si="0">A1*$B$1+SUM(A$1:A1)</f><v>3</v></c></row>` +
+		`<row r="2"><c r="A2"><v>3</v></c><c r="B2"><v>4</v></c><c r="C2"><f t="shared" si="0"/><v>10</v></c><c r="D2"><f t="shared" ref="D2:E2" si="1">C2+Sheet1!A1</f></c><c r="E2"><f t="shared" si="1"/></c></row>` +
+		`<row r="3"><c r="A3"><v>5</v></c><c r="B3"><v>6</v></c><c r="C3"><f t="shared" si="0"/><v>19</v></c><c r="F3"><f t="shared" si="2"/></c></row>` +
+		`</sheetData></worksheet>`)
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	for axis, expected := range map[string]string{
+		"C1": "A1*$B$1+SUM(A$1:A1)",
+		"C2": "A2*$B$1+SUM(A$1:A2)",
+		"C3": "A3*$B$1+SUM(A$1:A3)",
+		"D2": "C2+Sheet1!A1",
+		"E2": "D2+Sheet1!B1",
+		"F3": "",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, axis)
+	}
+	assert.NoError(t, f.CalcWorkbook())
+	val, err := f.GetCellValue("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "19", val)
+}
+
+func TestGetCellFormulaWithResult(t *testing.T) {
+	f := NewFile()
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c><c r="C1"><f t="shared" ref="C1:C2" si="0">A1+B1</f><v>3</v></c><c r="D1" t="str"><f>"a"&amp;"b"</f><v>ab</v></c></row>` +
+		`<row r="2"><c r="A2"><v>3</v></c><c r="B2"><v>4</v></c><c r="C2"><f t="shared" si="0"/><v>7</v></c><c r="D2"><f>A2/0</f></c></row>` +
+		`</sheetData></worksheet>`)
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	for axis, expected := range map[string][2]string{
+		"A1": {"", ""},
+		"C1": {"A1+B1", "3"},
+		"C2": {"A2+B2", "7"},
+		"D1": {`"a"&"b"`, "ab"},
+		"D2": {"A2/0", ""},
+		"E5": {"", ""},
+	} {
+		formula, cached, err := f.GetCellFormulaWithResult("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, [2]string{formula, cached}, axis)
+	}
+	// Test get cell formula with result on not exist worksheet.
+	_, _, err := f.GetCellFormulaWithResult("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestSetSharedFormula(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 3; row++ {
+		assert.NoError(t, f.SetCellValue("Sheet1", "A"+strconv.Itoa(row), row))
+		assert.NoError(t, f.SetCellValue("Sheet1", "B"+strconv.Itoa(row), row*10))
+	}
+	assert.NoError(t, f.SetSharedFormula("Sheet1", "C3:C1", "=A1+B1"))
+	for axis, expected := range map[string]string{
+		"C1": "A1+B1",
+		"C2": "A2+B2",
+		"C3": "A3+B3",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, axis)
+	}
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxF{T: STCellFormulaTypeShared, Ref: "C1:C3", Si: "0", Content: "A1+B1"}, xlsx.SheetData.Row[0].C[2].F)
+	assert.Equal(t, &xlsxF{T: STCellFormulaTypeShared, Si: "0"}, xlsx.SheetData.Row[1].C[2].F)
+	assert.NoError(t, f.CalcWorkbook())
+	val, err := f.GetCellValue("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "33", val)
+
+	// Test set shared formula with a new shared index, and overwrite the
+	// master cell of the existing shared formula.
+	assert.NoError(t, f.SetSharedFormula("Sheet1", "C1:D1", "A1*2"))
+	assert.Equal(t, "1", xlsx.SheetData.Row[0].C[2].F.Si)
+	for axis, expected := range map[string]string{
+		"C1": "A1*2",
+		"D1": "B1*2",
+		"C2": "A2+B2",
+		"C3": "A3+B3",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, axis)
+	}
+	assert.Equal(t, &xlsxF{Content: "A2+B2"}, xlsx.SheetData.Row[1].C[2].F)
+
+	// Test set shared formula on a single cell.
+	assert.NoError(t, f.SetSharedFormula("Sheet1", "E1", "SUM(A1:B1)"))
+	formula, err := f.GetCellFormula("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(A1:B1)", formula)
+
+	// Test set shared formula with invalid arguments.
+	assert.EqualError(t, f.SetSharedFormula("Sheet1", "C1:C3", ""), "the master formula can not be empty")
+	assert.EqualError(t, f.SetSharedFormula("Sheet1", "C1:C3", "SUM(A1"), "formula not valid: function SUM not closed")
+	assert.EqualError(t, f.SetSharedFormula("Sheet1", "C1:C3", `"A1`), `formula string not terminated: "A1`)
+	assert.EqualError(t, f.SetSharedFormula("Sheet1", "C1:C2:C3", "A1"), `invalid area "C1:C2:C3"`)
+	assert.EqualError(t, f.SetSharedFormula("Sheet1", "C:C3", "A1"), `cannot convert cell "C" to coordinates: invalid cell name "C"`)
+	assert.EqualError(t, f.SetSharedFormula("SheetN", "C1:C3", "A1"), "sheet SheetN is not exist")
 }
 
 func ExampleFile_SetCellFloat() {
@@ -141,3 +569,91 @@ func TestOverflowNumericCell(t *testing.T) {
 	// GOARCH=amd64 - all ok; GOARCH=386 - actual: "-2147483648"
 	assert.Equal(t, "8595602512225", val, "A1 should be 8595602512225")
 }
+
+func TestCopyRange(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "text"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "A1+$B$1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 2))
+	assert.NoError(t, f.MergeCell("Sheet1", "A2", "B2"))
+	style, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "E5", "F6"))
+
+	assert.NoError(t, f.CopyRange("Sheet1", "A1:C2", "E5"))
+	val, err := f.GetCellValue("Sheet1", "F5")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", val)
+	formula, err := f.GetCellFormula("Sheet1", "G5")
+	assert.NoError(t, err)
+	assert.Equal(t, "E5+$B$1", formula)
+	styleID, err := f.GetCellStyle("Sheet1", "F5")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, mergeCells, 2) {
+		assert.Equal(t, "A2", mergeCells[0].GetStartAxis())
+		assert.Equal(t, "E6", mergeCells[1].GetStartAxis())
+		assert.Equal(t, "F6", mergeCells[1].GetEndAxis())
+	}
+
+	// Test copy the overlapping range.
+	assert.NoError(t, f.CopyRange("Sheet1", "A1:B2", "B2"))
+	val, err = f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+	val, err = f.GetCellValue("Sheet1", "C2")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", val)
+	val, err = f.GetCellValue("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", val)
+
+	// Test copy range to another worksheet.
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.CopyRangeToSheet("Sheet1", "C1", "Sheet2", "A10"))
+	formula, err = f.GetCellFormula("Sheet2", "A10")
+	assert.NoError(t, err)
+	assert.Equal(t, "#REF!+$B$1", formula)
+
+	// Test copy range with invalid parameters.
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1:B2:C3", "E5"), `invalid area "A1:B2:C3"`)
+	assert.EqualError(t, f.CopyRange("Sheet1", "A:B", "E5"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1:B2", "E"), `cannot convert cell "E" to coordinates: invalid cell name "E"`)
+	assert.EqualError(t, f.CopyRange("Sheet1", "A1:B2", "XFD1"), "the destination range exceeds the worksheet limits")
+	assert.EqualError(t, f.CopyRange("SheetN", "A1:B2", "E5"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.CopyRangeToSheet("Sheet1", "A1:B2", "SheetN", "E5"), "sheet SheetN is not exist")
+}
+
+func TestGetCellValueWithColor(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(`{"custom_number_format":"#,##0.00;[Red]-#,##0.00;[Color10]0;[Blue]\"Text: \"@"}`)
+	assert.NoError(t, err)
+	builtInStyle, err := f.NewStyle(`{"number_format":40}`)
+	assert.NoError(t, err)
+	for _, c := range []struct {
+		axis, value, color string
+		cellValue          interface{}
+		style              int
+	}{
+		{"A1", "1,234.50", "", 1234.5, style},
+		{"A2", "-1,234.50", "#FF0000", -1234.5, style},
+		{"A3", "0", "#008000", 0, style},
+		{"A4", "Text: abc", "", "abc", style},
+		{"A5", "(1.50)", "#FF0000", -1.5, builtInStyle},
+		{"A6", "1.50", "", 1.5, builtInStyle},
+		{"A7", "1.5", "", 1.5, 0},
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", c.axis, c.cellValue))
+		assert.NoError(t, f.SetCellStyle("Sheet1", c.axis, c.axis, c.style))
+		value, color, err := f.GetCellValueWithColor("Sheet1", c.axis)
+		assert.NoError(t, err)
+		assert.Equal(t, c.value, value, c.axis)
+		assert.Equal(t, c.color, color, c.axis)
+	}
+	_, _, err = f.GetCellValueWithColor("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
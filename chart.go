@@ -68,6 +68,7 @@ const (
 	BarOfPieChart               = "barOfPie"
 	Radar                       = "radar"
 	Scatter                     = "scatter"
+	Stock                       = "stock"
 	Surface3D                   = "surface3D"
 	WireframeSurface3D          = "wireframeSurface3D"
 	Contour                     = "contour"
@@ -127,6 +128,7 @@ var (
 		BarOfPieChart:               0,
 		Radar:                       0,
 		Scatter:                     0,
+		Stock:                       0,
 		Surface3D:                   15,
 		WireframeSurface3D:          15,
 		Contour:                     90,
@@ -181,6 +183,7 @@ var (
 		BarOfPieChart:               0,
 		Radar:                       0,
 		Scatter:                     0,
+		Stock:                       0,
 		Surface3D:                   20,
 		WireframeSurface3D:          20,
 		Contour:                     0,
@@ -245,6 +248,7 @@ var (
 		BarOfPieChart:               0,
 		Radar:                       0,
 		Scatter:                     0,
+		Stock:                       0,
 		Surface3D:                   0,
 		WireframeSurface3D:          0,
 		Contour:                     0,
@@ -252,6 +256,7 @@ var (
 		Bubble3D:                    0,
 	}
 	chartLegendPosition = map[string]string{
+		"none":      "",
 		"bottom":    "b",
 		"left":      "l",
 		"right":     "r",
@@ -307,6 +312,7 @@ var (
 		BarOfPieChart:               "General",
 		Radar:                       "General",
 		Scatter:                     "General",
+		Stock:                       "General",
 		Surface3D:                   "General",
 		WireframeSurface3D:          "General",
 		Contour:                     "General",
@@ -363,6 +369,7 @@ var (
 		BarOfPieChart:               "between",
 		Radar:                       "between",
 		Scatter:                     "between",
+		Stock:                       "between",
 		Surface3D:                   "midCat",
 		WireframeSurface3D:          "midCat",
 		Contour:                     "midCat",
@@ -466,6 +473,45 @@ var (
 		Contour:          "none",
 		WireframeContour: "none",
 	}
+	chartDataLabelsPosition = map[string]string{
+		"best_fit":    "bestFit",
+		"below":       "b",
+		"center":      "ctr",
+		"inside_base": "inBase",
+		"inside_end":  "inEnd",
+		"left":        "l",
+		"outside_end": "outEnd",
+		"right":       "r",
+		"above":       "t",
+	}
+	chartTrendlineTypes = map[string]string{
+		"exponential":    "exp",
+		"linear":         "linear",
+		"log":            "log",
+		"moving_average": "movingAvg",
+		"polynomial":     "poly",
+		"power":          "power",
+	}
+	chartTrendlineSupported = map[string]bool{
+		Area: true, Bar: true, Col: true, Line: true, Stock: true, Scatter: true, Bubble: true,
+	}
+	chartErrorBarsTypes = map[string]string{
+		"both":  "both",
+		"minus": "minus",
+		"plus":  "plus",
+	}
+	chartErrorBarsValueTypes = map[string]string{
+		"fixed":              "fixedVal",
+		"percentage":         "percentage",
+		"standard_deviation": "stdDev",
+		"standard_error":     "stdErr",
+	}
+	chartErrorBarsSupported = map[string]bool{
+		Area: true, AreaStacked: true, AreaPercentStacked: true,
+		Bar: true, BarStacked: true, BarPercentStacked: true,
+		Col: true, ColStacked: true, ColPercentStacked: true,
+		Line: true, Scatter: true, Bubble: true,
+	}
 )
 
 // parseFormatChartSet provides a function to parse the format settings of the
@@ -583,6 +629,7 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //     barOfPie                    | bar of pie chart
 //     radar                       | radar chart
 //     scatter                     | scatter chart
+//     stock                       | stock chart
 //     surface3D                   | 3D surface chart
 //     wireframeSurface3D          | 3D wireframe surface chart
 //     contour                     | contour chart
@@ -595,8 +642,13 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 // The series options that can be set are:
 //
 //    name
+//    type
 //    categories
 //    values
+//    secondary_axis
+//    data_labels
+//    trendline
+//    error_bars
 //    line
 //
 // name: Set the name for the series. The name is displayed in the chart legend and in the formula bar. The name property is optional and if it isn't supplied it will default to Series 1..n. The name can also be a formula such as Sheet1!$A$1
@@ -605,29 +657,146 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // values: This is the most important property of a series and is the only mandatory option for every chart object. This option links the chart with the worksheet data that it displays.
 //
+// The stock chart requires 3 series in the order of high, low and close
+// values, or 4 series in the order of open, high, low and close values. The
+// high-low lines will be drawn between the high and low values, and the
+// up-down bars will be drawn between the open and close values for the 4
+// series stock chart.
+//
+// type: Specifies the chart type of the series. The series which set a
+// different chart type with the chart will be plotted as a combo chart. The
+// type property is optional, and the series use the type of the chart by
+// default.
+//
+// secondary_axis: Specifies that the series shall be plotted on the secondary
+// value axis, which will be displayed on the right side of the plot area. The
+// secondary_axis property is optional. The default value is false. The series
+// of the same chart type can't be plotted on both primary and secondary axis.
+//
+// data_labels: Set the data labels of the series, which overrides the data
+// labels settings of the plot area. The data_labels property is optional. The
+// options that can be set are:
+//
+//    show_val
+//    show_cat_name
+//    show_series_name
+//    show_percent
+//    show_bubble_size
+//    show_leader_lines
+//    number_format
+//    position
+//
+// number_format: Set the number format code of the data labels, such as
+// "0.0%". The data labels will use the number format of the source data by
+// default.
+//
+// position: Set the position of the data labels. The available positions are:
+//
+//    above
+//    below
+//    best_fit
+//    center
+//    inside_base
+//    inside_end
+//    left
+//    outside_end
+//    right
+//
+// trendline: Set the trendline of the series, which is supported in the
+// unstacked 2D area, bar, column, line, stock, scatter and bubble charts. The
+// trendline property is optional. The options that can be set are:
+//
+//    type
+//    name
+//    order
+//    period
+//    forward
+//    backward
+//    display_equation
+//    display_r_squared
+//
+// type: Specifies the type of the trendline, the available types are
+// exponential, linear, log, moving_average, polynomial and power.
+//
+// order: Specifies the order of the polynomial trendline between 2 and 6.
+//
+// period: Specifies the period of the moving average trendline between 2 and
+// 255.
+//
+// forward and backward: Specifies the number of periods to forecast forward
+// and backward for the trendline except the moving average trendline.
+//
+// display_equation and display_r_squared: Specifies the trendline equation
+// and R-squared value shall be displayed on the chart.
+//
+// error_bars: Set the error bars of the series, which is supported in the 2D
+// area, bar, column, line, scatter and bubble charts. The error_bars property
+// is optional. The options that can be set are:
+//
+//    direction
+//    type
+//    value_type
+//    value
+//    no_end_cap
+//
+// direction: Specifies the direction of the error bars, the available
+// directions are x and y, the x direction is only supported in the scatter
+// and bubble charts. The default value is y.
+//
+// type: Specifies the error bars shall be shown in both, minus or plus
+// direction. The default value is both.
+//
+// value_type: Specifies the type of the error amount, the available types
+// are fixed, percentage, standard_deviation and standard_error. The default
+// value is fixed.
+//
+// value: Specifies the error amount for the fixed, percentage and
+// standard_deviation value types. The default value is 1 for the fixed and
+// standard_deviation value types, and 5 for the percentage value type.
+//
+// no_end_cap: Specifies the end caps of the error bars shall not be drawn.
+// The default value is false.
+//
+// For example, add a linear trendline with the equation and the percentage
+// error bars for the series:
+//
+//    {"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","trendline":{"type":"linear","display_equation":true},"error_bars":{"value_type":"percentage","value":10}}
+//
 // line: This sets the line format of the line chart. The line property is optional and if it isn't supplied it will default style. The options that can be set is width. The range of width is 0.25pt - 999pt. If the value of width is outside the range, the default width of the line is 2pt.
 //
 // Set properties of the chart legend. The options that can be set are:
 //
+//    none
 //    position
 //    show_legend_key
 //
-// position: Set the position of the chart legend. The default legend position is right. The available positions are:
+// none: Specifies that the chart legend shall be hidden. The none property is optional. The default value is false.
+//
+// position: Set the position of the chart legend. The default legend position is bottom. The available positions are:
 //
+//    none
 //    top
 //    bottom
 //    left
 //    right
 //    top_right
 //
+// The none position is the same as set none to true.
+//
 // show_legend_key: Set the legend keys shall be shown in data labels. The default value is false.
 //
 // Set properties of the chart title. The properties that can be set are:
 //
-//    title
+//    none
+//    name
+//    font
+//
+// none: Specifies that the chart title shall be hidden. The none property is optional. The default value is false.
 //
 // name: Set the name (title) for the chart. The name is displayed above the chart. The name can also be a formula such as Sheet1!$A$1 or a list with a sheetname. The name property is optional. The default is to have no chart title.
 //
+// font: Set the font of the chart title. The options that can be set are bold, italic, underline, family, size and color, same as the font of the function NewStyle. The color should be RGB hex color, for example: #FF0000. The size should be between 1 and 4000. The font property is optional.
+//
 // Specifies how blank cells are plotted on the chart by show_blanks_as. The default value is gap. The options that can be set are:
 //
 //    gap
@@ -665,40 +834,58 @@ func parseFormatChartSet(formatSet string) (*formatChart, error) {
 //
 // Set the primary horizontal and vertical axis options by x_axis and y_axis. The properties of x_axis that can be set are:
 //
+//    name
+//    name_font
 //    major_grid_lines
 //    minor_grid_lines
 //    tick_label_skip
+//    tick_mark_skip
 //    reverse_order
 //    maximum
 //    minimum
 //
 // The properties of y_axis that can be set are:
 //
+//    name
+//    name_font
 //    major_grid_lines
 //    minor_grid_lines
 //    major_unit
+//    minor_unit
+//    log_base
 //    reverse_order
 //    maximum
 //    minimum
 //
+// name: Set the title of the axis. The name property is optional. The default is to have no axis title.
+//
+// name_font: Set the font of the axis title, same as the font of the chart title. The name_font property is optional.
+//
 // major_grid_lines: Specifies major gridlines.
 //
 // minor_grid_lines: Specifies minor gridlines.
 //
 // major_unit: Specifies the distance between major ticks. Shall contain a positive floating-point number. The major_unit property is optional. The default value is auto.
 //
+// minor_unit: Specifies the distance between minor ticks. Shall contain a positive floating-point number. The minor_unit property is optional. The default value is auto.
+//
+// log_base: Specifies the logarithmic scale of the value axis with the given base between 2 and 1000. The log_base property is optional. The default value is the linear scale.
+//
 // tick_label_skip: Specifies how many tick labels to skip between label that is drawn. The tick_label_skip property is optional. The default value is auto.
 //
+// tick_mark_skip: Specifies how many tick marks to skip before the next one is drawn. The tick_mark_skip property is optional. The default value is auto.
+//
 // reverse_order: Specifies that the categories or values on reverse order (orientation of the chart). The reverse_order property is optional. The default value is false.
 //
 // maximum: Specifies that the fixed maximum, 0 is auto. The maximum property is optional. The default value is auto.
 //
-// minimum: Specifies that the fixed minimum, 0 is auto. The minimum property is optional. The default value is auto.
+// minimum: Specifies that the fixed minimum, 0 is auto. The minimum property is optional. The default value is auto. The minimum must be less than the maximum if both of them are specified.
 //
 // Set chart size by dimension property. The dimension property is optional. The default width is 480, and height is 290.
 //
 // combo: Specifies the create a chart that combines two or more chart types
-// in a single chart. For example, create a clustered column - line chart with
+// in a single chart. Set secondary_axis to true in the format set of the combo
+// chart to plot all of its series on the secondary value axis. For example, create a clustered column - line chart with
 // data Sheet1!$E$1:$L$15:
 //
 //    package main
@@ -814,24 +1001,206 @@ func (f *File) getFormatChart(format string, combo []string) (*formatChart, []*f
 	if err != nil {
 		return formatSet, comboCharts, err
 	}
+	formatSet.SecondaryAxis = false
+	comboCharts = append(comboCharts, splitFormatChartSeries(formatSet)...)
 	for _, comboFormat := range combo {
 		comboChart, err := parseFormatChartSet(comboFormat)
 		if err != nil {
 			return formatSet, comboCharts, err
 		}
-		if _, ok := chartValAxNumFmtFormatCode[comboChart.Type]; !ok {
-			return formatSet, comboCharts, errors.New("unsupported chart type " + comboChart.Type)
-		}
 		comboCharts = append(comboCharts, comboChart)
+		comboCharts = append(comboCharts, splitFormatChartSeries(comboChart)...)
 	}
 	if _, ok := chartValAxNumFmtFormatCode[formatSet.Type]; !ok {
 		return formatSet, comboCharts, errors.New("unsupported chart type " + formatSet.Type)
 	}
-	return formatSet, comboCharts, err
+	axes := map[string]bool{formatSet.Type: false}
+	for _, comboChart := range comboCharts {
+		if _, ok := chartValAxNumFmtFormatCode[comboChart.Type]; !ok {
+			return formatSet, comboCharts, errors.New("unsupported chart type " + comboChart.Type)
+		}
+		if secondary, ok := axes[comboChart.Type]; ok && secondary != comboChart.SecondaryAxis {
+			return formatSet, comboCharts, errors.New("chart type " + comboChart.Type + " can't be plotted on both primary and secondary axis")
+		}
+		axes[comboChart.Type] = comboChart.SecondaryAxis
+		if err = checkFormatChartSeries(comboChart); err != nil {
+			return formatSet, comboCharts, err
+		}
+		if err = checkFormatChartAxis(comboChart); err != nil {
+			return formatSet, comboCharts, err
+		}
+	}
+	if err = checkFormatChartAxis(formatSet); err != nil {
+		return formatSet, comboCharts, err
+	}
+	if err = checkFormatChartTitle(formatSet); err != nil {
+		return formatSet, comboCharts, err
+	}
+	return formatSet, comboCharts, checkFormatChartSeries(formatSet)
+}
+
+// checkFormatChartTitle provides a function to check the font of the chart
+// title and the position of the chart legend.
+func checkFormatChartTitle(formatSet *formatChart) error {
+	if _, ok := chartLegendPosition[formatSet.Legend.Position]; !ok {
+		return errors.New("unsupported legend position " + formatSet.Legend.Position)
+	}
+	return checkFormatChartFont(&formatSet.Title.Font)
+}
+
+// checkFormatChartFont provides a function to check the color and size of
+// the font for the chart title and axis titles.
+func checkFormatChartFont(font *Font) error {
+	if font.Color != "" && !styleRGBColorRe.MatchString(font.Color) {
+		return fmt.Errorf("invalid chart font color %s", font.Color)
+	}
+	if font.Size != 0 && (font.Size < 1 || font.Size > 4000) {
+		return fmt.Errorf("invalid chart font size %g", font.Size)
+	}
+	return nil
+}
+
+// checkFormatChartAxis provides a function to check the scaling, units and
+// tick spacing settings of the chart axes. The minimum and maximum of the
+// axis with 0 value are auto.
+func checkFormatChartAxis(formatSet *formatChart) error {
+	for _, axis := range []formatChartAxis{formatSet.XAxis, formatSet.YAxis} {
+		if axis.Minimum != 0 && axis.Maximum != 0 && axis.Minimum >= axis.Maximum {
+			return fmt.Errorf("the minimum %g of the chart axis must be less than the maximum %g", axis.Minimum, axis.Maximum)
+		}
+		if axis.MajorUnit < 0 || axis.MinorUnit < 0 {
+			return errors.New("the major and minor unit of the chart axis can not be negative")
+		}
+		if axis.TickLabelSkip < 0 || axis.TickMarkSkip < 0 {
+			return errors.New("the tick label and tick mark skip of the chart axis can not be negative")
+		}
+		if err := checkFormatChartFont(&axis.NameFont); err != nil {
+			return err
+		}
+	}
+	if logBase := formatSet.YAxis.LogBase; logBase != 0 {
+		if logBase < 2 || logBase > 1000 {
+			return fmt.Errorf("invalid chart axis log base %g", logBase)
+		}
+		if formatSet.YAxis.Minimum < 0 {
+			return errors.New("the minimum of the chart axis must be positive with the logarithmic scale")
+		}
+	}
+	return nil
+}
+
+// splitFormatChartSeries provides a function to move the series which
+// specified a different chart type or plotted on the secondary axis out of
+// the given chart format set, and returns them grouped by the chart type and
+// the axis as combo charts.
+func splitFormatChartSeries(formatSet *formatChart) []*formatChart {
+	var series []formatChartSeries
+	comboCharts, groups := []*formatChart{}, map[string]*formatChart{}
+	for _, ser := range formatSet.Series {
+		if ser.Type == "" {
+			ser.Type = formatSet.Type
+		}
+		secondary := ser.SecondaryAxis || formatSet.SecondaryAxis
+		if ser.Type == formatSet.Type && secondary == formatSet.SecondaryAxis {
+			series = append(series, ser)
+			continue
+		}
+		key := ser.Type + strconv.FormatBool(secondary)
+		comboChart, ok := groups[key]
+		if !ok {
+			comboChart = &formatChart{}
+			*comboChart = *formatSet
+			comboChart.Type, comboChart.Series, comboChart.SecondaryAxis = ser.Type, nil, secondary
+			groups[key] = comboChart
+			comboCharts = append(comboCharts, comboChart)
+		}
+		comboChart.Series = append(comboChart.Series, ser)
+	}
+	formatSet.Series = series
+	return comboCharts
+}
+
+// checkFormatChartSeries provides a function to check the number of series
+// which required by the chart type and the data labels settings of the
+// series.
+func checkFormatChartSeries(formatSet *formatChart) error {
+	if formatSet.Type == Stock && len(formatSet.Series) != 3 && len(formatSet.Series) != 4 {
+		return errors.New("stock chart requires 3 or 4 series")
+	}
+	for _, ser := range formatSet.Series {
+		if err := checkFormatChartTrendline(formatSet.Type, ser.Trendline); err != nil {
+			return err
+		}
+		if err := checkFormatChartErrorBars(formatSet.Type, ser.ErrorBars); err != nil {
+			return err
+		}
+		if ser.DataLabels == nil || ser.DataLabels.Position == "" {
+			continue
+		}
+		if _, ok := chartDataLabelsPosition[ser.DataLabels.Position]; !ok {
+			return errors.New("unsupported data labels position " + ser.DataLabels.Position)
+		}
+	}
+	return nil
+}
+
+// checkFormatChartTrendline provides a function to check the trendline
+// settings of the series by given chart type.
+func checkFormatChartTrendline(chartType string, trendline *formatChartTrendline) error {
+	if trendline == nil {
+		return nil
+	}
+	if !chartTrendlineSupported[chartType] {
+		return errors.New("chart type " + chartType + " doesn't support trendline")
+	}
+	if _, ok := chartTrendlineTypes[trendline.Type]; !ok {
+		return errors.New("unsupported trendline type " + trendline.Type)
+	}
+	if trendline.Type == "polynomial" && (trendline.Order < 2 || trendline.Order > 6) {
+		return fmt.Errorf("invalid polynomial trendline order %d", trendline.Order)
+	}
+	if trendline.Type == "moving_average" && (trendline.Period < 2 || trendline.Period > 255) {
+		return fmt.Errorf("invalid moving average trendline period %d", trendline.Period)
+	}
+	if trendline.Forward < 0 || trendline.Backward < 0 {
+		return errors.New("the forward and backward of the trendline can not be negative")
+	}
+	return nil
+}
+
+// checkFormatChartErrorBars provides a function to check the error bars
+// settings of the series by given chart type.
+func checkFormatChartErrorBars(chartType string, errBars *formatChartErrorBars) error {
+	if errBars == nil {
+		return nil
+	}
+	if !chartErrorBarsSupported[chartType] {
+		return errors.New("chart type " + chartType + " doesn't support error bars")
+	}
+	switch errBars.Direction {
+	case "", "y":
+	case "x":
+		if chartType != Scatter && chartType != Bubble {
+			return errors.New("chart type " + chartType + " doesn't support error bars direction x")
+		}
+	default:
+		return errors.New("unsupported error bars direction " + errBars.Direction)
+	}
+	if _, ok := chartErrorBarsTypes[errBars.Type]; errBars.Type != "" && !ok {
+		return errors.New("unsupported error bars type " + errBars.Type)
+	}
+	if _, ok := chartErrorBarsValueTypes[errBars.ValueType]; errBars.ValueType != "" && !ok {
+		return errors.New("unsupported error bars value type " + errBars.ValueType)
+	}
+	if errBars.Value < 0 {
+		return fmt.Errorf("invalid error bars value %g", errBars.Value)
+	}
+	return nil
 }
 
 // DeleteChart provides a function to delete chart in XLSX by given worksheet
-// and cell name.
+// and cell name. The chart part, relationship and content type of the chart
+// will be removed, and the remaining charts will be renumbered if needed.
 func (f *File) DeleteChart(sheet, cell string) (err error) {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
@@ -5,6 +5,9 @@ import (
 	"encoding/xml"
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -208,6 +211,277 @@ func TestAddChart(t *testing.T) {
 	assert.EqualError(t, f.AddChart("Sheet2", "BD64", `{"type":"barOfPie","series":[{"name":"Sheet1!$A$30","categories":"Sheet1!$A$30:$D$37","values":"Sheet1!$B$30:$B$37"}],"format":{"x_scale":1.0,"y_scale":1.0,"x_offset":15,"y_offset":10,"print_obj":true,"lock_aspect_ratio":false,"locked":false},"legend":{"position":"left","show_legend_key":false},"title":{"name":"Bar of Pie Chart"},"plotarea":{"show_bubble_size":true,"show_cat_name":false,"show_leader_lines":false,"show_percent":true,"show_series_name":true,"show_val":true},"show_blanks_as":"zero","x_axis":{"major_grid_lines":true},"y_axis":{"major_grid_lines":true}}`, `{"type":"unknown","series":[{"name":"Sheet1!$A$30","categories":"Sheet1!$A$30:$D$37","values":"Sheet1!$B$30:$B$37"}],"format":{"x_scale":1.0,"y_scale":1.0,"x_offset":15,"y_offset":10,"print_obj":true,"lock_aspect_ratio":false,"locked":false},"legend":{"position":"left","show_legend_key":false},"title":{"name":"Bar of Pie Chart"},"plotarea":{"show_bubble_size":true,"show_cat_name":false,"show_leader_lines":false,"show_percent":true,"show_series_name":true,"show_val":true},"show_blanks_as":"zero","x_axis":{"major_grid_lines":true},"y_axis":{"major_grid_lines":true}}`), "unsupported chart type unknown")
 }
 
+func TestAddChartStockRadarSurface(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Date", "Open", "High", "Low", "Close"},
+		{"2019-12-02", 25, 28, 24, 27},
+		{"2019-12-03", 27, 30, 26, 26},
+		{"2019-12-04", 26, 29, 23, 28},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := func(cols ...string) string {
+		var ser []string
+		for _, col := range cols {
+			ser = append(ser, fmt.Sprintf(`{"name":"Sheet1!$%[1]s$1","categories":"Sheet1!$A$2:$A$4","values":"Sheet1!$%[1]s$2:$%[1]s$4"}`, col))
+		}
+		return "[" + strings.Join(ser, ",") + "]"
+	}
+	for _, c := range []struct {
+		cell, format string
+	}{
+		{"G1", `{"type":"stock","series":` + series("C", "D", "E") + `,"title":{"name":"High-Low-Close Stock Chart"}}`},
+		{"G16", `{"type":"stock","series":` + series("B", "C", "D", "E") + `,"title":{"name":"Open-High-Low-Close Stock Chart"}}`},
+		{"P1", `{"type":"radar","series":` + series("B", "C", "D", "E") + `,"title":{"name":"Radar Chart"}}`},
+		{"P16", `{"type":"contour","series":` + series("B", "C", "D", "E") + `,"title":{"name":"Contour Chart"}}`},
+		{"Y1", `{"type":"surface3D","series":` + series("B", "C", "D", "E") + `,"title":{"name":"3D Surface Chart"}}`},
+	} {
+		assert.NoError(t, f.AddChart("Sheet1", c.cell, c.format))
+	}
+	// Test add stock chart with invalid number of series.
+	assert.EqualError(t, f.AddChart("Sheet1", "Y16", `{"type":"stock","series":`+series("B", "C")+`}`), "stock chart requires 3 or 4 series")
+	assert.EqualError(t, f.AddChart("Sheet1", "Y16", `{"type":"col","series":`+series("B")+`}`, `{"type":"stock","series":`+series("B")+`}`), "stock chart requires 3 or 4 series")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook and check the charts.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for chart, expected := range map[string][]string{
+		"xl/charts/chart1.xml": {"<stockChart>", `<symbol val="dot"></symbol>`, "<hiLowLines></hiLowLines><axId"},
+		"xl/charts/chart2.xml": {"<stockChart>", "<hiLowLines></hiLowLines><upDownBars><gapWidth val=\"150\"></gapWidth><upBars></upBars><downBars></downBars></upDownBars>"},
+		"xl/charts/chart3.xml": {"<radarChart>", `<radarStyle val="marker"></radarStyle>`},
+		"xl/charts/chart4.xml": {"<surfaceChart>", "<serAx>"},
+		"xl/charts/chart5.xml": {"<surface3DChart>", "<serAx>"},
+	} {
+		content, ok := f.XLSX[chart]
+		if !assert.True(t, ok, chart) {
+			continue
+		}
+		assert.NoError(t, xml.Unmarshal(content, new(struct{})), chart)
+		for _, e := range expected {
+			assert.True(t, strings.Contains(string(content), e), chart+": "+e)
+		}
+	}
+	assert.False(t, strings.Contains(string(f.XLSX["xl/charts/chart1.xml"]), "<upDownBars>"))
+}
+
+func TestAddChartAxisScaling(t *testing.T) {
+	f := NewFile()
+	for k, v := range map[string]interface{}{"A2": "Small", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 30, "D2": 300} {
+		assert.NoError(t, f.SetCellValue("Sheet1", k, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"x_axis":{"tick_label_skip":2,"tick_mark_skip":2},"y_axis":{"minimum":1,"maximum":1000,"major_unit":10,"minor_unit":5,"log_base":10}}`))
+	// Test add chart with invalid axis settings.
+	for format, expected := range map[string]string{
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"minimum":10,"maximum":1}}`:   "the minimum 10 of the chart axis must be less than the maximum 1",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"x_axis":{"minimum":2,"maximum":2}}`:    "the minimum 2 of the chart axis must be less than the maximum 2",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"major_unit":-1}}`:            "the major and minor unit of the chart axis can not be negative",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"x_axis":{"tick_mark_skip":-1}}`:        "the tick label and tick mark skip of the chart axis can not be negative",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"log_base":1}}`:               "invalid chart axis log base 1",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"log_base":1001}}`:            "invalid chart axis log base 1001",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"log_base":10,"minimum":-1}}`: "the minimum of the chart axis must be positive with the logarithmic scale",
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "P1", format), expected, format)
+	}
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}]}`, `{"type":"line","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"log_base":0.5}}`), "invalid chart axis log base 0.5")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	chart := string(f.XLSX["xl/charts/chart1.xml"])
+	for _, expected := range []string{
+		`<scaling><logBase val="10"></logBase><orientation val="minMax"></orientation><max val="1000"></max><min val="1"></min></scaling>`,
+		`<majorUnit val="10"></majorUnit><minorUnit val="5"></minorUnit>`,
+		`<tickLblSkip val="2"></tickLblSkip><tickMarkSkip val="2"></tickMarkSkip>`,
+	} {
+		assert.True(t, strings.Contains(chart, expected), expected)
+	}
+}
+
+func TestAddChartTitleFont(t *testing.T) {
+	f := NewFile()
+	for k, v := range map[string]interface{}{"A2": "Small", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", k, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"title":{"name":"Fruit","font":{"bold":true,"italic":true,"size":18,"color":"#ff0000","family":"Arial","underline":"single"}},"x_axis":{"name":"Category"},"y_axis":{"name":"Amount","name_font":{"size":12,"color":"00FF00"}},"legend":{"position":"top"}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E20", `{"type":"bar","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"title":{"none":true},"x_axis":{"name":"Category"},"legend":{"none":true}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E40", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}],"legend":{"position":"none"}}`))
+	// Test add chart with invalid title and legend settings.
+	for format, expected := range map[string]string{
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"legend":{"position":"center"}}`:          "unsupported legend position center",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"title":{"font":{"color":"red"}}}`:        "invalid chart font color red",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"title":{"font":{"size":4001}}}`:          "invalid chart font size 4001",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"y_axis":{"name_font":{"color":"#FFF"}}}`: "invalid chart font color #FFF",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}],"x_axis":{"name_font":{"size":0.5}}}`:     "invalid chart font size 0.5",
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "P1", format), expected, format)
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	chart := string(f.XLSX["xl/charts/chart1.xml"])
+	for _, expected := range []string{
+		`<a:r><a:rPr altLang="en-US" b="true" baseline="0" i="true" kern="0" lang="en-US" spc="0" sz="1800" u="sng"><a:solidFill><a:srgbClr val="FF0000"></a:srgbClr></a:solidFill><a:latin typeface="Arial"></a:latin><a:ea typeface="Arial"></a:ea><a:cs typeface="Arial"></a:cs></a:rPr><a:t>Fruit</a:t></a:r>`,
+		`<a:t>Category</a:t>`,
+		`<a:bodyPr anchor="ctr" anchorCtr="true" rot="-5400000" spcFirstLastPara="true" vert="horz" vertOverflow="ellipsis" wrap="square"></a:bodyPr>`,
+		`sz="1200"><a:solidFill><a:srgbClr val="00FF00"></a:srgbClr></a:solidFill></a:rPr><a:t>Amount</a:t>`,
+		`<legendPos val="t"></legendPos>`,
+	} {
+		assert.True(t, strings.Contains(chart, expected), expected)
+	}
+	chart = string(f.XLSX["xl/charts/chart2.xml"])
+	assert.True(t, strings.Contains(chart, `<autoTitleDeleted val="true"></autoTitleDeleted>`))
+	assert.True(t, strings.Contains(chart, `<a:bodyPr anchor="ctr" anchorCtr="true" rot="-5400000" spcFirstLastPara="true" vert="horz" vertOverflow="ellipsis" wrap="square"></a:bodyPr><a:p><a:pPr><a:defRPr b="true" baseline="0" i="false" kern="1200" spc="0" strike="noStrike" sz="1000" u="none">`))
+	assert.False(t, strings.Contains(chart, "<legend>"))
+	assert.False(t, strings.Contains(string(f.XLSX["xl/charts/chart3.xml"]), "<legend>"))
+}
+
+func TestAddChartSecondaryAxis(t *testing.T) {
+	f := NewFile()
+	for k, v := range map[string]interface{}{"A2": "Small", "A3": "Normal", "A4": "Large", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4, "B4": 600, "C4": 700, "D4": 800} {
+		assert.NoError(t, f.SetCellValue("Sheet1", k, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3"},{"name":"Sheet1!$A$4","type":"line","secondary_axis":true,"categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$4:$D$4"}],"title":{"name":"Column - Line Chart on Secondary Axis"}}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`, `{"type":"line","secondary_axis":true,"series":[{"name":"Sheet1!$A$4","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$4:$D$4"}]}`))
+	// Test add chart with a primary axis chart following the secondary axis chart.
+	assert.NoError(t, f.AddChart("Sheet1", "E31", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`, `{"type":"line","secondary_axis":true,"series":[{"name":"Sheet1!$A$4","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$4:$D$4"}]}`, `{"type":"area","series":[{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3"}]}`))
+	// Test add chart with the same chart type on both primary and secondary axis.
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"},{"name":"Sheet1!$A$3","secondary_axis":true,"categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3"}]}`), "chart type col can't be plotted on both primary and secondary axis")
+	// Test add chart with unsupported series chart type.
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[{"name":"Sheet1!$A$2","type":"unknown","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`), "unsupported chart type unknown")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook and check the secondary axis chart references
+	// the secondary axes.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for _, chart := range []string{"xl/charts/chart1.xml", "xl/charts/chart2.xml"} {
+		chartSpace := xlsxChartSpace{}
+		assert.NoError(t, xml.Unmarshal(f.XLSX[chart], &chartSpace))
+		plotArea := chartSpace.Chart.PlotArea
+		if !assert.NotNil(t, plotArea.BarChart) || !assert.NotNil(t, plotArea.LineChart) {
+			continue
+		}
+		assert.Len(t, plotArea.CatAx, 2)
+		if !assert.Len(t, plotArea.ValAx, 2) {
+			continue
+		}
+		assert.Equal(t, *plotArea.CatAx[0].AxID.Val, *plotArea.BarChart.AxID[0].Val)
+		assert.Equal(t, *plotArea.ValAx[0].AxID.Val, *plotArea.BarChart.AxID[1].Val)
+		assert.Equal(t, *plotArea.CatAx[1].AxID.Val, *plotArea.LineChart.AxID[0].Val)
+		assert.Equal(t, *plotArea.ValAx[1].AxID.Val, *plotArea.LineChart.AxID[1].Val)
+		assert.NotEqual(t, *plotArea.ValAx[0].AxID.Val, *plotArea.ValAx[1].AxID.Val)
+		assert.Equal(t, "r", *plotArea.ValAx[1].AxPos.Val)
+		assert.Equal(t, "max", *plotArea.ValAx[1].Crosses.Val)
+		assert.True(t, *plotArea.CatAx[1].Delete.Val)
+	}
+	// Test every axis ID referenced in the charts resolves to an axis.
+	axIDRe := regexp.MustCompile(`<(?:c:)?(?:axId|crossAx) val="(\d+)"`)
+	for _, chart := range []string{"xl/charts/chart1.xml", "xl/charts/chart2.xml", "xl/charts/chart3.xml"} {
+		chartSpace := xlsxChartSpace{}
+		assert.NoError(t, xml.Unmarshal(f.XLSX[chart], &chartSpace))
+		plotArea := chartSpace.Chart.PlotArea
+		axIDs := map[string]bool{}
+		for _, axs := range [][]*cAxs{plotArea.CatAx, plotArea.ValAx, plotArea.SerAx} {
+			for _, ax := range axs {
+				assert.False(t, axIDs[strconv.Itoa(*ax.AxID.Val)], chart)
+				axIDs[strconv.Itoa(*ax.AxID.Val)] = true
+			}
+		}
+		matches := axIDRe.FindAllStringSubmatch(string(f.XLSX[chart]), -1)
+		assert.NotEmpty(t, matches, chart)
+		for _, match := range matches {
+			assert.True(t, axIDs[match[1]], chart)
+		}
+	}
+	chartSpace := xlsxChartSpace{}
+	assert.NoError(t, xml.Unmarshal(f.XLSX["xl/charts/chart3.xml"], &chartSpace))
+	assert.Len(t, chartSpace.Chart.PlotArea.CatAx, 2)
+	assert.Len(t, chartSpace.Chart.PlotArea.ValAx, 2)
+}
+
+func TestAddChartSeriesDataLabels(t *testing.T) {
+	f := NewFile()
+	for k, v := range map[string]interface{}{"A2": "Small", "A3": "Normal", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", k, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","data_labels":{"show_val":true,"number_format":"0.0","position":"outside_end"}},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3","data_labels":{"show_cat_name":true,"position":"center"}}]}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", `{"type":"pie","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","data_labels":{"show_percent":true,"show_leader_lines":true,"number_format":"0%","position":"best_fit"}}]}`))
+	// Test add chart with unsupported data labels position.
+	assert.EqualError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","data_labels":{"position":"unknown"}}]}`), "unsupported data labels position unknown")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for chart, expected := range map[string][]string{
+		"xl/charts/chart1.xml": {
+			`<dLbls><numFmt formatCode="0.0" sourceLinked="false"></numFmt><dLblPos val="outEnd"></dLblPos><showLegendKey val="false"></showLegendKey><showVal val="true"></showVal><showCatName val="false"></showCatName>`,
+			`<dLbls><dLblPos val="ctr"></dLblPos><showLegendKey val="false"></showLegendKey><showVal val="false"></showVal><showCatName val="true"></showCatName>`,
+		},
+		"xl/charts/chart2.xml": {
+			`<dLbls><numFmt formatCode="0%" sourceLinked="false"></numFmt><dLblPos val="bestFit"></dLblPos><showLegendKey val="false"></showLegendKey><showVal val="false"></showVal><showCatName val="false"></showCatName><showSerName val="false"></showSerName><showPercent val="true"></showPercent><showBubbleSize val="false"></showBubbleSize><showLeaderLines val="true"></showLeaderLines></dLbls>`,
+		},
+	} {
+		for _, e := range expected {
+			assert.True(t, strings.Contains(string(f.XLSX[chart]), e), chart+": "+e)
+		}
+	}
+}
+
+func TestAddChartSeriesTrendlineErrorBars(t *testing.T) {
+	f := NewFile()
+	for k, v := range map[string]interface{}{"A2": "Small", "A3": "Normal", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4} {
+		assert.NoError(t, f.SetCellValue("Sheet1", k, v))
+	}
+	assert.NoError(t, f.AddChart("Sheet1", "E1", `{"type":"col","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","trendline":{"type":"linear","name":"Trend","forward":1.5,"display_equation":true,"display_r_squared":true},"error_bars":{"value_type":"percentage","value":10}},{"name":"Sheet1!$A$3","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$3:$D$3","trendline":{"type":"moving_average","period":2},"error_bars":{"type":"plus","value_type":"standard_error","no_end_cap":true}}]}`))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", `{"type":"scatter","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2","trendline":{"type":"polynomial","order":3},"error_bars":{"direction":"x"}}]}`))
+	// Test add chart with unsupported trendline and error bars settings.
+	for format, expected := range map[string]string{
+		`{"type":"pie","series":[{"values":"Sheet1!$B$2:$D$2","trendline":{"type":"linear"}}]}`:                                            "chart type pie doesn't support trendline",
+		`{"type":"colStacked","series":[{"values":"Sheet1!$B$2:$D$2","trendline":{"type":"linear"}}]}`:                                     "chart type colStacked doesn't support trendline",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","trendline":{"type":"unknown"}}]}`:                                           "unsupported trendline type unknown",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","trendline":{"type":"polynomial","order":7}}]}`:                              "invalid polynomial trendline order 7",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","trendline":{"type":"moving_average"}}]}`:                                    "invalid moving average trendline period 0",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","trendline":{"type":"linear","backward":-1}}]}`:                              "the forward and backward of the trendline can not be negative",
+		`{"type":"pie","series":[{"values":"Sheet1!$B$2:$D$2","error_bars":{}}]}`:                                                          "chart type pie doesn't support error bars",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","error_bars":{"direction":"x"}}]}`:                                           "chart type col doesn't support error bars direction x",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","error_bars":{"direction":"z"}}]}`:                                           "unsupported error bars direction z",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","error_bars":{"type":"unknown"}}]}`:                                          "unsupported error bars type unknown",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","error_bars":{"value_type":"unknown"}}]}`:                                    "unsupported error bars value type unknown",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2","error_bars":{"value":-1}}]}`:                                                "invalid error bars value -1",
+		`{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"},{"type":"pie","values":"Sheet1!$B$3:$D$3","trendline":{"type":"linear"}}]}`: "chart type pie doesn't support trendline",
+	} {
+		assert.EqualError(t, f.AddChart("Sheet1", "P1", format), expected, format)
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for chart, expected := range map[string][]string{
+		"xl/charts/chart1.xml": {
+			`<trendline><name>Trend</name><trendlineType val="linear"></trendlineType><forward val="1.5"></forward><dispRSqr val="true"></dispRSqr><dispEq val="true"></dispEq></trendline><errBars><errBarType val="both"></errBarType><errValType val="percentage"></errValType><noEndCap val="false"></noEndCap><val val="10"></val></errBars><cat>`,
+			`<trendline><trendlineType val="movingAvg"></trendlineType><period val="2"></period><dispRSqr val="false"></dispRSqr><dispEq val="false"></dispEq></trendline><errBars><errBarType val="plus"></errBarType><errValType val="stdErr"></errValType><noEndCap val="true"></noEndCap></errBars><cat>`,
+		},
+		"xl/charts/chart2.xml": {
+			`<trendline><trendlineType val="poly"></trendlineType><order val="3"></order><dispRSqr val="false"></dispRSqr><dispEq val="false"></dispEq></trendline><errBars><errDir val="x"></errDir><errBarType val="both"></errBarType><errValType val="fixedVal"></errValType><noEndCap val="false"></noEndCap><val val="1"></val></errBars><xVal>`,
+		},
+	} {
+		for _, e := range expected {
+			assert.True(t, strings.Contains(string(f.XLSX[chart]), e), chart+": "+e)
+		}
+	}
+}
+
 func TestAddChartSheet(t *testing.T) {
 	categories := map[string]string{"A2": "Small", "A3": "Normal", "A4": "Large", "B1": "Apple", "C1": "Orange", "D1": "Pear"}
 	values := map[string]int{"B2": 2, "C2": 3, "D2": 3, "B3": 5, "C3": 2, "D3": 4, "B4": 6, "C4": 7, "D4": 8}
@@ -253,3 +527,55 @@ func TestDeleteChart(t *testing.T) {
 	// Test delete chart on no chart worksheet.
 	assert.NoError(t, NewFile().DeleteChart("Sheet1", "A1"))
 }
+
+func TestDeleteChartParts(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	for k, v := range map[string]interface{}{"A2": "Small", "B1": "Apple", "C1": "Orange", "D1": "Pear", "B2": 2, "C2": 3, "D2": 3} {
+		assert.NoError(t, f.SetCellValue("Sheet1", k, v))
+	}
+	format := `{"type":"%s","series":[{"name":"Sheet1!$A$2","categories":"Sheet1!$B$1:$D$1","values":"Sheet1!$B$2:$D$2"}]}`
+	assert.NoError(t, f.AddChart("Sheet1", "E1", fmt.Sprintf(format, "col")))
+	assert.NoError(t, f.AddChart("Sheet1", "E16", fmt.Sprintf(format, "line")))
+	assert.NoError(t, f.AddChart("Sheet2", "E1", fmt.Sprintf(format, "pie")))
+	assert.NoError(t, f.DeleteChart("Sheet1", "E1"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook and check the remaining charts have been
+	// renumbered.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.countCharts())
+	assert.True(t, strings.Contains(string(f.XLSX["xl/charts/chart1.xml"]), "<pieChart>"))
+	assert.True(t, strings.Contains(string(f.XLSX["xl/charts/chart2.xml"]), "<lineChart>"))
+	content := f.contentTypesReader()
+	var parts []string
+	for _, override := range content.Overrides {
+		if strings.HasPrefix(override.PartName, "/xl/charts/") {
+			parts = append(parts, override.PartName)
+		}
+	}
+	assert.ElementsMatch(t, []string{"/xl/charts/chart1.xml", "/xl/charts/chart2.xml"}, parts)
+	targets := map[string]string{}
+	for _, drawingRels := range []string{"xl/drawings/_rels/drawing1.xml.rels", "xl/drawings/_rels/drawing2.xml.rels"} {
+		rels := f.relsReader(drawingRels)
+		if !assert.NotNil(t, rels) || !assert.Len(t, rels.Relationships, 1) {
+			continue
+		}
+		targets[drawingRels] = rels.Relationships[0].Target
+	}
+	assert.Equal(t, map[string]string{
+		"xl/drawings/_rels/drawing1.xml.rels": "../charts/chart2.xml",
+		"xl/drawings/_rels/drawing2.xml.rels": "../charts/chart1.xml",
+	}, targets)
+
+	// Test add chart after delete chart doesn't overwrite the existing chart.
+	assert.NoError(t, f.DeleteChart("Sheet1", "E16"))
+	assert.Equal(t, 1, f.countCharts())
+	assert.NoError(t, f.AddChart("Sheet1", "E1", fmt.Sprintf(format, "col")))
+	assert.True(t, strings.Contains(string(f.XLSX["xl/charts/chart1.xml"]), "<pieChart>"))
+	assert.True(t, strings.Contains(string(f.XLSX["xl/charts/chart2.xml"]), "<barChart>"))
+	_, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+}
@@ -11,7 +11,9 @@ package excelize
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/mohae/deepcopy"
@@ -36,6 +38,9 @@ func (f *File) GetColVisible(sheet, col string) (bool, error) {
 	if err != nil {
 		return visible, err
 	}
+	if colNum > TotalColumns {
+		return visible, newInvalidColumnNameError(col)
+	}
 
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -81,6 +86,12 @@ func (f *File) SetColVisible(sheet, columns string, visible bool) error {
 	} else {
 		max = min
 	}
+	if min > TotalColumns {
+		return newInvalidColumnNameError(colsTab[0])
+	}
+	if max > TotalColumns {
+		return newInvalidColumnNameError(colsTab[len(colsTab)-1])
+	}
 	if max < min {
 		min, max = max, min
 	}
@@ -101,7 +112,7 @@ func (f *File) SetColVisible(sheet, columns string, visible bool) error {
 		xlsx.Cols = &cols
 		return nil
 	}
-	xlsx.Cols.Col = flatCols(colData, xlsx.Cols.Col, func(fc, c xlsxCol) xlsxCol {
+	xlsx.Cols.Col = coalesceCols(flatCols(colData, xlsx.Cols.Col, func(fc, c xlsxCol) xlsxCol {
 		fc.BestFit = c.BestFit
 		fc.Collapsed = c.Collapsed
 		fc.CustomWidth = c.CustomWidth
@@ -110,7 +121,7 @@ func (f *File) SetColVisible(sheet, columns string, visible bool) error {
 		fc.Style = c.Style
 		fc.Width = c.Width
 		return fc
-	})
+	}))
 	return nil
 }
 
@@ -221,6 +232,13 @@ func (f *File) SetColStyle(sheet, columns string, styleID int) error {
 	if max < min {
 		min, max = max, min
 	}
+	setColStyle(xlsx, min, max, styleID)
+	return nil
+}
+
+// setColStyle provides a function to set the style ID of the columns range
+// min:max in the cols element of the worksheet.
+func setColStyle(xlsx *xlsxWorksheet, min, max, styleID int) {
 	if xlsx.Cols == nil {
 		xlsx.Cols = &xlsxCols{}
 	}
@@ -239,7 +257,6 @@ func (f *File) SetColStyle(sheet, columns string, styleID int) error {
 		fc.Width = c.Width
 		return fc
 	})
-	return nil
 }
 
 // SetColWidth provides a function to set the width of a single column or
@@ -320,6 +337,26 @@ func flatCols(col xlsxCol, cols []xlsxCol, replacer func(fc, c xlsxCol) xlsxCol)
 	return fc
 }
 
+// coalesceCols provides a function to sort the columns by the column number
+// and merge the adjacent columns which have the same settings into a single
+// col element.
+func coalesceCols(cols []xlsxCol) []xlsxCol {
+	sort.Slice(cols, func(i, j int) bool { return cols[i].Min < cols[j].Min })
+	coalesced := []xlsxCol{}
+	for _, c := range cols {
+		if l := len(coalesced); l > 0 && coalesced[l-1].Max+1 == c.Min {
+			last := coalesced[l-1]
+			last.Min, last.Max = c.Min, c.Max
+			if last == c {
+				coalesced[l-1].Max = c.Max
+				continue
+			}
+		}
+		coalesced = append(coalesced, c)
+	}
+	return coalesced
+}
+
 // positionObjectPixels calculate the vertices that define the position of a
 // graphical object within the worksheet in pixels.
 //
@@ -477,11 +514,28 @@ func (f *File) GetColWidth(sheet, col string) (float64, error) {
 //    err := f.InsertCol("Sheet1", "C")
 //
 func (f *File) InsertCol(sheet, col string) error {
+	return f.InsertCols(sheet, col, 1)
+}
+
+// InsertCols provides a function to insert multiple columns before given
+// column index and the number of columns. The cells, formula references,
+// merged cells, defined names and data validations will be shifted right
+// accordingly. For example, create 2 new columns before column C in Sheet1:
+//
+//    err := f.InsertCols("Sheet1", "C", 2)
+//
+func (f *File) InsertCols(sheet, col string, n int) error {
 	num, err := ColumnNameToNumber(col)
 	if err != nil {
 		return err
 	}
-	return f.adjustHelper(sheet, columns, num, 1)
+	if num > TotalColumns {
+		return newInvalidColumnNameError(col)
+	}
+	if n < 1 || num+n-1 > TotalColumns {
+		return fmt.Errorf("invalid number of columns %d", n)
+	}
+	return f.adjustHelper(sheet, columns, num, n)
 }
 
 // RemoveCol provides a function to remove single column by given worksheet
@@ -489,15 +543,24 @@ func (f *File) InsertCol(sheet, col string) error {
 //
 //    err := f.RemoveCol("Sheet1", "C")
 //
+// The subsequent columns will be shifted left, and the formula references,
+// merged cells, defined names and data validations will be updated
+// accordingly. The references to the removed column will be turned into
+// #REF!, and the range references and merged cells which contain the removed
+// column will be shrunk.
+//
 // Use this method with caution, which will affect changes in references such
-// as formulas, charts, and so on. If there is any referenced value of the
-// worksheet, it will cause a file error when you open it. The excelize only
-// partially updates these references currently.
+// as charts, and so on. If there is any referenced value of the worksheet, it
+// will cause a file error when you open it. The excelize only partially
+// updates these references currently.
 func (f *File) RemoveCol(sheet, col string) error {
 	num, err := ColumnNameToNumber(col)
 	if err != nil {
 		return err
 	}
+	if num > TotalColumns {
+		return newInvalidColumnNameError(col)
+	}
 
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
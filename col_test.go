@@ -67,6 +67,33 @@ func TestColumnVisibility(t *testing.T) {
 	})
 }
 
+func TestSetColVisibleCoalesce(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetColVisible("Sheet1", "D:F", false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "B", false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "C", false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "E", true))
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []xlsxCol{
+		{Min: 2, Max: 4, Width: 9, Hidden: true, CustomWidth: true},
+		{Min: 5, Max: 5, Width: 9, CustomWidth: true},
+		{Min: 6, Max: 6, Width: 9, Hidden: true, CustomWidth: true},
+	}, xlsx.Cols.Col)
+	assert.NoError(t, f.SetColVisible("Sheet1", "E", false))
+	assert.Equal(t, []xlsxCol{{Min: 2, Max: 6, Width: 9, Hidden: true, CustomWidth: true}}, xlsx.Cols.Col)
+	for _, col := range []string{"B", "F"} {
+		visible, err := f.GetColVisible("Sheet1", col)
+		assert.NoError(t, err)
+		assert.False(t, visible)
+	}
+	// Test set and get column visible with out of range column.
+	assert.EqualError(t, f.SetColVisible("Sheet1", "XFE", false), `invalid column name "XFE"`)
+	assert.EqualError(t, f.SetColVisible("Sheet1", "A:XFE", false), `invalid column name "XFE"`)
+	_, err = f.GetColVisible("Sheet1", "XFE")
+	assert.EqualError(t, err, `invalid column name "XFE"`)
+}
+
 func TestOutlineLevel(t *testing.T) {
 	f := NewFile()
 	level, err := f.GetColOutlineLevel("Sheet1", "D")
@@ -186,6 +213,57 @@ func TestInsertCol(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestInsertCol.xlsx")))
 }
 
+func TestInsertCols(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 1))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "SUM(A1:D1)+$B1+SUM(B:B)"))
+	assert.NoError(t, f.InsertCols("Sheet1", "B", 3))
+	val, err := f.GetCellValue("Sheet1", "E1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+	formula, err := f.GetCellFormula("Sheet1", "H1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(A1:G1)+$E1+SUM(E:E)", formula)
+
+	assert.EqualError(t, f.InsertCols("Sheet1", "*", 1), `invalid column name "*"`)
+	assert.EqualError(t, f.InsertCols("Sheet1", "XFE", 1), `invalid column name "XFE"`)
+	assert.EqualError(t, f.InsertCols("Sheet1", "A", 0), "invalid number of columns 0")
+	assert.EqualError(t, f.InsertCols("Sheet1", "XFD", 2), "invalid number of columns 2")
+}
+
+func TestRemoveColReferences(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "B2+C2+SUM(B3:D3)+SUM(C:C)"))
+	assert.NoError(t, f.MergeCell("Sheet1", "B5", "D6"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C7", "C8"))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "C1:C3 D1"
+	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "C1:C3"
+	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	formula, err := f.GetCellFormula("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2+#REF!+SUM(B3:C3)+SUM(#REF!)", formula)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, mergeCells, 1) {
+		assert.Equal(t, "B5", mergeCells[0].GetStartAxis())
+		assert.Equal(t, "C6", mergeCells[0].GetEndAxis())
+	}
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, xlsx.DataValidations.DataValidation, 1) {
+		assert.Equal(t, "C1", xlsx.DataValidations.DataValidation[0].Sqref)
+		assert.Equal(t, 1, xlsx.DataValidations.Count)
+	}
+	assert.EqualError(t, f.RemoveCol("Sheet1", "XFE"), `invalid column name "XFE"`)
+}
+
 func TestRemoveCol(t *testing.T) {
 	f := NewFile()
 	sheet1 := f.GetSheetName(1)
@@ -11,61 +11,97 @@ package excelize
 
 import (
 	"bytes"
-	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// parseFormatCommentsSet provides a function to parse the format settings of
-// the comment with default value.
-func parseFormatCommentsSet(formatSet string) (*formatComment, error) {
-	format := formatComment{
-		Author: "Author:",
-		Text:   " ",
+// GetComments provides a function to get all comments in a worksheet by
+// given worksheet name. The cell reference, author, text and rich text runs
+// (if present) of each comment will be returned. An empty slice will be
+// returned if the worksheet doesn't have comments. For example, get all
+// comments in Sheet1:
+//
+//    comments, err := f.GetComments("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, comment := range comments {
+//        fmt.Println(comment.Ref, comment.Author, comment.Text)
+//    }
+//
+func (f *File) GetComments(sheet string) ([]Comment, error) {
+	comments := []Comment{}
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return comments, err
+	}
+	target := f.getSheetComments(sheet)
+	if target == "" {
+		return comments, nil
+	}
+	d := f.commentsReader(strings.Replace(target, "..", "xl", 1))
+	if d == nil {
+		return comments, nil
+	}
+	var cells []string
+	if xlsx.LegacyDrawing != nil {
+		drawingVML := strings.Replace(f.getSheetRelationshipsTargetByID(sheet, xlsx.LegacyDrawing.RID), "..", "xl", 1)
+		cells = f.getVMLNoteCells(drawingVML)
 	}
-	err := json.Unmarshal([]byte(formatSet), &format)
-	return &format, err
+	for i, comment := range d.CommentList.Comment {
+		sheetComment := Comment{Ref: comment.Ref, AuthorID: comment.AuthorID}
+		if comment.AuthorID < len(d.Authors) {
+			sheetComment.Author = d.Authors[comment.AuthorID].Author
+		}
+		// Resolve the cell reference by the anchor of the note in the VML
+		// drawing if the reference of the comment is missing or invalid.
+		if _, _, err := CellNameToCoordinates(sheetComment.Ref); err != nil && i < len(cells) {
+			sheetComment.Ref = cells[i]
+		}
+		if comment.Text.T != nil {
+			sheetComment.Text += *comment.Text.T
+		}
+		for _, text := range comment.Text.R {
+			sheetComment.Text += text.T
+			sheetComment.Runs = append(sheetComment.Runs, RichTextRun{
+				Font: newRichTextRunFont(text.RPr),
+				Text: text.T,
+			})
+		}
+		comments = append(comments, sheetComment)
+	}
+	return comments, nil
 }
 
-// GetComments retrieves all comments and returns a map of worksheet name to
-// the worksheet comments.
-func (f *File) GetComments() (comments map[string][]Comment) {
-	comments = map[string][]Comment{}
-	for n := range f.sheetMap {
-		if d := f.commentsReader("xl" + strings.TrimPrefix(f.getSheetComments(f.GetSheetIndex(n)), "..")); d != nil {
-			sheetComments := []Comment{}
-			for _, comment := range d.CommentList.Comment {
-				sheetComment := Comment{}
-				if comment.AuthorID < len(d.Authors) {
-					sheetComment.Author = d.Authors[comment.AuthorID].Author
-				}
-				sheetComment.Ref = comment.Ref
-				sheetComment.AuthorID = comment.AuthorID
-				if comment.Text.T != nil {
-					sheetComment.Text += *comment.Text.T
-				}
-				for _, text := range comment.Text.R {
-					sheetComment.Text += text.T
-				}
-				sheetComments = append(sheetComments, sheetComment)
+// getSheetComments provides the method to get the target comment reference by
+// given worksheet name.
+func (f *File) getSheetComments(sheet string) string {
+	var rels = "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	if sheetRels := f.relsReader(rels); sheetRels != nil {
+		for _, v := range sheetRels.Relationships {
+			if v.Type == SourceRelationshipComments {
+				return v.Target
 			}
-			comments[n] = sheetComments
 		}
 	}
-	return
+	return ""
 }
 
-// getSheetComments provides the method to get the target comment reference by
-// given worksheet index.
-func (f *File) getSheetComments(sheetID int) string {
-	var rels = "xl/worksheets/_rels/sheet" + strconv.Itoa(sheetID) + ".xml.rels"
+// getSheetThreadedComments provides a function to get the target threaded
+// comments reference by given worksheet name.
+func (f *File) getSheetThreadedComments(sheet string) string {
+	var rels = "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
 	if sheetRels := f.relsReader(rels); sheetRels != nil {
 		for _, v := range sheetRels.Relationships {
-			if v.Type == SourceRelationshipComments {
+			if v.Type == SourceRelationshipThreadedComment {
 				return v.Target
 			}
 		}
@@ -73,24 +109,211 @@ func (f *File) getSheetComments(sheetID int) string {
 	return ""
 }
 
-// AddComment provides the method to add comment in a sheet by given worksheet
-// index, cell and format set (such as author and text). Note that the max
-// author length is 255 and the max text length is 32512. For example, add a
-// comment in Sheet1!$A$30:
+// hasThreadedComment provides a function to check if the cell has a threaded
+// comment by given worksheet name and cell reference.
+func (f *File) hasThreadedComment(sheet, cell string) (bool, error) {
+	target := f.getSheetThreadedComments(sheet)
+	if target == "" {
+		return false, nil
+	}
+	threadedComments, err := f.threadedCommentsReader(strings.Replace(target, "..", "xl", 1))
+	if err != nil {
+		return false, err
+	}
+	for _, c := range threadedComments.ThreadedComment {
+		if c.Ref == cell {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getVMLNoteCells provides a function to get the cell references of the
+// notes in order by given VML drawing path.
+func (f *File) getVMLNoteCells(drawingVML string) []string {
+	var shapes []string
+	if vml, ok := f.VMLDrawing[drawingVML]; ok && vml != nil {
+		for _, shape := range vml.Shape {
+			shapes = append(shapes, shape.Val)
+		}
+	} else if d := f.decodeVMLDrawingReader(drawingVML); d != nil {
+		for _, shape := range d.Shape {
+			shapes = append(shapes, shape.Val)
+		}
+	}
+	var cells []string
+	for _, shape := range shapes {
+		var val decodeShapeVal
+		if err := xml.Unmarshal([]byte("<shape>"+shape+"</shape>"), &val); err != nil || val.ClientData == nil || val.ClientData.ObjectType != "Note" {
+			continue
+		}
+		cell, err := CoordinatesToCellName(val.ClientData.Column+1, val.ClientData.Row+1)
+		if err != nil {
+			continue
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+var (
+	vmlVisibilityRe      = regexp.MustCompile(`visibility\s*:\s*[A-Za-z]+`)
+	vmlVisibleRe         = regexp.MustCompile(`<(\w+:)?Visible\s*/>|<(\w+:)?Visible>\s*</(\w+:)?Visible>`)
+	vmlClientDataCloseRe = regexp.MustCompile(`</(\w+:)?ClientData>`)
+)
+
+// SetCommentVisible provides a function to set the comment of the cell
+// always be shown or only be shown when the mouse hovers over the cell by
+// given worksheet name, cell reference and visibility. The comments are
+// hidden by default. For example, always show the comment of Sheet1!$A$30:
 //
-//    err := f.AddComment("Sheet1", "A30", `{"author":"Excelize: ","text":"This is a comment."}`)
+//    err := f.SetCommentVisible("Sheet1", "A30", true)
 //
-func (f *File) AddComment(sheet, cell, format string) error {
-	formatSet, err := parseFormatCommentsSet(format)
+func (f *File) SetCommentVisible(sheet, cell string, visible bool) error {
+	if _, _, err := CellNameToCoordinates(cell); err != nil {
+		return err
+	}
+	count, err := f.setNotesVisible(sheet, cell, visible)
 	if err != nil {
 		return err
 	}
+	if count == 0 {
+		return fmt.Errorf("comment of the cell %s is not exist in the sheet %s", cell, sheet)
+	}
+	return err
+}
+
+// ShowAllComments provides a function to set all comments of the worksheet
+// always be shown by given worksheet name, which is useful for the printed
+// reports. For example:
+//
+//    err := f.ShowAllComments("Sheet1")
+//
+func (f *File) ShowAllComments(sheet string) error {
+	_, err := f.setNotesVisible(sheet, "", true)
+	return err
+}
+
+// setNotesVisible provides a function to set the visibility of the notes in
+// the VML drawing of the worksheet by given worksheet name, cell reference
+// and visibility. All notes of the worksheet will be set if the cell
+// reference is empty. It returns the count of the updated notes.
+func (f *File) setNotesVisible(sheet, cell string, visible bool) (int, error) {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil || xlsx.LegacyDrawing == nil {
+		return 0, err
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, xlsx.LegacyDrawing.RID)
+	drawingID, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(target, "../drawings/vmlDrawing"), ".vml"))
+	vml := f.vmlDrawingReader(strings.Replace(target, "..", "xl", 1), drawingID)
+	var count int
+	for idx, shape := range vml.Shape {
+		var val decodeShapeVal
+		if err := xml.Unmarshal([]byte("<shape>"+shape.Val+"</shape>"), &val); err != nil || val.ClientData == nil || val.ClientData.ObjectType != "Note" {
+			continue
+		}
+		if cell != "" {
+			ref, err := CoordinatesToCellName(val.ClientData.Column+1, val.ClientData.Row+1)
+			if err != nil || ref != strings.ToUpper(cell) {
+				continue
+			}
+		}
+		vml.Shape[idx] = setNoteShapeVisible(shape, visible)
+		count++
+	}
+	return count, err
+}
+
+// setNoteShapeVisible provides a function to set the visibility of the note
+// by given shape and visibility. The visibility will be stored both in the
+// style of the shape and the client data of the note.
+func setNoteShapeVisible(shape xlsxShape, visible bool) xlsxShape {
+	visibility := "visibility:hidden"
+	if visible {
+		visibility = "visibility:visible"
+	}
+	if vmlVisibilityRe.MatchString(shape.Style) {
+		shape.Style = vmlVisibilityRe.ReplaceAllString(shape.Style, visibility)
+	} else {
+		shape.Style = strings.TrimSuffix(shape.Style, ";") + ";" + visibility
+	}
+	shape.Val = vmlVisibleRe.ReplaceAllString(shape.Val, "")
+	if loc := vmlClientDataCloseRe.FindStringSubmatchIndex(shape.Val); visible && loc != nil {
+		var prefix string
+		if loc[2] >= 0 {
+			prefix = shape.Val[loc[2]:loc[3]]
+		}
+		shape.Val = shape.Val[:loc[0]] + "<" + prefix + "Visible></" + prefix + "Visible>" + shape.Val[loc[0]:]
+	}
+	return shape
+}
+
+// newRichTextRunFont provides a function to get the font settings of the
+// rich text run by given run properties.
+func newRichTextRunFont(rPr *xlsxRPr) *Font {
+	if rPr == nil {
+		return nil
+	}
+	font := Font{
+		Bold:   rPr.B != nil,
+		Italic: rPr.I != nil,
+		Strike: rPr.Strike != nil,
+	}
+	if rPr.U != nil {
+		font.Underline = "single"
+		if rPr.U.Val != nil {
+			font.Underline = *rPr.U.Val
+		}
+	}
+	if rPr.Sz != nil && rPr.Sz.Val != nil {
+		font.Size = *rPr.Sz.Val
+	}
+	if rPr.Color != nil && rPr.Color.RGB != "" {
+		font.Color = "#" + strings.TrimPrefix(rPr.Color.RGB, "FF")
+		if len(rPr.Color.RGB) != 8 {
+			font.Color = "#" + rPr.Color.RGB
+		}
+	}
+	if rPr.RFont != nil && rPr.RFont.Val != nil {
+		font.Family = *rPr.RFont.Val
+	}
+	return &font
+}
+
+// AddComment provides the method to add comment in a sheet by given worksheet
+// index, cell and comment options (such as author, text, rich text runs and
+// the size of the comment box). Note that the max author length is 255 and
+// the max text length is 32512. For example, add a comment in Sheet1!$A$30:
+//
+//    err := f.AddComment("Sheet1", "A30", &excelize.CommentOption{Author: "Excelize: ", Text: "This is a comment."})
+//
+// Add a rich text comment with the comment box size 200 x 100 pixels in
+// Sheet1!$B$30:
+//
+//    err := f.AddComment("Sheet1", "B30", &excelize.CommentOption{
+//        Author: "Excelize: ",
+//        Runs: []excelize.RichTextRun{
+//            {Text: "This is a "},
+//            {Text: "rich text", Font: &excelize.Font{Bold: true, Color: "#FF0000"}},
+//            {Text: " comment."},
+//        },
+//        Width:  200,
+//        Height: 100,
+//    })
+//
+func (f *File) AddComment(sheet, cell string, opts *CommentOption) error {
+	if opts == nil {
+		return errors.New("comment option can not be nil")
+	}
 	// Read sheet data.
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
 	commentID := f.countComments() + 1
+	if vmlID := f.countVMLDrawings() + 1; vmlID > commentID {
+		commentID = vmlID
+	}
 	drawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(commentID) + ".vml"
 	sheetRelationshipsComments := "../comments" + strconv.Itoa(commentID) + ".xml"
 	sheetRelationshipsDrawingVML := "../drawings/vmlDrawing" + strconv.Itoa(commentID) + ".vml"
@@ -99,6 +322,12 @@ func (f *File) AddComment(sheet, cell, format string) error {
 		sheetRelationshipsDrawingVML = f.getSheetRelationshipsTargetByID(sheet, xlsx.LegacyDrawing.RID)
 		commentID, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(sheetRelationshipsDrawingVML, "../drawings/vmlDrawing"), ".vml"))
 		drawingVML = strings.Replace(sheetRelationshipsDrawingVML, "..", "xl", -1)
+		if f.getSheetComments(sheet) == "" {
+			// The VML drawing of the worksheet is created by the form
+			// controls, add the comments relationships for it.
+			sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+			f.addRels(sheetRels, SourceRelationshipComments, "../comments"+strconv.Itoa(commentID)+".xml", "")
+		}
 	} else {
 		// Add first comment for given sheet.
 		sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
@@ -106,18 +335,29 @@ func (f *File) AddComment(sheet, cell, format string) error {
 		f.addRels(sheetRels, SourceRelationshipComments, sheetRelationshipsComments, "")
 		f.addSheetLegacyDrawing(sheet, rID)
 	}
+	threaded, err := f.hasThreadedComment(sheet, cell)
+	if err != nil {
+		return err
+	}
 	commentsXML := "xl/comments" + strconv.Itoa(commentID) + ".xml"
-	f.addComment(commentsXML, cell, formatSet)
+	f.addComment(commentsXML, cell, opts, threaded)
+	text := opts.Text
+	if len(opts.Runs) > 0 {
+		text = ""
+		for _, run := range opts.Runs {
+			text += run.Text
+		}
+	}
 	var colCount int
-	for i, l := range strings.Split(formatSet.Text, "\n") {
+	for i, l := range strings.Split(text, "\n") {
 		if ll := len(l); ll > colCount {
 			if i == 0 {
-				ll += len(formatSet.Author)
+				ll += len(opts.Author)
 			}
 			colCount = ll
 		}
 	}
-	err = f.addDrawingVML(commentID, drawingVML, cell, strings.Count(formatSet.Text, "\n")+1, colCount)
+	err = f.addDrawingVML(sheet, commentID, drawingVML, cell, strings.Count(text, "\n")+1, colCount, opts.Width, opts.Height)
 	if err != nil {
 		return err
 	}
@@ -126,43 +366,33 @@ func (f *File) AddComment(sheet, cell, format string) error {
 }
 
 // addDrawingVML provides a function to create comment as
-// xl/drawings/vmlDrawing%d.vml by given commit ID and cell.
-func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount, colCount int) error {
+// xl/drawings/vmlDrawing%d.vml by given commit ID, cell and the size in
+// pixels of the comment box. The size of the comment box will be calculated
+// by the line count and column count of the comment text if the width and
+// height are 0.
+func (f *File) addDrawingVML(sheet string, commentID int, drawingVML, cell string, lineCount, colCount, width, height int) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
 	}
 	yAxis := col - 1
 	xAxis := row - 1
-	vml := f.VMLDrawing[drawingVML]
-	if vml == nil {
-		vml = &vmlDrawing{
-			XMLNSv:  "urn:schemas-microsoft-com:vml",
-			XMLNSo:  "urn:schemas-microsoft-com:office:office",
-			XMLNSx:  "urn:schemas-microsoft-com:office:excel",
-			XMLNSmv: "http://macVmlSchemaUri",
-			Shapelayout: &xlsxShapelayout{
-				Ext: "edit",
-				IDmap: &xlsxIDmap{
-					Ext:  "edit",
-					Data: commentID,
-				},
-			},
-			Shapetype: &xlsxShapetype{
-				ID:        "_x0000_t202",
-				Coordsize: "21600,21600",
-				Spt:       202,
-				Path:      "m0,0l0,21600,21600,21600,21600,0xe",
-				Stroke: &xlsxStroke{
-					Joinstyle: "miter",
-				},
-				VPath: &vPath{
-					Gradientshapeok: "t",
-					Connecttype:     "miter",
-				},
-			},
+	anchor := fmt.Sprintf("%d, 23, %d, 0, %d, %d, %d, 5",
+		1+yAxis, 1+xAxis, 2+yAxis+lineCount, colCount+yAxis, 2+xAxis+lineCount)
+	style := "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden"
+	if width > 0 || height > 0 {
+		if width <= 0 {
+			width = 144
+		}
+		if height <= 0 {
+			height = 79
 		}
+		colStart, rowStart, _, _, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, col, xAxis, 23, 0, width, height)
+		anchor = fmt.Sprintf("%d, 23, %d, 0, %d, %d, %d, %d", colStart, rowStart, colEnd, x2, rowEnd, y2)
+		style = fmt.Sprintf("position:absolute;73.5pt;width:%gpt;height:%gpt;z-index:1;visibility:hidden", float64(width)*0.75, float64(height)*0.75)
 	}
+	vml := f.vmlDrawingReader(drawingVML, commentID)
+	vml.addShapetype("_x0000_t202")
 	sp := encodeShape{
 		Fill: &vFill{
 			Color2: "#fbfe82",
@@ -189,47 +419,181 @@ func (f *File) addDrawingVML(commentID int, drawingVML, cell string, lineCount,
 		},
 		ClientData: &xClientData{
 			ObjectType: "Note",
-			Anchor: fmt.Sprintf(
-				"%d, 23, %d, 0, %d, %d, %d, 5",
-				1+yAxis, 1+xAxis, 2+yAxis+lineCount, colCount+yAxis, 2+xAxis+lineCount),
-			AutoFill: "True",
-			Row:      xAxis,
-			Column:   yAxis,
+			Anchor:     anchor,
+			AutoFill:   "True",
+			Row:        xAxis,
+			Column:     yAxis,
 		},
 	}
 	s, _ := xml.Marshal(sp)
 	shape := xlsxShape{
-		ID:          "_x0000_s1025",
+		ID:          "_x0000_s" + strconv.Itoa(vml.nextShapeID(commentID)),
 		Type:        "#_x0000_t202",
-		Style:       "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden",
+		Style:       style,
 		Fillcolor:   "#fbf6d6",
 		Strokecolor: "#edeaa1",
 		Val:         string(s[13 : len(s)-14]),
 	}
-	d := f.decodeVMLDrawingReader(drawingVML)
-	if d != nil {
+	vml.Shape = append(vml.Shape, shape)
+	return err
+}
+
+// vmlDrawingReader provides a function to get the pointer to the structure
+// of xl/drawings/vmlDrawing%d.vml for adding shapes by given VML drawing
+// path and the index of the drawing, the existing shapes of the drawing will
+// be kept.
+func (f *File) vmlDrawingReader(drawingVML string, drawingID int) *vmlDrawing {
+	if vml := f.VMLDrawing[drawingVML]; vml != nil {
+		return vml
+	}
+	vml := &vmlDrawing{
+		XMLNSv:  "urn:schemas-microsoft-com:vml",
+		XMLNSo:  "urn:schemas-microsoft-com:office:office",
+		XMLNSx:  "urn:schemas-microsoft-com:office:excel",
+		XMLNSmv: "http://macVmlSchemaUri",
+		Shapelayout: &xlsxShapelayout{
+			Ext: "edit",
+			IDmap: &xlsxIDmap{
+				Ext:  "edit",
+				Data: drawingID,
+			},
+		},
+	}
+	if d := f.decodeVMLDrawingReader(drawingVML); d != nil {
 		for _, v := range d.Shape {
-			s := xlsxShape{
-				ID:          "_x0000_s1025",
-				Type:        "#_x0000_t202",
-				Style:       "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden",
-				Fillcolor:   "#fbf6d6",
-				Strokecolor: "#edeaa1",
+			shape := xlsxShape{
+				ID:          v.ID,
+				Spid:        v.Spid,
+				Type:        v.Type,
+				Style:       v.Style,
+				Filled:      v.Filled,
+				Fillcolor:   v.Fillcolor,
+				Stroked:     v.Stroked,
+				Insetmode:   v.Insetmode,
+				Strokecolor: v.Strokecolor,
 				Val:         v.Val,
 			}
-			vml.Shape = append(vml.Shape, s)
+			if shape.ID == "" {
+				shape.ID = "_x0000_s" + strconv.Itoa(vml.nextShapeID(drawingID))
+			}
+			if shape.Type == "" {
+				shape.Type = "#_x0000_t202"
+				shape.Fillcolor, shape.Strokecolor = "#fbf6d6", "#edeaa1"
+			}
+			if shape.Style == "" {
+				shape.Style = "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden"
+			}
+			vml.addShapetype(strings.TrimPrefix(shape.Type, "#"))
+			vml.Shape = append(vml.Shape, shape)
 		}
 	}
-	vml.Shape = append(vml.Shape, shape)
 	f.VMLDrawing[drawingVML] = vml
-	return err
+	return vml
+}
+
+// nextShapeID provides a function to get the next free shape ID in the VML
+// drawing by given index of the drawing. The shape IDs of the drawing start
+// from 1024 multiplied by the index of the drawing, and the IDs of the
+// existing shapes will be skipped.
+func (vml *vmlDrawing) nextShapeID(drawingID int) int {
+	shapeID := 1024 * drawingID
+	for _, shape := range vml.Shape {
+		for _, v := range []string{shape.ID, shape.Spid} {
+			if ID, _ := strconv.Atoi(strings.TrimPrefix(v, "_x0000_s")); ID > shapeID {
+				shapeID = ID
+			}
+		}
+	}
+	return shapeID + 1
+}
+
+// addShapetype provides a function to add the shape type definition to the
+// VML drawing by given shape type ID if it doesn't exist. The shape types of
+// the comment (_x0000_t202), the form control (_x0000_t201) and the picture
+// (_x0000_t75) are supported.
+func (vml *vmlDrawing) addShapetype(ID string) {
+	for _, shapetype := range vml.Shapetype {
+		if shapetype.ID == ID {
+			return
+		}
+	}
+	switch ID {
+	case "_x0000_t202":
+		vml.Shapetype = append(vml.Shapetype, xlsxShapetype{
+			ID:        ID,
+			Coordsize: "21600,21600",
+			Spt:       202,
+			Path:      "m0,0l0,21600,21600,21600,21600,0xe",
+			Stroke: &xlsxStroke{
+				Joinstyle: "miter",
+			},
+			VPath: &vPath{
+				Gradientshapeok: "t",
+				Connecttype:     "miter",
+			},
+		})
+	case "_x0000_t201":
+		vml.Shapetype = append(vml.Shapetype, xlsxShapetype{
+			ID:        ID,
+			Coordsize: "21600,21600",
+			Spt:       201,
+			Path:      "m,l,21600r21600,l21600,xe",
+			Stroke: &xlsxStroke{
+				Joinstyle: "miter",
+			},
+			VPath: &vPath{
+				Shadowok:    "f",
+				Extrusionok: "f",
+				Strokeok:    "f",
+				Fillok:      "f",
+				Connecttype: "rect",
+			},
+			Lock: &oLock{
+				Ext:       "edit",
+				Shapetype: "t",
+			},
+		})
+	case "_x0000_t75":
+		var formulas vFormulas
+		for _, eqn := range []string{
+			"if lineDrawn pixelLineWidth 0", "sum @0 1 0", "sum 0 0 @1",
+			"prod @2 1 2", "prod @3 21600 pixelWidth", "prod @3 21600 pixelHeight",
+			"sum @0 0 1", "prod @6 1 2", "prod @7 21600 pixelWidth",
+			"sum @8 21600 0", "prod @7 21600 pixelHeight", "sum @10 21600 0",
+		} {
+			formulas.Formula = append(formulas.Formula, vFormula{Eqn: eqn})
+		}
+		vml.Shapetype = append(vml.Shapetype, xlsxShapetype{
+			ID:             ID,
+			Coordsize:      "21600,21600",
+			Spt:            75,
+			Preferrelative: "t",
+			Path:           "m@4@5l@4@11@9@11@9@5xe",
+			Filled:         "f",
+			Stroked:        "f",
+			Stroke: &xlsxStroke{
+				Joinstyle: "miter",
+			},
+			Formulas: &formulas,
+			VPath: &vPath{
+				Extrusionok:     "f",
+				Gradientshapeok: "t",
+				Connecttype:     "rect",
+			},
+			Lock: &oLock{
+				Ext:         "edit",
+				Aspectratio: "t",
+			},
+		})
+	}
 }
 
 // addComment provides a function to create chart as xl/comments%d.xml by
-// given cell and format sets.
-func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
-	a := formatSet.Author
-	t := formatSet.Text
+// given cell and comment options. The comment is the placeholder of the
+// threaded comment if the threaded is true.
+func (f *File) addComment(commentsXML, cell string, opts *CommentOption, threaded bool) {
+	a := opts.Author
+	t := opts.Text
 	if len(a) > 255 {
 		a = a[0:255]
 	}
@@ -238,23 +602,27 @@ func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
 	}
 	comments := f.commentsReader(commentsXML)
 	if comments == nil {
-		comments = &xlsxComments{
-			Authors: []xlsxAuthor{
-				{
-					Author: formatSet.Author,
-				},
-			},
+		comments = &xlsxComments{}
+	}
+	authorID := len(comments.Authors)
+	for idx, author := range comments.Authors {
+		if author.Author == opts.Author {
+			authorID = idx
+			break
 		}
 	}
+	if authorID == len(comments.Authors) {
+		comments.Authors = append(comments.Authors, xlsxAuthor{Author: opts.Author})
+	}
 	defaultFont := f.GetDefaultFont()
 	cmt := xlsxComment{
 		Ref:      cell,
-		AuthorID: 0,
+		AuthorID: authorID,
 		Text: xlsxText{
 			R: []xlsxR{
 				{
 					RPr: &xlsxRPr{
-						B:  " ",
+						B:  stringPtr(""),
 						Sz: &attrValFloat{Val: float64Ptr(9)},
 						Color: &xlsxColor{
 							Indexed: 81,
@@ -264,24 +632,202 @@ func (f *File) addComment(commentsXML, cell string, formatSet *formatComment) {
 					},
 					T: a,
 				},
-				{
-					RPr: &xlsxRPr{
-						Sz: &attrValFloat{Val: float64Ptr(9)},
-						Color: &xlsxColor{
-							Indexed: 81,
-						},
-						RFont:  &attrValString{Val: stringPtr(defaultFont)},
-						Family: &attrValInt{Val: intPtr(2)},
-					},
-					T: t,
-				},
 			},
 		},
 	}
+	runs := opts.Runs
+	if len(runs) == 0 {
+		runs = []RichTextRun{{Text: t}}
+	}
+	// The author of the placeholder comment for the threaded comment
+	// shouldn't be displayed in the comment text.
+	if threaded {
+		cmt.Text.R = nil
+	}
+	for _, run := range runs {
+		cmt.Text.R = append(cmt.Text.R, xlsxR{RPr: newCommentRunProperties(run.Font, defaultFont), T: run.Text})
+	}
 	comments.CommentList.Comment = append(comments.CommentList.Comment, cmt)
 	f.Comments[commentsXML] = comments
 }
 
+// newCommentRunProperties provides a function to create the run properties of
+// the comment by given font settings and default font name.
+func newCommentRunProperties(font *Font, defaultFont string) *xlsxRPr {
+	rPr := &xlsxRPr{
+		Sz: &attrValFloat{Val: float64Ptr(9)},
+		Color: &xlsxColor{
+			Indexed: 81,
+		},
+		RFont:  &attrValString{Val: stringPtr(defaultFont)},
+		Family: &attrValInt{Val: intPtr(2)},
+	}
+	if font == nil {
+		return rPr
+	}
+	if font.Bold {
+		rPr.B = stringPtr("")
+	}
+	if font.Italic {
+		rPr.I = stringPtr("")
+	}
+	if font.Strike {
+		rPr.Strike = stringPtr("")
+	}
+	if font.Underline != "" {
+		rPr.U = &attrValString{Val: stringPtr(font.Underline)}
+	}
+	if font.Size > 0 {
+		rPr.Sz.Val = float64Ptr(font.Size)
+	}
+	if font.Color != "" {
+		rPr.Color = &xlsxColor{RGB: getPaletteColor(font.Color)}
+	}
+	if font.Family != "" {
+		rPr.RFont.Val = stringPtr(font.Family)
+	}
+	return rPr
+}
+
+// AddThreadedComment provides the method to add threaded comment in a sheet
+// by given worksheet name, cell and threaded comment settings. The threaded
+// comment will be added as the replies of the thread if the cell already has
+// a threaded comment. A legacy comment will be added as the placeholder of
+// the threaded comment for the applications which doesn't support threaded
+// comments. For example, add a threaded comment with a reply in
+// Sheet1!$A$30:
+//
+//    err := f.AddThreadedComment("Sheet1", "A30", excelize.ThreadedComment{
+//        Author: "Excelize",
+//        Text:   "Please review the value.",
+//        Replies: []excelize.ThreadedCommentReply{
+//            {Author: "Reviewer", Text: "Done."},
+//        },
+//    })
+//
+func (f *File) AddThreadedComment(sheet, cell string, comment ThreadedComment) error {
+	if _, _, err := CellNameToCoordinates(cell); err != nil {
+		return err
+	}
+	if comment.Author == "" {
+		return errors.New("the author of threaded comment can not be empty")
+	}
+	for _, reply := range comment.Replies {
+		if reply.Author == "" {
+			return errors.New("the author of threaded comment can not be empty")
+		}
+	}
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return err
+	}
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	threadedCommentsXML := strings.Replace(f.getSheetThreadedComments(sheet), "..", "xl", 1)
+	if threadedCommentsXML == "" {
+		threadedCommentID := f.countThreadedComments() + 1
+		threadedCommentsXML = "xl/threadedComments/threadedComment" + strconv.Itoa(threadedCommentID) + ".xml"
+		f.addRels(sheetRels, SourceRelationshipThreadedComment, "../threadedComments/threadedComment"+strconv.Itoa(threadedCommentID)+".xml", "")
+		f.addContentTypePart(threadedCommentID, "threadedComment")
+	}
+	threadedComments, err := f.threadedCommentsReader(threadedCommentsXML)
+	if err != nil {
+		return err
+	}
+	persons, err := f.personsReader()
+	if err != nil {
+		return err
+	}
+	var parentID string
+	for _, c := range threadedComments.ThreadedComment {
+		if c.Ref == cell && c.ParentID == "" {
+			parentID = c.ID
+			break
+		}
+	}
+	dT := time.Now().Format("2006-01-02T15:04:05.00")
+	entries := append([]ThreadedCommentReply{{Author: comment.Author, Text: comment.Text}}, comment.Replies...)
+	text := "[Threaded comment]\n\nYour version of Excel allows you to read this threaded comment; however, any edits to it will get removed if the file is opened in a newer version of Excel. Learn more: https://go.microsoft.com/fwlink/?linkid=870924\n\nComment:\n    " + comment.Text
+	placeholder := parentID == ""
+	for _, entry := range entries {
+		threadedComment := xlsxThreadedComment{
+			Ref:      cell,
+			DT:       dT,
+			PersonID: f.addPerson(persons, entry.Author),
+			ID:       genGUID(),
+			ParentID: parentID,
+			Text:     entry.Text,
+		}
+		if parentID == "" {
+			parentID = threadedComment.ID
+		} else if placeholder {
+			text += "\nReply:\n    " + entry.Text
+		}
+		threadedComments.ThreadedComment = append(threadedComments.ThreadedComment, threadedComment)
+	}
+	output, _ := xml.Marshal(threadedComments)
+	f.saveFileList(threadedCommentsXML, output)
+	if _, ok := f.XLSX["xl/persons/person.xml"]; !ok {
+		f.addRels("xl/_rels/workbook.xml.rels", SourceRelationshipPerson, "persons/person.xml", "")
+		f.addContentTypePart(0, "person")
+	}
+	output, _ = xml.Marshal(persons)
+	f.saveFileList("xl/persons/person.xml", output)
+	if !placeholder {
+		return err
+	}
+	return f.AddComment(sheet, cell, &CommentOption{Author: "tc=" + parentID, Text: text})
+}
+
+// threadedCommentsReader provides a function to get the pointer to the
+// structure after deserialization of xl/threadedComments/threadedComment%d.xml.
+func (f *File) threadedCommentsReader(path string) (*xlsxThreadedComments, error) {
+	threadedComments := xlsxThreadedComments{}
+	if content, ok := f.XLSX[path]; ok {
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+			Decode(&threadedComments); err != nil && err != io.EOF {
+			return &threadedComments, fmt.Errorf("xml decode error: %s", err)
+		}
+	}
+	return &threadedComments, nil
+}
+
+// personsReader provides a function to get the pointer to the structure after
+// deserialization of xl/persons/person.xml.
+func (f *File) personsReader() (*xlsxPersonList, error) {
+	persons := xlsxPersonList{}
+	if content, ok := f.XLSX["xl/persons/person.xml"]; ok {
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+			Decode(&persons); err != nil && err != io.EOF {
+			return &persons, fmt.Errorf("xml decode error: %s", err)
+		}
+	}
+	return &persons, nil
+}
+
+// addPerson provides a function to get the ID of the person by given person
+// list and display name, the person will be added if it doesn't exist.
+func (f *File) addPerson(persons *xlsxPersonList, name string) string {
+	for _, person := range persons.Person {
+		if person.DisplayName == name {
+			return person.ID
+		}
+	}
+	person := xlsxPerson{DisplayName: name, ID: genGUID(), UserID: name, ProviderID: "None"}
+	persons.Person = append(persons.Person, person)
+	return person.ID
+}
+
+// countThreadedComments provides a function to get threaded comments files
+// count storage in the folder xl/threadedComments.
+func (f *File) countThreadedComments() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.HasPrefix(k, "xl/threadedComments/threadedComment") {
+			count++
+		}
+	}
+	return count
+}
+
 // countComments provides a function to get comments files count storage in
 // the folder xl.
 func (f *File) countComments() int {
@@ -302,6 +848,26 @@ func (f *File) countComments() int {
 	return c1
 }
 
+// countVMLDrawings provides a function to get VML drawing files count storage
+// in the folder xl/drawings.
+func (f *File) countVMLDrawings() int {
+	c1, c2 := 0, 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/drawings/vmlDrawing") {
+			c1++
+		}
+	}
+	for rel := range f.VMLDrawing {
+		if strings.Contains(rel, "xl/drawings/vmlDrawing") {
+			c2++
+		}
+	}
+	if c1 < c2 {
+		return c2
+	}
+	return c1
+}
+
 // decodeVMLDrawingReader provides a function to get the pointer to the
 // structure after deserialization of xl/drawings/vmlDrawing%d.xml.
 func (f *File) decodeVMLDrawingReader(path string) *decodeVmlDrawing {
@@ -24,15 +24,213 @@ func TestAddComments(t *testing.T) {
 	}
 
 	s := strings.Repeat("c", 32768)
-	assert.NoError(t, f.AddComment("Sheet1", "A30", `{"author":"`+s+`","text":"`+s+`"}`))
-	assert.NoError(t, f.AddComment("Sheet2", "B7", `{"author":"Excelize: ","text":"This is a comment."}`))
+	assert.NoError(t, f.AddComment("Sheet1", "A30", &CommentOption{Author: s, Text: s}))
+	assert.NoError(t, f.AddComment("Sheet2", "B7", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
 
 	// Test add comment on not exists worksheet.
-	assert.EqualError(t, f.AddComment("SheetN", "B7", `{"author":"Excelize: ","text":"This is a comment."}`), "sheet SheetN is not exist")
+	assert.EqualError(t, f.AddComment("SheetN", "B7", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}), "sheet SheetN is not exist")
 
 	if assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddComments.xlsx"))) {
-		assert.Len(t, f.GetComments(), 2)
+		comments, err := f.GetComments("Sheet1")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, comments)
+		comments, err = f.GetComments("Sheet2")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, comments)
 	}
+	// Test get comments on not exists worksheet.
+	_, err = f.GetComments("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestAddRichTextComment(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	assert.NoError(t, f.AddComment("Sheet1", "C3", &CommentOption{
+		Author: "Excelize: ",
+		Runs: []RichTextRun{
+			{Text: "This is a "},
+			{Text: "rich text", Font: &Font{Bold: true, Italic: true, Strike: true, Underline: "single", Family: "Times New Roman", Size: 12, Color: "#FF0000"}},
+			{Text: " comment."},
+		},
+		Width:  200,
+		Height: 100,
+	}))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook and check the comments.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	comments := f.commentsReader("xl/comments1.xml")
+	if assert.NotNil(t, comments) && assert.Len(t, comments.CommentList.Comment, 2) {
+		runs := comments.CommentList.Comment[1].Text.R
+		if assert.Len(t, runs, 4) {
+			assert.Equal(t, "rich text", runs[2].T)
+			assert.Equal(t, "FFFF0000", runs[2].RPr.Color.RGB)
+			assert.Equal(t, 12.0, *runs[2].RPr.Sz.Val)
+			assert.Equal(t, "Times New Roman", *runs[2].RPr.RFont.Val)
+			assert.Equal(t, "single", *runs[2].RPr.U.Val)
+			assert.NotNil(t, runs[2].RPr.I)
+			assert.Nil(t, runs[1].RPr.B)
+		}
+	}
+	sheetComments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, sheetComments, 2) {
+		assert.Equal(t, "C3", sheetComments[1].Ref)
+		assert.Equal(t, "Excelize: This is a rich text comment.", sheetComments[1].Text)
+		if assert.Len(t, sheetComments[1].Runs, 4) {
+			assert.Equal(t, &Font{Bold: true, Size: 9, Family: "Calibri"}, sheetComments[1].Runs[0].Font)
+			assert.Equal(t, &Font{Bold: true, Italic: true, Strike: true, Underline: "single", Size: 12, Color: "#FF0000", Family: "Times New Roman"}, sheetComments[1].Runs[2].Font)
+		}
+	}
+	vml := f.decodeVMLDrawingReader("xl/drawings/vmlDrawing1.vml")
+	if assert.NotNil(t, vml) && assert.Len(t, vml.Shape, 2) {
+		assert.Equal(t, "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden", vml.Shape[0].Style)
+		assert.Equal(t, "position:absolute;73.5pt;width:150pt;height:75pt;z-index:1;visibility:hidden", vml.Shape[1].Style)
+		assert.True(t, strings.Contains(vml.Shape[1].Val, "<x:Anchor>3, 23, 2, 0, 6, 31, 7, 0</x:Anchor>"), vml.Shape[1].Val)
+	}
+	// Test add comment after reopen preserve the size of the existing comments.
+	assert.NoError(t, f.AddComment("Sheet1", "E5", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	vml = f.decodeVMLDrawingReader("xl/drawings/vmlDrawing1.vml")
+	if assert.NotNil(t, vml) && assert.Len(t, vml.Shape, 3) {
+		assert.Equal(t, "position:absolute;73.5pt;width:150pt;height:75pt;z-index:1;visibility:hidden", vml.Shape[1].Style)
+	}
+	sheetComments, err = f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, sheetComments, 3)
+	// Test resolve the cell reference of the comment by the VML drawing.
+	f.Comments["xl/comments1.xml"].CommentList.Comment[2].Ref = ""
+	sheetComments, err = f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, sheetComments, 3) {
+		assert.Equal(t, "E5", sheetComments[2].Ref)
+	}
+	// Test get comments on the worksheet without comments.
+	f.NewSheet("Sheet2")
+	sheetComments, err = f.GetComments("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, sheetComments, 0)
+}
+
+func TestAddThreadedComment(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddThreadedComment("Sheet1", "A1", ThreadedComment{
+		Author:  "Excelize",
+		Text:    "Threaded comment",
+		Replies: []ThreadedCommentReply{{Author: "Reviewer", Text: "Reply"}},
+	}))
+	assert.NoError(t, f.AddThreadedComment("Sheet1", "A1", ThreadedComment{Author: "Excelize", Text: "Second reply"}))
+	assert.NoError(t, f.AddThreadedComment("Sheet1", "B1", ThreadedComment{Author: "Reviewer", Text: "Another thread"}))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+
+	threadedComments, err := f.threadedCommentsReader("xl/threadedComments/threadedComment1.xml")
+	assert.NoError(t, err)
+	if assert.Len(t, threadedComments.ThreadedComment, 4) {
+		root := threadedComments.ThreadedComment[0]
+		assert.Equal(t, "", root.ParentID)
+		assert.Equal(t, "Threaded comment", root.Text)
+		assert.Equal(t, root.ID, threadedComments.ThreadedComment[1].ParentID)
+		assert.Equal(t, root.ID, threadedComments.ThreadedComment[2].ParentID)
+		assert.Equal(t, "", threadedComments.ThreadedComment[3].ParentID)
+		assert.Equal(t, "B1", threadedComments.ThreadedComment[3].Ref)
+	}
+	persons, err := f.personsReader()
+	assert.NoError(t, err)
+	if assert.Len(t, persons.Person, 2) {
+		assert.Equal(t, persons.Person[0].ID, threadedComments.ThreadedComment[0].PersonID)
+		assert.Equal(t, persons.Person[1].ID, threadedComments.ThreadedComment[1].PersonID)
+	}
+	assert.Contains(t, string(f.XLSX["[Content_Types].xml"]), ContentTypeSpreadSheetMLThreadedComments)
+	assert.Contains(t, string(f.XLSX["[Content_Types].xml"]), ContentTypeSpreadSheetMLPerson)
+	assert.Contains(t, string(f.XLSX["xl/_rels/workbook.xml.rels"]), SourceRelationshipPerson)
+	assert.Contains(t, string(f.XLSX["xl/worksheets/_rels/sheet1.xml.rels"]), SourceRelationshipThreadedComment)
+	// Test the placeholder comments for the threaded comments.
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 2) {
+		assert.Equal(t, "tc="+threadedComments.ThreadedComment[0].ID, comments[0].Author)
+		assert.Contains(t, comments[0].Text, "Comment:\n    Threaded comment\nReply:\n    Reply")
+	}
+
+	assert.EqualError(t, f.AddThreadedComment("Sheet1", "A", ThreadedComment{Author: "Excelize"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.AddThreadedComment("Sheet1", "A1", ThreadedComment{}), "the author of threaded comment can not be empty")
+	assert.EqualError(t, f.AddThreadedComment("Sheet1", "A1", ThreadedComment{Author: "Excelize", Replies: []ThreadedCommentReply{{}}}), "the author of threaded comment can not be empty")
+	assert.EqualError(t, f.AddThreadedComment("SheetN", "A1", ThreadedComment{Author: "Excelize"}), "sheet SheetN is not exist")
+	f.XLSX["xl/threadedComments/threadedComment1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.AddThreadedComment("Sheet1", "A1", ThreadedComment{Author: "Excelize"}), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "Excelize", Text: "Comment"}), "xml decode error: XML syntax error on line 1: invalid UTF-8")
+	// Test read the persons without creating the persons part.
+	f = NewFile()
+	persons, err = f.personsReader()
+	assert.NoError(t, err)
+	assert.Empty(t, persons.Person)
+	assert.NotContains(t, f.XLSX, "xl/persons/person.xml")
+	assert.NotContains(t, string(f.XLSX["xl/_rels/workbook.xml.rels"]), SourceRelationshipPerson)
+	// Test add the comment with the author beginning with "tc=" on the cell
+	// without threaded comment.
+	assert.NoError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "tc=Excelize", Text: "Comment"}))
+	comments, err = f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.Equal(t, "tc=Excelize", comments[0].Author)
+		assert.Equal(t, "tc=ExcelizeComment", comments[0].Text)
+	}
+}
+
+func TestSetCommentVisible(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	assert.NoError(t, f.AddComment("Sheet1", "C3", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	assert.NoError(t, f.SetCommentVisible("Sheet1", "c3", true))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook and check the visibility of the comments.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	vml := f.decodeVMLDrawingReader("xl/drawings/vmlDrawing1.vml")
+	if assert.NotNil(t, vml) && assert.Len(t, vml.Shape, 2) {
+		assert.Equal(t, "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden", vml.Shape[0].Style)
+		assert.NotContains(t, vml.Shape[0].Val, "Visible")
+		assert.Equal(t, "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:visible", vml.Shape[1].Style)
+		assert.Contains(t, vml.Shape[1].Val, "<x:Column>2</x:Column><x:Visible></x:Visible></x:ClientData>")
+		assert.Contains(t, vml.Shape[1].Val, "<x:Anchor>3, 23, 3, 0, 5, 30, 5, 5</x:Anchor>")
+	}
+	// Test show all comments and hide the comment again.
+	assert.NoError(t, f.ShowAllComments("Sheet1"))
+	assert.NoError(t, f.SetCommentVisible("Sheet1", "C3", false))
+	shapes := f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape
+	if assert.Len(t, shapes, 2) {
+		assert.Equal(t, "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:visible", shapes[0].Style)
+		assert.Contains(t, shapes[0].Val, "<x:Visible></x:Visible></x:ClientData>")
+		assert.Equal(t, "position:absolute;73.5pt;width:108pt;height:59.25pt;z-index:1;visibility:hidden", shapes[1].Style)
+		assert.NotContains(t, shapes[1].Val, "Visible")
+	}
+	// Test set the visibility of the note without the visibility style.
+	shape := setNoteShapeVisible(xlsxShape{Style: "position:absolute;", Val: "<x:ClientData ObjectType=\"Note\"><x:Visible/></x:ClientData>"}, false)
+	assert.Equal(t, xlsxShape{Style: "position:absolute;visibility:hidden", Val: "<x:ClientData ObjectType=\"Note\"></x:ClientData>"}, shape)
+	shape = setNoteShapeVisible(xlsxShape{Val: "<ClientData ObjectType=\"Note\"></ClientData>"}, true)
+	assert.Equal(t, "<ClientData ObjectType=\"Note\"><Visible></Visible></ClientData>", shape.Val)
+
+	// Test set the visibility of the comment on the cell without comment.
+	assert.EqualError(t, f.SetCommentVisible("Sheet1", "B2", true), "comment of the cell B2 is not exist in the sheet Sheet1")
+	f.NewSheet("Sheet2")
+	assert.EqualError(t, f.SetCommentVisible("Sheet2", "A1", true), "comment of the cell A1 is not exist in the sheet Sheet2")
+	assert.NoError(t, f.ShowAllComments("Sheet2"))
+	// Test set the visibility of the comment with invalid cell reference.
+	assert.EqualError(t, f.SetCommentVisible("Sheet1", "A", true), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	// Test set the visibility of the comment on not exists worksheet.
+	assert.EqualError(t, f.SetCommentVisible("SheetN", "A1", true), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ShowAllComments("SheetN"), "sheet SheetN is not exist")
 }
 
 func TestDecodeVMLDrawingReader(t *testing.T) {
@@ -0,0 +1,276 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormControlType is the type of the form control.
+type FormControlType byte
+
+// This section defines the currently supported form control types
+// enumeration.
+const (
+	FormControlButton FormControlType = iota
+	FormControlCheckBox
+	FormControlOptionButton
+)
+
+// formControlTypes defined the object type in the VML drawing, the object
+// type in the control properties and the default name of the form controls.
+var formControlTypes = map[FormControlType][3]string{
+	FormControlButton:       {"Button", "Button", "Button"},
+	FormControlCheckBox:     {"Checkbox", "CheckBox", "Check Box"},
+	FormControlOptionButton: {"Radio", "Radio", "Option Button"},
+}
+
+// FormControl directly maps the settings of the form control. Cell specifies
+// the top left cell of the control, Text specifies the caption of the
+// control, Macro specifies the name of the macro which will be run when the
+// button is clicked, CellLink specifies the cell which the value of the check
+// box or the option button is linked to, Checked specifies the initial state
+// of the check box or the option button, Width and Height specifies the size
+// of the control in pixels.
+type FormControl struct {
+	Cell     string
+	Type     FormControlType
+	Text     string
+	Macro    string
+	CellLink string
+	Checked  bool
+	Width    int
+	Height   int
+}
+
+// AddFormControl provides the method to add the form control, such as the
+// button, the check box and the option button on the worksheet by given
+// worksheet name and the settings of the form control. The button can be
+// assigned a macro, so the file extension should be .xlsm in that case. The
+// check box and the option button can be linked to a cell which stores the
+// state of the control. For example, add a check box linked to the cell A1
+// at the cell B1 on Sheet1:
+//
+//    err := f.AddFormControl("Sheet1", excelize.FormControl{
+//        Cell:     "B1",
+//        Type:     excelize.FormControlCheckBox,
+//        Text:     "Check Box 1",
+//        CellLink: "A1",
+//        Checked:  true,
+//    })
+//
+// Add a button at the cell D1 which runs the macro Button1_Click:
+//
+//    err := f.AddFormControl("Sheet1", excelize.FormControl{
+//        Cell:  "D1",
+//        Type:  excelize.FormControlButton,
+//        Text:  "Run",
+//        Macro: "Button1_Click",
+//    })
+//
+func (f *File) AddFormControl(sheet string, ctrl FormControl) error {
+	objectTypes, ok := formControlTypes[ctrl.Type]
+	if !ok {
+		return errors.New("unsupported form control type")
+	}
+	col, row, err := CellNameToCoordinates(ctrl.Cell)
+	if err != nil {
+		return err
+	}
+	var cellLink string
+	if ctrl.Type != FormControlButton && ctrl.CellLink != "" {
+		if cellLink, err = formControlCellLink(ctrl.CellLink); err != nil {
+			return err
+		}
+	}
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ctrl.Width <= 0 {
+		ctrl.Width = 96
+	}
+	if ctrl.Height <= 0 {
+		ctrl.Height = 20
+		if ctrl.Type == FormControlButton {
+			ctrl.Height = 32
+		}
+	}
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	vmlID := f.countComments() + 1
+	if id := f.countVMLDrawings() + 1; id > vmlID {
+		vmlID = id
+	}
+	drawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(vmlID) + ".vml"
+	if xlsx.LegacyDrawing != nil {
+		target := f.getSheetRelationshipsTargetByID(sheet, xlsx.LegacyDrawing.RID)
+		vmlID, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(target, "../drawings/vmlDrawing"), ".vml"))
+		drawingVML = strings.Replace(target, "..", "xl", -1)
+	} else {
+		rID := f.addRels(sheetRels, SourceRelationshipDrawingVML, "../drawings/vmlDrawing"+strconv.Itoa(vmlID)+".vml", "")
+		f.addSheetLegacyDrawing(sheet, rID)
+	}
+	f.setContentTypePartVMLExtensions()
+	vml := f.vmlDrawingReader(drawingVML, vmlID)
+	vml.addShapetype("_x0000_t201")
+	shapeID, firstButton := vml.nextShapeID(vmlID), true
+	for _, shape := range vml.Shape {
+		if strings.Contains(shape.Val, `ObjectType="Radio"`) {
+			firstButton = false
+		}
+	}
+	name := objectTypes[2] + " " + strconv.Itoa(shapeID-1024*vmlID)
+	if ctrl.Text == "" {
+		ctrl.Text = name
+	}
+	colStart, rowStart, xAbs, yAbs, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, col-1, row-1, 0, 0, ctrl.Width, ctrl.Height)
+	vml.Shape = append(vml.Shape, newFormControlShape(ctrl, objectTypes[0], shapeID,
+		fmt.Sprintf("%d, 0, %d, 0, %d, %d, %d, %d", colStart, rowStart, colEnd, x2, rowEnd, y2),
+		fmt.Sprintf("position:absolute;margin-left:%gpt;margin-top:%gpt;width:%gpt;height:%gpt;z-index:%d;mso-wrap-style:tight",
+			float64(xAbs)*0.75, float64(yAbs)*0.75, float64(ctrl.Width)*0.75, float64(ctrl.Height)*0.75, len(vml.Shape)+1),
+		cellLink, firstButton))
+	// Add the control properties part and the controls element of the
+	// worksheet for the form control.
+	ctrlPropID := f.countCtrlProps() + 1
+	ctrlProp := xlsxFormControlPr{ObjectType: objectTypes[1], FmlaLink: cellLink, LockText: true}
+	if ctrl.Type != FormControlButton {
+		ctrlProp.NoThreeD = true
+		if ctrl.Checked {
+			ctrlProp.Checked = "Checked"
+		}
+		ctrlProp.FirstButton = ctrl.Type == FormControlOptionButton && firstButton
+	}
+	output, _ := xml.Marshal(ctrlProp)
+	f.saveFileList("xl/ctrlProps/ctrlProp"+strconv.Itoa(ctrlPropID)+".xml", output)
+	f.addContentTypePart(ctrlPropID, "ctrlProp")
+	rID := f.addRels(sheetRels, SourceRelationshipCtrlProp, "../ctrlProps/ctrlProp"+strconv.Itoa(ctrlPropID)+".xml", "")
+	control := xlsxControl{
+		ShapeID: shapeID,
+		RID:     "rId" + strconv.Itoa(rID),
+		Name:    name,
+		ControlPr: &xlsxControlPr{
+			Macro: formControlMacro(ctrl),
+			Anchor: &xlsxControlAnchor{
+				MoveWithCells: true,
+				From:          xlsxFrom{Col: colStart, Row: rowStart},
+				To:            xlsxTo{Col: colEnd, ColOff: x2 * EMU, Row: rowEnd, RowOff: y2 * EMU},
+			},
+		},
+	}
+	output, _ = xml.Marshal(control)
+	if xlsx.Controls == nil {
+		xlsx.Controls = &xlsxInnerXML{}
+	}
+	xlsx.Controls.Content += `<mc:AlternateContent xmlns:mc="` + SourceRelationshipCompatibility +
+		`"><mc:Choice Requires="x14">` + string(output) + `</mc:Choice></mc:AlternateContent>`
+	return err
+}
+
+// newFormControlShape provides a function to create the shape of the form
+// control in the VML drawing by given settings of the form control, object
+// type, shape ID, anchor, style and the linked cell.
+func newFormControlShape(ctrl FormControl, objectType string, shapeID int, anchor, style, cellLink string, firstButton bool) xlsxShape {
+	sp := encodeFormControl{
+		Path: &vPath{Shadowok: "t", Strokeok: "t", Fillok: "t"},
+		Lock: &oLock{Ext: "edit", Rotation: "t"},
+		Textbox: &vTextbox{
+			Style:       "mso-direction-alt:auto",
+			Singleclick: "f",
+			Div: &xlsxDiv{
+				Style: "text-align:left",
+				Font:  &vmlTextFont{Face: "Tahoma", Size: 160, Color: "auto", Content: ctrl.Text},
+			},
+		},
+		ClientData: &xFormControlClientData{
+			ObjectType: objectType,
+			Anchor:     anchor,
+			AutoFill:   "False",
+			TextVAlign: "Center",
+		},
+	}
+	shape := xlsxShape{
+		ID:        "_x0000_s" + strconv.Itoa(shapeID),
+		Type:      "#_x0000_t201",
+		Style:     style,
+		Insetmode: "auto",
+	}
+	if ctrl.Type == FormControlButton {
+		sp.Fill = &vFill{Color2: "buttonFace [67]"}
+		sp.Textbox.Div.Style = "text-align:center"
+		sp.ClientData.PrintObject = "False"
+		sp.ClientData.FmlaMacro = formControlMacro(ctrl)
+		sp.ClientData.TextHAlign = "Center"
+		shape.Fillcolor, shape.Strokecolor = "buttonFace [67]", "windowText [64]"
+	} else {
+		sp.ClientData.AutoLine = "False"
+		sp.ClientData.FmlaLink = cellLink
+		if ctrl.Checked {
+			sp.ClientData.Checked = 1
+		}
+		if ctrl.Type == FormControlOptionButton && firstButton {
+			sp.ClientData.FirstButton = &struct{}{}
+		}
+		sp.ClientData.NoThreeD = &struct{}{}
+		shape.Filled, shape.Fillcolor = "f", "window [65]"
+		shape.Stroked, shape.Strokecolor = "f", "windowText [64]"
+	}
+	s, _ := xml.Marshal(sp)
+	shape.Val = string(s[7 : len(s)-8])
+	return shape
+}
+
+// formControlMacro provides a function to get the macro reference of the
+// button form control by given settings of the form control.
+func formControlMacro(ctrl FormControl) string {
+	if ctrl.Type != FormControlButton || ctrl.Macro == "" {
+		return ""
+	}
+	if strings.Contains(ctrl.Macro, "!") {
+		return ctrl.Macro
+	}
+	return "[0]!" + ctrl.Macro
+}
+
+// formControlCellLink provides a function to convert the linked cell of the
+// form control to the absolute reference, the worksheet name can be
+// specified in the linked cell, such as "Sheet2!A1".
+func formControlCellLink(cellLink string) (string, error) {
+	var sheetName string
+	cell := cellLink
+	if idx := strings.LastIndex(cellLink, "!"); idx != -1 {
+		sheetName, cell = cellLink[:idx+1], cellLink[idx+1:]
+	}
+	col, row, err := CellNameToCoordinates(strings.Replace(cell, "$", "", -1))
+	if err != nil {
+		return "", err
+	}
+	cell, err = CoordinatesToCellName(col, row)
+	if err != nil {
+		return "", err
+	}
+	colName := strings.TrimRight(cell, "0123456789")
+	return sheetName + "$" + colName + "$" + strings.TrimPrefix(cell, colName), err
+}
+
+// countCtrlProps provides a function to get control properties files count
+// storage in the folder xl/ctrlProps.
+func (f *File) countCtrlProps() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/ctrlProps/ctrlProp") {
+			count++
+		}
+	}
+	return count
+}
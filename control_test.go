@@ -0,0 +1,104 @@
+package excelize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFormControl(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddFormControl("Sheet1", FormControl{
+		Cell:     "B1",
+		Type:     FormControlCheckBox,
+		Text:     "Check Box 1",
+		CellLink: "A1",
+		Checked:  true,
+	}))
+	assert.NoError(t, f.AddFormControl("Sheet1", FormControl{Cell: "B3", Type: FormControlOptionButton, CellLink: "Sheet1!$A3"}))
+	assert.NoError(t, f.AddFormControl("Sheet1", FormControl{Cell: "B4", Type: FormControlOptionButton, CellLink: "A3"}))
+	assert.NoError(t, f.AddFormControl("Sheet1", FormControl{Cell: "D1", Type: FormControlButton, Text: "Run", Macro: "Button1_Click", Width: 140, Height: 60}))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+
+	// Test add form control with invalid settings.
+	assert.EqualError(t, f.AddFormControl("Sheet1", FormControl{Cell: "A1", Type: 0xFF}), "unsupported form control type")
+	assert.EqualError(t, f.AddFormControl("Sheet1", FormControl{Cell: "A", Type: FormControlCheckBox}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.AddFormControl("Sheet1", FormControl{Cell: "A1", Type: FormControlCheckBox, CellLink: "Sheet1!A"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.AddFormControl("SheetN", FormControl{Cell: "A1", Type: FormControlCheckBox}), "sheet SheetN is not exist")
+
+	// Test the form controls are bound to the cells after reopen the workbook.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	vml := string(f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	assert.Contains(t, vml, `<v:shapetype id="_x0000_t201"`)
+	assert.Contains(t, vml, `<v:shapetype id="_x0000_t202"`)
+	assert.Contains(t, vml, `<x:ClientData ObjectType="Checkbox"><x:Anchor>1, 0, 0, 0, 2, 32, 1, 0</x:Anchor><x:AutoFill>False</x:AutoFill><x:AutoLine>False</x:AutoLine><x:TextVAlign>Center</x:TextVAlign><x:FmlaLink>$A$1</x:FmlaLink><x:Checked>1</x:Checked><x:NoThreeD></x:NoThreeD></x:ClientData>`)
+	assert.Contains(t, vml, `<x:FmlaLink>Sheet1!$A$3</x:FmlaLink><x:FirstButton></x:FirstButton>`)
+	assert.Equal(t, 1, strings.Count(vml, "<x:FirstButton>"))
+	assert.Contains(t, vml, `<x:FmlaMacro>[0]!Button1_Click</x:FmlaMacro>`)
+	assert.Contains(t, vml, `<font face="Tahoma" size="160" color="auto">Run</font>`)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<formControlPr xmlns="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" objectType="CheckBox" checked="Checked" fmlaLink="$A$1" lockText="true" noThreeD="true"></formControlPr>`, string(f.XLSX["xl/ctrlProps/ctrlProp1.xml"]))
+	assert.Contains(t, string(f.XLSX["xl/ctrlProps/ctrlProp2.xml"]), `objectType="Radio" firstButton="true" fmlaLink="Sheet1!$A$3"`)
+	assert.Contains(t, string(f.XLSX["[Content_Types].xml"]), `<Override PartName="/xl/ctrlProps/ctrlProp4.xml" ContentType="application/vnd.ms-excel.controlproperties+xml"></Override>`)
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(xlsx.Controls.Content, "<control "))
+	assert.Contains(t, xlsx.Controls.Content, `<control shapeId="1028" r:id="rId5" name="Button 4"><controlPr defaultSize="false" autoFill="false" autoLine="false" autoPict="false" macro="[0]!Button1_Click">`)
+	assert.Equal(t, "../ctrlProps/ctrlProp4.xml", f.getSheetRelationshipsTargetByID("Sheet1", "rId5"))
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	// Test add form control on the worksheet with existing form controls.
+	assert.NoError(t, f.AddFormControl("Sheet1", FormControl{Cell: "B6", Type: FormControlCheckBox}))
+	vmlDrawing := f.VMLDrawing["xl/drawings/vmlDrawing1.vml"]
+	assert.Len(t, vmlDrawing.Shapetype, 2)
+	assert.Len(t, vmlDrawing.Shape, 6)
+	assert.Equal(t, "_x0000_s1029", vmlDrawing.Shape[4].ID)
+	assert.Equal(t, "_x0000_s1030", vmlDrawing.Shape[5].ID)
+	assert.Contains(t, vmlDrawing.Shape[5].Val, "Check Box 6")
+
+	// Test add form control on the new worksheet after the comments.
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddFormControl("Sheet2", FormControl{Cell: "A1", Type: FormControlButton}))
+	assert.Contains(t, f.VMLDrawing, "xl/drawings/vmlDrawing2.vml")
+	assert.Equal(t, "_x0000_s2049", f.VMLDrawing["xl/drawings/vmlDrawing2.vml"].Shape[0].ID)
+	assert.NoError(t, f.AddComment("Sheet2", "A1", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	assert.Equal(t, "../comments2.xml", f.getSheetComments("Sheet2"))
+	assert.Equal(t, "_x0000_s2050", f.VMLDrawing["xl/drawings/vmlDrawing2.vml"].Shape[1].ID)
+
+	// Test the shape IDs of the comments and the form controls are unique.
+	f = NewFile()
+	assert.NoError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	assert.NoError(t, f.AddFormControl("Sheet1", FormControl{Cell: "B1", Type: FormControlButton}))
+	assert.NoError(t, f.AddComment("Sheet1", "A2", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
+	shapeIDs := []string{}
+	for _, shape := range f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape {
+		shapeIDs = append(shapeIDs, shape.ID)
+	}
+	assert.Equal(t, []string{"_x0000_s1025", "_x0000_s1026", "_x0000_s1027"}, shapeIDs)
+	xlsx, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Contains(t, xlsx.Controls.Content, `<control shapeId="1026"`)
+}
+
+func TestFormControlCellLink(t *testing.T) {
+	for cellLink, expected := range map[string]string{
+		"A1":          "$A$1",
+		"$AB$12":      "$AB$12",
+		"Sheet1!b2":   "Sheet1!$B$2",
+		"'S!1'!$C3":   "'S!1'!$C$3",
+		"'Sheet 1'!D": "",
+	} {
+		result, err := formControlCellLink(cellLink)
+		if expected == "" {
+			assert.Error(t, err, cellLink)
+			continue
+		}
+		assert.NoError(t, err, cellLink)
+		assert.Equal(t, expected, result, cellLink)
+	}
+}
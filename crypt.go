@@ -0,0 +1,429 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+var (
+	blockKeyVerifierHashInput = []byte{0xfe, 0xa7, 0xd2, 0x76, 0x3b, 0x4b, 0x9e, 0x79}
+	blockKeyVerifierHashValue = []byte{0xd7, 0xaa, 0x0f, 0x6d, 0x30, 0x61, 0x34, 0x4e}
+	blockKeyEncryptedKeyValue = []byte{0x14, 0x6e, 0x0b, 0xe7, 0xab, 0xac, 0xd0, 0xd6}
+	errWorkbookPassword       = errors.New("the supplied open workbook password is not correct")
+	errCompoundFile           = errors.New("invalid compound file")
+)
+
+const (
+	cfbEndOfChain         = 0xFFFFFFFE
+	cfbMaxRegularSector   = 0xFFFFFFFA
+	packageSegmentLength  = 4096
+	packageSizeFieldBytes = 8
+)
+
+// encryption directly maps the encryption element of the agile encryption
+// descriptor in the EncryptionInfo stream of the encrypted workbook.
+type encryption struct {
+	XMLName       xml.Name          `xml:"encryption"`
+	KeyData       encryptionKeyData `xml:"keyData"`
+	KeyEncryptors struct {
+		KeyEncryptor []struct {
+			URI          string                `xml:"uri,attr"`
+			EncryptedKey encryptionPasswordKey `xml:"encryptedKey"`
+		} `xml:"keyEncryptor"`
+	} `xml:"keyEncryptors"`
+}
+
+// encryptionKeyData directly maps the keyData element, which specifies the
+// cryptographic attributes used to encrypt the package.
+type encryptionKeyData struct {
+	SaltSize        int    `xml:"saltSize,attr"`
+	BlockSize       int    `xml:"blockSize,attr"`
+	KeyBits         int    `xml:"keyBits,attr"`
+	HashSize        int    `xml:"hashSize,attr"`
+	CipherAlgorithm string `xml:"cipherAlgorithm,attr"`
+	CipherChaining  string `xml:"cipherChaining,attr"`
+	HashAlgorithm   string `xml:"hashAlgorithm,attr"`
+	SaltValue       string `xml:"saltValue,attr"`
+}
+
+// encryptionPasswordKey directly maps the encryptedKey element of the
+// password key encryptor, which is used to generate the secret key from the
+// password.
+type encryptionPasswordKey struct {
+	encryptionKeyData
+	SpinCount                  int    `xml:"spinCount,attr"`
+	EncryptedVerifierHashInput string `xml:"encryptedVerifierHashInput,attr"`
+	EncryptedVerifierHashValue string `xml:"encryptedVerifierHashValue,attr"`
+	EncryptedKeyValue          string `xml:"encryptedKeyValue,attr"`
+}
+
+// decrypt provides a function to decrypt the workbook encrypted by the
+// ECMA-376 agile encryption by given raw content of the compound file and
+// password, and returns the content of the decrypted package.
+func decrypt(raw []byte, password string) ([]byte, error) {
+	doc, err := newCompoundFile(raw)
+	if err != nil {
+		return nil, err
+	}
+	info, err := doc.stream("EncryptionInfo")
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := doc.stream("EncryptedPackage")
+	if err != nil {
+		return nil, err
+	}
+	if len(info) < 8 {
+		return nil, errors.New("invalid encryption info")
+	}
+	if major, minor := binary.LittleEndian.Uint16(info), binary.LittleEndian.Uint16(info[2:]); major != 4 || minor != 4 {
+		return nil, fmt.Errorf("unsupported encryption mechanism version %d.%d", major, minor)
+	}
+	var enc encryption
+	if err = xml.Unmarshal(info[8:], &enc); err != nil {
+		return nil, err
+	}
+	key, err := enc.secretKey(password)
+	if err != nil {
+		return nil, err
+	}
+	return enc.decryptPackage(key, pkg)
+}
+
+// secretKey provides a function to get the secret key of the package by
+// given password, the password will be verified by the verifier hash.
+func (enc *encryption) secretKey(password string) ([]byte, error) {
+	for _, encryptor := range enc.KeyEncryptors.KeyEncryptor {
+		if encryptor.URI != "http://schemas.microsoft.com/office/2006/keyEncryptor/password" {
+			continue
+		}
+		k := encryptor.EncryptedKey
+		salt, err := base64.StdEncoding.DecodeString(k.SaltValue)
+		if err != nil {
+			return nil, err
+		}
+		pwd := utf16.Encode([]rune(password))
+		buf := make([]byte, len(pwd)*2)
+		for i, c := range pwd {
+			binary.LittleEndian.PutUint16(buf[i*2:], c)
+		}
+		h, err := hashData(k.HashAlgorithm, salt, buf)
+		if err != nil {
+			return nil, err
+		}
+		iterator := make([]byte, 4)
+		for i := 0; i < k.SpinCount; i++ {
+			binary.LittleEndian.PutUint32(iterator, uint32(i))
+			h, _ = hashData(k.HashAlgorithm, iterator, h)
+		}
+		iv := fixBytesSize(salt, k.BlockSize, 0x36)
+		decryptValue := func(blockKey []byte, value string) ([]byte, error) {
+			fh, _ := hashData(k.HashAlgorithm, h, blockKey)
+			ciphertext, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, err
+			}
+			return decryptCBC(k.CipherAlgorithm, k.CipherChaining, fixBytesSize(fh, k.KeyBits/8, 0x36), iv, ciphertext)
+		}
+		hashInput, err := decryptValue(blockKeyVerifierHashInput, k.EncryptedVerifierHashInput)
+		if err != nil {
+			return nil, err
+		}
+		hashValue, err := decryptValue(blockKeyVerifierHashValue, k.EncryptedVerifierHashValue)
+		if err != nil {
+			return nil, err
+		}
+		verifier, _ := hashData(k.HashAlgorithm, fixBytesSize(hashInput, k.SaltSize, 0))
+		if len(hashValue) < k.HashSize || !bytes.Equal(fixBytesSize(verifier, k.HashSize, 0), hashValue[:k.HashSize]) {
+			return nil, errWorkbookPassword
+		}
+		key, err := decryptValue(blockKeyEncryptedKeyValue, k.EncryptedKeyValue)
+		if err != nil {
+			return nil, err
+		}
+		return fixBytesSize(key, enc.KeyData.KeyBits/8, 0), err
+	}
+	return nil, errors.New("the password key encryptor is not exist")
+}
+
+// decryptPackage provides a function to decrypt the EncryptedPackage stream
+// segment by segment by given secret key and the content of the stream.
+func (enc *encryption) decryptPackage(key, pkg []byte) ([]byte, error) {
+	if len(pkg) < packageSizeFieldBytes {
+		return nil, errors.New("invalid encrypted package")
+	}
+	salt, err := base64.StdEncoding.DecodeString(enc.KeyData.SaltValue)
+	if err != nil {
+		return nil, err
+	}
+	size, data := binary.LittleEndian.Uint64(pkg), pkg[packageSizeFieldBytes:]
+	var out []byte
+	segment := make([]byte, 4)
+	for i := 0; i*packageSegmentLength < len(data); i++ {
+		end := (i + 1) * packageSegmentLength
+		if end > len(data) {
+			end = len(data)
+		}
+		binary.LittleEndian.PutUint32(segment, uint32(i))
+		iv, err := hashData(enc.KeyData.HashAlgorithm, salt, segment)
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := decryptCBC(enc.KeyData.CipherAlgorithm, enc.KeyData.CipherChaining, key, fixBytesSize(iv, enc.KeyData.BlockSize, 0x36), data[i*packageSegmentLength:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, plaintext...)
+	}
+	if uint64(len(out)) < size {
+		return nil, errors.New("invalid encrypted package")
+	}
+	return out[:size], nil
+}
+
+// hashData provides a function to calculate the hash of the data by given
+// hash algorithm name.
+func hashData(algorithm string, data ...[]byte) ([]byte, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "MD5":
+		h = md5.New()
+	case "SHA1", "SHA-1":
+		h = sha1.New()
+	case "SHA256", "SHA-256":
+		h = sha256.New()
+	case "SHA384", "SHA-384":
+		h = sha512.New384()
+	case "SHA512", "SHA-512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %s", algorithm)
+	}
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil), nil
+}
+
+// decryptCBC provides a function to decrypt the ciphertext with the AES
+// cipher in the CBC mode by given key and initialization vector.
+func decryptCBC(algorithm, chaining string, key, iv, ciphertext []byte) ([]byte, error) {
+	if algorithm != "AES" || chaining != "ChainingModeCBC" {
+		return nil, fmt.Errorf("unsupported cipher algorithm %s with %s", algorithm, chaining)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid cipher block size")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	return plaintext, nil
+}
+
+// fixBytesSize provides a function to truncate the bytes or pad the bytes
+// with the given padding byte to the given size.
+func fixBytesSize(b []byte, size int, padding byte) []byte {
+	if len(b) >= size {
+		return b[:size]
+	}
+	return append(append([]byte{}, b...), bytes.Repeat([]byte{padding}, size-len(b))...)
+}
+
+// compoundFile defines the structure used to read the streams of the
+// compound file binary format, which is the container of the encrypted
+// workbook.
+type compoundFile struct {
+	data             []byte
+	sectorSize       int
+	miniSectorSize   int
+	miniStreamCutoff uint64
+	fat, miniFAT     []uint32
+	miniStream       []byte
+	entries          []compoundFileEntry
+}
+
+// compoundFileEntry defines the structure of the directory entry of the
+// compound file.
+type compoundFileEntry struct {
+	name  string
+	typ   byte
+	start uint32
+	size  uint64
+}
+
+// newCompoundFile provides a function to parse the header, allocation tables
+// and directory entries of the compound file by given raw content.
+func newCompoundFile(data []byte) (*compoundFile, error) {
+	if len(data) < 512 || !bytes.HasPrefix(data, oleIdentifier) {
+		return nil, errCompoundFile
+	}
+	cf := &compoundFile{
+		data:             data,
+		sectorSize:       1 << binary.LittleEndian.Uint16(data[30:]),
+		miniSectorSize:   1 << binary.LittleEndian.Uint16(data[32:]),
+		miniStreamCutoff: uint64(binary.LittleEndian.Uint32(data[56:])),
+	}
+	if cf.sectorSize != 512 && cf.sectorSize != 4096 || cf.miniSectorSize != 64 {
+		return nil, errCompoundFile
+	}
+	var fatSectors []uint32
+	for i := 0; i < 109; i++ {
+		if id := binary.LittleEndian.Uint32(data[76+i*4:]); id < cfbMaxRegularSector {
+			fatSectors = append(fatSectors, id)
+		}
+	}
+	difat := binary.LittleEndian.Uint32(data[68:])
+	for i := uint32(0); i < binary.LittleEndian.Uint32(data[72:]) && difat < cfbMaxRegularSector; i++ {
+		sector, err := cf.sector(difat)
+		if err != nil {
+			return nil, err
+		}
+		n := cf.sectorSize/4 - 1
+		for j := 0; j < n; j++ {
+			if id := binary.LittleEndian.Uint32(sector[j*4:]); id < cfbMaxRegularSector {
+				fatSectors = append(fatSectors, id)
+			}
+		}
+		difat = binary.LittleEndian.Uint32(sector[n*4:])
+	}
+	for _, id := range fatSectors {
+		sector, err := cf.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		cf.fat = append(cf.fat, bytesToUint32s(sector)...)
+	}
+	dir, err := cf.chain(cf.fat, binary.LittleEndian.Uint32(data[48:]), cf.sector)
+	if err != nil {
+		return nil, err
+	}
+	for off := 0; off+128 <= len(dir); off += 128 {
+		entry := compoundFileEntry{
+			typ:   dir[off+66],
+			start: binary.LittleEndian.Uint32(dir[off+116:]),
+			size:  binary.LittleEndian.Uint64(dir[off+120:]),
+		}
+		if cf.sectorSize == 512 {
+			entry.size &= 0xFFFFFFFF
+		}
+		if nameLen := int(binary.LittleEndian.Uint16(dir[off+64:])); nameLen >= 2 && nameLen <= 64 {
+			name := make([]uint16, nameLen/2-1)
+			for i := range name {
+				name[i] = binary.LittleEndian.Uint16(dir[off+i*2:])
+			}
+			entry.name = string(utf16.Decode(name))
+		}
+		cf.entries = append(cf.entries, entry)
+	}
+	if len(cf.entries) == 0 {
+		return nil, errCompoundFile
+	}
+	if cf.miniStream, err = cf.chain(cf.fat, cf.entries[0].start, cf.sector); err != nil {
+		return nil, err
+	}
+	miniFAT, err := cf.chain(cf.fat, binary.LittleEndian.Uint32(data[60:]), cf.sector)
+	if err != nil {
+		return nil, err
+	}
+	cf.miniFAT = bytesToUint32s(miniFAT)
+	return cf, nil
+}
+
+// sector provides a function to get the content of the sector by given
+// sector ID.
+func (cf *compoundFile) sector(id uint32) ([]byte, error) {
+	off := (int(id) + 1) * cf.sectorSize
+	if off+cf.sectorSize > len(cf.data) {
+		return nil, errCompoundFile
+	}
+	return cf.data[off : off+cf.sectorSize], nil
+}
+
+// miniSector provides a function to get the content of the mini sector in
+// the mini stream by given mini sector ID.
+func (cf *compoundFile) miniSector(id uint32) ([]byte, error) {
+	off := int(id) * cf.miniSectorSize
+	if off+cf.miniSectorSize > len(cf.miniStream) {
+		return nil, errCompoundFile
+	}
+	return cf.miniStream[off : off+cf.miniSectorSize], nil
+}
+
+// chain provides a function to read the sectors chain by given allocation
+// table, start sector ID and the function to read sector.
+func (cf *compoundFile) chain(table []uint32, start uint32, read func(uint32) ([]byte, error)) ([]byte, error) {
+	var buf []byte
+	for id, count := start, 0; id != cfbEndOfChain && id < cfbMaxRegularSector; count++ {
+		if int(id) >= len(table) || count > len(table) {
+			return nil, errCompoundFile
+		}
+		sector, err := read(id)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, sector...)
+		id = table[id]
+	}
+	return buf, nil
+}
+
+// stream provides a function to read the content of the stream by given
+// stream name.
+func (cf *compoundFile) stream(name string) ([]byte, error) {
+	for _, entry := range cf.entries[1:] {
+		if entry.typ != 2 || entry.name != name {
+			continue
+		}
+		var (
+			buf []byte
+			err error
+		)
+		if entry.size < cf.miniStreamCutoff {
+			buf, err = cf.chain(cf.miniFAT, entry.start, cf.miniSector)
+		} else {
+			buf, err = cf.chain(cf.fat, entry.start, cf.sector)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(buf)) < entry.size {
+			return nil, errCompoundFile
+		}
+		return buf[:entry.size], nil
+	}
+	return nil, fmt.Errorf("stream %s is not exist in the compound file", name)
+}
+
+// bytesToUint32s provides a function to convert the little-endian bytes to
+// uint32 numbers.
+func bytesToUint32s(b []byte) []uint32 {
+	nums := make([]uint32, len(b)/4)
+	for i := range nums {
+		nums[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return nums
+}
@@ -0,0 +1,299 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenEncryptedFile(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "encrypted"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	raw, err := encryptAgileForTest(buf.Bytes(), "password", 512)
+	assert.NoError(t, err)
+	path := filepath.Join(os.TempDir(), "TestOpenEncryptedFile.xlsx")
+	assert.NoError(t, ioutil.WriteFile(path, raw, 0644))
+	defer os.Remove(path)
+
+	f, err = OpenFile(path, Options{Password: "password"})
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "encrypted", val)
+	assert.Equal(t, path, f.Path)
+
+	// Test open the encrypted file with the large sectors.
+	raw, err = encryptAgileForTest(buf.Bytes(), "密码", 4096)
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(raw), Options{Password: "密码"})
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "encrypted", val)
+	f, err = OpenReaderStream(bytes.NewReader(raw), Options{Password: "密码"})
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "encrypted", val)
+
+	// Test open the encrypted file with incorrect password.
+	_, err = OpenFile(path, Options{Password: "passwd"})
+	assert.EqualError(t, err, "the supplied open workbook password is not correct")
+	// Test open the encrypted file without password.
+	_, err = OpenFile(path)
+	assert.EqualError(t, err, "zip: not a valid zip file")
+	// Test open the file without encryption with password.
+	f, err = OpenReader(buf, Options{Password: "password"})
+	assert.NoError(t, err)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "encrypted", val)
+}
+
+func TestDecrypt(t *testing.T) {
+	raw, err := encryptAgileForTest(make([]byte, 5000), "password", 512)
+	assert.NoError(t, err)
+	pkg, err := decrypt(raw, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 5000), pkg)
+
+	// Test decrypt the invalid compound file.
+	_, err = decrypt([]byte("invalid"), "password")
+	assert.EqualError(t, err, "invalid compound file")
+	_, err = decrypt(raw[:1024], "password")
+	assert.EqualError(t, err, "invalid compound file")
+	invalid := append([]byte{}, raw...)
+	binary.LittleEndian.PutUint16(invalid[30:], 10)
+	_, err = decrypt(invalid, "password")
+	assert.EqualError(t, err, "invalid compound file")
+
+	// Test decrypt with the unsupported encryption mechanism.
+	invalid = append([]byte{}, raw...)
+	invalid[bytes.Index(invalid, []byte{4, 0, 4, 0, 0x40, 0, 0, 0})] = 3
+	_, err = decrypt(invalid, "password")
+	assert.EqualError(t, err, "unsupported encryption mechanism version 3.4")
+	doc, err := newCompoundFile(raw)
+	assert.NoError(t, err)
+	_, err = doc.stream("Stream")
+	assert.EqualError(t, err, "stream Stream is not exist in the compound file")
+
+	_, err = hashData("SHA0")
+	assert.EqualError(t, err, "unsupported hash algorithm SHA0")
+	for _, algorithm := range []string{"MD5", "SHA1", "SHA256", "SHA384"} {
+		_, err = hashData(algorithm, []byte("data"))
+		assert.NoError(t, err)
+	}
+	_, err = decryptCBC("DES", "ChainingModeCBC", nil, nil, nil)
+	assert.EqualError(t, err, "unsupported cipher algorithm DES with ChainingModeCBC")
+	_, err = decryptCBC("AES", "ChainingModeCBC", make([]byte, 16), make([]byte, 16), make([]byte, 15))
+	assert.EqualError(t, err, "invalid cipher block size")
+	_, err = decryptCBC("AES", "ChainingModeCBC", make([]byte, 15), make([]byte, 16), make([]byte, 16))
+	assert.EqualError(t, err, "crypto/aes: invalid key size 15")
+
+	enc := encryption{}
+	_, err = enc.secretKey("password")
+	assert.EqualError(t, err, "the password key encryptor is not exist")
+	_, err = enc.decryptPackage(nil, nil)
+	assert.EqualError(t, err, "invalid encrypted package")
+	assert.Equal(t, []byte{1, 0x36, 0x36}, fixBytesSize([]byte{1}, 3, 0x36))
+	assert.Equal(t, []byte{1}, fixBytesSize([]byte{1, 2}, 1, 0x36))
+}
+
+// encryptAgileForTest provides a function to encrypt the package with the
+// ECMA-376 agile encryption and store it in the compound file by given
+// package content, password and sector size.
+func encryptAgileForTest(pkg []byte, password string, sectorSize int) ([]byte, error) {
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		_, _ = rand.Read(b)
+		return b
+	}
+	encryptCBC := func(key, iv, plaintext []byte) []byte {
+		block, _ := aes.NewCipher(key)
+		plaintext = append(plaintext, make([]byte, (16-len(plaintext)%16)%16)...)
+		ciphertext := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+		return ciphertext
+	}
+	keyDataSalt, passwordSalt, secretKey, verifier := random(16), random(16), random(32), random(16)
+	pwd := utf16.Encode([]rune(password))
+	buf := make([]byte, len(pwd)*2)
+	for i, c := range pwd {
+		binary.LittleEndian.PutUint16(buf[i*2:], c)
+	}
+	h, _ := hashData("SHA512", passwordSalt, buf)
+	iterator := make([]byte, 4)
+	for i := 0; i < 100000; i++ {
+		binary.LittleEndian.PutUint32(iterator, uint32(i))
+		h, _ = hashData("SHA512", iterator, h)
+	}
+	encryptValue := func(blockKey, value []byte) string {
+		key, _ := hashData("SHA512", h, blockKey)
+		return base64.StdEncoding.EncodeToString(encryptCBC(key[:32], passwordSalt, value))
+	}
+	verifierHash := sha512.Sum512(verifier)
+	info := bytes.NewBuffer([]byte{4, 0, 4, 0, 0x40, 0, 0, 0})
+	info.WriteString(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\r\n"+
+		`<encryption xmlns="http://schemas.microsoft.com/office/2006/encryption" xmlns:p="http://schemas.microsoft.com/office/2006/keyEncryptor/password">`+
+		`<keyData saltSize="16" blockSize="16" keyBits="256" hashSize="64" cipherAlgorithm="AES" cipherChaining="ChainingModeCBC" hashAlgorithm="SHA512" saltValue="%s"/>`+
+		`<keyEncryptors><keyEncryptor uri="http://schemas.microsoft.com/office/2006/keyEncryptor/password">`+
+		`<p:encryptedKey spinCount="100000" saltSize="16" blockSize="16" keyBits="256" hashSize="64" cipherAlgorithm="AES" cipherChaining="ChainingModeCBC" hashAlgorithm="SHA512" saltValue="%s" encryptedVerifierHashInput="%s" encryptedVerifierHashValue="%s" encryptedKeyValue="%s"/>`+
+		`</keyEncryptor></keyEncryptors></encryption>`,
+		base64.StdEncoding.EncodeToString(keyDataSalt), base64.StdEncoding.EncodeToString(passwordSalt),
+		encryptValue(blockKeyVerifierHashInput, verifier), encryptValue(blockKeyVerifierHashValue, verifierHash[:]),
+		encryptValue(blockKeyEncryptedKeyValue, secretKey)))
+	encrypted := make([]byte, 8)
+	binary.LittleEndian.PutUint64(encrypted, uint64(len(pkg)))
+	segment := make([]byte, 4)
+	for i := 0; i*packageSegmentLength < len(pkg); i++ {
+		end := (i + 1) * packageSegmentLength
+		if end > len(pkg) {
+			end = len(pkg)
+		}
+		binary.LittleEndian.PutUint32(segment, uint32(i))
+		iv, _ := hashData("SHA512", keyDataSalt, segment)
+		encrypted = append(encrypted, encryptCBC(secretKey, iv[:16], pkg[i*packageSegmentLength:end])...)
+	}
+	return compoundFileForTest(sectorSize, map[string][]byte{"EncryptionInfo": info.Bytes(), "EncryptedPackage": encrypted}), nil
+}
+
+// compoundFileForTest provides a function to create the compound file by
+// given sector size and streams, the streams smaller than 4096 bytes will be
+// stored in the mini stream.
+func compoundFileForTest(sectorSize int, streams map[string][]byte) []byte {
+	const (
+		freeSector = 0xFFFFFFFF
+		fatSector  = 0xFFFFFFFD
+	)
+	var (
+		names            = []string{"EncryptionInfo", "EncryptedPackage"}
+		sectors          [][]byte
+		fat, miniFAT     []uint32
+		miniStream       []byte
+		starts           = map[string]uint32{}
+		addChain         func(table *[]uint32, n int) uint32
+		appendRegularBuf = func(b []byte) uint32 {
+			n := (len(b) + sectorSize - 1) / sectorSize
+			start := addChain(&fat, n)
+			b = append(b, make([]byte, n*sectorSize-len(b))...)
+			for i := 0; i < n; i++ {
+				sectors = append(sectors, b[i*sectorSize:(i+1)*sectorSize])
+			}
+			return start
+		}
+	)
+	addChain = func(table *[]uint32, n int) uint32 {
+		if n == 0 {
+			return cfbEndOfChain
+		}
+		start := uint32(len(*table))
+		for i := 1; i < n; i++ {
+			*table = append(*table, start+uint32(i))
+		}
+		*table = append(*table, cfbEndOfChain)
+		return start
+	}
+	for _, name := range names {
+		if data := streams[name]; len(data) >= 4096 {
+			starts[name] = appendRegularBuf(append([]byte{}, data...))
+		} else {
+			n := (len(data) + 63) / 64
+			starts[name] = addChain(&miniFAT, n)
+			miniStream = append(miniStream, data...)
+			miniStream = append(miniStream, make([]byte, n*64-len(data))...)
+		}
+	}
+	rootStart := appendRegularBuf(append([]byte{}, miniStream...))
+	miniFATBuf := make([]byte, len(miniFAT)*4)
+	for i, id := range miniFAT {
+		binary.LittleEndian.PutUint32(miniFATBuf[i*4:], id)
+	}
+	miniFATStart := appendRegularBuf(miniFATBuf)
+	entry := func(name string, typ byte, child, right, start uint32, size int) []byte {
+		b := make([]byte, 128)
+		u := utf16.Encode([]rune(name))
+		for i, c := range u {
+			binary.LittleEndian.PutUint16(b[i*2:], c)
+		}
+		binary.LittleEndian.PutUint16(b[64:], uint16(len(u)*2+2))
+		b[66], b[67] = typ, 1
+		binary.LittleEndian.PutUint32(b[68:], freeSector)
+		binary.LittleEndian.PutUint32(b[72:], right)
+		binary.LittleEndian.PutUint32(b[76:], child)
+		binary.LittleEndian.PutUint32(b[116:], start)
+		binary.LittleEndian.PutUint64(b[120:], uint64(size))
+		return b
+	}
+	dir := entry("Root Entry", 5, 1, freeSector, rootStart, len(miniStream))
+	dir = append(dir, entry(names[0], 2, freeSector, 2, starts[names[0]], len(streams[names[0]]))...)
+	dir = append(dir, entry(names[1], 2, freeSector, freeSector, starts[names[1]], len(streams[names[1]]))...)
+	dirStart := appendRegularBuf(dir)
+	fatSectors := 1
+	for (len(sectors)+fatSectors)*4 > fatSectors*sectorSize {
+		fatSectors++
+	}
+	fatStart := uint32(len(sectors))
+	for i := 0; i < fatSectors; i++ {
+		fat = append(fat, fatSector)
+	}
+	for len(fat)*4 < fatSectors*sectorSize {
+		fat = append(fat, freeSector)
+	}
+	fatBuf := make([]byte, len(fat)*4)
+	for i, id := range fat {
+		binary.LittleEndian.PutUint32(fatBuf[i*4:], id)
+	}
+	header := make([]byte, 512)
+	copy(header, oleIdentifier)
+	binary.LittleEndian.PutUint16(header[24:], 0x3E)
+	binary.LittleEndian.PutUint16(header[26:], 3)
+	shift := uint16(9)
+	if sectorSize == 4096 {
+		binary.LittleEndian.PutUint16(header[26:], 4)
+		shift = 12
+	}
+	binary.LittleEndian.PutUint16(header[28:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[30:], shift)
+	binary.LittleEndian.PutUint16(header[32:], 6)
+	binary.LittleEndian.PutUint32(header[44:], uint32(fatSectors))
+	binary.LittleEndian.PutUint32(header[48:], dirStart)
+	binary.LittleEndian.PutUint32(header[56:], 4096)
+	binary.LittleEndian.PutUint32(header[60:], miniFATStart)
+	binary.LittleEndian.PutUint32(header[64:], uint32((len(miniFATBuf)+sectorSize-1)/sectorSize))
+	binary.LittleEndian.PutUint32(header[68:], cfbEndOfChain)
+	for i := 0; i < 109; i++ {
+		id := uint32(freeSector)
+		if i < fatSectors {
+			id = fatStart + uint32(i)
+		}
+		binary.LittleEndian.PutUint32(header[76+i*4:], id)
+	}
+	out := append(header, make([]byte, sectorSize-len(header))...)
+	for _, sector := range sectors {
+		out = append(out, sector...)
+	}
+	return append(out, fatBuf...)
+}
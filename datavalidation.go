@@ -10,8 +10,12 @@
 package excelize
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // DataValidationType defined the type of data validation.
@@ -36,6 +40,12 @@ const (
 	dataValidationFormulaStrLen = 257
 	// dataValidationFormulaStrLenErr
 	dataValidationFormulaStrLenErr = "data validation must be 0-255 characters"
+	// dataValidationTitleStrLen is the maximum characters of the title of the
+	// input message and error alert
+	dataValidationTitleStrLen = 32
+	// dataValidationMsgStrLen is the maximum characters of the input message
+	// and error alert
+	dataValidationMsgStrLen = 255
 )
 
 // DataValidationErrorStyle defined the style of data validation error alert.
@@ -81,11 +91,13 @@ func NewDataValidation(allowBlank bool) *DataValidation {
 	}
 }
 
-// SetError set error notice.
-func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg string) {
-	dd.Error = &msg
-	dd.ErrorTitle = &title
-	strStyle := styleStop
+// SetError set error notice by given error alert style, title and message.
+// The available error alert styles are DataValidationErrorStyleStop,
+// DataValidationErrorStyleWarning and DataValidationErrorStyleInformation.
+// The title is limited to 32 characters and the message is limited to 255
+// characters.
+func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg string) error {
+	var strStyle string
 	switch style {
 	case DataValidationErrorStyleStop:
 		strStyle = styleStop
@@ -93,17 +105,41 @@ func (dd *DataValidation) SetError(style DataValidationErrorStyle, title, msg st
 		strStyle = styleWarning
 	case DataValidationErrorStyleInformation:
 		strStyle = styleInformation
-
+	default:
+		return fmt.Errorf("unsupported data validation error style %d", style)
 	}
+	if err := checkDataValidationMsg(title, msg); err != nil {
+		return err
+	}
+	dd.Error = &msg
+	dd.ErrorTitle = &title
 	dd.ShowErrorMessage = true
 	dd.ErrorStyle = &strStyle
+	return nil
 }
 
-// SetInput set prompt notice.
-func (dd *DataValidation) SetInput(title, msg string) {
+// SetInput set prompt notice by given title and message. The title is
+// limited to 32 characters and the message is limited to 255 characters.
+func (dd *DataValidation) SetInput(title, msg string) error {
+	if err := checkDataValidationMsg(title, msg); err != nil {
+		return err
+	}
 	dd.ShowInputMessage = true
 	dd.PromptTitle = &title
 	dd.Prompt = &msg
+	return nil
+}
+
+// checkDataValidationMsg provides a function to check the length of the
+// title and message of the data validation input message and error alert.
+func checkDataValidationMsg(title, msg string) error {
+	if utf8.RuneCountInString(title) > dataValidationTitleStrLen {
+		return fmt.Errorf("data validation title must be 0-%d characters", dataValidationTitleStrLen)
+	}
+	if utf8.RuneCountInString(msg) > dataValidationMsgStrLen {
+		return fmt.Errorf("data validation message must be 0-%d characters", dataValidationMsgStrLen)
+	}
+	return nil
 }
 
 // SetDropList data validation list.
@@ -144,13 +180,76 @@ func (dd *DataValidation) SetRange(f1, f2 int, t DataValidationType, o DataValid
 //     dvRange.SetSqrefDropList("$E$1:$E$3", true)
 //     f.AddDataValidation("Sheet1", dvRange)
 //
+// The source can also be a range on another worksheet with isCurrentSheet
+// false, such as Lists!$A$1:$A$10 or 'My Lists'!$A$1:$A$10, or a defined
+// name of the workbook, such as Fruits.
 func (dd *DataValidation) SetSqrefDropList(sqref string, isCurrentSheet bool) error {
-	if isCurrentSheet {
-		dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", sqref)
-		dd.Type = convDataValidationType(typeList)
+	ref := strings.TrimPrefix(strings.TrimSpace(sqref), "=")
+	if err := checkDataValidationRef(ref, isCurrentSheet); err != nil {
+		return err
+	}
+	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", escapeDataValidationFormula(ref))
+	dd.Type = convDataValidationType(typeList)
+	return nil
+}
+
+// SetCustomFormula provides a function to set the custom data validation by
+// given formula, the value of the cell will be valid when the formula
+// evaluates to true. The formula is relative to the top-left cell of the
+// data validation range. For example, only allow the text value on
+// Sheet1!A1:A10:
+//
+//     dvRange := excelize.NewDataValidation(true)
+//     dvRange.Sqref = "A1:A10"
+//     dvRange.SetCustomFormula("ISTEXT(A1)")
+//     f.AddDataValidation("Sheet1", dvRange)
+//
+func (dd *DataValidation) SetCustomFormula(formula string) error {
+	formula = strings.TrimPrefix(strings.TrimSpace(formula), "=")
+	if dataValidationFormulaStrLen-2 < utf8.RuneCountInString(formula) {
+		return fmt.Errorf(dataValidationFormulaStrLenErr)
+	}
+	if _, err := parseFormula(formula); err != nil {
+		return err
+	}
+	dd.Formula1 = fmt.Sprintf("<formula1>%s</formula1>", escapeDataValidationFormula(formula))
+	dd.Formula2 = ""
+	dd.Operator = ""
+	dd.Type = convDataValidationType(DataValidationTypeCustom)
+	return nil
+}
+
+// dataValidationDefinedNameRe matches the defined name which could be used
+// as the source of the data validation.
+var dataValidationDefinedNameRe = regexp.MustCompile(`^[A-Za-z_\\][A-Za-z0-9_.\\]*$`)
+
+// checkDataValidationRef provides a function to check the source reference
+// of the data validation drop list, the reference should be a cell or range
+// reference with an optional sheet name prefix, or a defined name.
+func checkDataValidationRef(ref string, isCurrentSheet bool) error {
+	area := ref
+	if i := strings.LastIndex(ref, "!"); i != -1 {
+		sheet := strings.TrimSuffix(strings.TrimPrefix(ref[:i], "'"), "'")
+		if isCurrentSheet || sheet == "" {
+			return fmt.Errorf("invalid data validation reference %s", ref)
+		}
+		area = ref[i+1:]
+	}
+	if isFormulaReference(area) {
+		return nil
+	}
+	if area == ref && dataValidationDefinedNameRe.MatchString(ref) {
 		return nil
 	}
-	return fmt.Errorf("cross-sheet sqref cell are not supported")
+	return fmt.Errorf("invalid data validation reference %s", ref)
+}
+
+// escapeDataValidationFormula provides a function to escape the formula of
+// the data validation for the inner XML.
+func escapeDataValidationFormula(formula string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(formula))
+	return buf.String()
 }
 
 // SetSqref provides function to set data validation range in drop list.
@@ -241,7 +340,14 @@ func (f *File) AddDataValidation(sheet string, dv *DataValidation) error {
 }
 
 // DeleteDataValidation delete data validation by given worksheet name and
-// reference sequence.
+// reference sequence. The data validation will be removed if its reference
+// sequence is equal to the given one, otherwise the cells of the given ranges
+// will be removed from the ranges of the data validation, and the data
+// validations element will be removed when there are no data validations left
+// in the worksheet. For example, delete the data validation on Sheet1!A1:B2:
+//
+//    err := f.DeleteDataValidation("Sheet1", "A1:B2")
+//
 func (f *File) DeleteDataValidation(sheet, sqref string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -251,12 +357,16 @@ func (f *File) DeleteDataValidation(sheet, sqref string) error {
 		return nil
 	}
 	dv := ws.DataValidations
-	for i := 0; i < len(dv.DataValidation); i++ {
-		if dv.DataValidation[i].Sqref == sqref {
-			dv.DataValidation = append(dv.DataValidation[:i], dv.DataValidation[i+1:]...)
-			i--
+	dataValidations := dv.DataValidation[:0]
+	for _, v := range dv.DataValidation {
+		if v.Sqref, err = subtractSqref(v.Sqref, sqref); err != nil {
+			return err
+		}
+		if v.Sqref != "" {
+			dataValidations = append(dataValidations, v)
 		}
 	}
+	dv.DataValidation = dataValidations
 	dv.Count = len(dv.DataValidation)
 	if dv.Count == 0 {
 		ws.DataValidations = nil
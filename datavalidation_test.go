@@ -25,16 +25,17 @@ func TestDataValidation(t *testing.T) {
 	dvRange := NewDataValidation(true)
 	dvRange.Sqref = "A1:B2"
 	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
-	dvRange.SetError(DataValidationErrorStyleStop, "error title", "error body")
-	dvRange.SetError(DataValidationErrorStyleWarning, "error title", "error body")
-	dvRange.SetError(DataValidationErrorStyleInformation, "error title", "error body")
+	assert.NoError(t, dvRange.SetError(DataValidationErrorStyleStop, "error title", "error body"))
+	assert.NoError(t, dvRange.SetError(DataValidationErrorStyleWarning, "error title", "error body"))
+	assert.NoError(t, dvRange.SetError(DataValidationErrorStyleInformation, "error title", "error body"))
+	assert.Equal(t, "information", *dvRange.ErrorStyle)
 	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
 	assert.NoError(t, f.SaveAs(resultFile))
 
 	dvRange = NewDataValidation(true)
 	dvRange.Sqref = "A3:B4"
 	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorGreaterThan))
-	dvRange.SetInput("input title", "input body")
+	assert.NoError(t, dvRange.SetInput("input title", "input body"))
 	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
 	assert.NoError(t, f.SaveAs(resultFile))
 
@@ -58,8 +59,13 @@ func TestDataValidationError(t *testing.T) {
 	dvRange.SetSqref("A7:B8")
 	assert.NoError(t, dvRange.SetSqrefDropList("$E$1:$E$3", true))
 
-	err := dvRange.SetSqrefDropList("$E$1:$E$3", false)
-	assert.EqualError(t, err, "cross-sheet sqref cell are not supported")
+	err := dvRange.SetSqrefDropList("Sheet1!$E$1:$E$3", true)
+	assert.EqualError(t, err, "invalid data validation reference Sheet1!$E$1:$E$3")
+	for _, ref := range []string{"", "!$E$1:$E$3", "Lists!Fruits", "$E$1:", "1Fruits"} {
+		assert.EqualError(t, dvRange.SetSqrefDropList(ref, false), "invalid data validation reference "+ref)
+	}
+	assert.EqualError(t, dvRange.SetCustomFormula(""), "formula not valid")
+	assert.EqualError(t, dvRange.SetCustomFormula(strings.Repeat("s", 256)), "data validation must be 0-255 characters")
 
 	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
 	assert.NoError(t, f.SaveAs(resultFile))
@@ -81,11 +87,51 @@ func TestDataValidationError(t *testing.T) {
 	dvRange.Formula1 = strings.Repeat("s", dataValidationFormulaStrLen+22)
 	assert.EqualError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorGreaterThan), "data validation must be 0-255 characters")
 
+	// Test set input message and error alert with invalid settings.
+	assert.EqualError(t, dvRange.SetError(DataValidationErrorStyle(4), "error title", "error body"), "unsupported data validation error style 4")
+	assert.EqualError(t, dvRange.SetError(DataValidationErrorStyleStop, strings.Repeat("s", 33), "error body"), "data validation title must be 0-32 characters")
+	assert.EqualError(t, dvRange.SetInput("input title", strings.Repeat("中", 256)), "data validation message must be 0-255 characters")
+	assert.NoError(t, dvRange.SetInput(strings.Repeat("中", 32), strings.Repeat("s", 255)))
+
 	// Test add data validation on no exists worksheet.
 	f = NewFile()
 	assert.EqualError(t, f.AddDataValidation("SheetN", nil), "sheet SheetN is not exist")
 }
 
+func TestDataValidationFormulaSource(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Lists")
+	for _, c := range [][2]string{{"A1", "Apple"}, {"A2", "Orange"}, {"A3", "Pear"}} {
+		assert.NoError(t, f.SetCellStr("Lists", c[0], c[1]))
+	}
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Fruits", RefersTo: "Lists!$A$1:$A$3"}))
+
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A1:A5"
+	assert.NoError(t, dvRange.SetSqrefDropList("=Lists!$A$1:$A$3", false))
+	assert.Equal(t, "<formula1>Lists!$A$1:$A$3</formula1>", dvRange.Formula1)
+	assert.Equal(t, "list", dvRange.Type)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "B1:B5"
+	assert.NoError(t, dvRange.SetSqrefDropList("Fruits", false))
+	assert.Equal(t, "<formula1>Fruits</formula1>", dvRange.Formula1)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "C1:C5"
+	assert.NoError(t, dvRange.SetSqrefDropList("'My Lists'!$A$1:$A$3", false))
+	assert.Equal(t, "<formula1>&#39;My Lists&#39;!$A$1:$A$3</formula1>", dvRange.Formula1)
+
+	dvRange.Sqref = "D1:D5"
+	assert.NoError(t, dvRange.SetCustomFormula("=AND(ISNUMBER(D1),D1<10)"))
+	assert.Equal(t, "<formula1>AND(ISNUMBER(D1),D1&lt;10)</formula1>", dvRange.Formula1)
+	assert.Equal(t, "custom", dvRange.Type)
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDataValidationFormulaSource.xlsx")))
+}
+
 func TestDeleteDataValidation(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B2"))
@@ -93,10 +139,43 @@ func TestDeleteDataValidation(t *testing.T) {
 	dvRange := NewDataValidation(true)
 	dvRange.Sqref = "A1:B2"
 	assert.NoError(t, dvRange.SetRange(10, 20, DataValidationTypeWhole, DataValidationOperatorBetween))
-	dvRange.SetInput("input title", "input body")
+	assert.NoError(t, dvRange.SetInput("input title", "input body"))
 	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
 
 	assert.NoError(t, f.DeleteDataValidation("Sheet1", "A1:B2"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.DataValidations)
+
+	// Test delete data validation which applies to multiple ranges.
+	dvRange.Sqref = "A1:B2 D1:D5"
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "d1:d5"))
+	assert.Equal(t, 1, ws.DataValidations.Count)
+	assert.Equal(t, "A1:B2", ws.DataValidations.DataValidation[0].Sqref)
+	// Test delete data validation by the whole multiple ranges.
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "E1:F2 G1:G3"
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "E1:F2 G1:G3"))
+	assert.Equal(t, 1, ws.DataValidations.Count)
+	// Test delete data validation on a sub range.
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "H1:H10"
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "H2"))
+	assert.Equal(t, "H1 H3:H10", ws.DataValidations.DataValidation[1].Sqref)
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "H1:H10"))
+	// Test delete data validation on a partly overlapped range.
+	dvRange = NewDataValidation(true)
+	dvRange.Sqref = "B4:D6"
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.DeleteDataValidation("Sheet1", "C5:E7"))
+	assert.Equal(t, "B4:D4 B5:B6", ws.DataValidations.DataValidation[1].Sqref)
+	assert.Equal(t, "A1:B2", ws.DataValidations.DataValidation[0].Sqref)
+	// Test delete data validation with invalid reference.
+	assert.EqualError(t, f.DeleteDataValidation("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.Equal(t, 2, ws.DataValidations.Count)
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestDeleteDataValidation.xlsx")))
 
 	// Test delete data validation on no exists worksheet.
@@ -22,13 +22,13 @@ const (
 
 var (
 	excelMinTime1900      = time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC)
+	excelMinTime1904      = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
 	excelBuggyPeriodStart = time.Date(1900, time.March, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
 )
 
-// timeToExcelTime provides a function to convert time to Excel time.
-func timeToExcelTime(t time.Time) (float64, error) {
-	// TODO in future this should probably also handle date1904 and like TimeFromExcelTime
-
+// timeToExcelTime provides a function to convert time to Excel time in the
+// 1900 or 1904 date system.
+func timeToExcelTime(t time.Time, date1904 bool) (float64, error) {
 	// Force user to explicit convet passed value to UTC time.
 	// Because for example 1900-01-01 00:00:00 +0300 MSK converts to 1900-01-01 00:00:00 +0230 LMT
 	// probably due to daylight saving.
@@ -36,18 +36,22 @@ func timeToExcelTime(t time.Time) (float64, error) {
 		return 0.0, errors.New("only UTC time expected")
 	}
 
-	if t.Before(excelMinTime1900) {
+	minTime := excelMinTime1900
+	if date1904 {
+		minTime = excelMinTime1904
+	}
+	if t.Before(minTime) {
 		return 0.0, nil
 	}
 
 	tt := t
-	diff := t.Sub(excelMinTime1900)
+	diff := t.Sub(minTime)
 	result := float64(0)
 
 	for diff >= maxDuration {
 		result += float64(maxDuration / dayNanoseconds)
 		tt = tt.Add(-maxDuration)
-		diff = tt.Sub(excelMinTime1900)
+		diff = tt.Sub(minTime)
 	}
 
 	rem := diff % dayNanoseconds
@@ -58,7 +62,7 @@ func timeToExcelTime(t time.Time) (float64, error) {
 	// Microsoft intentionally included this bug in Excel so that it would remain compatible with the spreadsheet
 	// program that had the majority market share at the time; Lotus 1-2-3.
 	// https://www.myonlinetraininghub.com/excel-date-and-time
-	if t.After(excelBuggyPeriodStart) {
+	if !date1904 && t.After(excelBuggyPeriodStart) {
 		result += 1.0
 	}
 	return result, nil
@@ -39,7 +39,7 @@ var excelTimeInputList = []dateTest{
 func TestTimeToExcelTime(t *testing.T) {
 	for i, test := range trueExpectedDateList {
 		t.Run(fmt.Sprintf("TestData%d", i+1), func(t *testing.T) {
-			excelTime, err := timeToExcelTime(test.GoValue)
+			excelTime, err := timeToExcelTime(test.GoValue, false)
 			assert.NoError(t, err)
 			assert.Equalf(t, test.ExcelValue, excelTime,
 				"Time: %s", test.GoValue.String())
@@ -54,12 +54,19 @@ func TestTimeToExcelTime_Timezone(t *testing.T) {
 	}
 	for i, test := range trueExpectedDateList {
 		t.Run(fmt.Sprintf("TestData%d", i+1), func(t *testing.T) {
-			_, err := timeToExcelTime(test.GoValue.In(location))
+			_, err := timeToExcelTime(test.GoValue.In(location), false)
 			assert.EqualError(t, err, "only UTC time expected")
 		})
 	}
 }
 
+func TestTimeToExcelTime_1904(t *testing.T) {
+	excelTime, err := timeToExcelTime(time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 39813.0, excelTime)
+	assert.Equal(t, time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC), timeFromExcelTime(39813, true))
+}
+
 func TestTimeFromExcelTime(t *testing.T) {
 	for i, test := range excelTimeInputList {
 		t.Run(fmt.Sprintf("TestData%d", i+1), func(t *testing.T) {
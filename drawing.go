@@ -187,6 +187,15 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 			},
 		},
 	}
+	if formatSet.Title.None {
+		xlsxChartSpace.Chart.Title = nil
+		xlsxChartSpace.Chart.AutoTitleDeleted = &cAutoTitleDeleted{Val: true}
+	} else {
+		drawChartFont(&formatSet.Title.Font, &xlsxChartSpace.Chart.Title.Tx.Rich.P.R.RPr)
+	}
+	if formatSet.Legend.None || formatSet.Legend.Position == "none" {
+		xlsxChartSpace.Chart.Legend = nil
+	}
 	plotAreaFunc := map[string]func(*formatChart) *cPlotArea{
 		Area:                        f.drawBaseChart,
 		AreaStacked:                 f.drawBaseChart,
@@ -236,6 +245,7 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 		BarOfPieChart:               f.drawBarOfPieChart,
 		Radar:                       f.drawRadarChart,
 		Scatter:                     f.drawScatterChart,
+		Stock:                       f.drawStockChart,
 		Surface3D:                   f.drawSurface3DChart,
 		WireframeSurface3D:          f.drawSurface3DChart,
 		Contour:                     f.drawSurfaceChart,
@@ -253,12 +263,33 @@ func (f *File) addChart(formatSet *formatChart, comboCharts []*formatChart) {
 			immutable.FieldByName(mutable.Type().Field(i).Name).Set(field)
 		}
 	}
-	addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[formatSet.Type](formatSet))
-	order := len(formatSet.Series)
-	for idx := range comboCharts {
-		comboCharts[idx].order = order
-		addChart(xlsxChartSpace.Chart.PlotArea, plotAreaFunc[comboCharts[idx].Type](comboCharts[idx]))
-		order += len(comboCharts[idx].Series)
+	// The primary and secondary axes are drawn once by the first chart on each
+	// axis, so that the axes referenced by all charts will be kept.
+	var primary, secondary *cPlotArea
+	order := 0
+	for idx, chart := range append([]*formatChart{formatSet}, comboCharts...) {
+		if idx > 0 {
+			chart.order = order
+		}
+		plotArea := plotAreaFunc[chart.Type](chart)
+		if plotArea.ValAx != nil {
+			if !chart.SecondaryAxis && primary == nil {
+				primary = &cPlotArea{CatAx: plotArea.CatAx, ValAx: plotArea.ValAx, SerAx: plotArea.SerAx}
+			}
+			if chart.SecondaryAxis && secondary == nil {
+				secondary = &cPlotArea{CatAx: plotArea.CatAx, ValAx: plotArea.ValAx}
+			}
+		}
+		plotArea.CatAx, plotArea.ValAx, plotArea.SerAx = nil, nil, nil
+		addChart(xlsxChartSpace.Chart.PlotArea, plotArea)
+		order += len(chart.Series)
+	}
+	for _, axs := range []*cPlotArea{primary, secondary} {
+		if axs != nil {
+			xlsxChartSpace.Chart.PlotArea.CatAx = append(xlsxChartSpace.Chart.PlotArea.CatAx, axs.CatAx...)
+			xlsxChartSpace.Chart.PlotArea.ValAx = append(xlsxChartSpace.Chart.PlotArea.ValAx, axs.ValAx...)
+			xlsxChartSpace.Chart.PlotArea.SerAx = append(xlsxChartSpace.Chart.PlotArea.SerAx, axs.SerAx...)
+		}
 	}
 	chart, _ := xml.Marshal(xlsxChartSpace)
 	media := "xl/charts/chart" + strconv.Itoa(count+1) + ".xml"
@@ -278,13 +309,10 @@ func (f *File) drawBaseChart(formatSet *formatChart) *cPlotArea {
 		VaryColors: &attrValBool{
 			Val: boolPtr(true),
 		},
-		Ser:   f.drawChartSeries(formatSet),
-		Shape: f.drawChartShape(formatSet),
-		DLbls: f.drawChartDLbls(formatSet),
-		AxID: []*attrValInt{
-			{Val: intPtr(754001152)},
-			{Val: intPtr(753999904)},
-		},
+		Ser:     f.drawChartSeries(formatSet),
+		Shape:   f.drawChartShape(formatSet),
+		DLbls:   f.drawChartDLbls(formatSet),
+		AxID:    f.drawChartAxID(formatSet),
 		Overlap: &attrValInt{Val: intPtr(100)},
 	}
 	var ok bool
@@ -544,10 +572,7 @@ func (f *File) drawLineChart(formatSet *formatChart) *cPlotArea {
 			Smooth: &attrValBool{
 				Val: boolPtr(false),
 			},
-			AxID: []*attrValInt{
-				{Val: intPtr(754001152)},
-				{Val: intPtr(753999904)},
-			},
+			AxID: f.drawChartAxID(formatSet),
 		},
 		CatAx: f.drawPlotAreaCatAx(formatSet),
 		ValAx: f.drawPlotAreaValAx(formatSet),
@@ -627,10 +652,7 @@ func (f *File) drawRadarChart(formatSet *formatChart) *cPlotArea {
 			},
 			Ser:   f.drawChartSeries(formatSet),
 			DLbls: f.drawChartDLbls(formatSet),
-			AxID: []*attrValInt{
-				{Val: intPtr(754001152)},
-				{Val: intPtr(753999904)},
-			},
+			AxID:  f.drawChartAxID(formatSet),
 		},
 		CatAx: f.drawPlotAreaCatAx(formatSet),
 		ValAx: f.drawPlotAreaValAx(formatSet),
@@ -650,14 +672,36 @@ func (f *File) drawScatterChart(formatSet *formatChart) *cPlotArea {
 			},
 			Ser:   f.drawChartSeries(formatSet),
 			DLbls: f.drawChartDLbls(formatSet),
-			AxID: []*attrValInt{
-				{Val: intPtr(754001152)},
-				{Val: intPtr(753999904)},
-			},
+			AxID:  f.drawChartAxID(formatSet),
+		},
+		CatAx: f.drawPlotAreaCatAx(formatSet),
+		ValAx: f.drawPlotAreaValAx(formatSet),
+	}
+}
+
+// drawStockChart provides a function to draw the c:plotArea element for stock
+// chart by given format sets. The high-low lines will be drawn for the stock
+// chart, and the up-down bars will be drawn for the open-high-low-close stock
+// chart which has 4 series.
+func (f *File) drawStockChart(formatSet *formatChart) *cPlotArea {
+	plotArea := &cPlotArea{
+		StockChart: &cCharts{
+			Ser:        f.drawChartSeries(formatSet),
+			DLbls:      f.drawChartDLbls(formatSet),
+			HiLowLines: &cChartLines{},
+			AxID:       f.drawChartAxID(formatSet),
 		},
 		CatAx: f.drawPlotAreaCatAx(formatSet),
 		ValAx: f.drawPlotAreaValAx(formatSet),
 	}
+	if len(formatSet.Series) == 4 {
+		plotArea.StockChart.UpDownBars = &cUpDownBars{
+			GapWidth: &attrValInt{Val: intPtr(150)},
+			UpBars:   &cChartLines{},
+			DownBars: &cChartLines{},
+		}
+	}
+	return plotArea
 }
 
 // drawSurface3DChart provides a function to draw the c:surface3DChart element by
@@ -752,7 +796,9 @@ func (f *File) drawChartSeries(formatSet *formatChart) *[]cSer {
 			SpPr:       f.drawChartSeriesSpPr(k, formatSet),
 			Marker:     f.drawChartSeriesMarker(k, formatSet),
 			DPt:        f.drawChartSeriesDPt(k, formatSet),
-			DLbls:      f.drawChartSeriesDLbls(formatSet),
+			DLbls:      f.drawChartSeriesDLbls(k, formatSet),
+			Trendline:  f.drawChartSeriesTrendline(k, formatSet),
+			ErrBars:    f.drawChartSeriesErrBars(k, formatSet),
 			Cat:        f.drawChartSeriesCat(formatSet.Series[k], formatSet),
 			Val:        f.drawChartSeriesVal(formatSet.Series[k], formatSet),
 			XVal:       f.drawChartSeriesXVal(formatSet.Series[k], formatSet),
@@ -784,7 +830,7 @@ func (f *File) drawChartSeriesSpPr(i int, formatSet *formatChart) *cSpPr {
 			SchemeClr: &aSchemeClr{Val: "accent" + strconv.Itoa(i+formatSet.order+1)},
 		}
 	}
-	chartSeriesSpPr := map[string]*cSpPr{Line: spPrLine, Scatter: spPrScatter}
+	chartSeriesSpPr := map[string]*cSpPr{Line: spPrLine, Scatter: spPrScatter, Stock: spPrScatter}
 	return chartSeriesSpPr[formatSet.Type]
 }
 
@@ -871,7 +917,16 @@ func (f *File) drawChartSeriesMarker(i int, formatSet *formatChart) *cMarker {
 			},
 		}
 	}
-	chartSeriesMarker := map[string]*cMarker{Scatter: marker}
+	// The stock chart only shows the marker of the close values in the
+	// high-low-close stock chart.
+	stockMarker := &cMarker{Symbol: &attrValString{Val: stringPtr("none")}}
+	if len(formatSet.Series) == 3 && i == 2 {
+		stockMarker = &cMarker{
+			Symbol: &attrValString{Val: stringPtr("dot")},
+			Size:   &attrValInt{Val: intPtr(3)},
+		}
+	}
+	chartSeriesMarker := map[string]*cMarker{Scatter: marker, Stock: stockMarker}
 	return chartSeriesMarker[formatSet.Type]
 }
 
@@ -936,8 +991,31 @@ func (f *File) drawChartDLbls(formatSet *formatChart) *cDLbls {
 }
 
 // drawChartSeriesDLbls provides a function to draw the c:dLbls element by
-// given format sets.
-func (f *File) drawChartSeriesDLbls(formatSet *formatChart) *cDLbls {
+// given format sets. The data labels settings of the series will be used if
+// it has been specified, otherwise the data labels of the chart will be used.
+func (f *File) drawChartSeriesDLbls(i int, formatSet *formatChart) *cDLbls {
+	if labels := formatSet.Series[i].DataLabels; labels != nil {
+		chartSeriesDLbls := map[string]*cDLbls{Surface3D: nil, WireframeSurface3D: nil, Contour: nil, WireframeContour: nil}
+		if _, ok := chartSeriesDLbls[formatSet.Type]; ok {
+			return nil
+		}
+		dLbls := &cDLbls{
+			ShowLegendKey:   &attrValBool{Val: boolPtr(false)},
+			ShowVal:         &attrValBool{Val: boolPtr(labels.ShowVal)},
+			ShowCatName:     &attrValBool{Val: boolPtr(labels.ShowCatName)},
+			ShowSerName:     &attrValBool{Val: boolPtr(labels.ShowSerName)},
+			ShowPercent:     &attrValBool{Val: boolPtr(labels.ShowPercent)},
+			ShowBubbleSize:  &attrValBool{Val: boolPtr(labels.ShowBubbleSize)},
+			ShowLeaderLines: &attrValBool{Val: boolPtr(labels.ShowLeaderLines)},
+		}
+		if labels.NumFmt != "" {
+			dLbls.NumFmt = &cNumFmt{FormatCode: labels.NumFmt}
+		}
+		if labels.Position != "" {
+			dLbls.DLblPos = &attrValString{Val: stringPtr(chartDataLabelsPosition[labels.Position])}
+		}
+		return dLbls
+	}
 	dLbls := f.drawChartDLbls(formatSet)
 	chartSeriesDLbls := map[string]*cDLbls{Scatter: nil, Surface3D: nil, WireframeSurface3D: nil, Contour: nil, WireframeContour: nil, Bubble: nil, Bubble3D: nil}
 	if _, ok := chartSeriesDLbls[formatSet.Type]; ok {
@@ -946,8 +1024,93 @@ func (f *File) drawChartSeriesDLbls(formatSet *formatChart) *cDLbls {
 	return dLbls
 }
 
+// drawChartSeriesTrendline provides a function to draw the c:trendline element
+// by given series index and format sets.
+func (f *File) drawChartSeriesTrendline(i int, formatSet *formatChart) *cTrendline {
+	settings := formatSet.Series[i].Trendline
+	if settings == nil {
+		return nil
+	}
+	trendline := &cTrendline{
+		Name:          settings.Name,
+		TrendlineType: &attrValString{Val: stringPtr(chartTrendlineTypes[settings.Type])},
+		DispRSqr:      &attrValBool{Val: boolPtr(settings.DisplayRSquared)},
+		DispEq:        &attrValBool{Val: boolPtr(settings.DisplayEquation)},
+	}
+	switch settings.Type {
+	case "polynomial":
+		trendline.Order = &attrValInt{Val: intPtr(settings.Order)}
+	case "moving_average":
+		trendline.Period = &attrValInt{Val: intPtr(settings.Period)}
+	}
+	if settings.Type != "moving_average" {
+		if settings.Forward > 0 {
+			trendline.Forward = &attrValFloat{Val: float64Ptr(settings.Forward)}
+		}
+		if settings.Backward > 0 {
+			trendline.Backward = &attrValFloat{Val: float64Ptr(settings.Backward)}
+		}
+	}
+	return trendline
+}
+
+// drawChartSeriesErrBars provides a function to draw the c:errBars element by
+// given series index and format sets. The error bars use the fixed value type
+// and show both plus and minus directions by default.
+func (f *File) drawChartSeriesErrBars(i int, formatSet *formatChart) *cErrBars {
+	settings := formatSet.Series[i].ErrorBars
+	if settings == nil {
+		return nil
+	}
+	errBarType, valueType := "both", "fixed"
+	if settings.Type != "" {
+		errBarType = settings.Type
+	}
+	if settings.ValueType != "" {
+		valueType = settings.ValueType
+	}
+	errBars := &cErrBars{
+		ErrBarType: &attrValString{Val: stringPtr(chartErrorBarsTypes[errBarType])},
+		ErrValType: &attrValString{Val: stringPtr(chartErrorBarsValueTypes[valueType])},
+		NoEndCap:   &attrValBool{Val: boolPtr(settings.NoEndCap)},
+	}
+	if formatSet.Type == Scatter || formatSet.Type == Bubble {
+		direction := "y"
+		if settings.Direction != "" {
+			direction = settings.Direction
+		}
+		errBars.ErrDir = &attrValString{Val: stringPtr(direction)}
+	}
+	if valueType != "standard_error" {
+		value := settings.Value
+		if value == 0 {
+			value = map[string]float64{"fixed": 1, "percentage": 5, "standard_deviation": 1}[valueType]
+		}
+		errBars.Val = &attrValFloat{Val: float64Ptr(value)}
+	}
+	return errBars
+}
+
+// drawChartAxID provides a function to draw the c:axId elements of the chart
+// by given format sets, the chart on the secondary axis references the
+// secondary category and value axes.
+func (f *File) drawChartAxID(formatSet *formatChart) []*attrValInt {
+	catAxID, valAxID := chartAxID(formatSet)
+	return []*attrValInt{{Val: intPtr(catAxID)}, {Val: intPtr(valAxID)}}
+}
+
+// chartAxID provides a function to get the ID of category and value axes by
+// given format sets.
+func chartAxID(formatSet *formatChart) (int, int) {
+	if formatSet.SecondaryAxis {
+		return 754001153, 753999905
+	}
+	return 754001152, 753999904
+}
+
 // drawPlotAreaCatAx provides a function to draw the c:catAx element.
 func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
+	catAxID, valAxID := chartAxID(formatSet)
 	min := &attrValFloat{Val: float64Ptr(formatSet.XAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.XAxis.Maximum)}
 	if formatSet.XAxis.Minimum == 0 {
@@ -958,7 +1121,7 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 	}
 	axs := []*cAxs{
 		{
-			AxID: &attrValInt{Val: intPtr(754001152)},
+			AxID: &attrValInt{Val: intPtr(catAxID)},
 			Scaling: &cScaling{
 				Orientation: &attrValString{Val: stringPtr(orientation[formatSet.XAxis.ReverseOrder])},
 				Max:         max,
@@ -975,7 +1138,7 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 			TickLblPos:    &attrValString{Val: stringPtr("nextTo")},
 			SpPr:          f.drawPlotAreaSpPr(),
 			TxPr:          f.drawPlotAreaTxPr(),
-			CrossAx:       &attrValInt{Val: intPtr(753999904)},
+			CrossAx:       &attrValInt{Val: intPtr(valAxID)},
 			Crosses:       &attrValString{Val: stringPtr("autoZero")},
 			Auto:          &attrValBool{Val: boolPtr(true)},
 			LblAlgn:       &attrValString{Val: stringPtr("ctr")},
@@ -992,11 +1155,20 @@ func (f *File) drawPlotAreaCatAx(formatSet *formatChart) []*cAxs {
 	if formatSet.XAxis.TickLabelSkip != 0 {
 		axs[0].TickLblSkip = &attrValInt{Val: intPtr(formatSet.XAxis.TickLabelSkip)}
 	}
+	if formatSet.XAxis.TickMarkSkip != 0 {
+		axs[0].TickMarkSkip = &attrValInt{Val: intPtr(formatSet.XAxis.TickMarkSkip)}
+	}
+	axs[0].Title = f.drawPlotAreaAxisTitle(&formatSet.XAxis, isHorizontalBarChart(formatSet.Type))
+	if formatSet.SecondaryAxis {
+		axs[0].Delete.Val = boolPtr(true)
+		axs[0].MajorGridlines, axs[0].MinorGridlines, axs[0].Title = nil, nil, nil
+	}
 	return axs
 }
 
 // drawPlotAreaValAx provides a function to draw the c:valAx element.
 func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
+	catAxID, valAxID := chartAxID(formatSet)
 	min := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Minimum)}
 	max := &attrValFloat{Val: float64Ptr(formatSet.YAxis.Maximum)}
 	if formatSet.YAxis.Minimum == 0 {
@@ -1007,7 +1179,7 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 	}
 	axs := []*cAxs{
 		{
-			AxID: &attrValInt{Val: intPtr(753999904)},
+			AxID: &attrValInt{Val: intPtr(valAxID)},
 			Scaling: &cScaling{
 				Orientation: &attrValString{Val: stringPtr(orientation[formatSet.YAxis.ReverseOrder])},
 				Max:         max,
@@ -1024,7 +1196,7 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 			TickLblPos:    &attrValString{Val: stringPtr("nextTo")},
 			SpPr:          f.drawPlotAreaSpPr(),
 			TxPr:          f.drawPlotAreaTxPr(),
-			CrossAx:       &attrValInt{Val: intPtr(754001152)},
+			CrossAx:       &attrValInt{Val: intPtr(catAxID)},
 			Crosses:       &attrValString{Val: stringPtr("autoZero")},
 			CrossBetween:  &attrValString{Val: stringPtr(chartValAxCrossBetween[formatSet.Type])},
 		},
@@ -1041,6 +1213,18 @@ func (f *File) drawPlotAreaValAx(formatSet *formatChart) []*cAxs {
 	if formatSet.YAxis.MajorUnit != 0 {
 		axs[0].MajorUnit = &attrValFloat{Val: float64Ptr(formatSet.YAxis.MajorUnit)}
 	}
+	if formatSet.YAxis.MinorUnit != 0 {
+		axs[0].MinorUnit = &attrValFloat{Val: float64Ptr(formatSet.YAxis.MinorUnit)}
+	}
+	if formatSet.YAxis.LogBase != 0 {
+		axs[0].Scaling.LogBase = &attrValFloat{Val: float64Ptr(formatSet.YAxis.LogBase)}
+	}
+	axs[0].Title = f.drawPlotAreaAxisTitle(&formatSet.YAxis, !isHorizontalBarChart(formatSet.Type))
+	if formatSet.SecondaryAxis {
+		axs[0].AxPos.Val = stringPtr("r")
+		axs[0].Crosses.Val = stringPtr("max")
+		axs[0].MajorGridlines, axs[0].MinorGridlines = nil, nil
+	}
 	return axs
 }
 
@@ -1072,6 +1256,108 @@ func (f *File) drawPlotAreaSerAx(formatSet *formatChart) []*cAxs {
 	}
 }
 
+// isHorizontalBarChart provides a function to check if the given chart type
+// is a bar chart which plots the categories on the vertical axis.
+func isHorizontalBarChart(chartType string) bool {
+	return strings.HasPrefix(chartType, Bar) && chartType != BarOfPieChart
+}
+
+// drawPlotAreaAxisTitle provides a function to draw the c:title element of
+// the chart axis. The title of the vertical axis will be rotated.
+func (f *File) drawPlotAreaAxisTitle(axis *formatChartAxis, vertical bool) *cTitle {
+	if axis.Name == "" {
+		return nil
+	}
+	bodyPr := aBodyPr{
+		SpcFirstLastPara: true,
+		VertOverflow:     "ellipsis",
+		Vert:             "horz",
+		Wrap:             "square",
+		Anchor:           "ctr",
+		AnchorCtr:        true,
+	}
+	if vertical {
+		bodyPr.Rot = -5400000
+	}
+	title := &cTitle{
+		Tx: cTx{
+			Rich: &cRich{
+				BodyPr: bodyPr,
+				P: aP{
+					PPr: &aPPr{
+						DefRPr: aRPr{
+							Kern:   1200,
+							Strike: "noStrike",
+							U:      "none",
+							Sz:     1000,
+							B:      true,
+							SolidFill: &aSolidFill{
+								SchemeClr: &aSchemeClr{
+									Val:    "tx1",
+									LumMod: &attrValInt{Val: intPtr(65000)},
+									LumOff: &attrValInt{Val: intPtr(35000)},
+								},
+							},
+							Latin: &aLatin{Typeface: "+mn-lt"},
+							Ea:    &aEa{Typeface: "+mn-ea"},
+							Cs:    &aCs{Typeface: "+mn-cs"},
+						},
+					},
+					R: &aR{
+						RPr: aRPr{Lang: "en-US", AltLang: "en-US"},
+						T:   axis.Name,
+					},
+				},
+			},
+		},
+		TxPr: cTxPr{
+			BodyPr: bodyPr,
+			P: aP{
+				PPr: &aPPr{
+					DefRPr: aRPr{
+						Kern:   1200,
+						U:      "none",
+						Sz:     1000,
+						Strike: "noStrike",
+					},
+				},
+				EndParaRPr: &aEndParaRPr{Lang: "en-US"},
+			},
+		},
+		Overlay: &attrValBool{Val: boolPtr(false)},
+	}
+	drawChartFont(&axis.NameFont, &title.Tx.Rich.P.R.RPr)
+	return title
+}
+
+// drawChartFont provides a function to set the run properties of the chart
+// title and axis titles by given font settings.
+func drawChartFont(font *Font, rPr *aRPr) {
+	rPr.B, rPr.I = font.Bold, font.Italic
+	if font.Strike {
+		rPr.Strike = "sngStrike"
+	}
+	switch font.Underline {
+	case "single", "singleAccounting":
+		rPr.U = "sng"
+	case "double", "doubleAccounting":
+		rPr.U = "dbl"
+	}
+	if font.Size != 0 {
+		rPr.Sz = font.Size * 100
+	}
+	if font.Color != "" {
+		rPr.SolidFill = &aSolidFill{
+			SrgbClr: &attrValString{Val: stringPtr(strings.ToUpper(strings.TrimPrefix(font.Color, "#")))},
+		}
+	}
+	if font.Family != "" {
+		rPr.Latin = &aLatin{Typeface: font.Family}
+		rPr.Ea = &aEa{Typeface: font.Family}
+		rPr.Cs = &aCs{Typeface: font.Family}
+	}
+}
+
 // drawPlotAreaSpPr provides a function to draw the c:spPr element.
 func (f *File) drawPlotAreaSpPr() *cSpPr {
 	return &cSpPr{
@@ -1275,6 +1561,7 @@ func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (err
 	var (
 		wsDr            *xlsxWsDr
 		deTwoCellAnchor *decodeTwoCellAnchor
+		rIDs            []string
 	)
 	xdrCellAnchorFuncs := map[string]func(anchor *xdrCellAnchor) bool{
 		"Chart": func(anchor *xdrCellAnchor) bool { return anchor.Pic == nil },
@@ -1288,6 +1575,12 @@ func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (err
 	for idx := 0; idx < len(wsDr.TwoCellAnchor); idx++ {
 		if err = nil; wsDr.TwoCellAnchor[idx].From != nil && xdrCellAnchorFuncs[drawingType](wsDr.TwoCellAnchor[idx]) {
 			if wsDr.TwoCellAnchor[idx].From.Col == col && wsDr.TwoCellAnchor[idx].From.Row == row {
+				deTwoCellAnchor = new(decodeTwoCellAnchor)
+				_ = f.xmlNewDecoder(bytes.NewReader([]byte("<decodeTwoCellAnchor>" + wsDr.TwoCellAnchor[idx].GraphicFrame + "</decodeTwoCellAnchor>"))).
+					Decode(deTwoCellAnchor)
+				if deTwoCellAnchor.GraphicFrame != nil {
+					rIDs = append(rIDs, deTwoCellAnchor.GraphicFrame.Graphic.GraphicData.Chart.RID)
+				}
 				wsDr.TwoCellAnchor = append(wsDr.TwoCellAnchor[:idx], wsDr.TwoCellAnchor[idx+1:]...)
 				idx--
 			}
@@ -1302,11 +1595,97 @@ func (f *File) deleteDrawing(col, row int, drawingXML, drawingType string) (err
 		}
 		if err = nil; deTwoCellAnchor.From != nil && decodeTwoCellAnchorFuncs[drawingType](deTwoCellAnchor) {
 			if deTwoCellAnchor.From.Col == col && deTwoCellAnchor.From.Row == row {
+				if deTwoCellAnchor.GraphicFrame != nil {
+					rIDs = append(rIDs, deTwoCellAnchor.GraphicFrame.Graphic.GraphicData.Chart.RID)
+				}
 				wsDr.TwoCellAnchor = append(wsDr.TwoCellAnchor[:idx], wsDr.TwoCellAnchor[idx+1:]...)
 				idx--
 			}
 		}
 	}
 	f.Drawings[drawingXML] = wsDr
+	if drawingType == "Chart" {
+		drawingRels := strings.Replace(drawingXML, "xl/drawings/", "xl/drawings/_rels/", 1) + ".rels"
+		for _, rID := range rIDs {
+			f.deleteDrawingChart(drawingRels, rID)
+		}
+	}
 	return err
 }
+
+// deleteDrawingChart provides a function to delete the chart part, the
+// relationship in the drawing relationships and the content type part of the
+// chart by given drawing relationships path and relationship ID. The last
+// chart part will be renumbered to fill the gap of the deleted chart.
+func (f *File) deleteDrawingChart(drawingRels, rID string) {
+	rels := f.relsReader(drawingRels)
+	if rels == nil || rID == "" {
+		return
+	}
+	var chartXML string
+	for k, v := range rels.Relationships {
+		if v.ID == rID && v.Type == SourceRelationshipChart {
+			chartXML = strings.Replace(v.Target, "..", "xl", 1)
+			rels.Relationships = append(rels.Relationships[:k], rels.Relationships[k+1:]...)
+			break
+		}
+	}
+	if _, ok := f.XLSX[chartXML]; !ok {
+		return
+	}
+	chartRels := strings.Replace(chartXML, "xl/charts/", "xl/charts/_rels/", 1) + ".rels"
+	delete(f.XLSX, chartXML)
+	delete(f.XLSX, chartRels)
+	delete(f.Relationships, chartRels)
+	content := f.contentTypesReader()
+	for k, v := range content.Overrides {
+		if v.PartName == "/"+chartXML {
+			content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
+			break
+		}
+	}
+	lastChartXML := "xl/charts/chart" + strconv.Itoa(f.countCharts()+1) + ".xml"
+	if _, ok := f.XLSX[lastChartXML]; !ok {
+		return
+	}
+	f.renameDrawingChart(lastChartXML, chartXML)
+}
+
+// renameDrawingChart provides a function to rename the chart part by given
+// old and new path of the chart, and update the relationships of the
+// drawings and the content type part which referenced the chart.
+func (f *File) renameDrawingChart(oldPath, newPath string) {
+	f.XLSX[newPath] = f.XLSX[oldPath]
+	delete(f.XLSX, oldPath)
+	oldRels := strings.Replace(oldPath, "xl/charts/", "xl/charts/_rels/", 1) + ".rels"
+	newRels := strings.Replace(newPath, "xl/charts/", "xl/charts/_rels/", 1) + ".rels"
+	if rels, ok := f.XLSX[oldRels]; ok {
+		f.XLSX[newRels] = rels
+		delete(f.XLSX, oldRels)
+	}
+	if rels, ok := f.Relationships[oldRels]; ok {
+		f.Relationships[newRels] = rels
+		delete(f.Relationships, oldRels)
+	}
+	content := f.contentTypesReader()
+	for k, v := range content.Overrides {
+		if v.PartName == "/"+oldPath {
+			content.Overrides[k].PartName = "/" + newPath
+		}
+	}
+	for path := range f.XLSX {
+		if strings.HasPrefix(path, "xl/drawings/_rels/") {
+			f.relsReader(path)
+		}
+	}
+	for path, rels := range f.Relationships {
+		if !strings.HasPrefix(path, "xl/drawings/_rels/") || rels == nil {
+			continue
+		}
+		for k, v := range rels.Relationships {
+			if v.Type == SourceRelationshipChart && strings.Replace(v.Target, "..", "xl", 1) == oldPath {
+				rels.Relationships[k].Target = strings.Replace(newPath, "xl", "..", 1)
+			}
+		}
+	}
+}
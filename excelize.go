@@ -20,6 +20,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -46,19 +48,46 @@ type File struct {
 	Relationships    map[string]*xlsxRelationships
 	XLSX             map[string][]byte
 	CharsetReader    charsetTranscoderFn
+	functions        map[string]func(args []FormulaArg) (FormulaArg, error)
+	digits           int
+	themeUpdated     bool
+	lazyWorksheets   map[string]*zip.File
+	warnings         []string
 }
 
 type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, err error)
 
+// Options define the options for opening the spreadsheet. CharsetReader
+// specifies the user defined codepage transcoder function for reading the
+// XML parts from non UTF-8 encoding while opening the spreadsheet, the
+// default transcoder will be used if it's nil. Password specifies the
+// password of the spreadsheet encrypted by the ECMA-376 agile encryption.
+// WarnUnsupported specifies whether to check the parts of the spreadsheet
+// which are not supported by the library while opening, the result could be
+// got by the Warnings function.
+type Options struct {
+	CharsetReader   charsetTranscoderFn
+	Password        string
+	WarnUnsupported bool
+}
+
 // OpenFile take the name of an XLSX file and returns a populated XLSX file
-// struct for it.
-func OpenFile(filename string) (*File, error) {
+// struct for it. The password of the encrypted spreadsheet could be
+// specified by the options. For example, open the spreadsheet with password
+// protection:
+//
+//    f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{Password: "password"})
+//    if err != nil {
+//        return
+//    }
+//
+func OpenFile(filename string, opts ...Options) (*File, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-	f, err := OpenReader(file)
+	f, err := OpenReader(file, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -81,14 +110,98 @@ func newFile() *File {
 	}
 }
 
-// OpenReader take an io.Reader and return a populated XLSX file.
-func OpenReader(r io.Reader) (*File, error) {
+// OpenReader take an io.Reader and return a populated XLSX file. The options
+// for opening the spreadsheet could be specified optionally.
+func OpenReader(r io.Reader, opts ...Options) (*File, error) {
+	var options Options
+	for _, opt := range opts {
+		options = opt
+	}
+	zr, err := newZipReader(r, options)
+	if err != nil {
+		return nil, err
+	}
+	file, sheetCount, err := ReadZipReader(zr)
+	if err != nil {
+		return nil, err
+	}
+	f := newFile()
+	if options.CharsetReader != nil {
+		f.CharsetReader = options.CharsetReader
+	}
+	f.SheetCount, f.XLSX = sheetCount, file
+	f.CalcChain = f.calcChainReader()
+	f.sheetMap = f.getSheetMap()
+	f.Styles = f.stylesReader()
+	f.Theme = f.themeReader()
+	if options.WarnUnsupported {
+		f.checkUnsupportedParts()
+	}
+	return f, nil
+}
+
+// OpenReaderStream take an io.Reader and return a populated XLSX file in the
+// read-only streaming mode, which is useful for the services that only
+// iterate over the rows of the large spreadsheet. The worksheets will not be
+// decompressed and parsed while opening, the rows iterator created by the
+// Rows function will read the worksheet from the archive directly. Note that
+// the worksheet will be decompressed and parsed on first use of the random
+// access functions, such as GetCellValue and SetCellValue, and the memory
+// usage will be the same as OpenReader since then. For example, open the
+// spreadsheet and iterate over the rows of Sheet1:
+//
+//    f, err := excelize.OpenReaderStream(r, excelize.Options{})
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    rows, err := f.Rows("Sheet1")
+//
+func OpenReaderStream(r io.Reader, opts Options) (*File, error) {
+	zr, err := newZipReader(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	f := newFile()
+	if opts.CharsetReader != nil {
+		f.CharsetReader = opts.CharsetReader
+	}
+	f.XLSX = make(map[string][]byte, len(zr.File))
+	f.lazyWorksheets = make(map[string]*zip.File)
+	for _, v := range zr.File {
+		if strings.HasPrefix(v.Name, "xl/worksheets/sheet") {
+			f.lazyWorksheets[v.Name] = v
+			f.SheetCount++
+			continue
+		}
+		f.XLSX[v.Name] = readFile(v)
+	}
+	f.CalcChain = f.calcChainReader()
+	f.sheetMap = f.getSheetMap()
+	f.Styles = f.stylesReader()
+	f.Theme = f.themeReader()
+	if opts.WarnUnsupported {
+		f.checkUnsupportedParts()
+	}
+	return f, nil
+}
+
+// newZipReader provides a function to read all content from the reader and
+// create the archive reader for it, the encrypted spreadsheet will be
+// decrypted if the password has been specified in the options.
+func newZipReader(r io.Reader, opts Options) (*zip.Reader, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
 	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil && opts.Password != "" && bytes.HasPrefix(b, oleIdentifier) {
+		if b, err = decrypt(b, opts.Password); err != nil {
+			return nil, err
+		}
+		return zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	}
 	if err != nil {
 		identifier := []byte{
 			// checking protect workbook by [MS-OFFCRYPTO] - v20181211 3.1 FeatureIdentifier
@@ -99,28 +212,122 @@ func OpenReader(r io.Reader) (*File, error) {
 			0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
 		}
 		if bytes.Contains(b, identifier) {
-			return nil, errors.New("not support encrypted file currently")
+			return nil, errors.New("the password is required to open the encrypted file")
 		}
 		return nil, err
 	}
+	return zr, err
+}
 
-	file, sheetCount, err := ReadZipReader(zr)
-	if err != nil {
-		return nil, err
+// supportedContentTypes defined the list of the content types of the parts
+// which could be handled by the library.
+var supportedContentTypes = []string{
+	ContentTypeControlProperties,
+	ContentTypeDrawing,
+	ContentTypeDrawingML,
+	ContentTypeMacro,
+	ContentTypeSheetML,
+	ContentTypeSpreadSheetMLChartsheet,
+	ContentTypeSpreadSheetMLComments,
+	ContentTypeSpreadSheetMLThreadedComments,
+	ContentTypeSpreadSheetMLPerson,
+	ContentTypeSpreadSheetMLPivotCacheDefinition,
+	ContentTypeSpreadSheetMLPivotTable,
+	ContentTypeSpreadSheetMLTable,
+	ContentTypeSlicer,
+	ContentTypeSlicerCache,
+	ContentTypeSpreadSheetMLWorksheet,
+	ContentTypeVBA,
+	ContentTypeVML,
+	"application/vnd.ms-office.vbaProjectSignature",
+	"application/vnd.openxmlformats-officedocument.extended-properties+xml",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.calcChain+xml",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sharedStrings+xml",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml",
+	"application/vnd.openxmlformats-officedocument.theme+xml",
+	"application/vnd.openxmlformats-package.core-properties+xml",
+	"application/vnd.openxmlformats-package.relationships+xml",
+	"application/xml",
+}
+
+// checkUnsupportedParts provides a function to compare the content types of
+// the parts in the spreadsheet against the supported content types, and
+// record the warnings of the parts which will not be handled by the library.
+func (f *File) checkUnsupportedParts() {
+	content := f.contentTypesReader()
+	defaults, overrides := map[string]string{}, map[string]string{}
+	for _, d := range content.Defaults {
+		defaults[strings.ToLower(d.Extension)] = d.ContentType
 	}
-	f := newFile()
-	f.SheetCount, f.XLSX = sheetCount, file
-	f.CalcChain = f.calcChainReader()
-	f.sheetMap = f.getSheetMap()
-	f.Styles = f.stylesReader()
-	f.Theme = f.themeReader()
-	return f, nil
+	for _, o := range content.Overrides {
+		overrides[strings.TrimPrefix(o.PartName, "/")] = o.ContentType
+	}
+	parts := make([]string, 0, len(f.XLSX))
+	for name := range f.XLSX {
+		if name != "[Content_Types].xml" && !strings.HasSuffix(name, "/") {
+			parts = append(parts, name)
+		}
+	}
+	sort.Strings(parts)
+	f.warnings = nil
+	for _, name := range parts {
+		contentType, ok := overrides[name]
+		if !ok {
+			contentType = defaults[strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))]
+		}
+		if contentType == "" {
+			f.warnings = append(f.warnings, fmt.Sprintf("part %s has no content type", name))
+			continue
+		}
+		if strings.HasPrefix(contentType, "image/") || inStrSlice(supportedContentTypes, contentType) != -1 {
+			continue
+		}
+		f.warnings = append(f.warnings, fmt.Sprintf("part %s with unsupported content type %s", name, contentType))
+	}
+}
+
+// Warnings provides a function to get the warnings of the parts which are
+// not supported by the library, these parts will be kept as is on save, but
+// will not be updated with the changes of the spreadsheet, so the features
+// depending on them may be lost or broken. The warnings are only available
+// if the spreadsheet was opened with the WarnUnsupported option. For example:
+//
+//    f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{WarnUnsupported: true})
+//    if err != nil {
+//        return
+//    }
+//    for _, warning := range f.Warnings() {
+//        fmt.Println(warning)
+//    }
+//
+func (f *File) Warnings() []string {
+	return f.warnings
 }
 
 // CharsetTranscoder Set user defined codepage transcoder function for open
 // XLSX from non UTF-8 encoding.
 func (f *File) CharsetTranscoder(fn charsetTranscoderFn) *File { f.CharsetReader = fn; return f }
 
+// SignificantDigits set the number of significant digits of the float
+// numbers which written by the SetCellValue, the SetCellFloat with negative
+// precision and the StreamWriter, in the range from 1 to 17. The float
+// numbers will be rounded to the given significant digits and always be
+// written in the decimal notation instead of the scientific notation, set 15
+// digits to store the float numbers with the same precision of Excel. The
+// shortest representation of the float numbers will be written if the digits
+// is out of range, that's the default behavior. For example:
+//
+//    f := excelize.NewFile().SignificantDigits(15)
+//    err := f.SetCellValue("Sheet1", "A1", 0.1+0.2) // 0.3
+//
+func (f *File) SignificantDigits(digits int) *File {
+	if digits < 1 || digits > 17 {
+		digits = 0
+	}
+	f.digits = digits
+	return f
+}
+
 // Creates new XML decoder with charset reader.
 func (f *File) xmlNewDecoder(rdr io.Reader) (ret *xml.Decoder) {
 	ret = xml.NewDecoder(rdr)
@@ -143,6 +350,12 @@ func (f *File) setDefaultTimeStyle(sheet, axis string, format int) error {
 	return err
 }
 
+// isChartSheet provides a function to check if the sheet is a chart sheet by
+// given sheet name.
+func (f *File) isChartSheet(sheet string) bool {
+	return strings.HasPrefix(f.sheetMap[trimSheetName(sheet)], "xl/chartsheets")
+}
+
 // workSheetReader provides a function to get the pointer to the structure
 // after deserialization by given worksheet name.
 func (f *File) workSheetReader(sheet string) (xlsx *xlsxWorksheet, err error) {
@@ -161,12 +374,16 @@ func (f *File) workSheetReader(sheet string) (xlsx *xlsxWorksheet, err error) {
 			return
 		}
 		xlsx = new(xlsxWorksheet)
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(name)))).
+		content := namespaceStrictToTransitional(f.readXML(name))
+		if err = f.xmlNewDecoder(bytes.NewReader(content)).
 			Decode(xlsx); err != nil && err != io.EOF {
 			err = fmt.Errorf("xml decode error: %s", err)
 			return
 		}
 		err = nil
+		if xlsx.DecodeUnknown != nil {
+			xlsx.DecodeUnknown, xlsx.Unknown = nil, getUnknownElements(content)
+		}
 		if f.checked == nil {
 			f.checked = make(map[string]bool)
 		}
@@ -183,6 +400,52 @@ func (f *File) workSheetReader(sheet string) (xlsx *xlsxWorksheet, err error) {
 	return
 }
 
+// getUnknownElements provides a function to get the raw XML of the child
+// elements of the worksheet which are not supported by the worksheet model,
+// and the names of the supported elements before them. The unknown elements
+// will be dropped if the worksheet isn't encoded in UTF-8.
+func getUnknownElements(content []byte) []xlsxUnknownElement {
+	known := make(map[string]bool)
+	typ := reflect.TypeOf(xlsxWorksheet{})
+	for i := 1; i < typ.NumField(); i++ {
+		if name := strings.Split(typ.Field(i).Tag.Get("xml"), ",")[0]; name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	var (
+		elements []xlsxUnknownElement
+		after    string
+		depth    int
+		start    int64 = -1
+		decoder        = xml.NewDecoder(bytes.NewReader(content))
+	)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			return elements
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth++; depth == 2 {
+				if known[t.Name.Local] {
+					after = t.Name.Local
+					continue
+				}
+				start = offset
+			}
+		case xml.EndElement:
+			if depth--; depth == 1 && start != -1 {
+				elements = append(elements, xlsxUnknownElement{
+					After:   after,
+					Content: string(content[start:decoder.InputOffset()]),
+				})
+				start = -1
+			}
+		}
+	}
+}
+
 // checkSheet provides a function to fill each row element and make that is
 // continuous in a worksheet of XML.
 func checkSheet(xlsx *xlsxWorksheet) {
@@ -286,27 +549,31 @@ func (f *File) UpdateLinkedValue() error {
 	return nil
 }
 
+// oleIdentifier defined the magic header of the OLE compound file, which the
+// VBA project binary is stored as.
+var oleIdentifier = []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}
+
 // AddVBAProject provides the method to add vbaProject.bin file which contains
-// functions and/or macros. The file extension should be .xlsm. For example:
+// functions and/or macros by given content of the VBA project binary. The
+// file extension should be .xlsm. For example:
 //
 //    if err := f.SetSheetPrOptions("Sheet1", excelize.CodeName("Sheet1")); err != nil {
 //        fmt.Println(err)
 //    }
-//    if err := f.AddVBAProject("vbaProject.bin"); err != nil {
+//    file, err := ioutil.ReadFile("vbaProject.bin")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := f.AddVBAProject(file); err != nil {
 //        fmt.Println(err)
 //    }
 //    if err := f.SaveAs("macros.xlsm"); err != nil {
 //        fmt.Println(err)
 //    }
 //
-func (f *File) AddVBAProject(bin string) error {
-	var err error
-	// Check vbaProject.bin exists first.
-	if _, err = os.Stat(bin); os.IsNotExist(err) {
-		return err
-	}
-	if path.Ext(bin) != ".bin" {
-		return errors.New("unsupported VBA project extension")
+func (f *File) AddVBAProject(bin []byte) error {
+	if !bytes.HasPrefix(bin, oleIdentifier) {
+		return errors.New("unsupported VBA project")
 	}
 	f.setContentTypePartVBAProjectExtensions()
 	wb := f.relsReader("xl/_rels/workbook.xml.rels")
@@ -330,9 +597,8 @@ func (f *File) AddVBAProject(bin string) error {
 			Type:   SourceRelationshipVBAProject,
 		})
 	}
-	file, _ := ioutil.ReadFile(bin)
-	f.XLSX["xl/vbaProject.bin"] = file
-	return err
+	f.XLSX["xl/vbaProject.bin"] = bin
+	return nil
 }
 
 // setContentTypePartVBAProjectExtensions provides a function to set the
@@ -357,3 +623,69 @@ func (f *File) setContentTypePartVBAProjectExtensions() {
 		})
 	}
 }
+
+// RemoveVBAProject provides the method to remove the vbaProject.bin file
+// which contains functions and/or macros, and convert the workbook to the
+// macro-free workbook, the file extension should be .xlsx after that. The
+// VBA project of the macro-enabled workbook will be kept when saving if this
+// function is not called. For example:
+//
+//    f, err := excelize.OpenFile("macros.xlsm")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.RemoveVBAProject(); err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := f.SaveAs("macros.xlsx"); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) RemoveVBAProject() error {
+	wb := f.relsReader("xl/_rels/workbook.xml.rels")
+	if wb != nil {
+		rels := wb.Relationships[:0]
+		for _, rel := range wb.Relationships {
+			if rel.Type != SourceRelationshipVBAProject {
+				rels = append(rels, rel)
+			}
+		}
+		wb.Relationships = rels
+	}
+	// Remove the parts related to the VBA project, such as the digital
+	// signature of the VBA project.
+	parts := []string{"xl/vbaProject.bin"}
+	if rels := f.relsReader("xl/_rels/vbaProject.bin.rels"); rels != nil {
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" {
+				parts = append(parts, path.Join("xl", rel.Target))
+			}
+		}
+	}
+	delete(f.Relationships, "xl/_rels/vbaProject.bin.rels")
+	delete(f.XLSX, "xl/_rels/vbaProject.bin.rels")
+	for _, part := range parts {
+		delete(f.XLSX, part)
+	}
+	content := f.contentTypesReader()
+	overrides := content.Overrides[:0]
+	for _, o := range content.Overrides {
+		if inStrSlice(parts, strings.TrimPrefix(o.PartName, "/")) != -1 {
+			continue
+		}
+		if o.PartName == "/xl/workbook.xml" && o.ContentType == ContentTypeMacro {
+			o.ContentType = ContentTypeSheetML
+		}
+		overrides = append(overrides, o)
+	}
+	content.Overrides = overrides
+	defaults := content.Defaults[:0]
+	for _, d := range content.Defaults {
+		if d.Extension != "bin" || d.ContentType != ContentTypeVBA {
+			defaults = append(defaults, d)
+		}
+	}
+	content.Defaults = defaults
+	return nil
+}
@@ -5,10 +5,12 @@ import (
 	"compress/gzip"
 	"encoding/xml"
 	"fmt"
+	"image"
 	"image/color"
 	_ "image/gif"
 	_ "image/jpeg"
-	_ "image/png"
+	"image/png"
+	"io"
 	"io/ioutil"
 	"math"
 	"os"
@@ -83,7 +85,7 @@ func TestOpenFile(t *testing.T) {
 	assert.NoError(t, err)
 	_, err = f.GetCellFormula("Sheet2", "I11")
 	assert.NoError(t, err)
-	getSharedForumula(&xlsxWorksheet{}, "")
+	getSharedForumula(&xlsxWorksheet{}, "", "")
 
 	// Test read cell value with given illegal rows number.
 	_, err = f.GetCellValue("Sheet2", "a-1")
@@ -207,7 +209,7 @@ func TestOpenReader(t *testing.T) {
 		0x74, 0x00, 0x61, 0x00, 0x53, 0x00, 0x70, 0x00, 0x61, 0x00, 0x63, 0x00, 0x65, 0x00, 0x73, 0x00,
 		0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
 	}))
-	assert.EqualError(t, err, "not support encrypted file currently")
+	assert.EqualError(t, err, "the password is required to open the encrypted file")
 
 	// Test unexpected EOF.
 	var b bytes.Buffer
@@ -222,6 +224,109 @@ func TestOpenReader(t *testing.T) {
 	assert.EqualError(t, err, "unexpected EOF")
 }
 
+func TestWarnUnsupported(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{WarnUnsupported: true})
+	assert.NoError(t, err)
+	assert.Empty(t, f.Warnings())
+
+	f.XLSX["xl/webextensions/webextension1.xml"] = []byte(`<we:webextension xmlns:we="http://schemas.microsoft.com/office/webextensions/webextension/2010/11"/>`)
+	f.XLSX["xl/unknown.dat"] = []byte{0x00}
+	content := f.contentTypesReader()
+	content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/webextensions/webextension1.xml", ContentType: "application/vnd.ms-office.webextension+xml"})
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()), Options{WarnUnsupported: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"part xl/unknown.dat has no content type",
+		"part xl/webextensions/webextension1.xml with unsupported content type application/vnd.ms-office.webextension+xml",
+	}, f.Warnings())
+	f, err = OpenReaderStream(bytes.NewReader(buf.Bytes()), Options{WarnUnsupported: true})
+	assert.NoError(t, err)
+	assert.Len(t, f.Warnings(), 2)
+	// Test open the spreadsheet without the warnings option.
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Nil(t, f.Warnings())
+}
+
+func TestOpenReaderStream(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"A", 1}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"B", 2}))
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "Sheet2"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReaderStream(bytes.NewReader(buf.Bytes()), Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.SheetCount)
+	assert.Len(t, f.lazyWorksheets, 2)
+	assert.NotContains(t, f.XLSX, "xl/worksheets/sheet1.xml")
+	// Test iterate over the rows without decompressing the worksheet.
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]string
+	for rows.Next() {
+		row, err := rows.Columns()
+		assert.NoError(t, err)
+		results = append(results, row)
+	}
+	assert.Equal(t, [][]string{{"A", "1"}, nil, {"B", "2"}}, results)
+	assert.Len(t, f.lazyWorksheets, 2)
+	// Test the worksheet is closed after reading the last row.
+	assert.Nil(t, rows.reader)
+	// Test close the rows iterator which stopped early.
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.NotNil(t, rows.reader)
+	assert.NoError(t, rows.Close())
+	assert.Nil(t, rows.reader)
+	assert.NoError(t, rows.Close())
+	// Test save the workbook with the lazy loaded worksheets.
+	out, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f2, err := OpenReader(out)
+	assert.NoError(t, err)
+	val, err := f2.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "B", val)
+	// Test the worksheet will be loaded on first use of the random access functions.
+	val, err = f.GetCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet2", val)
+	assert.Len(t, f.lazyWorksheets, 1)
+	assert.Contains(t, f.XLSX, "xl/worksheets/sheet2.xml")
+	assert.NoError(t, f.SetCellValue("Sheet2", "B1", "B1"))
+	f.DeleteSheet("Sheet1")
+	assert.Len(t, f.lazyWorksheets, 0)
+	out, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f2, err = OpenReader(out)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{2: "Sheet2"}, f2.GetSheetMap())
+	val, err = f2.GetCellValue("Sheet2", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B1", val)
+
+	// Test open the workbook with the user defined charset transcoder.
+	f, err = OpenReaderStream(bytes.NewReader(buf.Bytes()), Options{CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.SheetCount)
+
+	_, err = OpenReaderStream(strings.NewReader(""), Options{})
+	assert.EqualError(t, err, "zip: not a valid zip file")
+}
+
 func TestBrokenFile(t *testing.T) {
 	// Test write file with broken file struct.
 	f := File{}
@@ -287,7 +392,7 @@ func TestNewFile(t *testing.T) {
 func TestAddDrawingVML(t *testing.T) {
 	// Test addDrawingVML with illegal cell coordinates.
 	f := NewFile()
-	assert.EqualError(t, f.addDrawingVML(0, "", "*", 0, 0), `cannot convert cell "*" to coordinates: invalid cell name "*"`)
+	assert.EqualError(t, f.addDrawingVML("Sheet1", 0, "", "*", 0, 0, 0, 0), `cannot convert cell "*" to coordinates: invalid cell name "*"`)
 }
 
 func TestSetCellHyperLink(t *testing.T) {
@@ -642,9 +747,9 @@ func TestSetCellStyleBorderErrors(t *testing.T) {
 
 	// Set border with invalid style index number.
 	_, err = f.NewStyle(`{"border":[{"type":"left","color":"0000FF","style":-1},{"type":"top","color":"00FF00","style":14},{"type":"bottom","color":"FFFF00","style":5},{"type":"right","color":"FF0000","style":6},{"type":"diagonalDown","color":"A020F0","style":9},{"type":"diagonalUp","color":"A020F0","style":8}]}`)
-	if !assert.NoError(t, err) {
-		t.FailNow()
-	}
+	assert.EqualError(t, err, "invalid border style -1")
+	_, err = f.NewStyle(`{"border":[{"type":"top","color":"00FF00","style":14}]}`)
+	assert.EqualError(t, err, "invalid border style 14")
 }
 
 func TestSetCellStyleNumberFormat(t *testing.T) {
@@ -881,8 +986,8 @@ func TestSetDeleteSheet(t *testing.T) {
 			t.FailNow()
 		}
 		f.DeleteSheet("Sheet1")
-		assert.EqualError(t, f.AddComment("Sheet1", "A1", ""), "unexpected end of JSON input")
-		assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize: ","text":"This is a comment."}`))
+		assert.EqualError(t, f.AddComment("Sheet1", "A1", nil), "comment option can not be nil")
+		assert.NoError(t, f.AddComment("Sheet1", "A1", &CommentOption{Author: "Excelize: ", Text: "This is a comment."}))
 		assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetDeleteSheet.TestBook4.xlsx")))
 	})
 }
@@ -901,6 +1006,26 @@ func TestSheetVisibility(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSheetVisibility.xlsx")))
 }
 
+func TestGetSheetList(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet3")
+	assert.NoError(t, f.SetSheetVisible("Sheet2", false))
+	f.workbookReader().Sheets.Sheet[2].State = "veryHidden"
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []SheetInfo{
+		{Name: "Sheet1", Index: 1, State: "visible"},
+		{Name: "Sheet2", Index: 2, State: "hidden"},
+		{Name: "Sheet3", Index: 3, State: "veryHidden"},
+	}, f.GetSheetList())
+	assert.True(t, f.GetSheetVisible("Sheet1"))
+	assert.False(t, f.GetSheetVisible("Sheet2"))
+	assert.False(t, f.GetSheetVisible("Sheet3"))
+}
+
 func TestCopySheet(t *testing.T) {
 	f, err := prepareTestBook1()
 	if !assert.NoError(t, err) {
@@ -918,6 +1043,109 @@ func TestCopySheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheet.xlsx")))
 }
 
+func TestCopySheetWithParts(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Name"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 3.5))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 7))
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A2:B2", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"5"}]`, format)))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A2:B2"
+	assert.NoError(t, dvRange.SetRange(1, 10, DataValidationTypeWhole, DataValidationOperatorBetween))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com", "External"))
+	assert.NoError(t, f.AddComment("Sheet1", "A2", &CommentOption{Author: "Excelize", Text: "Comment"}))
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, png.Encode(buf, img))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "D1", "", "Picture", ".png", buf.Bytes()))
+	assert.NoError(t, f.AddChart("Sheet1", "D8", `{"type":"col","series":[{"name":"Sheet1!$A$2","values":"Sheet1!$A$2:$B$2"}]}`))
+
+	idx := f.NewSheet("Dashboard Copy")
+	assert.NoError(t, f.CopySheet(1, idx))
+
+	// Test edit the duplicated worksheet doesn't affect the source worksheet.
+	assert.NoError(t, f.SetCellValue("Dashboard Copy", "A1", "Copy"))
+	assert.NoError(t, f.AddPictureFromBytes("Dashboard Copy", "H1", "", "Picture", ".png", buf.Bytes()))
+	assert.NoError(t, f.AddComment("Dashboard Copy", "B2", &CommentOption{Author: "Excelize", Text: "Copy"}))
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Name", val)
+
+	b, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(b)
+	assert.NoError(t, err)
+	for sheet, expected := range map[string]struct{ pics, comments int }{"Sheet1": {1, 1}, "Dashboard Copy": {2, 2}} {
+		pics, err := f.GetPictures(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, pics, expected.pics)
+		assert.Equal(t, buf.Bytes(), pics[0].File)
+		comments, err := f.GetComments(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, comments, expected.comments)
+		link, target, err := f.GetCellHyperLink(sheet, "A1")
+		assert.NoError(t, err)
+		assert.True(t, link)
+		assert.Equal(t, "https://github.com", target)
+		ws, err := f.workSheetReader(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, ws.DataValidations.DataValidation, 1)
+		assert.Len(t, ws.ConditionalFormatting, 1)
+	}
+	assert.Contains(t, string(f.readXML("xl/charts/chart1.xml")), "<f>Sheet1!$A$2:$B$2</f>")
+	assert.Contains(t, string(f.readXML("xl/charts/chart2.xml")), "<f>'Dashboard Copy'!$A$2:$B$2</f>")
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "../drawings/drawing1.xml", f.getSheetRelationshipsTargetByID("Sheet1", ws.Drawing.RID))
+	for _, rel := range f.relsReader("xl/worksheets/_rels/sheet2.xml.rels").Relationships {
+		assert.NotEqual(t, "../drawings/drawing1.xml", rel.Target)
+	}
+}
+
+func TestCopySheetWithHeaderFooterImage(t *testing.T) {
+	f := NewFile()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, png.Encode(buf, img))
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "center", Extension: ".png", File: buf.Bytes()}))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &FormatHeaderFooter{OddHeader: "&C&G"}))
+	assert.NoError(t, f.CopySheet(1, f.NewSheet("Sheet2")))
+
+	// Test import the worksheet with the header image into another workbook.
+	dst := NewFile()
+	assert.NoError(t, dst.ImportSheet(f, "Sheet1", "Sheet2"))
+
+	for _, wb := range []*File{f, dst} {
+		b, err := wb.WriteToBuffer()
+		assert.NoError(t, err)
+		wb, err = OpenReader(b)
+		assert.NoError(t, err)
+		ws, err := wb.workSheetReader("Sheet2")
+		assert.NoError(t, err)
+		if !assert.NotNil(t, ws.LegacyDrawingHF) {
+			continue
+		}
+		target := wb.getSheetRelationshipsTargetByID("Sheet2", ws.LegacyDrawingHF.RID)
+		assert.NotEqual(t, "", target)
+		drawingVML := strings.Replace(target, "..", "xl", 1)
+		assert.Contains(t, string(wb.XLSX[drawingVML]), `id="CH"`)
+		drawingRels := wb.relsReader(strings.Replace(drawingVML, "xl/drawings/", "xl/drawings/_rels/", 1) + ".rels")
+		if !assert.NotNil(t, drawingRels) || !assert.Len(t, drawingRels.Relationships, 1) {
+			continue
+		}
+		assert.Equal(t, buf.Bytes(), wb.XLSX[strings.Replace(drawingRels.Relationships[0].Target, "..", "xl", 1)])
+	}
+	// Test the duplicated worksheet uses its own VML drawing.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws2, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, f.getSheetRelationshipsTargetByID("Sheet1", ws.LegacyDrawingHF.RID), f.getSheetRelationshipsTargetByID("Sheet2", ws2.LegacyDrawingHF.RID))
+}
+
 func TestCopySheetError(t *testing.T) {
 	f, err := prepareTestBook1()
 	if !assert.NoError(t, err) {
@@ -932,9 +1160,171 @@ func TestCopySheetError(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCopySheetError.xlsx")))
 }
 
+func TestImportSheet(t *testing.T) {
+	src := NewFile()
+	assert.NoError(t, src.SetCellValue("Sheet1", "A1", "Name"))
+	assert.NoError(t, src.SetCellValue("Sheet1", "A2", 3.5))
+	assert.NoError(t, src.SetCellValue("Sheet1", "B2", 7))
+	// Test import the cells reference the shared strings.
+	src.SharedStrings = &xlsxSST{Count: 1, UniqueCount: 1, SI: []xlsxSI{{T: "Shared"}}}
+	ws, err := src.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C = append(ws.SheetData.Row[0].C, xlsxC{R: "B1", T: "s", V: "0"})
+	style, err := src.NewStyle(`{"font":{"bold":true,"color":"#FF0000"},"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, src.SetCellStyle("Sheet1", "A2", "B2", style))
+	assert.NoError(t, src.SetColStyle("Sheet1", "C", style))
+	format, err := src.NewConditionalStyle(`{"font":{"color":"#9A0511"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, src.SetConditionalFormat("Sheet1", "A2:B2", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"5"}]`, format)))
+	assert.NoError(t, src.SetCellHyperLink("Sheet1", "A1", "https://github.com", "External"))
+	assert.NoError(t, src.AddComment("Sheet1", "A2", &CommentOption{Author: "Excelize", Text: "Comment"}))
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	buf := new(bytes.Buffer)
+	assert.NoError(t, png.Encode(buf, img))
+	assert.NoError(t, src.AddPictureFromBytes("Sheet1", "D1", "", "Picture", ".png", buf.Bytes()))
+	assert.NoError(t, src.AddChart("Sheet1", "D8", `{"type":"col","series":[{"name":"Sheet1!$A$2","values":"Sheet1!$A$2:$B$2"}]}`))
+
+	f := NewFile()
+	// Occupy the style IDs of the workbook to test the style IDs remapping.
+	_, err = f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+	assert.NoError(t, err)
+	_, err = f.NewConditionalStyle(`{"font":{"color":"#FFFFFF"}}`)
+	assert.NoError(t, err)
+	assert.EqualError(t, f.ImportSheet(src, "Sheet1", ""), "sheet Sheet1 already exists")
+	assert.EqualError(t, f.ImportSheet(src, "SheetN", "Data"), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ImportSheet(nil, "Sheet1", "Data"), "the source workbook can not be nil")
+	assert.NoError(t, f.ImportSheet(src, "Sheet1", "Imported Data"))
+
+	b, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(b)
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Imported Data")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name", "Shared"}, {"3.50", "7.00"}}, rows)
+	styleID, err := f.GetCellStyle("Imported Data", "B2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, style, styleID)
+	expected, err := src.GetStyleDefinition(style)
+	assert.NoError(t, err)
+	definition, err := f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, definition)
+	styleID, err = f.GetCellStyle("Imported Data", "C5")
+	assert.NoError(t, err)
+	definition, err = f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, definition)
+	ws, err = f.workSheetReader("Imported Data")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *ws.ConditionalFormatting[0].CfRule[0].DxfID)
+	assert.Len(t, f.Styles.Dxfs.Dxfs, 2)
+	link, target, err := f.GetCellHyperLink("Imported Data", "A1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com", target)
+	comments, err := f.GetComments("Imported Data")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "ExcelizeComment", comments[0].Text)
+	assert.Len(t, comments[0].Runs, 2)
+	pics, err := f.GetPictures("Imported Data")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	assert.Equal(t, buf.Bytes(), pics[0].File)
+	chart := string(f.readXML("xl/charts/chart1.xml"))
+	assert.Contains(t, chart, "<f>'Imported Data'!$A$2:$B$2</f>")
+	assert.NotContains(t, chart, "Sheet1!")
+	assert.Equal(t, "<c:f>(Data!$A$1,Sheet10!$A$1)</c:f>", string(renameChartSheetRefs([]byte("<c:f>('Sheet 1'!$A$1,Sheet10!$A$1)</c:f>"), "Sheet 1", "Data")))
+	assert.Equal(t, "<c:f>'It''s'!$A$1</c:f>", string(renameChartSheetRefs([]byte("<c:f>Sheet1!$A$1</c:f>"), "Sheet1", "It's")))
+
+	// Test import worksheet with invalid style ID.
+	ws, err = src.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].S = 100
+	assert.EqualError(t, f.ImportSheet(src, "Sheet1", "Data"), "invalid style ID 100")
+}
+
+func TestSetPanesSplit(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetPanes("Sheet1", `{"split":true,"x_split":3270,"y_split":1800}`))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxPane{ActivePane: "bottomRight", State: "split", TopLeftCell: "D6", XSplit: 3270, YSplit: 1800}, ws.SheetViews.SheetView[0].Pane)
+	output, err := xml.Marshal(ws.SheetViews)
+	assert.NoError(t, err)
+	assert.Contains(t, string(output), `<pane activePane="bottomRight" state="split" topLeftCell="D6" xSplit="3270" ySplit="1800"></pane>`)
+	// Test calculate the top left cell by the custom column width and row height.
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "B", 30))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 60))
+	assert.NoError(t, f.SetPanes("Sheet1", `{"split":true,"x_split":3270,"y_split":1800}`))
+	assert.Equal(t, "B3", ws.SheetViews.SheetView[0].Pane.TopLeftCell)
+	// Test split panes with only one direction.
+	assert.NoError(t, f.SetPanes("Sheet1", `{"split":true,"x_split":0,"y_split":1800}`))
+	assert.Equal(t, &xlsxPane{ActivePane: "bottomLeft", State: "split", TopLeftCell: "A3", YSplit: 1800}, ws.SheetViews.SheetView[0].Pane)
+	assert.NoError(t, f.SetPanes("Sheet1", `{"split":true,"x_split":3270,"y_split":0,"top_left_cell":"C1","active_pane":"topLeft"}`))
+	assert.Equal(t, &xlsxPane{ActivePane: "topLeft", State: "split", TopLeftCell: "C1", XSplit: 3270}, ws.SheetViews.SheetView[0].Pane)
+	// Test freeze panes with the calculated top left cell.
+	assert.NoError(t, f.SetPanes("Sheet1", `{"freeze":true,"x_split":1,"y_split":2}`))
+	assert.Equal(t, &xlsxPane{ActivePane: "bottomRight", State: "frozen", TopLeftCell: "B3", XSplit: 1, YSplit: 2}, ws.SheetViews.SheetView[0].Pane)
+	// Test remove the panes without split position.
+	assert.NoError(t, f.SetPanes("Sheet1", `{"split":true,"x_split":0,"y_split":0}`))
+	assert.Nil(t, ws.SheetViews.SheetView[0].Pane)
+	// Test set panes with invalid split position.
+	assert.EqualError(t, f.SetPanes("Sheet1", `{"split":true,"x_split":-1,"y_split":1800}`), "invalid split position -1, 1800 of the panes")
+	assert.EqualError(t, f.SetPanes("Sheet1", fmt.Sprintf(`{"freeze":true,"x_split":%d,"y_split":1}`, TotalColumns)), "invalid split position 16384, 1 of the panes")
+}
+
+func TestSetSheetView(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetViewOptions("Sheet1", -1, ShowFormulas(true)))
+	assert.NoError(t, f.SetSheetView("Sheet1", SheetViewOptions{
+		Panes:         &SheetViewPanes{Freeze: true, YSplit: 1},
+		ZoomScale:     float64Ptr(120),
+		ShowGridLines: boolPtr(false),
+		ActiveCell:    stringPtr("B5"),
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	view := ws.SheetViews.SheetView[0]
+	assert.True(t, view.ShowFormulas)
+	assert.Equal(t, &xlsxPane{ActivePane: "bottomLeft", State: "frozen", TopLeftCell: "A2", YSplit: 1}, view.Pane)
+	assert.Equal(t, []*xlsxSelection{{ActiveCell: "B5", Pane: "bottomLeft", SQRef: "B5"}}, view.Selection)
+	opts, err := f.GetSheetView("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, SheetViewOptions{
+		Panes:             &SheetViewPanes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"},
+		ZoomScale:         float64Ptr(120),
+		ShowGridLines:     boolPtr(false),
+		ShowRowColHeaders: boolPtr(true),
+		RightToLeft:       boolPtr(false),
+		TopLeftCell:       stringPtr(""),
+		ActiveCell:        stringPtr("B5"),
+	}, opts)
+	// Test merge with the existing view, keep the active cell after unfreeze.
+	assert.NoError(t, f.SetSheetView("Sheet1", SheetViewOptions{Panes: &SheetViewPanes{}, RightToLeft: boolPtr(true)}))
+	opts, err = f.GetSheetView("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, opts.Panes)
+	assert.Equal(t, 120.0, *opts.ZoomScale)
+	assert.False(t, *opts.ShowGridLines)
+	assert.True(t, *opts.RightToLeft)
+	assert.Equal(t, "B5", *opts.ActiveCell)
+	assert.Equal(t, []*xlsxSelection{{ActiveCell: "B5", SQRef: "B5"}}, ws.SheetViews.SheetView[0].Selection)
+	// Test set sheet view with invalid settings.
+	assert.EqualError(t, f.SetSheetView("Sheet1", SheetViewOptions{ZoomScale: float64Ptr(500)}), "zoom scale 500 out of range")
+	assert.EqualError(t, f.SetSheetView("Sheet1", SheetViewOptions{ActiveCell: stringPtr("A")}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetSheetView("Sheet1", SheetViewOptions{Panes: &SheetViewPanes{Split: true, XSplit: -1}}), "invalid split position -1, 0 of the panes")
+	// Test set and get sheet view on not exists worksheet.
+	assert.EqualError(t, f.SetSheetView("SheetN", SheetViewOptions{}), "sheet SheetN is not exist")
+	_, err = f.GetSheetView("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
 func TestGetSheetComments(t *testing.T) {
 	f := NewFile()
-	assert.Equal(t, "", f.getSheetComments(0))
+	assert.Equal(t, "", f.getSheetComments("Sheet1"))
 }
 
 func TestSetActiveSheet(t *testing.T) {
@@ -948,6 +1338,31 @@ func TestSetActiveSheet(t *testing.T) {
 	f.SetActiveSheet(1)
 }
 
+func TestSetSelectedSheets(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	f.NewSheet("Sheet3")
+	f.NewSheet("Sheet4")
+	assert.Equal(t, []int{1}, f.GetSelectedSheets())
+	assert.NoError(t, f.SetSelectedSheets([]int{1, 3}))
+	assert.Equal(t, []int{1, 3}, f.GetSelectedSheets())
+	assert.Equal(t, 1, f.GetActiveSheetIndex())
+	// Test select worksheets without the active worksheet.
+	assert.NoError(t, f.SetSelectedSheets([]int{4, 2}))
+	assert.Equal(t, []int{2, 4}, f.GetSelectedSheets())
+	assert.Equal(t, 4, f.GetActiveSheetIndex())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetSelectedSheets.xlsx")))
+
+	// Test select worksheets with invalid settings.
+	assert.EqualError(t, f.SetSelectedSheets(nil), "at least one worksheet should be selected")
+	assert.EqualError(t, f.SetSelectedSheets([]int{1, 5}), "invalid worksheet index 5")
+	assert.NoError(t, f.SetSheetVisible("Sheet3", false))
+	assert.EqualError(t, f.SetSelectedSheets([]int{3}), "hidden sheet Sheet3 can not be selected")
+	assert.NoError(t, f.AddChartSheet("Chart1", `{"type":"col","series":[{"values":"Sheet1!$B$2:$D$2"}]}`))
+	assert.EqualError(t, f.SetSelectedSheets([]int{f.GetSheetIndex("Chart1")}), "sheet Chart1 is chart sheet")
+	assert.Equal(t, []int{2, 4}, f.GetSelectedSheets())
+}
+
 func TestSetSheetVisible(t *testing.T) {
 	f := NewFile()
 	f.WorkBook.Sheets.Sheet[0].Name = "SheetN"
@@ -960,6 +1375,140 @@ func TestGetActiveSheetIndex(t *testing.T) {
 	assert.Equal(t, 1, f.GetActiveSheetIndex())
 }
 
+func TestSetWorkbookView(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	opts := f.GetWorkbookView()
+	assert.Equal(t, WorkbookViewOptions{
+		ActiveSheet:          1,
+		WindowWidth:          14805,
+		WindowHeight:         8010,
+		TabRatio:             intPtr(600),
+		ShowHorizontalScroll: boolPtr(true),
+		ShowVerticalScroll:   boolPtr(true),
+		ShowSheetTabs:        boolPtr(true),
+	}, opts)
+	assert.NoError(t, f.SetWorkbookView(WorkbookViewOptions{
+		ActiveSheet:          2,
+		WindowWidth:          20000,
+		TabRatio:             intPtr(0),
+		ShowHorizontalScroll: boolPtr(false),
+		ShowSheetTabs:        boolPtr(false),
+	}))
+	assert.Equal(t, WorkbookViewOptions{
+		ActiveSheet:          2,
+		WindowWidth:          20000,
+		WindowHeight:         8010,
+		TabRatio:             intPtr(0),
+		ShowHorizontalScroll: boolPtr(false),
+		ShowVerticalScroll:   boolPtr(true),
+		ShowSheetTabs:        boolPtr(false),
+	}, f.GetWorkbookView())
+	output, err := xml.Marshal(f.WorkBook.BookViews)
+	assert.NoError(t, err)
+	assert.Equal(t, `<xlsxBookViews><workbookView activeTab="1" showHorizontalScroll="false" showSheetTabs="false" tabRatio="0" windowHeight="8010" windowWidth="20000" xWindow="0" yWindow="0"></workbookView></xlsxBookViews>`, string(output))
+
+	// Test set workbook view with invalid settings.
+	assert.EqualError(t, f.SetWorkbookView(WorkbookViewOptions{ActiveSheet: 3}), "sheet index 3 is not exist")
+	assert.EqualError(t, f.SetWorkbookView(WorkbookViewOptions{TabRatio: intPtr(1001)}), "tab ratio 1001 out of range")
+	assert.EqualError(t, f.SetWorkbookView(WorkbookViewOptions{WindowHeight: -1}), "the size of the window must not be negative")
+	assert.Equal(t, 2, f.GetActiveSheetIndex())
+
+	// Test set workbook view without the workbook views.
+	f.WorkBook.BookViews = nil
+	assert.NoError(t, f.SetWorkbookView(WorkbookViewOptions{ShowVerticalScroll: boolPtr(false)}))
+	assert.Equal(t, WorkbookViewOptions{
+		ActiveSheet:          1,
+		TabRatio:             intPtr(600),
+		ShowHorizontalScroll: boolPtr(true),
+		ShowVerticalScroll:   boolPtr(false),
+		ShowSheetTabs:        boolPtr(true),
+	}, f.GetWorkbookView())
+}
+
+func TestSetCalcProps(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, 122211, *f.GetCalcProps().CalcID)
+	calcID, calcMode, iterateCount, iterateDelta := 162913, "manual", 50, 0.0001
+	assert.NoError(t, f.SetCalcProps(CalcPropsOptions{
+		CalcID:         &calcID,
+		CalcMode:       &calcMode,
+		FullCalcOnLoad: boolPtr(true),
+		Iterate:        boolPtr(true),
+		IterateCount:   &iterateCount,
+		IterateDelta:   &iterateDelta,
+	}))
+	assert.Equal(t, CalcPropsOptions{
+		CalcID:         intPtr(162913),
+		CalcMode:       stringPtr("manual"),
+		FullCalcOnLoad: boolPtr(true),
+		Iterate:        boolPtr(true),
+		IterateCount:   intPtr(50),
+		IterateDelta:   float64Ptr(0.0001),
+	}, f.GetCalcProps())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestSetCalcProps.xlsx")))
+
+	// Test set calculation properties with default calculation id.
+	f.WorkBook.CalcPr = nil
+	assert.Equal(t, CalcPropsOptions{
+		CalcID:         intPtr(0),
+		CalcMode:       stringPtr("auto"),
+		FullCalcOnLoad: boolPtr(false),
+		Iterate:        boolPtr(false),
+		IterateCount:   intPtr(100),
+		IterateDelta:   float64Ptr(0.001),
+	}, f.GetCalcProps())
+	assert.NoError(t, f.SetCalcProps(CalcPropsOptions{}))
+	assert.Equal(t, defaultCalcID, *f.GetCalcProps().CalcID)
+
+	// Test set calculation properties with invalid settings.
+	calcID, calcMode, iterateCount, iterateDelta = 0, "none", 0, -1
+	assert.EqualError(t, f.SetCalcProps(CalcPropsOptions{CalcID: &calcID}), "invalid calculation id 0")
+	assert.EqualError(t, f.SetCalcProps(CalcPropsOptions{CalcMode: &calcMode}), "invalid calculation mode none")
+	assert.EqualError(t, f.SetCalcProps(CalcPropsOptions{IterateCount: &iterateCount}), "iterate count 0 out of range")
+	assert.EqualError(t, f.SetCalcProps(CalcPropsOptions{IterateDelta: &iterateDelta}), "the iterate delta must not be negative")
+}
+
+func TestSetWorkbookDateSystem(t *testing.T) {
+	f := NewFile()
+	assert.False(t, f.GetWorkbookDateSystem())
+	assert.NoError(t, f.SetWorkbookDateSystem(true))
+	assert.True(t, f.GetWorkbookDateSystem())
+
+	// Test set and get time value in the 1904 date system.
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "39813", ws.SheetData.Row[0].C[0].V)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1/1/13 00:00", val)
+
+	// Test calculate date function in the 1904 date system.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=DATE(2013,1,1)"))
+	result, err := f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "39813", result)
+
+	// Test round-trip the workbook in the 1904 date system.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.True(t, f.GetWorkbookDateSystem())
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1/1/13 00:00", val)
+
+	// Test switch back to the 1900 date system.
+	assert.NoError(t, f.SetWorkbookDateSystem(false))
+	assert.False(t, f.GetWorkbookDateSystem())
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "41275", ws.SheetData.Row[1].C[0].V)
+}
+
 func TestRelsWriter(t *testing.T) {
 	f := NewFile()
 	f.Relationships["xl/worksheets/sheet/rels/sheet1.xml.rel"] = &xlsxRelationships{}
@@ -1154,21 +1703,327 @@ func TestUnprotectSheet(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnprotectSheet.xlsx")))
 }
 
+func TestDefinedNameAttributes(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetDefinedName(&DefinedName{
+		Name:     "Rate",
+		Comment:  "internal rate",
+		Hidden:   true,
+		RefersTo: "Sheet1!$A$1",
+	}))
+	// Test the unknown attributes of the existing defined names will be kept.
+	wb := f.workbookReader()
+	wb.DefinedNames.DefinedName[0].Function = true
+	wb.DefinedNames.DefinedName[0].ShortcutKey = "r"
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$B$1:$B$4"}))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Contains(t, string(f.XLSX["xl/workbook.xml"]), `<definedName comment="internal rate" function="true" hidden="true" name="Rate" shortcutKey="r">Sheet1!$A$1</definedName>`)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []DefinedName{
+		{Name: "Rate", Comment: "internal rate", Hidden: true, RefersTo: "Sheet1!$A$1", Scope: "Workbook"},
+		{Name: "Amount", RefersTo: "Sheet1!$B$1:$B$4", Scope: "Workbook"},
+	}, f.GetDefinedName())
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$C$1"}))
+	wb = f.workbookReader()
+	if assert.Len(t, wb.DefinedNames.DefinedName, 3) {
+		assert.True(t, wb.DefinedNames.DefinedName[0].Function)
+		assert.Equal(t, "r", wb.DefinedNames.DefinedName[0].ShortcutKey)
+	}
+}
+
+func TestSetPageLayoutFitToPage(t *testing.T) {
+	f := NewFile()
+	// Test set the fit to page options of the page layout.
+	assert.NoError(t, f.SetPageLayout("Sheet1", FitToWidth(1), FitToHeight(2)))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ws.PageSetUp.FitToWidth)
+	assert.Equal(t, 2, ws.PageSetUp.FitToHeight)
+	var fitToPage FitToPage
+	assert.NoError(t, f.GetSheetPrOptions("Sheet1", &fitToPage))
+	assert.True(t, bool(fitToPage))
+	// Test set the print scaling of the page layout.
+	var scale PageLayoutScale
+	assert.NoError(t, f.GetPageLayout("Sheet1", &scale))
+	assert.Equal(t, PageLayoutScale(100), scale)
+	assert.NoError(t, f.SetPageLayout("Sheet1", PageLayoutScale(75)))
+	assert.NoError(t, f.GetPageLayout("Sheet1", &scale))
+	assert.Equal(t, PageLayoutScale(75), scale)
+	assert.NoError(t, f.GetSheetPrOptions("Sheet1", &fitToPage))
+	assert.False(t, bool(fitToPage))
+	// Test set the print scaling without the sheet properties.
+	assert.NoError(t, f.SetPageLayout("Sheet1", PageLayoutOrientation(OrientationLandscape)))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetPageLayout("Sheet2", PageLayoutScale(400)))
+	ws, err = f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.SheetPr)
+	assert.Equal(t, 400, ws.PageSetUp.Scale)
+	// Test set the page layout with invalid scale and contradictory options.
+	assert.EqualError(t, f.SetPageLayout("Sheet1", PageLayoutScale(9)), "invalid page layout scale 9")
+	assert.EqualError(t, f.SetPageLayout("Sheet1", PageLayoutScale(401)), "invalid page layout scale 401")
+	assert.EqualError(t, f.SetPageLayout("Sheet1", FitToWidth(1), PageLayoutScale(50)), "the fit to page and scale options of the page layout can not be set at the same time")
+	assert.EqualError(t, f.SetPageLayout("SheetN", PageLayoutScale(50)), "sheet SheetN is not exist")
+}
+
+func TestSetOutlineProps(t *testing.T) {
+	f := NewFile()
+	summaryBelow, summaryRight, err := f.GetOutlineProps("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, summaryBelow)
+	assert.True(t, summaryRight)
+	assert.NoError(t, f.SetOutlineProps("Sheet1", false, true))
+	assert.NoError(t, f.SetRowOutlineLevel("Sheet1", 2, 1))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Contains(t, string(f.readXML("xl/worksheets/sheet1.xml")), `<sheetPr><outlinePr summaryBelow="false" summaryRight="true"></outlinePr></sheetPr>`)
+	summaryBelow, summaryRight, err = f.GetOutlineProps("Sheet1")
+	assert.NoError(t, err)
+	assert.False(t, summaryBelow)
+	assert.True(t, summaryRight)
+	assert.NoError(t, f.SetOutlineProps("Sheet1", true, false))
+	summaryBelow, summaryRight, err = f.GetOutlineProps("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, summaryBelow)
+	assert.False(t, summaryRight)
+
+	// Test set and get outline properties on not exists worksheet.
+	assert.EqualError(t, f.SetOutlineProps("SheetN", true, true), "sheet SheetN is not exist")
+	_, _, err = f.GetOutlineProps("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestPageBreaks(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "A1"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.RowBreaks)
+	assert.Nil(t, ws.ColBreaks)
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "A20"))
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "C10"))
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "C10"))
+	assert.Equal(t, &xlsxBreaks{Brk: []*xlsxBrk{
+		{ID: 9, Max: 16383, Man: true},
+		{ID: 19, Max: 16383, Man: true},
+	}, Count: 2, ManualBreakCount: 2}, ws.RowBreaks)
+	assert.Equal(t, &xlsxBreaks{Brk: []*xlsxBrk{{ID: 2, Max: 1048575, Man: true}}, Count: 1, ManualBreakCount: 1}, ws.ColBreaks)
+	// Test the automatic page breaks will not be counted as manual page breaks.
+	ws.RowBreaks.Brk = append(ws.RowBreaks.Brk, &xlsxBrk{ID: 50, Max: 16383})
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "A30"))
+	assert.Equal(t, 4, ws.RowBreaks.Count)
+	assert.Equal(t, 3, ws.RowBreaks.ManualBreakCount)
+
+	assert.NoError(t, f.RemovePageBreak("Sheet1", "A20"))
+	assert.NoError(t, f.RemovePageBreak("Sheet1", "A40"))
+	assert.Equal(t, 3, ws.RowBreaks.Count)
+	assert.Equal(t, 2, ws.RowBreaks.ManualBreakCount)
+	assert.NoError(t, f.RemovePageBreak("Sheet1", "C1"))
+	assert.Nil(t, ws.ColBreaks)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Contains(t, string(f.XLSX["xl/worksheets/sheet1.xml"]), `<rowBreaks count="3" manualBreakCount="2"><brk id="9" max="16383" man="true"></brk><brk id="29" max="16383" man="true"></brk><brk id="50" max="16383"></brk></rowBreaks>`)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.NoError(t, f.RemovePageBreak("Sheet1", "A10"))
+	assert.NoError(t, f.RemovePageBreak("Sheet1", "A30"))
+	assert.NoError(t, f.RemovePageBreak("Sheet1", "A51"))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.RowBreaks)
+
+	// Test insert page breaks exceeds the limit.
+	for row := 2; row <= maxPageBreaks+1; row++ {
+		assert.NoError(t, f.InsertPageBreak("Sheet1", "A"+strconv.Itoa(row)))
+	}
+	assert.EqualError(t, f.InsertPageBreak("Sheet1", "A2000"), "the number of page breaks exceeds the limit 1026")
+	assert.EqualError(t, f.InsertPageBreak("Sheet1", "B2000"), "the number of page breaks exceeds the limit 1026")
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "B2"))
+	assert.Equal(t, 1, f.Sheet["xl/worksheets/sheet1.xml"].ColBreaks.Count)
+	// Test insert and remove page breaks with invalid cell reference.
+	assert.EqualError(t, f.InsertPageBreak("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.RemovePageBreak("Sheet1", "A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestGetSheetDimension(t *testing.T) {
+	f := NewFile()
+	dimension, err := f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", dimension)
+	assert.NoError(t, f.UpdateSheetDimension("Sheet1"))
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", dimension)
+
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "C3"))
+	assert.NoError(t, f.UpdateSheetDimension("Sheet1"))
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C3", dimension)
+
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E2", "SUM(C3)"))
+	styleID, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B10", "B10", styleID))
+	// Test the stored dimension will be returned before updating.
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "C3", dimension)
+	assert.NoError(t, f.UpdateSheetDimension("Sheet1"))
+	dimension, err = f.GetSheetDimension("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:E10", dimension)
+
+	// Test calculate the dimension when the stored dimension is missing.
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet2", "D4", 1))
+	assert.NoError(t, f.SetCellValue("Sheet2", "B6", 1))
+	xlsx, err := f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	xlsx.Dimension = nil
+	dimension, err = f.GetSheetDimension("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, "B4:D6", dimension)
+	xlsx.SheetData.Row[3].C[3].R = "A"
+	_, err = f.GetSheetDimension("Sheet2")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.UpdateSheetDimension("Sheet2"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+
+	// Test get and update the dimension on not exists worksheet.
+	_, err = f.GetSheetDimension("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	assert.EqualError(t, f.UpdateSheetDimension("SheetN"), "sheet SheetN is not exist")
+}
+
+func TestProtectWorkbook(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.ProtectWorkbook(WorkbookProtectionOptions{
+		Password:      "password",
+		LockStructure: true,
+	}))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	wb := f.workbookReader()
+	assert.Equal(t, &xlsxWorkbookProtection{LockStructure: true, WorkbookPassword: "83AF"}, wb.WorkbookProtection)
+	assert.Contains(t, string(f.XLSX["xl/workbook.xml"]), `<workbookProtection lockStructure="true" workbookPassword="83AF"></workbookProtection>`)
+	// Test protect the workbook without password.
+	assert.NoError(t, f.ProtectWorkbook(WorkbookProtectionOptions{LockWindows: true}))
+	assert.Equal(t, &xlsxWorkbookProtection{LockWindows: true}, wb.WorkbookProtection)
+	// Test protect the workbook without locking.
+	assert.EqualError(t, f.ProtectWorkbook(WorkbookProtectionOptions{Password: "password"}), "at least one of the structure and the windows of the workbook should be locked")
+
+	assert.NoError(t, f.UnprotectWorkbook())
+	assert.Nil(t, wb.WorkbookProtection)
+}
+
 func TestSetDefaultTimeStyle(t *testing.T) {
 	f := NewFile()
 	// Test set default time style on not exists worksheet.
 	assert.EqualError(t, f.setDefaultTimeStyle("SheetN", "", 0), "sheet SheetN is not exist")
 }
 
+func TestSetSheetCodeName(t *testing.T) {
+	f := NewFile()
+	codeName, err := f.GetSheetCodeName("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, codeName)
+	assert.NoError(t, f.SetSheetCodeName("Sheet1", "DataSheet"))
+	// Test set the same code name on the same worksheet.
+	assert.NoError(t, f.SetSheetCodeName("Sheet1", "DataSheet"))
+	f.NewSheet("Sheet2")
+	assert.EqualError(t, f.SetSheetCodeName("Sheet2", "datasheet"), "code name datasheet is already used by the sheet Sheet1")
+	assert.NoError(t, f.SetSheetCodeName("Sheet2", "Summary_2"))
+	// Test the code name will be kept after the worksheet has been renamed.
+	f.SetSheetName("Sheet1", "Data")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	codeName, err = f.GetSheetCodeName("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, "DataSheet", codeName)
+	var opt CodeName
+	assert.NoError(t, f.GetSheetPrOptions("Sheet2", &opt))
+	assert.Equal(t, CodeName("Summary_2"), opt)
+
+	// Test set code name with invalid code name.
+	for _, codeName := range []string{"", "1Sheet", "_Sheet", "Data Sheet", "Data-Sheet", strings.Repeat("A", 32)} {
+		assert.EqualError(t, f.SetSheetCodeName("Data", codeName), fmt.Sprintf("invalid code name %q", codeName))
+	}
+	// Test set and get code name on not exists worksheet.
+	assert.EqualError(t, f.SetSheetCodeName("SheetN", "Sheet"), "sheet SheetN is not exist")
+	_, err = f.GetSheetCodeName("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
 func TestAddVBAProject(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetSheetPrOptions("Sheet1", CodeName("Sheet1")))
-	assert.EqualError(t, f.AddVBAProject("macros.bin"), "stat macros.bin: no such file or directory")
-	assert.EqualError(t, f.AddVBAProject(filepath.Join("test", "Book1.xlsx")), "unsupported VBA project extension")
-	assert.NoError(t, f.AddVBAProject(filepath.Join("test", "vbaProject.bin")))
+	assert.EqualError(t, f.AddVBAProject(nil), "unsupported VBA project")
+	assert.EqualError(t, f.AddVBAProject([]byte("macros")), "unsupported VBA project")
+	bin := append(oleIdentifier, make([]byte, 504)...)
+	assert.NoError(t, f.AddVBAProject(bin))
 	// Test add VBA project twice.
-	assert.NoError(t, f.AddVBAProject(filepath.Join("test", "vbaProject.bin")))
-	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAddVBAProject.xlsm")))
+	assert.NoError(t, f.AddVBAProject(bin))
+	rels := f.relsReader("xl/_rels/workbook.xml.rels")
+	var count int
+	for _, rel := range rels.Relationships {
+		if rel.Type == SourceRelationshipVBAProject {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, bin, f.XLSX["xl/vbaProject.bin"])
+	assert.Contains(t, string(f.XLSX["[Content_Types].xml"]), ContentTypeMacro)
+}
+
+func TestRemoveVBAProject(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetPrOptions("Sheet1", CodeName("Sheet1")))
+	assert.NoError(t, f.AddVBAProject(oleIdentifier))
+	f.XLSX["xl/vbaProjectSignature.bin"] = []byte{0xD0, 0xCF, 0x11, 0xE0}
+	f.XLSX["xl/_rels/vbaProject.bin.rels"] = []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.microsoft.com/office/2006/relationships/vbaProjectSignature" Target="vbaProjectSignature.bin"/></Relationships>`)
+	content := f.contentTypesReader()
+	content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/vbaProjectSignature.bin", ContentType: "application/vnd.ms-office.vbaProjectSignature"})
+
+	// Test the VBA project will be kept on save.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Contains(t, f.XLSX, "xl/vbaProject.bin")
+	assert.Contains(t, string(f.XLSX["[Content_Types].xml"]), ContentTypeMacro)
+	assert.Contains(t, string(f.XLSX["xl/_rels/workbook.xml.rels"]), SourceRelationshipVBAProject)
+
+	// Test remove the VBA project.
+	assert.NoError(t, f.RemoveVBAProject())
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	for _, part := range []string{"xl/vbaProject.bin", "xl/vbaProjectSignature.bin", "xl/_rels/vbaProject.bin.rels"} {
+		assert.NotContains(t, f.XLSX, part)
+	}
+	for _, s := range []string{ContentTypeMacro, ContentTypeVBA, "vbaProjectSignature"} {
+		assert.NotContains(t, string(f.XLSX["[Content_Types].xml"]), s)
+	}
+	assert.Contains(t, string(f.XLSX["[Content_Types].xml"]), ContentTypeSheetML)
+	assert.NotContains(t, string(f.XLSX["xl/_rels/workbook.xml.rels"]), SourceRelationshipVBAProject)
+
+	// Test remove the VBA project on the macro-free workbook.
+	assert.NoError(t, NewFile().RemoveVBAProject())
 }
 
 func TestContentTypesReader(t *testing.T) {
@@ -1204,6 +2059,49 @@ func TestWorkSheetReader(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestWorkSheetUnknownElements(t *testing.T) {
+	alternateContent := `<mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><mc:Choice Requires="x14"><controls><control shapeId="1025" r:id="rId1" name="Button 1"/></controls></mc:Choice></mc:AlternateContent>`
+	newFile := func() *File {
+		f := NewFile()
+		delete(f.Sheet, "xl/worksheets/sheet1.xml")
+		f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main">` +
+			`<x15:unknown val="1"/><sheetData><row r="1"><c r="A1"><v>1</v></c></row></sheetData>` +
+			`<pageMargins left="0.7" right="0.7" top="0.75" bottom="0.75" header="0.3" footer="0.3"/>` + alternateContent + `</worksheet>`)
+		return f
+	}
+	f := newFile()
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, ws.DecodeUnknown)
+	assert.Equal(t, []xlsxUnknownElement{
+		{Content: `<x15:unknown val="1"/>`},
+		{After: "pageMargins", Content: alternateContent},
+	}, ws.Unknown)
+	// Test the unknown elements will be kept in the right position on save.
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 2))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	sheetXML := string(f.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, `xr:uid="{00000000-0001-0000-0000-000000000000}"><x15:unknown val="1"/><sheetData>`)
+	assert.Contains(t, sheetXML, `top="0.75"></pageMargins>`+alternateContent+`</worksheet>`)
+	val, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", val)
+	// Test the unknown elements will be kept by the stream writer.
+	f = newFile()
+	streamWriter, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"A"}))
+	assert.NoError(t, streamWriter.Flush())
+	sheetXML = string(f.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, `<x15:unknown val="1"/><sheetData>`)
+	assert.Contains(t, sheetXML, `top="0.75"></pageMargins>`+alternateContent+`</worksheet>`)
+	// Test get unknown elements from the worksheet with unsupported charset.
+	assert.Nil(t, getUnknownElements([]byte(`<?xml version="1.0" encoding="x-mac-cyrillic"?><worksheet><unknown/></worksheet>`)))
+}
+
 func TestRelsReader(t *testing.T) {
 	// Test unsupport charset.
 	f := NewFile()
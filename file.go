@@ -85,6 +85,23 @@ func (f *File) WriteTo(w io.Writer) (int64, error) {
 	return buf.WriteTo(w)
 }
 
+// writeLazyWorksheet provides a function to copy the worksheet which hasn't
+// been decompressed from the opened archive file to the archive writer by
+// given part name.
+func writeLazyWorksheet(zw *zip.Writer, path string, file *zip.File) error {
+	fi, err := zw.Create(path)
+	if err != nil {
+		return err
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(fi, rc)
+	return err
+}
+
 // WriteToBuffer provides a function to get bytes.Buffer from the saved file.
 func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
@@ -98,6 +115,7 @@ func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	f.workSheetWriter()
 	f.relsWriter()
 	f.styleSheetWriter()
+	f.themeWriter()
 
 	for path, content := range f.XLSX {
 		fi, err := zw.Create(path)
@@ -111,5 +129,14 @@ func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 			return buf, err
 		}
 	}
+	for path, file := range f.lazyWorksheets {
+		if _, ok := f.XLSX[path]; ok {
+			continue
+		}
+		if err := writeLazyWorksheet(zw, path, file); err != nil {
+			zw.Close()
+			return buf, err
+		}
+	}
 	return buf, zw.Close()
 }
@@ -0,0 +1,362 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HTMLOptions directly maps the settings of the HTML table exported from the
+// worksheet. Range specifies the cell range to be exported, such as "A1:D10",
+// the used range of the worksheet will be exported if it is empty.
+type HTMLOptions struct {
+	Range string
+}
+
+// htmlBorderStyles defined the CSS border style by given border line style
+// of the cell.
+var htmlBorderStyles = map[string]string{
+	"thin":             "1px solid",
+	"medium":           "2px solid",
+	"thick":            "3px solid",
+	"dashed":           "1px dashed",
+	"dotted":           "1px dotted",
+	"double":           "3px double",
+	"hair":             "1px dotted",
+	"mediumDashed":     "2px dashed",
+	"dashDot":          "1px dashed",
+	"mediumDashDot":    "2px dashed",
+	"dashDotDot":       "1px dotted",
+	"mediumDashDotDot": "2px dotted",
+	"slantDashDot":     "2px dashed",
+}
+
+// htmlHorizontalAlignments defined the CSS text-align by given horizontal
+// alignment of the cell.
+var htmlHorizontalAlignments = map[string]string{
+	"left":             "left",
+	"center":           "center",
+	"right":            "right",
+	"justify":          "justify",
+	"centerContinuous": "center",
+	"distributed":      "justify",
+}
+
+// htmlVerticalAlignments defined the CSS vertical-align by given vertical
+// alignment of the cell.
+var htmlVerticalAlignments = map[string]string{
+	"top":         "top",
+	"center":      "middle",
+	"bottom":      "bottom",
+	"justify":     "middle",
+	"distributed": "middle",
+}
+
+var (
+	// htmlColorRe matches the RGB color code which could be used in the
+	// inline CSS.
+	htmlColorRe = regexp.MustCompile(`^[0-9A-Fa-f]{6}$`)
+	// htmlFontNameRe matches the font name which could be quoted in the
+	// inline CSS without escaping.
+	htmlFontNameRe = regexp.MustCompile(`^[\p{L}\p{N} _\-]+$`)
+)
+
+// GetSheetHTML provides a function to export the worksheet or cell range as
+// the HTML table by given worksheet name and HTML options. The font, fill,
+// borders and alignment of the cells will be rendered as inline CSS, the
+// merged cells will be rendered with colspan and rowspan, and the cell values
+// will be displayed with the number formats and their colors. For example,
+// export the range A1:D10 on Sheet1 as HTML table:
+//
+//    table, err := f.GetSheetHTML("Sheet1", excelize.HTMLOptions{Range: "A1:D10"})
+//
+func (f *File) GetSheetHTML(sheet string, opts HTMLOptions) ([]byte, error) {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	cells := make(map[[2]int]*xlsxC)
+	var area []int
+	for rowIdx := range xlsx.SheetData.Row {
+		for colIdx := range xlsx.SheetData.Row[rowIdx].C {
+			c := &xlsx.SheetData.Row[rowIdx].C[colIdx]
+			col, row, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return nil, err
+			}
+			cells[[2]int{col, row}] = c
+			if area == nil {
+				area = []int{col, row, col, row}
+			}
+			if col < area[0] {
+				area[0] = col
+			}
+			if col > area[2] {
+				area[2] = col
+			}
+			if row < area[1] {
+				area[1] = row
+			}
+			if row > area[3] {
+				area[3] = row
+			}
+		}
+	}
+	if opts.Range != "" {
+		ref := strings.Split(opts.Range, ":")
+		if len(ref) == 1 {
+			ref = append(ref, ref[0])
+		}
+		if len(ref) != 2 {
+			return nil, fmt.Errorf("invalid area %q", opts.Range)
+		}
+		if area, err = areaRangeToCoordinates(ref[0], ref[1]); err != nil {
+			return nil, err
+		}
+		_ = sortCoordinates(area)
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<table style="border-collapse:collapse">`)
+	if area == nil {
+		buf.WriteString("</table>")
+		return buf.Bytes(), err
+	}
+	spans, covered, err := f.getHTMLMergeCells(xlsx, area)
+	if err != nil {
+		return nil, err
+	}
+	sst, styles := f.sharedStringsReader(), make(map[int]string)
+	for row := area[1]; row <= area[3]; row++ {
+		buf.WriteString("\n<tr>")
+		for col := area[0]; col <= area[2]; col++ {
+			if covered[[2]int{col, row}] {
+				continue
+			}
+			buf.WriteString("<td")
+			cellCol, cellRow := col, row
+			if span, ok := spans[[2]int{col, row}]; ok {
+				if span[0] > 1 {
+					buf.WriteString(` colspan="` + strconv.Itoa(span[0]) + `"`)
+				}
+				if span[1] > 1 {
+					buf.WriteString(` rowspan="` + strconv.Itoa(span[1]) + `"`)
+				}
+				// Use the top left cell of the merged cell which may be out
+				// of the given area.
+				cellCol, cellRow = span[2], span[3]
+			}
+			var val, css string
+			c, ok := cells[[2]int{cellCol, cellRow}]
+			styleID := f.prepareCellStyle(xlsx, cellCol, cellRow, 0)
+			if ok {
+				if val, err = c.getValueFrom(f, sst); err != nil {
+					return nil, err
+				}
+				styleID = f.prepareCellStyle(xlsx, cellCol, cellRow, c.S)
+			}
+			if css, ok = styles[styleID]; !ok {
+				css = f.getStyleCSS(styleID)
+				styles[styleID] = css
+			}
+			if c != nil && (c.T == "" || c.T == "n") && val != "" {
+				if color := f.formattedValueColor(styleID, c.V); color != "" {
+					css += "color:" + color + ";"
+				}
+				if !strings.Contains(css, "text-align:") {
+					css += "text-align:right;"
+				}
+			}
+			if css != "" {
+				buf.WriteString(` style="` + html.EscapeString(css) + `"`)
+			}
+			buf.WriteString(">" + strings.Replace(html.EscapeString(val), "\n", "<br>", -1) + "</td>")
+		}
+		buf.WriteString("</tr>")
+	}
+	buf.WriteString("\n</table>")
+	return buf.Bytes(), err
+}
+
+// getHTMLMergeCells provides a function to get the column and row spans of
+// the merged cells in the given area, and the cells covered by the merged
+// cells. The spans are indexed by the top left cell of the merged cell in the
+// area, and also contain the column and row number of the top left cell of
+// the merged cell.
+func (f *File) getHTMLMergeCells(xlsx *xlsxWorksheet, area []int) (map[[2]int][4]int, map[[2]int]bool, error) {
+	spans, covered := make(map[[2]int][4]int), make(map[[2]int]bool)
+	if xlsx.MergeCells == nil {
+		return spans, covered, nil
+	}
+	for _, mergeCell := range xlsx.MergeCells.Cells {
+		rect, err := f.areaRefToCoordinates(mergeCell.Ref)
+		if err != nil {
+			return spans, covered, err
+		}
+		_ = sortCoordinates(rect)
+		if rect[0] > area[2] || rect[2] < area[0] || rect[1] > area[3] || rect[3] < area[1] {
+			continue
+		}
+		col, row := rect[0], rect[1]
+		for i := 0; i < 2; i++ {
+			if rect[i] < area[i] {
+				rect[i] = area[i]
+			}
+			if rect[i+2] > area[i+2] {
+				rect[i+2] = area[i+2]
+			}
+		}
+		spans[[2]int{rect[0], rect[1]}] = [4]int{rect[2] - rect[0] + 1, rect[3] - rect[1] + 1, col, row}
+		for col := rect[0]; col <= rect[2]; col++ {
+			for row := rect[1]; row <= rect[3]; row++ {
+				if col != rect[0] || row != rect[1] {
+					covered[[2]int{col, row}] = true
+				}
+			}
+		}
+	}
+	return spans, covered, nil
+}
+
+// getStyleCSS provides a function to convert the font, fill, borders and
+// alignment of the cell style to the inline CSS by given style index.
+func (f *File) getStyleCSS(styleID int) string {
+	s := f.stylesReader()
+	if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return ""
+	}
+	var css strings.Builder
+	xf := s.CellXfs.Xf[styleID]
+	if s.Fonts != nil && xf.FontID > 0 && xf.FontID < len(s.Fonts.Font) {
+		font := s.Fonts.Font[xf.FontID]
+		if font.Name != nil && font.Name.Val != nil && htmlFontNameRe.MatchString(*font.Name.Val) {
+			css.WriteString("font-family:'" + *font.Name.Val + "';")
+		}
+		if font.Sz != nil && font.Sz.Val != nil {
+			css.WriteString("font-size:" + strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64) + "pt;")
+		}
+		if font.B != nil {
+			css.WriteString("font-weight:bold;")
+		}
+		if font.I != nil {
+			css.WriteString("font-style:italic;")
+		}
+		var decorations []string
+		if font.U != nil {
+			decorations = append(decorations, "underline")
+		}
+		if font.Strike != nil {
+			decorations = append(decorations, "line-through")
+		}
+		if len(decorations) > 0 {
+			css.WriteString("text-decoration:" + strings.Join(decorations, " ") + ";")
+		}
+		if color := f.getHTMLColor(font.Color); color != "" {
+			css.WriteString("color:" + color + ";")
+		}
+	}
+	if s.Fills != nil && xf.FillID > 0 && xf.FillID < len(s.Fills.Fill) {
+		fill := s.Fills.Fill[xf.FillID]
+		var color string
+		if fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+			color = f.getHTMLColor(&fill.PatternFill.FgColor)
+		}
+		if fill.GradientFill != nil && len(fill.GradientFill.Stop) > 0 {
+			color = f.getHTMLColor(&fill.GradientFill.Stop[0].Color)
+		}
+		if color != "" {
+			css.WriteString("background-color:" + color + ";")
+		}
+	}
+	if s.Borders != nil && xf.BorderID > 0 && xf.BorderID < len(s.Borders.Border) {
+		border := s.Borders.Border[xf.BorderID]
+		for _, line := range []struct {
+			side string
+			line xlsxLine
+		}{
+			{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom},
+		} {
+			style, ok := htmlBorderStyles[line.line.Style]
+			if !ok {
+				continue
+			}
+			color := f.getHTMLColor(line.line.Color)
+			if color == "" {
+				color = "#000000"
+			}
+			css.WriteString("border-" + line.side + ":" + style + " " + color + ";")
+		}
+	}
+	if xf.Alignment != nil {
+		if align, ok := htmlHorizontalAlignments[xf.Alignment.Horizontal]; ok {
+			css.WriteString("text-align:" + align + ";")
+		}
+		if align, ok := htmlVerticalAlignments[xf.Alignment.Vertical]; ok {
+			css.WriteString("vertical-align:" + align + ";")
+		}
+		if xf.Alignment.WrapText {
+			css.WriteString("white-space:pre-wrap;")
+		}
+		if xf.Alignment.Indent > 0 {
+			css.WriteString("padding-left:" + strconv.Itoa(xf.Alignment.Indent*9) + "px;")
+		}
+	}
+	return css.String()
+}
+
+// getHTMLColor provides a function to convert the color of the style to the
+// RGB color code by given color, the theme and indexed colors are supported.
+// An empty string will be returned if the color is not a valid RGB color.
+func (f *File) getHTMLColor(color *xlsxColor) string {
+	if color == nil {
+		return ""
+	}
+	if color.RGB != "" {
+		rgb := color.RGB
+		if len(rgb) == 8 {
+			rgb = rgb[2:]
+		}
+		if !htmlColorRe.MatchString(rgb) {
+			return ""
+		}
+		return "#" + rgb
+	}
+	if color.Theme != nil && f.Theme != nil {
+		// The first four theme colors are lt1, dk1, lt2 and dk2, which stored
+		// in the order of dk1, lt1, dk2 and lt2 in the color scheme.
+		idx := *color.Theme
+		if idx < 4 {
+			idx ^= 1
+		}
+		children := f.Theme.ThemeElements.ClrScheme.Children
+		if idx < 0 || idx >= len(children) {
+			return ""
+		}
+		var base string
+		if children[idx].SrgbClr != nil && children[idx].SrgbClr.Val != nil {
+			base = *children[idx].SrgbClr.Val
+		}
+		if children[idx].SysClr != nil {
+			base = children[idx].SysClr.LastClr
+		}
+		if !htmlColorRe.MatchString(base) {
+			return ""
+		}
+		return "#" + ThemeColor(base, color.Tint)[2:]
+	}
+	if color.Indexed > 0 && color.Indexed < len(indexedColorMapping) {
+		return "#" + indexedColorMapping[color.Indexed]
+	}
+	return ""
+}
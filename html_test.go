@@ -0,0 +1,75 @@
+package excelize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSheetHTML(t *testing.T) {
+	f := NewFile()
+	table, err := f.GetSheetHTML("Sheet1", HTMLOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, `<table style="border-collapse:collapse"></table>`, string(table))
+
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Name & <Title>"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", -1234.5))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 10))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "end"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A1", "C1"))
+	style, err := f.NewStyle(`{"font":{"bold":true,"italic":true,"underline":"single","family":"Arial","size":12,"color":"#777777"},"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1},"border":[{"type":"left","color":"0000FF","style":2}],"alignment":{"horizontal":"center","vertical":"center","wrap_text":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	numFmtStyle, err := f.NewStyle(`{"custom_number_format":"#,##0.00;[Red]-#,##0.00","font":{"theme_color":{"theme":4,"tint":0}},"fill":{"type":"gradient","color":["#FFFFFF","#E0EBF5"],"shading":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "B2", numFmtStyle))
+
+	table, err = f.GetSheetHTML("Sheet1", HTMLOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, `<table style="border-collapse:collapse">`+"\n"+
+		`<tr><td colspan="3" style="font-family:&#39;Arial&#39;;font-size:12pt;font-weight:bold;font-style:italic;text-decoration:underline;color:#777777;background-color:#E0EBF5;border-left:2px solid #0000FF;text-align:center;vertical-align:middle;white-space:pre-wrap;">Name &amp; &lt;Title&gt;</td></tr>`+"\n"+
+		`<tr><td style="font-family:&#39;Calibri&#39;;font-size:11pt;color:#5B9BD5;background-color:#FFFFFF;color:#FF0000;text-align:right;">-1,234.50</td><td style="font-family:&#39;Calibri&#39;;font-size:11pt;color:#5B9BD5;background-color:#FFFFFF;text-align:right;">10.00</td><td></td></tr>`+"\n"+
+		`<tr><td></td><td></td><td>end</td></tr>`+"\n"+
+		`</table>`, string(table))
+
+	// Test export the cell range which intersects with the merged cell.
+	table, err = f.GetSheetHTML("Sheet1", HTMLOptions{Range: "C1:B2"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(table), `<tr><td colspan="2" style="font-family:&#39;Arial&#39;;`)
+	assert.Contains(t, string(table), `Name &amp; &lt;Title&gt;</td></tr>`)
+	table, err = f.GetSheetHTML("Sheet1", HTMLOptions{Range: "C3"})
+	assert.NoError(t, err)
+	assert.Equal(t, `<table style="border-collapse:collapse">`+"\n<tr><td>end</td></tr>\n</table>", string(table))
+
+	// Test export with invalid options.
+	_, err = f.GetSheetHTML("Sheet1", HTMLOptions{Range: "A1:B2:C3"})
+	assert.EqualError(t, err, `invalid area "A1:B2:C3"`)
+	_, err = f.GetSheetHTML("Sheet1", HTMLOptions{Range: "A:B"})
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.GetSheetHTML("SheetN", HTMLOptions{})
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test export with the font name and color which are not safe in CSS.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "A1"))
+	style, err = f.NewStyle(`{"font":{"family":"Arial';}</style><script>","color":"#777777\"><script>"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	table, err = f.GetSheetHTML("Sheet1", HTMLOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, `<table style="border-collapse:collapse">`+"\n"+
+		`<tr><td style="font-size:11pt;">A1</td></tr>`+"\n"+
+		`</table>`, string(table))
+}
+
+func TestGetHTMLColor(t *testing.T) {
+	f := NewFile()
+	assert.Equal(t, "", f.getHTMLColor(nil))
+	assert.Equal(t, "#FF0000", f.getHTMLColor(&xlsxColor{RGB: "FF0000"}))
+	assert.Equal(t, "#FF0000", f.getHTMLColor(&xlsxColor{RGB: "FFFF0000"}))
+	assert.Equal(t, "", f.getHTMLColor(&xlsxColor{RGB: `FF0000;" onclick="`}))
+	assert.Equal(t, "#FFFFFF", f.getHTMLColor(&xlsxColor{Theme: intPtr(0)}))
+	assert.Equal(t, "#000000", f.getHTMLColor(&xlsxColor{Theme: intPtr(1)}))
+	assert.Equal(t, "", f.getHTMLColor(&xlsxColor{Theme: intPtr(12)}))
+	assert.Equal(t, "#800000", f.getHTMLColor(&xlsxColor{Indexed: 16}))
+}
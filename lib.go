@@ -12,11 +12,16 @@ package excelize
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/rand"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ReadZipReader can be used to read an XLSX in memory without touching the
@@ -33,14 +38,36 @@ func ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 	return fileList, worksheets, nil
 }
 
-// readXML provides a function to read XML content as string.
+// readXML provides a function to read XML content as string. The lazy loaded
+// worksheet will be decompressed and stored in the file list on first read.
 func (f *File) readXML(name string) []byte {
 	if content, ok := f.XLSX[name]; ok {
 		return content
 	}
+	if file, ok := f.lazyWorksheets[name]; ok {
+		content := readFile(file)
+		f.XLSX[name] = content
+		delete(f.lazyWorksheets, name)
+		return content
+	}
 	return []byte{}
 }
 
+// readXMLReader provides a function to get the reader of the XML content by
+// given part name. The lazy loaded worksheet will be read from the archive
+// file directly without storing the decompressed content. The reader should
+// be closed by the caller after reading.
+func (f *File) readXMLReader(name string) io.ReadCloser {
+	if _, ok := f.XLSX[name]; !ok {
+		if file, ok := f.lazyWorksheets[name]; ok {
+			if rc, err := file.Open(); err == nil {
+				return rc
+			}
+		}
+	}
+	return ioutil.NopCloser(bytes.NewReader(f.readXML(name)))
+}
+
 // saveFileList provides a function to update given file content in file list
 // of XLSX.
 func (f *File) saveFileList(name string, content []byte) {
@@ -195,6 +222,322 @@ func CoordinatesToCellName(col, row int) (string, error) {
 	return fmt.Sprintf("%s%d", colname, row), nil
 }
 
+// subtractSqref provides a function to remove the given space-separated list
+// of ranges from the space-separated list of ranges, the remaining ranges will
+// be returned. The whole list will be removed if it's equal to the given
+// ranges, otherwise the cells of each given range will be subtracted from the
+// ranges of the list, and a partly overlapped range will be split into at
+// most four ranges.
+func subtractSqref(sqref, area string) (string, error) {
+	if strings.EqualFold(strings.Join(strings.Fields(sqref), " "), strings.Join(strings.Fields(area), " ")) {
+		return "", nil
+	}
+	var subtrahends [][]int
+	for _, ref := range strings.Fields(area) {
+		coordinates, err := sqrefToCoordinates(ref)
+		if err != nil {
+			return sqref, err
+		}
+		subtrahends = append(subtrahends, coordinates)
+	}
+	var refs []string
+	for _, ref := range strings.Fields(sqref) {
+		coordinates, err := sqrefToCoordinates(ref)
+		if err != nil {
+			refs = append(refs, ref)
+			continue
+		}
+		areas := [][]int{coordinates}
+		for _, subtrahend := range subtrahends {
+			var remains [][]int
+			for _, a := range areas {
+				remains = append(remains, subtractCoordinates(a, subtrahend)...)
+			}
+			areas = remains
+		}
+		if len(areas) == 1 && areas[0][0] == coordinates[0] && areas[0][1] == coordinates[1] &&
+			areas[0][2] == coordinates[2] && areas[0][3] == coordinates[3] {
+			refs = append(refs, ref)
+			continue
+		}
+		for _, a := range areas {
+			first, _ := CoordinatesToCellName(a[0], a[1])
+			if a[0] == a[2] && a[1] == a[3] {
+				refs = append(refs, first)
+				continue
+			}
+			last, _ := CoordinatesToCellName(a[2], a[3])
+			refs = append(refs, first+":"+last)
+		}
+	}
+	return strings.Join(refs, " "), nil
+}
+
+// sqrefToCoordinates provides a function to convert a cell reference or a
+// range reference in the sequence of references to the sorted coordinates.
+func sqrefToCoordinates(ref string) ([]int, error) {
+	rng := strings.Split(ref, ":")
+	if len(rng) == 1 {
+		rng = append(rng, rng[0])
+	}
+	if len(rng) != 2 {
+		return nil, fmt.Errorf("invalid area %q", ref)
+	}
+	coordinates, err := areaRangeToCoordinates(rng[0], rng[1])
+	if err != nil {
+		return nil, err
+	}
+	return coordinates, sortCoordinates(coordinates)
+}
+
+// subtractCoordinates provides a function to subtract the area b from the
+// area a by given coordinates, the remaining part of the area a will be
+// returned as up to four areas: above, below, left and right of the area b.
+func subtractCoordinates(a, b []int) [][]int {
+	if b[0] > a[2] || b[2] < a[0] || b[1] > a[3] || b[3] < a[1] {
+		return [][]int{a}
+	}
+	var areas [][]int
+	if a[1] < b[1] {
+		areas = append(areas, []int{a[0], a[1], a[2], b[1] - 1})
+	}
+	if b[3] < a[3] {
+		areas = append(areas, []int{a[0], b[3] + 1, a[2], a[3]})
+	}
+	top, bottom := a[1], a[3]
+	if b[1] > top {
+		top = b[1]
+	}
+	if b[3] < bottom {
+		bottom = b[3]
+	}
+	if a[0] < b[0] {
+		areas = append(areas, []int{a[0], top, b[0] - 1, bottom})
+	}
+	if b[2] < a[2] {
+		areas = append(areas, []int{b[2] + 1, top, a[2], bottom})
+	}
+	return areas
+}
+
+// ConvertFormulaA1ToR1C1 converts the references in the formula from the A1
+// notation to the R1C1 notation by given formula and the cell where the
+// formula located. The relative references will be converted to the offsets
+// from the cell, and the absolute references will be converted to the row or
+// column numbers. The string literals and the sheet name prefixes of the
+// references will be kept as is.
+//
+// Example:
+//
+//    ConvertFormulaA1ToR1C1("SUM(A1:$B$2,Sheet2!C:C)", "C3") // returns "SUM(R[-2]C[-2]:R2C2,Sheet2!C:C)", nil
+//
+func ConvertFormulaA1ToR1C1(formula, anchor string) (string, error) {
+	col, row, err := CellNameToCoordinates(anchor)
+	if err != nil {
+		return formula, err
+	}
+	return replaceFormulaReferences(formula, func(word string) string {
+		ref := word
+		if idx := strings.LastIndex(word, "!"); idx != -1 {
+			ref = word[idx+1:]
+		}
+		parts := strings.Split(ref, ":")
+		for idx, part := range parts {
+			match := referencePartRe.FindStringSubmatch(part)
+			if match == nil || part == "" {
+				return word
+			}
+			var r1c1 string
+			if match[4] != "" {
+				n, _ := strconv.Atoi(match[4])
+				r1c1 = "R" + formatR1C1Axis(n, row, match[3] == "$" || match[2] == "" && match[1] == "$")
+			}
+			if match[2] != "" {
+				n, err := ColumnNameToNumber(match[2])
+				if err != nil {
+					return word
+				}
+				r1c1 += "C" + formatR1C1Axis(n, col, match[1] == "$")
+			}
+			parts[idx] = r1c1
+		}
+		return word[:len(word)-len(ref)] + strings.Join(parts, ":")
+	}), nil
+}
+
+// ConvertFormulaR1C1ToA1 converts the references in the formula from the
+// R1C1 notation to the A1 notation by given formula and the cell where the
+// formula located. The offsets in square brackets will be converted to the
+// relative references, and the row or column numbers will be converted to
+// the absolute references. The references out of the worksheet will be
+// turned into #REF!.
+//
+// Example:
+//
+//    ConvertFormulaR1C1ToA1("SUM(R[-2]C[-2]:R2C2,Sheet2!C3)", "C3") // returns "SUM(A1:$B$2,Sheet2!$C:$C)", nil
+//
+func ConvertFormulaR1C1ToA1(formula, anchor string) (string, error) {
+	col, row, err := CellNameToCoordinates(anchor)
+	if err != nil {
+		return formula, err
+	}
+	var result strings.Builder
+	r := []rune(formula)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(r); j++ {
+				if r[j] == c {
+					if j+1 < len(r) && r[j+1] == c {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j < len(r) {
+				j++
+			}
+			result.WriteString(string(r[i:j]))
+			i = j
+		case isR1C1WordRune(c):
+			end, rowAxis, colAxis, ok := parseR1C1Reference(r, i)
+			if !ok {
+				// Skip the word with the brackets, such as the structured
+				// references.
+				j := i
+				for j < len(r) && (isR1C1WordRune(r[j]) || r[j] == '[') {
+					if r[j] == '[' {
+						for j < len(r)-1 && r[j] != ']' {
+							j++
+						}
+					}
+					j++
+				}
+				result.WriteString(string(r[i:j]))
+				i = j
+				break
+			}
+			whole := (rowAxis == nil || colAxis == nil) &&
+				(i == 0 || r[i-1] != ':') && (end >= len(r) || r[end] != ':')
+			result.WriteString(formatA1Reference(rowAxis, colAxis, col, row, whole))
+			i = end
+		default:
+			result.WriteRune(c)
+			i++
+		}
+	}
+	return result.String(), nil
+}
+
+// r1c1Axis directly maps the row or column part of the reference in the R1C1
+// notation.
+type r1c1Axis struct {
+	relative bool
+	n        int
+}
+
+// isR1C1WordRune checks if the rune could be a part of function names,
+// defined names or references in the R1C1 notation.
+func isR1C1WordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.\\", r)
+}
+
+// parseR1C1Reference provides a function to parse the reference in the R1C1
+// notation starting from the given position, the end position, the row and
+// column parts of the reference will be returned. The nil row or column part
+// means the whole columns or whole rows reference.
+func parseR1C1Reference(r []rune, i int) (int, *r1c1Axis, *r1c1Axis, bool) {
+	var axes [2]*r1c1Axis
+	j := i
+	for idx, prefix := range []rune{'R', 'C'} {
+		if j >= len(r) || unicode.ToUpper(r[j]) != prefix {
+			continue
+		}
+		j++
+		axes[idx] = &r1c1Axis{relative: true}
+		if j < len(r) && r[j] == '[' {
+			k := j + 1
+			if k < len(r) && r[k] == '-' {
+				k++
+			}
+			for k < len(r) && unicode.IsDigit(r[k]) {
+				k++
+			}
+			if k >= len(r) || r[k] != ']' {
+				return i, nil, nil, false
+			}
+			n, err := strconv.Atoi(string(r[j+1 : k]))
+			if err != nil {
+				return i, nil, nil, false
+			}
+			axes[idx].n, j = n, k+1
+			continue
+		}
+		k := j
+		for k < len(r) && unicode.IsDigit(r[k]) {
+			k++
+		}
+		if k > j {
+			axes[idx].relative = false
+			axes[idx].n, _ = strconv.Atoi(string(r[j:k]))
+			j = k
+		}
+	}
+	if j == i || (j < len(r) && (isR1C1WordRune(r[j]) || r[j] == '(' || r[j] == '[')) {
+		return i, nil, nil, false
+	}
+	return j, axes[0], axes[1], true
+}
+
+// formatA1Reference provides a function to format the row and column parts of
+// the reference in the R1C1 notation to the A1 notation by given column and
+// row number of the cell where the formula located. The whole columns or
+// whole rows reference will be formatted as a range if whole is true.
+func formatA1Reference(rowAxis, colAxis *r1c1Axis, col, row int, whole bool) string {
+	var ref string
+	if colAxis != nil {
+		n, abs := colAxis.n, "$"
+		if colAxis.relative {
+			n, abs = col+colAxis.n, ""
+		}
+		if n < 1 || n > TotalColumns {
+			return "#REF!"
+		}
+		name, _ := ColumnNumberToName(n)
+		ref = abs + name
+	}
+	if rowAxis != nil {
+		n, abs := rowAxis.n, "$"
+		if rowAxis.relative {
+			n, abs = row+rowAxis.n, ""
+		}
+		if n < 1 || n > TotalRows {
+			return "#REF!"
+		}
+		ref += abs + strconv.Itoa(n)
+	}
+	if whole {
+		ref += ":" + ref
+	}
+	return ref
+}
+
+// formatR1C1Axis provides a function to format the row or column number to
+// the R1C1 notation by given number, the row or column number of the cell
+// where the formula located and the absolute reference marker.
+func formatR1C1Axis(n, base int, abs bool) string {
+	if abs {
+		return strconv.Itoa(n)
+	}
+	if n == base {
+		return ""
+	}
+	return "[" + strconv.Itoa(n-base) + "]"
+}
+
 // boolPtr returns a pointer to a bool with the given value.
 func boolPtr(b bool) *bool { return &b }
 
@@ -267,3 +610,53 @@ func genSheetPasswd(plaintext string) string {
 	password ^= 0xCE4B
 	return strings.ToUpper(strconv.FormatInt(password, 16))
 }
+
+// bstrExp defined the regular expression of the escaped character in the
+// Excel convention, such as _x000D_.
+var bstrExp = regexp.MustCompile(`_x[0-9A-Fa-f]{4}_`)
+
+// bstrMarshal provides a function to encode the string in the Excel
+// convention. The control characters which are illegal in XML will be
+// escaped as _xHHHH_, and the literal text in the form of _xHHHH_ will be
+// escaped as _x005F_xHHHH_ to keep it unchanged after decoding. The invalid
+// UTF-8 sequences, the surrogate-range runes and the non-characters U+FFFE
+// and U+FFFF will be stripped.
+func bstrMarshal(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1, r == 0xFFFE, r == 0xFFFF:
+		case r < 0x20 && r != '\t' && r != '\n' && r != '\r':
+			fmt.Fprintf(&b, "_x%04X_", r)
+		case r == '_' && i+7 <= len(s) && bstrExp.MatchString(s[i:i+7]):
+			b.WriteString("_x005F_")
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// bstrUnmarshal provides a function to decode the string which escaped in
+// the Excel convention, such as _x000D_ will be decoded as carriage return.
+func bstrUnmarshal(s string) string {
+	if !strings.Contains(s, "_x") {
+		return s
+	}
+	return bstrExp.ReplaceAllStringFunc(s, func(match string) string {
+		r, _ := strconv.ParseUint(match[2:6], 16, 32)
+		return string(rune(r))
+	})
+}
+
+// genGUID provides a function to generate a random GUID in the registry
+// format, such as {1D3F5C7A-9E0B-4D2F-8A6C-3E5B7D9F1A2C}.
+func genGUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -203,3 +203,93 @@ func TestCoordinatesToCellName_Error(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertFormulaA1ToR1C1(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"SUM(A1:$B$2,Sheet2!C:C)":   "SUM(R[-2]C[-2]:R2C2,Sheet2!C:C)",
+		"=C3+$C3+C$3+'My Sheet'!D4": "=RC+RC3+R3C+'My Sheet'!R[1]C[1]",
+		`IF(A1="B2",1:1,$2:$3)`:     `IF(R[-2]C[-2]="B2",R[-2]:R[-2],R2:R3)`,
+		"ROUND(E10*1.5E+2,2)":       "ROUND(R[7]C[2]*1.5E+2,2)",
+		"SUM($A:B)":                 "SUM(C1:C[-1])",
+		"COUNT(Data)":               "COUNT(Data)",
+	} {
+		result, err := ConvertFormulaA1ToR1C1(formula, "C3")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, formula)
+	}
+	_, err := ConvertFormulaA1ToR1C1("A1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestConvertFormulaR1C1ToA1(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"SUM(R[-2]C[-2]:R2C2,Sheet2!C3)":     "SUM(A1:$B$2,Sheet2!$C:$C)",
+		"=RC+RC3+R3C+'My Sheet'!R[1]C[1]":    "=C3+$C3+C$3+'My Sheet'!D4",
+		`IF(R[-2]C[-2]="RC",R[-2]:R[-2],R2)`: `IF(A1="RC",1:1,$2:$2)`,
+		"ROUND(r[7]c[2]*1.5E+2,2)":           "ROUND(E10*1.5E+2,2)",
+		"SUM(C1:C[-1],C)":                    "SUM($A:B,C:C)",
+		"COUNT(Rate)+RC[-3]+R[-3]C":          "COUNT(Rate)+#REF!+#REF!",
+		"RC[16381]+R1048576C+R[x]C":          "XFD3+C$1048576+R[x]C",
+		"Table1[Col]+R[1":                    "Table1[Col]+R[1",
+	} {
+		result, err := ConvertFormulaR1C1ToA1(formula, "C3")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, formula)
+	}
+	_, err := ConvertFormulaR1C1ToA1("RC", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
+func TestSubtractSqref(t *testing.T) {
+	for _, c := range [][3]string{
+		{"A1:B2 C1:C3", "a1:b2  c1:c3", ""},
+		{"A1:C3", "B2", "A1:C1 A3:C3 A2 C2"},
+		{"A1:A10", "A2", "A1 A3:A10"},
+		{"B4:D6", "C5:E7", "B4:D4 B5:B6"},
+		{"A1:B2 D1:D5", "A1:B2", "D1:D5"},
+		{"a1:b2 $D$1 E:E", "F1", "a1:b2 $D$1 E:E"},
+		{"B2:C3", "A1:D4", ""},
+	} {
+		sqref, err := subtractSqref(c[0], c[1])
+		assert.NoError(t, err)
+		assert.Equal(t, c[2], sqref, c[0]+" - "+c[1])
+	}
+	_, err := subtractSqref("A1:B2", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = subtractSqref("A1:B2", "A1:B2:C3")
+	assert.EqualError(t, err, `invalid area "A1:B2:C3"`)
+}
+
+func TestBstrMarshal(t *testing.T) {
+	for value, expected := range map[string]string{
+		"Hello":               "Hello",
+		"a\x00b":              "a_x0000_b",
+		"a\x0bb\x1bc":         "a_x000B_b_x001B_c",
+		"a\tb\nc\rd":          "a\tb\nc\rd",
+		"a\xed\xa0\x80b":      "ab",
+		"a\uFFFEb\uFFFF":      "ab",
+		"_x0041_":             "_x005F_x0041_",
+		"_x0041_x0042_":       "_x005F_x0041_x005F_x0042_",
+		"_x004_ _xGGGG_ _x00": "_x004_ _xGGGG_ _x00",
+		"\u4e2d\u6587":        "\u4e2d\u6587",
+	} {
+		assert.Equal(t, expected, bstrMarshal(value), value)
+	}
+}
+
+func TestBstrUnmarshal(t *testing.T) {
+	for value, expected := range map[string]string{
+		"Hello":                     "Hello",
+		"a_x0000_b":                 "a\x00b",
+		"a_x000B_b_x001b_c":         "a\x0bb\x1bc",
+		"_x005F_x0041_":             "_x0041_",
+		"_x005F_x0041_x005F_x0042_": "_x0041_x0042_",
+		"_x0041_x0042_":             "Ax0042_",
+		"_x004_":                    "_x004_",
+	} {
+		assert.Equal(t, expected, bstrUnmarshal(value), value)
+	}
+	for _, value := range []string{"\x00", "\x0b", "_x0041_", "a\x1b_x000D_b"} {
+		assert.Equal(t, value, bstrUnmarshal(bstrMarshal(value)))
+	}
+}
@@ -14,11 +14,24 @@ import (
 	"strings"
 )
 
+// MergeCellOpts can be passed to MergeCell to control the styles of the
+// merged cells.
+type MergeCellOpts struct {
+	SkipStyle bool // Keep the styles of the cells covered by the merged cell
+}
+
 // MergeCell provides a function to merge cells by given coordinate area and
 // sheet name. For example create a merged cell of D3:E9 on Sheet1:
 //
 //    err := f.MergeCell("Sheet1", "D3", "E9")
 //
+// The style of the top-left cell will be applied to all the cells covered by
+// the merged cell, so that the borders of the merged cell can be displayed
+// on all sides. Set SkipStyle in the options to manage the styles of the
+// covered cells manually, for example:
+//
+//    err := f.MergeCell("Sheet1", "D3", "E9", excelize.MergeCellOpts{SkipStyle: true})
+//
 // If you create a merged cell that overlaps with another existing merged cell,
 // those merged cells that already exist will be removed.
 //
@@ -34,7 +47,7 @@ import (
 //    |A8(x3,y4)      C8(x4,y4)|
 //    +------------------------+
 //
-func (f *File) MergeCell(sheet, hcell, vcell string) error {
+func (f *File) MergeCell(sheet, hcell, vcell string, opts ...MergeCellOpts) error {
 	rect1, err := f.areaRefToCoordinates(hcell + ":" + vcell)
 	if err != nil {
 		return err
@@ -65,6 +78,7 @@ func (f *File) MergeCell(sheet, hcell, vcell string) error {
 			if err != nil {
 				return err
 			}
+			_ = sortCoordinates(rect2)
 
 			// Delete the merged cells of the overlapping area.
 			if isOverlap(rect1, rect2) {
@@ -95,7 +109,27 @@ func (f *File) MergeCell(sheet, hcell, vcell string) error {
 	} else {
 		xlsx.MergeCells = &xlsxMergeCells{Cells: []*xlsxMergeCell{{Ref: ref}}}
 	}
-	return err
+	for _, o := range opts {
+		if o.SkipStyle {
+			return err
+		}
+	}
+	return f.setMergeCellStyle(sheet, rect1)
+}
+
+// setMergeCellStyle provides a function to apply the style of the top-left
+// cell to all the cells in the merged cell by given worksheet name and the
+// coordinates of the merged cell. The cells will not be created if the
+// top-left cell has the default style.
+func (f *File) setMergeCellStyle(sheet string, rect []int) error {
+	_ = sortCoordinates(rect)
+	hcell, _ := CoordinatesToCellName(rect[0], rect[1])
+	vcell, _ := CoordinatesToCellName(rect[2], rect[3])
+	styleID, err := f.GetCellStyle(sheet, hcell)
+	if err != nil || styleID == 0 {
+		return err
+	}
+	return f.SetCellStyle(sheet, hcell, vcell, styleID)
 }
 
 // UnmergeCell provides a function to unmerge a given coordinate area.
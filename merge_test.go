@@ -81,6 +81,55 @@ func TestMergeCell(t *testing.T) {
 	assert.EqualError(t, f.MergeCell("Sheet1", "A2", "B3"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
+func TestMergeCellStyle(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(`{"border":[{"type":"left","color":"0000FF","style":1},{"type":"top","color":"0000FF","style":1},{"type":"right","color":"0000FF","style":1},{"type":"bottom","color":"0000FF","style":1}]}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", styleID))
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "D4"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for r := 1; r <= 3; r++ {
+		for c := 1; c <= 3; c++ {
+			assert.Equal(t, styleID, ws.SheetData.Row[r].C[c].S, ws.SheetData.Row[r].C[c].R)
+		}
+	}
+
+	// Test merge cells with skip the style.
+	assert.NoError(t, f.SetCellStyle("Sheet1", "F2", "F2", styleID))
+	assert.NoError(t, f.MergeCell("Sheet1", "F2", "G3", MergeCellOpts{SkipStyle: true}))
+	assert.Len(t, ws.SheetData.Row[1].C, 6)
+	assert.Len(t, ws.SheetData.Row[2].C, 4)
+
+	// Test merge cells with the default style.
+	assert.NoError(t, f.MergeCell("Sheet1", "I5", "J6"))
+	assert.Len(t, ws.SheetData.Row, 5)
+	assert.Len(t, ws.SheetData.Row[4].C, 9)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestMergeCellStyle.xlsx")))
+
+	// Test merge cells overlapped with the merged cell in the reversed order,
+	// the style of the top-left cell of the merged area will be applied.
+	f = NewFile()
+	styleID, err = f.NewStyle(`{"border":[{"type":"left","color":"0000FF","style":1}]}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", styleID))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.MergeCells = &xlsxMergeCells{Cells: []*xlsxMergeCell{{Ref: "C3:B2"}}}
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "A1"))
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, mergeCells, 1) {
+		assert.Equal(t, "A1", mergeCells[0].GetStartAxis())
+		assert.Equal(t, "C3", mergeCells[0].GetEndAxis())
+	}
+	for _, cell := range []string{"A1", "B2", "C3"} {
+		style, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, styleID, style, cell)
+	}
+}
+
 func TestGetMergeCells(t *testing.T) {
 	wants := []struct {
 		value string
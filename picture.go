@@ -16,15 +16,21 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"image/png"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+// blipExtLstRe defined the regular expression to match the extension list of
+// the blip, which contains the SVG image of the picture.
+var blipExtLstRe = regexp.MustCompile(`(?s)<a:extLst>.*</a:extLst>`)
+
 // parseFormatPictureSet provides a function to parse the format settings of
 // the picture with default value.
 func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
@@ -82,6 +88,14 @@ func parseFormatPictureSet(formatSet string) (*formatPicture, error) {
 // spreadsheet, "oneCell" (Move but don't size with cells) or "absolute"
 // (Don't move or size with cells). If you don't set this parameter, default
 // positioning is move and size with cells.
+//
+// Fallback specifies the path of a PNG picture as the raster fallback of the
+// SVG picture, which will be displayed by the applications which doesn't
+// support SVG. A transparent placeholder picture will be used if you don't
+// set this parameter. For example, insert a SVG picture with fallback:
+//
+//    err := f.AddPicture("Sheet1", "A2", "logo.svg", `{"fallback": "logo.png"}`)
+//
 func (f *File) AddPicture(sheet, cell, picture, format string) error {
 	var err error
 	// Check picture exists first.
@@ -137,8 +151,15 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 	if err != nil {
 		return err
 	}
-	img, _, err := image.DecodeConfig(bytes.NewReader(file))
-	if err != nil {
+	var (
+		img      image.Config
+		fallback []byte
+	)
+	if ext == ".svg" {
+		if img, fallback, err = parseSVGPicture(file, formatSet); err != nil {
+			return err
+		}
+	} else if img, _, err = image.DecodeConfig(bytes.NewReader(file)); err != nil {
 		return err
 	}
 	// Read sheet data.
@@ -153,6 +174,13 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 	drawingRels := "xl/drawings/_rels/drawing" + strconv.Itoa(drawingID) + ".xml.rels"
 	mediaStr := ".." + strings.TrimPrefix(f.addMedia(file, ext), "xl")
 	drawingRID := f.addRels(drawingRels, SourceRelationshipImage, mediaStr, hyperlinkType)
+	// Add the raster fallback of the SVG picture.
+	var drawingSVGRID int
+	if fallback != nil {
+		drawingSVGRID = drawingRID
+		mediaStr = ".." + strings.TrimPrefix(f.addMedia(fallback, ".png"), "xl")
+		drawingRID = f.addRels(drawingRels, SourceRelationshipImage, mediaStr, hyperlinkType)
+	}
 	// Add picture with hyperlink.
 	if formatSet.Hyperlink != "" && formatSet.HyperlinkType != "" {
 		if formatSet.HyperlinkType == "External" {
@@ -160,7 +188,7 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 		}
 		drawingHyperlinkRID = f.addRels(drawingRels, SourceRelationshipHyperLink, formatSet.Hyperlink, hyperlinkType)
 	}
-	err = f.addDrawingPicture(sheet, drawingXML, cell, name, img.Width, img.Height, drawingRID, drawingHyperlinkRID, formatSet)
+	err = f.addDrawingPicture(sheet, drawingXML, cell, name, img.Width, img.Height, drawingRID, drawingHyperlinkRID, drawingSVGRID, formatSet)
 	if err != nil {
 		return err
 	}
@@ -168,6 +196,189 @@ func (f *File) AddPictureFromBytes(sheet, cell, format, name, extension string,
 	return err
 }
 
+// AddHeaderFooterImage provides the method to add a picture in the header or
+// footer of the worksheet by given worksheet name and the settings of the
+// picture, such as the branded logo watermark printed on each page. The
+// picture will only be shown when the "&G" code is specified in the
+// corresponding section of the header or footer by the SetHeaderFooter
+// function, and the existing picture in the same section will be replaced.
+// For example, add a logo in the center section of the header on Sheet1:
+//
+//    file, err := ioutil.ReadFile("logo.png")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := f.AddHeaderFooterImage("Sheet1", &excelize.HeaderFooterImage{
+//        Position:  "center",
+//        Extension: ".png",
+//        File:      file,
+//    }); err != nil {
+//        fmt.Println(err)
+//    }
+//    err = f.SetHeaderFooter("Sheet1", &excelize.FormatHeaderFooter{
+//        OddHeader: "&C&G",
+//    })
+//
+func (f *File) AddHeaderFooterImage(sheet string, opts *HeaderFooterImage) error {
+	if opts == nil {
+		return errors.New("header footer image option can not be nil")
+	}
+	positions := map[string]string{"left": "L", "center": "C", "right": "R"}
+	shapeID, ok := positions[opts.Position]
+	if !ok {
+		return fmt.Errorf("invalid header footer image position %s", opts.Position)
+	}
+	shapeID += "H"
+	if opts.IsFooter {
+		shapeID = shapeID[:1] + "F"
+	}
+	ext, ok := supportImageTypes[opts.Extension]
+	if !ok || ext == ".svg" {
+		return errors.New("unsupported image extension")
+	}
+	img, _, err := image.DecodeConfig(bytes.NewReader(opts.File))
+	if err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	vmlID := f.countComments() + 1
+	if id := f.countVMLDrawings() + 1; id > vmlID {
+		vmlID = id
+	}
+	drawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(vmlID) + ".vml"
+	if ws.LegacyDrawingHF != nil {
+		target := f.getSheetRelationshipsTargetByID(sheet, ws.LegacyDrawingHF.RID)
+		vmlID, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(target, "../drawings/vmlDrawing"), ".vml"))
+		drawingVML = strings.Replace(target, "..", "xl", -1)
+	} else {
+		sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+		rID := f.addRels(sheetRels, SourceRelationshipDrawingVML, "../drawings/vmlDrawing"+strconv.Itoa(vmlID)+".vml", "")
+		ws.LegacyDrawingHF = &xlsxLegacyDrawingHF{RID: "rId" + strconv.Itoa(rID)}
+	}
+	drawingRels := "xl/drawings/_rels/vmlDrawing" + strconv.Itoa(vmlID) + ".vml.rels"
+	mediaStr := ".." + strings.TrimPrefix(f.addMedia(opts.File, ext), "xl")
+	var rID int
+	if rels := f.relsReader(drawingRels); rels != nil {
+		// Reuse the relationship of the same picture in the drawing.
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipImage && rel.Target == mediaStr {
+				rID, _ = strconv.Atoi(strings.TrimPrefix(rel.ID, "rId"))
+			}
+		}
+	}
+	if rID == 0 {
+		rID = f.addRels(drawingRels, SourceRelationshipImage, mediaStr, "")
+	}
+	f.setContentTypePartVMLExtensions()
+	f.setContentTypePartImageExtensions()
+	vml := f.vmlDrawingReader(drawingVML, vmlID)
+	vml.addShapetype("_x0000_t75")
+	sp, _ := xml.Marshal(encodeHeaderFooterImage{
+		Imagedata: &vImagedata{RelID: "rId" + strconv.Itoa(rID)},
+		Lock:      &oLock{Ext: "edit", Rotation: "t"},
+	})
+	shape := xlsxShape{
+		ID:    shapeID,
+		Spid:  "_x0000_s" + strconv.Itoa(vml.nextShapeID(vmlID)),
+		Type:  "#_x0000_t75",
+		Style: fmt.Sprintf("position:absolute;margin-left:0;margin-top:0;width:%gpt;height:%gpt;z-index:1", float64(img.Width)*0.75, float64(img.Height)*0.75),
+		Val:   string(sp[7 : len(sp)-8]),
+	}
+	for idx, v := range vml.Shape {
+		if v.ID == shapeID {
+			if v.Spid != "" {
+				shape.Spid = v.Spid
+			}
+			vml.Shape[idx] = shape
+			return err
+		}
+	}
+	vml.Shape = append(vml.Shape, shape)
+	return err
+}
+
+// parseSVGPicture provides a function to check the SVG picture is well-formed
+// XML and get the size of the picture by given SVG picture content. It
+// returns the content of the PNG picture which specified by the fallback
+// format setting, or a transparent placeholder PNG picture as the raster
+// fallback of the SVG picture.
+func parseSVGPicture(file []byte, formatSet *formatPicture) (image.Config, []byte, error) {
+	var (
+		img  image.Config
+		root bool
+		buf  bytes.Buffer
+	)
+	decoder := xml.NewDecoder(bytes.NewReader(file))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return img, nil, fmt.Errorf("invalid SVG image: %s", err)
+		}
+		if start, ok := token.(xml.StartElement); ok && !root {
+			if start.Name.Local != "svg" {
+				return img, nil, errors.New("invalid SVG image: the root element should be svg")
+			}
+			root = true
+			img.Width, img.Height = parseSVGSize(start.Attr)
+		}
+	}
+	if !root {
+		return img, nil, errors.New("invalid SVG image: the root element should be svg")
+	}
+	if formatSet.Fallback != "" {
+		fallback, err := ioutil.ReadFile(formatSet.Fallback)
+		if err != nil {
+			return img, nil, err
+		}
+		if _, format, err := image.DecodeConfig(bytes.NewReader(fallback)); err != nil || format != "png" {
+			return img, nil, errors.New("the fallback of SVG image should be PNG format")
+		}
+		return img, fallback, nil
+	}
+	err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	return img, buf.Bytes(), err
+}
+
+// parseSVGSize provides a function to get the width and height in pixels of
+// the SVG picture by given attributes of the svg element. The width and
+// height attributes will be used first, and then the view box. The default
+// size is 300 x 150 pixels.
+func parseSVGSize(attrs []xml.Attr) (int, int) {
+	width, height := 300.0, 150.0
+	for _, attr := range attrs {
+		if attr.Name.Local != "viewBox" {
+			continue
+		}
+		if box := strings.FieldsFunc(attr.Value, func(r rune) bool { return r == ' ' || r == ',' }); len(box) == 4 {
+			if w, err := strconv.ParseFloat(box[2], 64); err == nil && w > 0 {
+				width = w
+			}
+			if h, err := strconv.ParseFloat(box[3], 64); err == nil && h > 0 {
+				height = h
+			}
+		}
+	}
+	for _, attr := range attrs {
+		size, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(attr.Value), "px"), 64)
+		if err != nil || size <= 0 {
+			continue
+		}
+		switch attr.Name.Local {
+		case "width":
+			width = size
+		case "height":
+			height = size
+		}
+	}
+	return int(width), int(height)
+}
+
 // deleteSheetRelationships provides a function to delete relationships in
 // xl/worksheets/_rels/sheet%d.xml.rels by given worksheet name and
 // relationship index.
@@ -238,8 +449,9 @@ func (f *File) countDrawings() int {
 
 // addDrawingPicture provides a function to add picture by given sheet,
 // drawingXML, cell, file name, width, height relationship index and format
-// sets.
-func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, height, rID, hyperlinkRID int, formatSet *formatPicture) error {
+// sets. The blip of the picture will reference the SVG image if the SVG
+// relationship index isn't 0.
+func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, height, rID, hyperlinkRID, svgRID int, formatSet *formatPicture) error {
 	col, row, err := CellNameToCoordinates(cell)
 	if err != nil {
 		return err
@@ -278,6 +490,15 @@ func (f *File) addDrawingPicture(sheet, drawingXML, cell, file string, width, he
 	}
 	pic.BlipFill.Blip.R = SourceRelationship
 	pic.BlipFill.Blip.Embed = "rId" + strconv.Itoa(rID)
+	if svgRID != 0 {
+		pic.BlipFill.Blip.ExtLst = &xlsxBlipExtLst{Ext: []*xlsxBlipExt{{
+			URI: ExtURISVG,
+			SVGBlip: &xlsxSVGBlip{
+				XMLNSaSVG: NameSpaceDrawing2016SVG,
+				Embed:     "rId" + strconv.Itoa(svgRID),
+			},
+		}}}
+	}
 	pic.SpPr.PrstGeom.Prst = "rect"
 
 	twoCellAnchor.Pic = &pic
@@ -316,6 +537,12 @@ func (f *File) addMedia(file []byte, ext string) string {
 		}
 	}
 	media := "xl/media/image" + strconv.Itoa(count+1) + ext
+	for idx := count + 2; ; idx++ {
+		if _, ok := f.XLSX[media]; !ok {
+			break
+		}
+		media = "xl/media/image" + strconv.Itoa(idx) + ext
+	}
 	f.XLSX[media] = file
 	return media
 }
@@ -323,7 +550,7 @@ func (f *File) addMedia(file []byte, ext string) string {
 // setContentTypePartImageExtensions provides a function to set the content
 // type for relationship parts and the Main Document part.
 func (f *File) setContentTypePartImageExtensions() {
-	var imageTypes = map[string]bool{"jpeg": false, "png": false, "gif": false, "tiff": false}
+	var imageTypes = map[string]bool{"jpeg": false, "png": false, "gif": false, "svg": false, "tiff": false}
 	content := f.contentTypesReader()
 	for _, v := range content.Defaults {
 		_, ok := imageTypes[v.Extension]
@@ -333,9 +560,13 @@ func (f *File) setContentTypePartImageExtensions() {
 	}
 	for k, v := range imageTypes {
 		if !v {
+			contentType := "image/" + k
+			if k == "svg" {
+				contentType = "image/svg+xml"
+			}
 			content.Defaults = append(content.Defaults, xlsxDefault{
 				Extension:   k,
-				ContentType: "image/" + k,
+				ContentType: contentType,
 			})
 		}
 	}
@@ -364,25 +595,36 @@ func (f *File) setContentTypePartVMLExtensions() {
 func (f *File) addContentTypePart(index int, contentType string) {
 	setContentType := map[string]func(){
 		"comments": f.setContentTypePartVMLExtensions,
+		"ctrlProp": f.setContentTypePartVMLExtensions,
 		"drawings": f.setContentTypePartImageExtensions,
 	}
 	partNames := map[string]string{
-		"chart":      "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
-		"chartsheet": "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
-		"comments":   "/xl/comments" + strconv.Itoa(index) + ".xml",
-		"drawings":   "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
-		"table":      "/xl/tables/table" + strconv.Itoa(index) + ".xml",
-		"pivotTable": "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
-		"pivotCache": "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
+		"chart":           "/xl/charts/chart" + strconv.Itoa(index) + ".xml",
+		"chartsheet":      "/xl/chartsheets/sheet" + strconv.Itoa(index) + ".xml",
+		"comments":        "/xl/comments" + strconv.Itoa(index) + ".xml",
+		"ctrlProp":        "/xl/ctrlProps/ctrlProp" + strconv.Itoa(index) + ".xml",
+		"drawings":        "/xl/drawings/drawing" + strconv.Itoa(index) + ".xml",
+		"table":           "/xl/tables/table" + strconv.Itoa(index) + ".xml",
+		"pivotTable":      "/xl/pivotTables/pivotTable" + strconv.Itoa(index) + ".xml",
+		"pivotCache":      "/xl/pivotCache/pivotCacheDefinition" + strconv.Itoa(index) + ".xml",
+		"slicer":          "/xl/slicers/slicer" + strconv.Itoa(index) + ".xml",
+		"slicerCache":     "/xl/slicerCaches/slicerCache" + strconv.Itoa(index) + ".xml",
+		"threadedComment": "/xl/threadedComments/threadedComment" + strconv.Itoa(index) + ".xml",
+		"person":          "/xl/persons/person.xml",
 	}
 	contentTypes := map[string]string{
-		"chart":      ContentTypeDrawingML,
-		"chartsheet": ContentTypeSpreadSheetMLChartsheet,
-		"comments":   ContentTypeSpreadSheetMLComments,
-		"drawings":   ContentTypeDrawing,
-		"table":      ContentTypeSpreadSheetMLTable,
-		"pivotTable": ContentTypeSpreadSheetMLPivotTable,
-		"pivotCache": ContentTypeSpreadSheetMLPivotCacheDefinition,
+		"chart":           ContentTypeDrawingML,
+		"chartsheet":      ContentTypeSpreadSheetMLChartsheet,
+		"comments":        ContentTypeSpreadSheetMLComments,
+		"ctrlProp":        ContentTypeControlProperties,
+		"drawings":        ContentTypeDrawing,
+		"table":           ContentTypeSpreadSheetMLTable,
+		"pivotTable":      ContentTypeSpreadSheetMLPivotTable,
+		"pivotCache":      ContentTypeSpreadSheetMLPivotCacheDefinition,
+		"slicer":          ContentTypeSlicer,
+		"slicerCache":     ContentTypeSlicerCache,
+		"threadedComment": ContentTypeSpreadSheetMLThreadedComments,
+		"person":          ContentTypeSpreadSheetMLPerson,
 	}
 	s, ok := setContentType[contentType]
 	if ok {
@@ -465,6 +707,318 @@ func (f *File) GetPicture(sheet, cell string) (string, []byte, error) {
 	return f.getPicture(row, col, drawingXML, drawingRelationships)
 }
 
+// GetPictures provides a function to get all pictures embedded in the given
+// worksheet with their anchor cells. For example, export all pictures in
+// Sheet1:
+//
+//    f, err := excelize.OpenFile("Book1.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    pics, err := f.GetPictures("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for idx, pic := range pics {
+//        name := fmt.Sprintf("image%d%s", idx+1, pic.Extension)
+//        if err := ioutil.WriteFile(name, pic.File, 0644); err != nil {
+//            fmt.Println(err)
+//        }
+//    }
+//
+func (f *File) GetPictures(sheet string) ([]Picture, error) {
+	var pics []Picture
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil || xlsx.Drawing == nil {
+		return pics, err
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, xlsx.Drawing.RID)
+	drawingXML := strings.Replace(target, "..", "xl", -1)
+	if _, ok := f.XLSX[drawingXML]; !ok && f.Drawings[drawingXML] == nil {
+		return pics, err
+	}
+	drawingRelationships := strings.Replace(
+		strings.Replace(target, "../drawings", "xl/drawings/_rels", -1), ".xml", ".xml.rels", -1)
+	wsDr, _ := f.drawingParser(drawingXML)
+	for _, anchors := range [][]*xdrCellAnchor{wsDr.OneCellAnchor, wsDr.TwoCellAnchor} {
+		for _, anchor := range anchors {
+			pic, err := f.getPictureFromAnchor(anchor, drawingRelationships)
+			if err != nil {
+				return pics, err
+			}
+			if pic != nil {
+				pics = append(pics, *pic)
+			}
+		}
+	}
+	return pics, err
+}
+
+// getPictureFromAnchor provides a function to get picture by given drawing
+// cell anchor and drawing relationships. It returns nil if the anchor isn't a
+// picture.
+func (f *File) getPictureFromAnchor(anchor *xdrCellAnchor, drawingRelationships string) (*Picture, error) {
+	var (
+		fromCol, fromRow, toCol, toRow int
+		hasTo                          bool
+		embed                          string
+	)
+	if anchor.From != nil {
+		if anchor.Pic == nil {
+			return nil, nil
+		}
+		fromCol, fromRow, embed = anchor.From.Col, anchor.From.Row, anchor.Pic.BlipFill.Blip.Embed
+		if extLst := anchor.Pic.BlipFill.Blip.ExtLst; extLst != nil {
+			for _, ext := range extLst.Ext {
+				if ext.URI == ExtURISVG && ext.SVGBlip != nil {
+					embed = ext.SVGBlip.Embed
+				}
+			}
+		}
+		if anchor.To != nil {
+			toCol, toRow, hasTo = anchor.To.Col, anchor.To.Row, true
+		}
+	} else {
+		deTwoCellAnchor := new(decodeTwoCellAnchor)
+		if err := f.xmlNewDecoder(bytes.NewReader([]byte("<decodeTwoCellAnchor>" + anchor.GraphicFrame + "</decodeTwoCellAnchor>"))).
+			Decode(deTwoCellAnchor); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("xml decode error: %s", err)
+		}
+		if deTwoCellAnchor.From == nil || deTwoCellAnchor.Pic == nil {
+			return nil, nil
+		}
+		fromCol, fromRow, embed = deTwoCellAnchor.From.Col, deTwoCellAnchor.From.Row, deTwoCellAnchor.Pic.BlipFill.Blip.Embed
+		for _, ext := range deTwoCellAnchor.Pic.BlipFill.Blip.ExtLst.Ext {
+			if ext.URI == ExtURISVG && ext.SVGBlip.Embed != "" {
+				embed = ext.SVGBlip.Embed
+			}
+		}
+		if deTwoCellAnchor.To != nil {
+			toCol, toRow, hasTo = deTwoCellAnchor.To.Col, deTwoCellAnchor.To.Row, true
+		}
+	}
+	drawRel := f.getDrawingRelationships(drawingRelationships, embed)
+	if drawRel == nil {
+		return nil, nil
+	}
+	if _, ok := supportImageTypes[filepath.Ext(drawRel.Target)]; !ok {
+		return nil, nil
+	}
+	cell, err := CoordinatesToCellName(fromCol+1, fromRow+1)
+	if err != nil {
+		return nil, err
+	}
+	pic := &Picture{
+		Name:      filepath.Base(drawRel.Target),
+		Cell:      cell,
+		Extension: filepath.Ext(drawRel.Target),
+		File:      f.XLSX[strings.Replace(drawRel.Target, "..", "xl", -1)],
+	}
+	if hasTo {
+		toCell, err := CoordinatesToCellName(toCol+1, toRow+1)
+		if err != nil {
+			return nil, err
+		}
+		pic.Range = cell + ":" + toCell
+	}
+	return pic, nil
+}
+
+// ReplacePicture provides a function to replace the picture at the given
+// worksheet and cell name with the given picture content. The drawing anchor
+// and size of the picture will be preserved if the format set is empty,
+// otherwise the picture will be placed and scaled by the given format set
+// (such as offset, scale, aspect ratio setting and print settings). For
+// example, replace the picture at cell A2 in Sheet1:
+//
+//    file, err := ioutil.ReadFile("logo.png")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if err := f.ReplacePicture("Sheet1", "A2", file, ""); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) ReplacePicture(sheet, cell string, img []byte, format string) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	_, imgFormat, err := image.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		return err
+	}
+	ext, ok := supportImageTypes["."+imgFormat]
+	if !ok {
+		return errors.New("unsupported image extension")
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	if ws.Drawing == nil {
+		return fmt.Errorf("no picture at cell %s", cell)
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+	drawingXML := strings.Replace(target, "..", "xl", -1)
+	drawingRels := strings.Replace(
+		strings.Replace(target, "../drawings", "xl/drawings/_rels", -1), ".xml", ".xml.rels", -1)
+	anchor, embed, svgEmbed, err := f.getPictureAnchor(col-1, row-1, drawingXML)
+	if err != nil {
+		return err
+	}
+	if anchor == nil {
+		return fmt.Errorf("no picture at cell %s", cell)
+	}
+	if format != "" {
+		if _, err = parseFormatPictureSet(format); err != nil {
+			return err
+		}
+		var name string
+		if drawRel := f.getDrawingRelationships(drawingRels, embed); drawRel != nil {
+			name = filepath.Base(drawRel.Target)
+		}
+		if err = f.deleteDrawing(col-1, row-1, drawingXML, "Pic"); err != nil {
+			return err
+		}
+		if err = f.AddPictureFromBytes(sheet, cell, format, name, ext, img); err != nil {
+			return err
+		}
+		f.deletePictureRels(drawingXML, drawingRels, embed, svgEmbed)
+		return err
+	}
+	mediaStr := ".." + strings.TrimPrefix(f.addMedia(img, ext), "xl")
+	rID := "rId" + strconv.Itoa(f.addRels(drawingRels, SourceRelationshipImage, mediaStr, ""))
+	f.setContentTypePartImageExtensions()
+	if anchor.Pic != nil {
+		anchor.Pic.BlipFill.Blip.Embed, anchor.Pic.BlipFill.Blip.ExtLst = rID, nil
+		f.deletePictureRels(drawingXML, drawingRels, embed, svgEmbed)
+		return err
+	}
+	// Update the picture relationship and remove the SVG image of the blip in
+	// the existing drawing.
+	start := strings.Index(anchor.GraphicFrame, "<a:blip ")
+	end := strings.Index(anchor.GraphicFrame, "</a:blip>")
+	blip := anchor.GraphicFrame
+	if start != -1 && end > start {
+		blip = anchor.GraphicFrame[start:end]
+	}
+	newBlip := strings.Replace(blip, `embed="`+embed+`"`, `embed="`+rID+`"`, 1)
+	newBlip = blipExtLstRe.ReplaceAllString(newBlip, "")
+	anchor.GraphicFrame = strings.Replace(anchor.GraphicFrame, blip, newBlip, 1)
+	f.deletePictureRels(drawingXML, drawingRels, embed, svgEmbed)
+	return err
+}
+
+// deletePictureRels provides a function to delete the picture relationships
+// of the drawing by given drawing part path, drawing relationships path and
+// relationship IDs, such as the relationships of the picture and its SVG
+// image. The empty relationship IDs will be skipped.
+func (f *File) deletePictureRels(drawingXML, drawingRels string, rIDs ...string) {
+	for _, rID := range rIDs {
+		if rID != "" {
+			f.deletePictureRel(drawingXML, drawingRels, rID)
+		}
+	}
+}
+
+// deletePictureRel provides a function to delete the picture relationship
+// of the drawing by given drawing part path, drawing relationships path and
+// relationship ID if the relationship isn't referenced by the drawing, and
+// delete the media file if it isn't referenced by any relationships.
+func (f *File) deletePictureRel(drawingXML, drawingRels, rID string) {
+	if wsDr, ok := f.Drawings[drawingXML]; ok && wsDr != nil {
+		content, _ := xml.Marshal(wsDr)
+		if bytes.Contains(content, []byte(`embed="`+rID+`"`)) {
+			return
+		}
+	}
+	rels := f.relsReader(drawingRels)
+	if rels == nil {
+		return
+	}
+	var media string
+	for k, v := range rels.Relationships {
+		if v.ID == rID && v.Type == SourceRelationshipImage {
+			media = getRelsTargetPath(drawingRels, v.Target)
+			rels.Relationships = append(rels.Relationships[:k], rels.Relationships[k+1:]...)
+			break
+		}
+	}
+	if media == "" {
+		return
+	}
+	for name := range f.XLSX {
+		if strings.HasSuffix(name, ".rels") {
+			f.relsReader(name)
+		}
+	}
+	for name, rels := range f.Relationships {
+		if rels == nil {
+			continue
+		}
+		for _, v := range rels.Relationships {
+			if v.TargetMode != "External" && getRelsTargetPath(name, v.Target) == media {
+				return
+			}
+		}
+	}
+	delete(f.XLSX, media)
+}
+
+// getRelsTargetPath provides a function to get the path of the part in the
+// package by given relationships part path and the target of the
+// relationship.
+func getRelsTargetPath(relsPath, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(path.Dir(relsPath)), target)
+}
+
+// getPictureAnchor provides a function to get the drawing cell anchor, the
+// relationship ID of the picture and the relationship ID of the SVG image of
+// the picture by given coordinates and drawing XML path.
+func (f *File) getPictureAnchor(col, row int, drawingXML string) (*xdrCellAnchor, string, string, error) {
+	wsDr, _ := f.drawingParser(drawingXML)
+	for _, anchors := range [][]*xdrCellAnchor{wsDr.OneCellAnchor, wsDr.TwoCellAnchor} {
+		for _, anchor := range anchors {
+			if anchor.From != nil {
+				if anchor.Pic != nil && anchor.From.Col == col && anchor.From.Row == row {
+					var svgEmbed string
+					if extLst := anchor.Pic.BlipFill.Blip.ExtLst; extLst != nil {
+						for _, ext := range extLst.Ext {
+							if ext.SVGBlip != nil {
+								svgEmbed = ext.SVGBlip.Embed
+							}
+						}
+					}
+					return anchor, anchor.Pic.BlipFill.Blip.Embed, svgEmbed, nil
+				}
+				continue
+			}
+			deTwoCellAnchor := new(decodeTwoCellAnchor)
+			if err := f.xmlNewDecoder(bytes.NewReader([]byte("<decodeTwoCellAnchor>" + anchor.GraphicFrame + "</decodeTwoCellAnchor>"))).
+				Decode(deTwoCellAnchor); err != nil && err != io.EOF {
+				return nil, "", "", fmt.Errorf("xml decode error: %s", err)
+			}
+			if deTwoCellAnchor.From != nil && deTwoCellAnchor.Pic != nil && deTwoCellAnchor.From.Col == col && deTwoCellAnchor.From.Row == row {
+				var svgEmbed string
+				for _, ext := range deTwoCellAnchor.Pic.BlipFill.Blip.ExtLst.Ext {
+					if ext.SVGBlip.Embed != "" {
+						svgEmbed = ext.SVGBlip.Embed
+					}
+				}
+				return anchor, deTwoCellAnchor.Pic.BlipFill.Blip.Embed, svgEmbed, nil
+			}
+		}
+	}
+	return nil, "", "", nil
+}
+
 // DeletePicture provides a function to delete charts in XLSX by given
 // worksheet and cell name. Note that the image file won't be deleted from the
 // document currently.
@@ -1,13 +1,17 @@
 package excelize
 
 import (
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
 
 	_ "golang.org/x/image/tiff"
 
+	"bytes"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -145,10 +149,254 @@ func TestGetPicture(t *testing.T) {
 	assert.Empty(t, raw)
 }
 
+func TestGetPictures(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	assert.NoError(t, f.AddPicture("Sheet1", "F21", filepath.Join("test", "images", "excel.jpg"), `{"x_scale": 2, "y_scale": 2}`))
+	assert.NoError(t, f.AddChart("Sheet1", "P1", `{"type":"col","series":[{"name":"Sheet1!$A$1","values":"Sheet1!$B$1:$D$1"}]}`))
+	pics, err := f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, pics, 2) {
+		assert.Equal(t, "A1", pics[0].Cell)
+		assert.Equal(t, ".png", pics[0].Extension)
+		assert.True(t, strings.HasPrefix(pics[0].Range, "A1:"))
+		assert.Equal(t, "F21", pics[1].Cell)
+		assert.Equal(t, ".jpeg", pics[1].Extension)
+		raw, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.jpg"))
+		assert.NoError(t, err)
+		assert.Equal(t, raw, pics[1].File)
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test get pictures after reopen the workbook.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	reopened, err := f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, pics, reopened)
+
+	// Test get pictures with one cell anchor.
+	f.Drawings = make(map[string]*xlsxWsDr)
+	f.XLSX["xl/drawings/drawing1.xml"] = []byte(`<xdr:wsDr xmlns:xdr="http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><xdr:oneCellAnchor><xdr:from><xdr:col>2</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>3</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:from><xdr:ext cx="190500" cy="95250"/><xdr:pic><xdr:nvPicPr><xdr:cNvPr id="2" name="Picture 1"/><xdr:cNvPicPr/></xdr:nvPicPr><xdr:blipFill><a:blip r:embed="rId1"/><a:stretch><a:fillRect/></a:stretch></xdr:blipFill><xdr:spPr><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></xdr:spPr></xdr:pic><xdr:clientData/></xdr:oneCellAnchor></xdr:wsDr>`)
+	pics, err = f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, pics, 1) {
+		assert.Equal(t, Picture{Name: "image1.png", Cell: "C4", Extension: ".png", File: reopened[0].File}, pics[0])
+	}
+
+	// Test get pictures from none drawing worksheet.
+	pics, err = NewFile().GetPictures("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, pics)
+	// Test get pictures on not exists worksheet.
+	_, err = f.GetPictures("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get pictures with invalid drawing XML.
+	f.Drawings["xl/drawings/drawing1.xml"] = &xlsxWsDr{TwoCellAnchor: []*xdrCellAnchor{{GraphicFrame: "<from><col>x</col></from>"}}}
+	_, err = f.GetPictures("Sheet1")
+	assert.EqualError(t, err, `xml decode error: strconv.ParseInt: parsing "x": invalid syntax`)
+}
+
+func TestAddPictureSVG(t *testing.T) {
+	f := NewFile()
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="120px" height="60" viewBox="0 0 240 120"><rect width="240" height="120" fill="#4472C4"/></svg>`)
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "A1", "", "logo", ".svg", svg))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "F1", `{"fallback": "`+filepath.ToSlash(filepath.Join("test", "images", "excel.png"))+`"}`, "logo", ".svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0,0,64,32"/>`)))
+	// Test add SVG picture with invalid XML.
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A10", "", "logo", ".svg", []byte(`<svg><rect></svg>`)), "invalid SVG image: XML syntax error on line 1: element <rect> closed by </svg>")
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A10", "", "logo", ".svg", []byte(`<html></html>`)), "invalid SVG image: the root element should be svg")
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A10", "", "logo", ".svg", []byte(``)), "invalid SVG image: the root element should be svg")
+	// Test add SVG picture with invalid fallback.
+	assert.EqualError(t, f.AddPictureFromBytes("Sheet1", "A10", `{"fallback": "`+filepath.ToSlash(filepath.Join("test", "images", "excel.jpg"))+`"}`, "logo", ".svg", svg), "the fallback of SVG image should be PNG format")
+	assert.Error(t, f.AddPictureFromBytes("Sheet1", "A10", `{"fallback": "missing.png"}`, "logo", ".svg", svg))
+
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	drawing := string(f.XLSX["xl/drawings/drawing1.xml"])
+	assert.True(t, strings.Contains(drawing, `<a:blip r:embed="rId2" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><a:extLst><a:ext uri="{96DAC541-7B7A-43D3-8B79-37D633B846F1}"><asvg:svgBlip xmlns:asvg="http://schemas.microsoft.com/office/drawing/2016/SVG/main" r:embed="rId1"></asvg:svgBlip></a:ext></a:extLst></a:blip>`), drawing)
+	rels := f.relsReader("xl/drawings/_rels/drawing1.xml.rels")
+	if assert.NotNil(t, rels) && assert.Len(t, rels.Relationships, 4) {
+		assert.Equal(t, "../media/image1.svg", rels.Relationships[0].Target)
+		assert.Equal(t, "../media/image2.png", rels.Relationships[1].Target)
+	}
+	var svgContentType string
+	for _, d := range f.contentTypesReader().Defaults {
+		if d.Extension == "svg" {
+			svgContentType = d.ContentType
+		}
+	}
+	assert.Equal(t, "image/svg+xml", svgContentType)
+	pics, err := f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, pics, 2) {
+		assert.Equal(t, svg, pics[0].File)
+		assert.Equal(t, ".svg", pics[0].Extension)
+	}
+}
+
+func TestParseSVGSize(t *testing.T) {
+	for _, c := range []struct {
+		attrs         []xml.Attr
+		width, height int
+	}{
+		{nil, 300, 150},
+		{[]xml.Attr{{Name: xml.Name{Local: "viewBox"}, Value: "0 0 64 32"}}, 64, 32},
+		{[]xml.Attr{{Name: xml.Name{Local: "width"}, Value: "100px"}, {Name: xml.Name{Local: "viewBox"}, Value: "0 0 64 32"}}, 100, 32},
+		{[]xml.Attr{{Name: xml.Name{Local: "width"}, Value: "50%"}, {Name: xml.Name{Local: "height"}, Value: "20"}}, 300, 20},
+	} {
+		width, height := parseSVGSize(c.attrs)
+		assert.Equal(t, c.width, width)
+		assert.Equal(t, c.height, height)
+	}
+}
+
+func TestReplacePicture(t *testing.T) {
+	f := NewFile()
+	png, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	jpg, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.jpg"))
+	assert.NoError(t, err)
+	gif, err := ioutil.ReadFile(filepath.Join("test", "images", "excel.gif"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "A1", "", "excel", ".png", png))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "F1", "", "logo", ".svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="60" height="30"/>`)))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "K1", "", "excel", ".png", png))
+	pics, err := f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.ReplacePicture("Sheet1", "A1", jpg, ""))
+	assert.NoError(t, f.ReplacePicture("Sheet1", "F1", gif, ""))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test replace picture in the reopened workbook.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.NoError(t, f.ReplacePicture("Sheet1", "K1", jpg, ""))
+	replaced, err := f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, replaced, 3) {
+		for idx, img := range [][]byte{jpg, gif, jpg} {
+			assert.Equal(t, img, replaced[idx].File)
+			assert.Equal(t, pics[idx].Cell, replaced[idx].Cell)
+			assert.Equal(t, pics[idx].Range, replaced[idx].Range)
+		}
+		assert.Equal(t, ".jpeg", replaced[0].Extension)
+		assert.Equal(t, ".gif", replaced[1].Extension)
+	}
+	// Test replace picture with format set.
+	assert.NoError(t, f.ReplacePicture("Sheet1", "K1", png, `{"x_scale": 2, "y_scale": 2}`))
+	replaced, err = f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, replaced, 3) {
+		assert.Equal(t, "K1", replaced[2].Cell)
+		assert.Equal(t, png, replaced[2].File)
+		assert.NotEqual(t, pics[2].Range, replaced[2].Range)
+	}
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	_, err = OpenReader(buf)
+	assert.NoError(t, err)
+
+	// Test replace picture at the cell without picture.
+	assert.EqualError(t, f.ReplacePicture("Sheet1", "B2", png, ""), "no picture at cell B2")
+	assert.EqualError(t, NewFile().ReplacePicture("Sheet1", "A1", png, ""), "no picture at cell A1")
+	// Test replace picture with invalid arguments.
+	assert.EqualError(t, f.ReplacePicture("SheetN", "A1", png, ""), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ReplacePicture("Sheet1", "A", png, ""), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.ReplacePicture("Sheet1", "A1", []byte("unknown"), ""), "image: unknown format")
+	assert.EqualError(t, f.ReplacePicture("Sheet1", "A1", png, "{x_scale: 1}"), "invalid character 'x' looking for beginning of object key string")
+	pics, err = f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 3)
+}
+
+func TestReplacePictureMedia(t *testing.T) {
+	newImage := func(encode func(w io.Writer, m image.Image) error) []byte {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, encode(buf, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+		return buf.Bytes()
+	}
+	pngImg := newImage(png.Encode)
+	jpgImg := newImage(func(w io.Writer, m image.Image) error { return jpeg.Encode(w, m, nil) })
+	gifImg := newImage(func(w io.Writer, m image.Image) error { return gif.Encode(w, m, nil) })
+	f := NewFile()
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "A1", "", "excel", ".png", pngImg))
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "K1", "", "excel", ".png", pngImg))
+	// Test replace picture with the media file referenced by another picture.
+	assert.NoError(t, f.ReplacePicture("Sheet1", "A1", jpgImg, ""))
+	assert.Equal(t, pngImg, f.XLSX["xl/media/image1.png"])
+	assert.Equal(t, jpgImg, f.XLSX["xl/media/image2.jpeg"])
+	rels := f.relsReader("xl/drawings/_rels/drawing1.xml.rels")
+	assert.Len(t, rels.Relationships, 2)
+	// Test replace picture without other reference to the media file.
+	assert.NoError(t, f.ReplacePicture("Sheet1", "K1", gifImg, `{"x_scale": 2}`))
+	_, ok := f.XLSX["xl/media/image1.png"]
+	assert.False(t, ok)
+	assert.Len(t, rels.Relationships, 2)
+	assert.NoError(t, f.ReplacePicture("Sheet1", "A1", pngImg, ""))
+	_, ok = f.XLSX["xl/media/image2.jpeg"]
+	assert.False(t, ok)
+	// Test the content type of the replaced picture without default
+	// extensions.
+	f.ContentTypes.Defaults = f.ContentTypes.Defaults[:0]
+	assert.NoError(t, f.ReplacePicture("Sheet1", "A1", jpgImg, ""))
+	var extensions []string
+	for _, d := range f.ContentTypes.Defaults {
+		extensions = append(extensions, d.Extension)
+	}
+	assert.Contains(t, extensions, "jpeg")
+
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	pics, err := f.GetPictures("Sheet1")
+	assert.NoError(t, err)
+	if assert.Len(t, pics, 2) {
+		assert.Equal(t, jpgImg, pics[0].File)
+		assert.Equal(t, gifImg, pics[1].File)
+	}
+	// Test replace the SVG picture deletes the SVG image relationship and
+	// media file.
+	countSVG := func(f *File) (media, rels int) {
+		for name := range f.XLSX {
+			if strings.HasPrefix(name, "xl/media/") && strings.HasSuffix(name, ".svg") {
+				media++
+			}
+		}
+		for _, rel := range f.relsReader("xl/drawings/_rels/drawing1.xml.rels").Relationships {
+			if strings.HasSuffix(rel.Target, ".svg") {
+				rels++
+			}
+		}
+		return
+	}
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="60" height="30"/>`)
+	for _, cell := range []string{"P1", "U1", "Z1"} {
+		assert.NoError(t, f.AddPictureFromBytes("Sheet1", cell, "", "logo", ".svg", svg))
+	}
+	assert.NoError(t, f.ReplacePicture("Sheet1", "P1", pngImg, ""))
+	media, svgRels := countSVG(f)
+	assert.Equal(t, 2, svgRels)
+	assert.Equal(t, 1, media)
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.NoError(t, f.ReplacePicture("Sheet1", "U1", gifImg, ""))
+	assert.NoError(t, f.ReplacePicture("Sheet1", "Z1", jpgImg, `{"x_scale": 2}`))
+	media, svgRels = countSVG(f)
+	assert.Equal(t, 0, svgRels)
+	assert.Equal(t, 0, media)
+}
+
 func TestAddDrawingPicture(t *testing.T) {
 	// testing addDrawingPicture with illegal cell coordinates.
 	f := NewFile()
-	assert.EqualError(t, f.addDrawingPicture("sheet1", "", "A", "", 0, 0, 0, 0, nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.addDrawingPicture("sheet1", "", "A", "", 0, 0, 0, 0, 0, nil), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
 func TestAddPictureFromBytes(t *testing.T) {
@@ -167,6 +415,59 @@ func TestAddPictureFromBytes(t *testing.T) {
 	assert.EqualError(t, f.AddPictureFromBytes("SheetN", fmt.Sprint("A", 1), "", "logo", ".png", imgFile), "sheet SheetN is not exist")
 }
 
+func TestAddHeaderFooterImage(t *testing.T) {
+	f := NewFile()
+	buf := new(bytes.Buffer)
+	assert.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+	file := buf.Bytes()
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "center", Extension: ".png", File: file}))
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "right", IsFooter: true, Extension: ".png", File: file}))
+	// Test replace the picture in the same section of the header.
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "center", Extension: ".png", File: file}))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &FormatHeaderFooter{OddHeader: "&C&G", OddFooter: "&R&G"}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxLegacyDrawingHF{RID: "rId1"}, ws.LegacyDrawingHF)
+
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	vml := string(f.XLSX["xl/drawings/vmlDrawing1.vml"])
+	assert.Equal(t, 1, strings.Count(vml, `<v:shapetype id="_x0000_t75"`))
+	assert.Contains(t, vml, `<v:shape id="CH" o:spid="_x0000_s1025" type="#_x0000_t75" style="position:absolute;margin-left:0;margin-top:0;width:30pt;height:15pt;z-index:1"><v:imagedata o:relid="rId1"></v:imagedata><o:lock v:ext="edit" rotation="t"></o:lock></v:shape>`)
+	assert.Contains(t, vml, `<v:shape id="RF" o:spid="_x0000_s1026" type="#_x0000_t75"`)
+	assert.Equal(t, 2, strings.Count(vml, "<v:shape "))
+	rels := f.relsReader("xl/drawings/_rels/vmlDrawing1.vml.rels")
+	if assert.NotNil(t, rels) && assert.Len(t, rels.Relationships, 1) {
+		assert.Equal(t, SourceRelationshipImage, rels.Relationships[0].Type)
+		assert.Equal(t, "../media/image1.png", rels.Relationships[0].Target)
+	}
+	rels = f.relsReader("xl/worksheets/_rels/sheet1.xml.rels")
+	if assert.NotNil(t, rels) && assert.Len(t, rels.Relationships, 1) {
+		assert.Equal(t, SourceRelationshipDrawingVML, rels.Relationships[0].Type)
+		assert.Equal(t, "../drawings/vmlDrawing1.vml", rels.Relationships[0].Target)
+	}
+	contentTypes := map[string]string{}
+	for _, d := range f.contentTypesReader().Defaults {
+		contentTypes[d.Extension] = d.ContentType
+	}
+	assert.Equal(t, ContentTypeVML, contentTypes["vml"])
+	assert.Equal(t, "image/png", contentTypes["png"])
+	// Test add the picture to the existing header and footer drawing.
+	assert.NoError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "left", Extension: ".png", File: file}))
+	assert.Equal(t, 3, len(f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape))
+	assert.Equal(t, "LH", f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape[2].ID)
+	assert.Equal(t, "_x0000_s1027", f.VMLDrawing["xl/drawings/vmlDrawing1.vml"].Shape[2].Spid)
+
+	// Test add the header and footer picture with invalid settings.
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", nil), "header footer image option can not be nil")
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "top", Extension: ".png", File: file}), "invalid header footer image position top")
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "left", Extension: ".svg", File: file}), "unsupported image extension")
+	assert.EqualError(t, f.AddHeaderFooterImage("Sheet1", &HeaderFooterImage{Position: "left", Extension: ".png", File: []byte("png")}), "image: unknown format")
+	assert.EqualError(t, f.AddHeaderFooterImage("SheetN", &HeaderFooterImage{Position: "left", Extension: ".png", File: file}), "sheet SheetN is not exist")
+}
+
 func TestDeletePicture(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	assert.NoError(t, err)
@@ -10,14 +10,21 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 // PivotTableOption directly maps the format settings of the pivot table.
+// Page, Rows, Columns and Data specifies the fields in the report filter,
+// rows, columns and values area of the pivot table. RowGrandTotals and
+// ColGrandTotals specifies whether to show the grand totals for rows and
+// columns, the grand totals will be shown if they are nil.
 type PivotTableOption struct {
 	DataRange       string
 	PivotTableRange string
@@ -25,6 +32,8 @@ type PivotTableOption struct {
 	Rows            []PivotTableField
 	Columns         []PivotTableField
 	Data            []PivotTableField
+	RowGrandTotals  *bool
+	ColGrandTotals  *bool
 }
 
 // PivotTableField directly maps the field settings of the pivot table.
@@ -46,10 +55,15 @@ type PivotTableOption struct {
 //
 // Name specifies the name of the data field. Maximum 255 characters
 // are allowed in data field name, excess characters will be truncated.
+//
+// DefaultSubtotal specifies whether to show the subtotals of the items for
+// the fields in the rows and columns area, the subtotals will be shown if it
+// is nil.
 type PivotTableField struct {
-	Data     string
-	Name     string
-	Subtotal string
+	Data            string
+	Name            string
+	Subtotal        string
+	DefaultSubtotal *bool
 }
 
 // AddPivotTable provides the method to add pivot table by given pivot table
@@ -155,6 +169,11 @@ func (f *File) parseFormatPivotTableSet(opt *PivotTableOption) (*xlsxWorksheet,
 	if !ok {
 		return dataSheet, pivotTableSheetPath, fmt.Errorf("sheet %s is not exist", pivotTableSheetName)
 	}
+	for _, fields := range [][]PivotTableField{opt.Page, opt.Rows, opt.Columns, opt.Data} {
+		if _, err = f.getPivotFieldsIndex(fields, opt); err != nil {
+			return dataSheet, pivotTableSheetPath, err
+		}
+	}
 	return dataSheet, pivotTableSheetPath, err
 }
 
@@ -283,7 +302,9 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 			Count: 1,
 			I:     []*xlsxI{{}},
 		},
-		DataFields: &xlsxDataFields{},
+		DataFields:     &xlsxDataFields{},
+		RowGrandTotals: opt.RowGrandTotals,
+		ColGrandTotals: opt.ColGrandTotals,
 		PivotTableStyleInfo: &xlsxPivotTableStyleInfo{
 			Name:           "PivotStyleLight16",
 			ShowRowHeaders: true,
@@ -320,6 +341,11 @@ func (f *File) addPivotTable(cacheID, pivotTableID int, pivotTableXML string, op
 		return err
 	}
 
+	// page fields
+	if err = f.addPivotPageFields(&pt, opt); err != nil {
+		return err
+	}
+
 	// data fields
 	dataFieldsIndex, err := f.getPivotFieldsIndex(opt.Data, opt)
 	if err != nil {
@@ -370,6 +396,16 @@ func inPivotTableField(a []PivotTableField, x string) int {
 // definition and option.
 func (f *File) addPivotColFields(pt *xlsxPivotTableDefinition, opt *PivotTableOption) error {
 	if len(opt.Columns) == 0 {
+		if len(opt.Data) <= 1 {
+			return nil
+		}
+		pt.ColFields = &xlsxColFields{}
+		// The multiple data fields are displayed in the columns area by the
+		// values field which the index is -2.
+		pt.ColFields.Field = append(pt.ColFields.Field, &xlsxField{
+			X: -2,
+		})
+		pt.ColFields.Count = len(pt.ColFields.Field)
 		return nil
 	}
 
@@ -386,6 +422,13 @@ func (f *File) addPivotColFields(pt *xlsxPivotTableDefinition, opt *PivotTableOp
 		})
 	}
 
+	// values field
+	if len(opt.Data) > 1 {
+		pt.ColFields.Field = append(pt.ColFields.Field, &xlsxField{
+			X: -2,
+		})
+	}
+
 	// count col fields
 	pt.ColFields.Count = len(pt.ColFields.Field)
 	return err
@@ -399,33 +442,23 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opt *PivotTableOptio
 		return err
 	}
 	for _, name := range order {
-		if inPivotTableField(opt.Rows, name) != -1 {
-			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{
-				Axis: "axisRow",
-				Name: f.getPivotTableFieldName(name, opt.Rows),
-				Items: &xlsxItems{
-					Count: 1,
-					Item: []*xlsxItem{
-						{T: "default"},
-					},
-				},
-			})
+		dataField := inPivotTableField(opt.Data, name) != -1
+		if idx := inPivotTableField(opt.Rows, name); idx != -1 {
+			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField,
+				newPivotField("axisRow", f.getPivotTableFieldName(name, opt.Rows), opt.Rows[idx].DefaultSubtotal, dataField))
 			continue
 		}
-		if inPivotTableField(opt.Columns, name) != -1 {
-			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{
-				Axis: "axisCol",
-				Name: f.getPivotTableFieldName(name, opt.Columns),
-				Items: &xlsxItems{
-					Count: 1,
-					Item: []*xlsxItem{
-						{T: "default"},
-					},
-				},
-			})
+		if idx := inPivotTableField(opt.Columns, name); idx != -1 {
+			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField,
+				newPivotField("axisCol", f.getPivotTableFieldName(name, opt.Columns), opt.Columns[idx].DefaultSubtotal, dataField))
+			continue
+		}
+		if idx := inPivotTableField(opt.Page, name); idx != -1 {
+			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField,
+				newPivotField("axisPage", f.getPivotTableFieldName(name, opt.Page), opt.Page[idx].DefaultSubtotal, dataField))
 			continue
 		}
-		if inPivotTableField(opt.Data, name) != -1 {
+		if dataField {
 			pt.PivotFields.PivotField = append(pt.PivotFields.PivotField, &xlsxPivotField{
 				DataField: true,
 			})
@@ -436,6 +469,50 @@ func (f *File) addPivotFields(pt *xlsxPivotTableDefinition, opt *PivotTableOptio
 	return err
 }
 
+// newPivotField provides a function to create the pivot field in the rows,
+// columns or page area by given axis, field name and whether to show the
+// subtotals of the items.
+func newPivotField(axis, name string, defaultSubtotal *bool, dataField bool) *xlsxPivotField {
+	field := &xlsxPivotField{
+		Axis:            axis,
+		Name:            name,
+		DataField:       dataField,
+		DefaultSubtotal: defaultSubtotal,
+	}
+	if defaultSubtotal == nil || *defaultSubtotal {
+		field.Items = &xlsxItems{
+			Count: 1,
+			Item: []*xlsxItem{
+				{T: "default"},
+			},
+		}
+	}
+	return field
+}
+
+// addPivotPageFields create pivot page fields by given pivot table
+// definition and option.
+func (f *File) addPivotPageFields(pt *xlsxPivotTableDefinition, opt *PivotTableOption) error {
+	if len(opt.Page) == 0 {
+		return nil
+	}
+	pageFieldsIndex, err := f.getPivotFieldsIndex(opt.Page, opt)
+	if err != nil {
+		return err
+	}
+	pt.PageFields = &xlsxPageFields{}
+	for _, fieldIdx := range pageFieldsIndex {
+		pt.PageFields.PageField = append(pt.PageFields.PageField, &xlsxPageField{
+			Fld:  fieldIdx,
+			Hier: -1,
+		})
+	}
+	pt.PageFields.Count = len(pt.PageFields.PageField)
+	pt.Location.RowPageCount = pt.PageFields.Count
+	pt.Location.ColPageCount = 1
+	return err
+}
+
 // countPivotTables provides a function to get drawing files count storage in
 // the folder xl/pivotTables.
 func (f *File) countPivotTables() int {
@@ -469,9 +546,11 @@ func (f *File) getPivotFieldsIndex(fields []PivotTableField, opt *PivotTableOpti
 		return pivotFieldsIndex, err
 	}
 	for _, field := range fields {
-		if pos := inStrSlice(orders, field.Data); pos != -1 {
-			pivotFieldsIndex = append(pivotFieldsIndex, pos)
+		pos := inStrSlice(orders, field.Data)
+		if pos == -1 {
+			return pivotFieldsIndex, fmt.Errorf("field %s is not exist in the data range", field.Data)
 		}
+		pivotFieldsIndex = append(pivotFieldsIndex, pos)
 	}
 	return pivotFieldsIndex, nil
 }
@@ -538,3 +617,56 @@ func (f *File) addWorkbookPivotCache(RID int) int {
 	})
 	return cacheID
 }
+
+// RefreshPivotTable provides the method to mark the pivot cache of the pivot
+// table to be refreshed when the workbook is opened by given pivot table
+// name, so that the spreadsheet application will recompute the pivot table
+// from the source data on load. This is useful when the source data of the
+// pivot table has been changed. For example, refresh the pivot table named
+// "Pivot Table1" on load:
+//
+//    err := f.RefreshPivotTable("Pivot Table1")
+//
+func (f *File) RefreshPivotTable(name string) error {
+	var pivotTables []string
+	for path := range f.XLSX {
+		if strings.HasPrefix(path, "xl/pivotTables/pivotTable") && strings.HasSuffix(path, ".xml") {
+			pivotTables = append(pivotTables, path)
+		}
+	}
+	sort.Strings(pivotTables)
+	for _, pivotTableXML := range pivotTables {
+		var pt struct {
+			Name string `xml:"name,attr"`
+		}
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(pivotTableXML)))).
+			Decode(&pt); err != nil || pt.Name != name {
+			continue
+		}
+		rels := f.relsReader(strings.Replace(pivotTableXML, "xl/pivotTables/", "xl/pivotTables/_rels/", 1) + ".rels")
+		if rels == nil {
+			rels = &xlsxRelationships{}
+		}
+		for _, rel := range rels.Relationships {
+			if rel.Type != SourceRelationshipPivotCache {
+				continue
+			}
+			pivotCacheXML := strings.Replace(rel.Target, "..", "xl", 1)
+			content, ok := f.XLSX[pivotCacheXML]
+			if !ok {
+				break
+			}
+			pc := xlsxPivotCacheDefinition{}
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+				Decode(&pc); err != nil && err != io.EOF {
+				return err
+			}
+			pc.RefreshOnLoad = true
+			pivotCache, err := xml.Marshal(pc)
+			f.saveFileList(pivotCacheXML, pivotCache)
+			return err
+		}
+		return fmt.Errorf("pivot cache of the pivot table %s is not exist", name)
+	}
+	return fmt.Errorf("pivot table %s is not exist", name)
+}
@@ -1,6 +1,8 @@
 package excelize
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"math/rand"
 	"path/filepath"
@@ -178,6 +180,128 @@ func TestAddPivotTable(t *testing.T) {
 	assert.EqualError(t, err, `parameter 'DataRange' parsing error: parameter is required`)
 }
 
+func TestAddPivotTableFields(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Year", "Type", "Sales", "Region"}))
+	for i, row := range [][]interface{}{
+		{"Jan", 2019, "Meat", 100, "East"},
+		{"Feb", 2019, "Dairy", 200, "West"},
+		{"Jan", 2020, "Meat", 300, "East"},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+2), &row))
+	}
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$E$4",
+		PivotTableRange: "Sheet1!$G$2:$M$20",
+		Page:            []PivotTableField{{Data: "Region"}},
+		Rows:            []PivotTableField{{Data: "Month"}, {Data: "Year", DefaultSubtotal: boolPtr(false)}},
+		Columns:         []PivotTableField{{Data: "Type", DefaultSubtotal: boolPtr(false)}},
+		Data:            []PivotTableField{{Data: "Sales", Subtotal: "Sum", Name: "Sum of Sales"}, {Data: "Sales", Subtotal: "Average", Name: "Average of Sales"}},
+		ColGrandTotals:  boolPtr(false),
+	}))
+	var pt xlsxPivotTableDefinition
+	assert.NoError(t, xml.Unmarshal(f.XLSX["xl/pivotTables/pivotTable1.xml"], &pt))
+	assert.Equal(t, 5, pt.PivotFields.Count)
+	for idx, expected := range []struct {
+		axis            string
+		dataField       bool
+		defaultSubtotal *bool
+		items           int
+	}{
+		{"axisRow", false, nil, 1},
+		{"axisRow", false, boolPtr(false), 0},
+		{"axisCol", false, boolPtr(false), 0},
+		{"", true, nil, 0},
+		{"axisPage", false, nil, 1},
+	} {
+		field := pt.PivotFields.PivotField[idx]
+		assert.Equal(t, expected.axis, field.Axis, idx)
+		assert.Equal(t, expected.dataField, field.DataField, idx)
+		assert.Equal(t, expected.defaultSubtotal, field.DefaultSubtotal, idx)
+		if expected.items == 0 {
+			assert.Nil(t, field.Items, idx)
+			continue
+		}
+		assert.Len(t, field.Items.Item, expected.items, idx)
+	}
+	assert.Equal(t, []*xlsxField{{X: 0}, {X: 1}}, pt.RowFields.Field)
+	assert.Equal(t, []*xlsxField{{X: 2}, {X: -2}}, pt.ColFields.Field)
+	assert.Equal(t, 1, pt.PageFields.Count)
+	assert.Equal(t, &xlsxPageField{Fld: 4, Hier: -1}, pt.PageFields.PageField[0])
+	assert.Equal(t, 1, pt.Location.RowPageCount)
+	assert.Equal(t, 2, pt.DataFields.Count)
+	assert.Equal(t, &xlsxDataField{Name: "Sum of Sales", Fld: 3, Subtotal: "sum"}, pt.DataFields.DataField[0])
+	assert.Equal(t, &xlsxDataField{Name: "Average of Sales", Fld: 3, Subtotal: "average"}, pt.DataFields.DataField[1])
+	assert.Nil(t, pt.RowGrandTotals)
+	assert.Equal(t, boolPtr(false), pt.ColGrandTotals)
+
+	// Test add pivot table with multiple data fields and without column fields.
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$E$4",
+		PivotTableRange: "Sheet1!$O$2:$S$20",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}, {Data: "Year", Subtotal: "Count"}},
+	}))
+	pt = xlsxPivotTableDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.XLSX["xl/pivotTables/pivotTable2.xml"], &pt))
+	assert.Equal(t, []*xlsxField{{X: -2}}, pt.ColFields.Field)
+	assert.Nil(t, pt.PageFields)
+
+	// Test add pivot table with the field which is not exist in the data range.
+	for _, opt := range []*PivotTableOption{
+		{Page: []PivotTableField{{Data: "Field"}}},
+		{Rows: []PivotTableField{{Data: "Field"}}},
+		{Columns: []PivotTableField{{Data: "Field"}}},
+		{Data: []PivotTableField{{Data: "Field"}}},
+	} {
+		opt.DataRange, opt.PivotTableRange = "Sheet1!$A$1:$E$4", "Sheet1!$U$2:$Y$20"
+		assert.EqualError(t, f.AddPivotTable(opt), "field Field is not exist in the data range")
+	}
+	assert.Equal(t, 2, f.countPivotTables())
+	assert.Equal(t, 2, f.countPivotCache())
+}
+
+func TestRefreshPivotTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]string{"Month", "Sales"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Jan", 100}))
+	assert.NoError(t, f.AddPivotTable(&PivotTableOption{
+		DataRange:       "Sheet1!$A$1:$B$2",
+		PivotTableRange: "Sheet1!$D$1:$E$5",
+		Rows:            []PivotTableField{{Data: "Month"}},
+		Data:            []PivotTableField{{Data: "Sales"}},
+	}))
+	// Test refresh the pivot table which the pivot cache doesn't refresh on load.
+	pivotCacheXML := "xl/pivotCache/pivotCacheDefinition1.xml"
+	f.XLSX[pivotCacheXML] = bytes.Replace(f.XLSX[pivotCacheXML], []byte(` refreshOnLoad="true"`), []byte(` refreshOnLoad="0"`), 1)
+	assert.NoError(t, f.RefreshPivotTable("Pivot Table1"))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	var pc xlsxPivotCacheDefinition
+	assert.NoError(t, xml.Unmarshal(f.XLSX[pivotCacheXML], &pc))
+	assert.True(t, pc.RefreshOnLoad)
+	assert.Equal(t, 1, bytes.Count(f.XLSX[pivotCacheXML], []byte("refreshOnLoad=")))
+	assert.NotNil(t, pc.CacheSource)
+
+	// Test refresh the pivot table on the self-closing pivot cache definition.
+	f.XLSX[pivotCacheXML] = []byte(`<x:pivotCacheDefinition xmlns:x="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`)
+	assert.NoError(t, f.RefreshPivotTable("Pivot Table1"))
+	pc = xlsxPivotCacheDefinition{}
+	assert.NoError(t, xml.Unmarshal(f.XLSX[pivotCacheXML], &pc))
+	assert.True(t, pc.RefreshOnLoad)
+	// Test refresh the pivot table with invalid pivot cache definition.
+	f.XLSX[pivotCacheXML] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.RefreshPivotTable("Pivot Table1"), "XML syntax error on line 1: invalid UTF-8")
+
+	// Test refresh the pivot table which is not exist.
+	assert.EqualError(t, f.RefreshPivotTable("Pivot Table2"), "pivot table Pivot Table2 is not exist")
+	// Test refresh the pivot table without pivot cache.
+	delete(f.XLSX, pivotCacheXML)
+	assert.EqualError(t, f.RefreshPivotTable("Pivot Table1"), "pivot cache of the pivot table Pivot Table1 is not exist")
+}
+
 func TestInStrSlice(t *testing.T) {
 	assert.EqualValues(t, -1, inStrSlice([]string{}, ""))
 }
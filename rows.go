@@ -18,6 +18,8 @@ import (
 	"log"
 	"math"
 	"strconv"
+
+	"github.com/mohae/deepcopy"
 )
 
 // GetRows return all the rows in a sheet by given worksheet name (case
@@ -44,6 +46,7 @@ func (f *File) GetRows(sheet string) ([][]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	results := make([][]string, 0, 64)
 	for rows.Next() {
 		if rows.Error() != nil {
@@ -58,6 +61,196 @@ func (f *File) GetRows(sheet string) ([][]string, error) {
 	return results, nil
 }
 
+// GetRowsRange provides a function to get the rows between the start and end
+// row number (inclusive) in a sheet by given worksheet name (case sensitive).
+// The rows after the end row will not be decoded, so it could be used to read
+// the worksheet page by page. The returned rows will be truncated at the last
+// non-empty row of the worksheet. For example, get the rows 101 to 200 of
+// Sheet1:
+//
+//    rows, err := f.GetRowsRange("Sheet1", 101, 200)
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, row := range rows {
+//        for _, colCell := range row {
+//            fmt.Print(colCell, "\t")
+//        }
+//        fmt.Println()
+//    }
+//
+func (f *File) GetRowsRange(sheet string, start, end int) ([][]string, error) {
+	if start < 1 || start > TotalRows {
+		return nil, newInvalidRowNumberError(start)
+	}
+	if end < 1 || end > TotalRows {
+		return nil, newInvalidRowNumberError(end)
+	}
+	if start > end {
+		return nil, fmt.Errorf("invalid row range %d to %d", start, end)
+	}
+	var (
+		d       = f.sharedStringsReader()
+		results = make([][]string, 0, end-start+1)
+	)
+	err := f.decodeRows(sheet, start, end, func(row int, rowData *xlsxRow) error {
+		for len(results) < row-start {
+			results = append(results, nil)
+		}
+		var columns []string
+		for _, colCell := range rowData.C {
+			col := len(columns) + 1
+			if colCell.R != "" {
+				var err error
+				if col, _, err = CellNameToCoordinates(colCell.R); err != nil {
+					return err
+				}
+			}
+			for len(columns) < col-1 {
+				columns = append(columns, "")
+			}
+			val, _ := colCell.getValueFrom(f, d)
+			columns = append(columns, val)
+		}
+		results = append(results, columns)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CellInfo directly maps the value, type and style of a cell. The Value is
+// the formatted value of the cell, the same as the value returned by
+// GetCellValue, and the RawValue is the value without applying the number
+// format. The Type is the type of the cell, such as "s" for shared string,
+// "str" for formula string, "inlineStr", "b" for boolean, "e" for error, and
+// empty or "n" for number. The StyleID is the style index of the cell.
+type CellInfo struct {
+	Value    string
+	RawValue string
+	Type     string
+	StyleID  int
+}
+
+// GetRowsWithStyle return all the rows in a sheet by given worksheet name
+// (case sensitive), each cell contains the formatted value, raw value, type
+// and style index, which could be used to avoid calling GetCellStyle for each
+// cell. The worksheet will be decoded row by row in the same way as
+// GetRowsRange, but the result takes more memory than GetRows, since each
+// cell keeps the raw value, type and style index besides the formatted value.
+// For example:
+//
+//    rows, err := f.GetRowsWithStyle("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, row := range rows {
+//        for _, cell := range row {
+//            fmt.Print(cell.Value, "(", cell.StyleID, ")\t")
+//        }
+//        fmt.Println()
+//    }
+//
+func (f *File) GetRowsWithStyle(sheet string) ([][]CellInfo, error) {
+	var (
+		d       = f.sharedStringsReader()
+		results = make([][]CellInfo, 0, 64)
+	)
+	err := f.decodeRows(sheet, 1, TotalRows, func(row int, rowData *xlsxRow) error {
+		for len(results) < row-1 {
+			results = append(results, nil)
+		}
+		var columns []CellInfo
+		for _, colCell := range rowData.C {
+			col := len(columns) + 1
+			if colCell.R != "" {
+				var err error
+				if col, _, err = CellNameToCoordinates(colCell.R); err != nil {
+					return err
+				}
+			}
+			for len(columns) < col-1 {
+				columns = append(columns, CellInfo{})
+			}
+			val, _ := colCell.getValueFrom(f, d)
+			rawCell := colCell
+			rawCell.S = 0
+			rawVal, _ := rawCell.getValueFrom(f, d)
+			columns = append(columns, CellInfo{Value: val, RawValue: rawVal, Type: colCell.T, StyleID: colCell.S})
+		}
+		results = append(results, columns)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// decodeRows provides a function to decode the rows between the start and
+// end row number (inclusive) in a sheet by given worksheet name one by one,
+// and call the function fn with the row number and the decoded row. The rows
+// after the end row will not be decoded.
+func (f *File) decodeRows(sheet string, start, end int, fn func(row int, rowData *xlsxRow) error) error {
+	name, ok := f.sheetMap[trimSheetName(sheet)]
+	if !ok {
+		return ErrSheetNotExist{sheet}
+	}
+	if f.Sheet[name] != nil {
+		// flush data
+		output, _ := xml.Marshal(f.Sheet[name])
+		f.saveFileList(name, replaceRelationshipsNameSpaceBytes(output))
+	}
+	reader := f.readXMLReader(name)
+	defer reader.Close()
+	var (
+		decoder = f.xmlNewDecoder(reader)
+		row     int
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		startElement, ok := token.(xml.StartElement)
+		if !ok || startElement.Name.Local != "row" {
+			continue
+		}
+		row++
+		for _, attr := range startElement.Attr {
+			if attr.Name.Local == "r" {
+				if row, err = strconv.Atoi(attr.Value); err != nil {
+					return err
+				}
+			}
+		}
+		if row < start {
+			if err = decoder.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if row > end {
+			break
+		}
+		var rowData xlsxRow
+		if err = decoder.DecodeElement(&rowData, &startElement); err != nil {
+			return err
+		}
+		if err = fn(row, &rowData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Rows defines an iterator to a sheet
 type Rows struct {
 	err                        error
@@ -65,13 +258,30 @@ type Rows struct {
 	sheet                      string
 	rows                       []xlsxRow
 	f                          *File
+	reader                     io.ReadCloser
 	decoder                    *xml.Decoder
 }
 
-// Next will return true if find the next row element.
+// Next will return true if find the next row element. The worksheet will be
+// closed if there are no more rows.
 func (rows *Rows) Next() bool {
 	rows.curRow++
-	return rows.curRow <= rows.totalRow
+	if rows.curRow > rows.totalRow {
+		_ = rows.Close()
+		return false
+	}
+	return true
+}
+
+// Close closes the worksheet which opened by the rows iterator, it should be
+// called if the iteration is stopped before reaching the last row.
+func (rows *Rows) Close() error {
+	if rows.reader == nil {
+		return nil
+	}
+	err := rows.reader.Close()
+	rows.reader = nil
+	return err
 }
 
 // Error will return the error when the find next row element
@@ -148,7 +358,9 @@ func (err ErrSheetNotExist) Error() string {
 	return fmt.Sprintf("sheet %s is not exist", string(err.SheetName))
 }
 
-// Rows return a rows iterator. For example:
+// Rows return a rows iterator. The worksheet will be closed after reading the
+// last row, and Close should be called if the iteration is stopped early. For
+// example:
 //
 //    rows, err := f.Rows("Sheet1")
 //    if err != nil {
@@ -182,7 +394,9 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 		row       int
 		rows      Rows
 	)
-	decoder := f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
+	reader := f.readXMLReader(name)
+	defer reader.Close()
+	decoder := f.xmlNewDecoder(reader)
 	for {
 		token, _ := decoder.Token()
 		if token == nil {
@@ -207,7 +421,8 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	}
 	rows.f = f
 	rows.sheet = name
-	rows.decoder = f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
+	rows.reader = f.readXMLReader(name)
+	rows.decoder = f.xmlNewDecoder(rows.reader)
 	return &rows, nil
 }
 
@@ -304,16 +519,16 @@ func (xlsx *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
 		xlsxSI := 0
 		xlsxSI, _ = strconv.Atoi(xlsx.V)
 		if len(d.SI) > xlsxSI {
-			return f.formattedValue(xlsx.S, d.SI[xlsxSI].String()), nil
+			return f.formattedText(xlsx.S, bstrUnmarshal(d.SI[xlsxSI].String())), nil
 		}
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formattedText(xlsx.S, xlsx.V), nil
 	case "str":
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formattedText(xlsx.S, bstrUnmarshal(xlsx.V)), nil
 	case "inlineStr":
 		if xlsx.IS != nil {
-			return f.formattedValue(xlsx.S, xlsx.IS.String()), nil
+			return f.formattedText(xlsx.S, bstrUnmarshal(xlsx.IS.String())), nil
 		}
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formattedText(xlsx.S, bstrUnmarshal(xlsx.V)), nil
 	default:
 		return f.formattedValue(xlsx.S, xlsx.V), nil
 	}
@@ -325,7 +540,7 @@ func (xlsx *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
 //    err := f.SetRowVisible("Sheet1", 2, false)
 //
 func (f *File) SetRowVisible(sheet string, row int, visible bool) error {
-	if row < 1 {
+	if row < 1 || row > TotalRows {
 		return newInvalidRowNumberError(row)
 	}
 
@@ -345,7 +560,7 @@ func (f *File) SetRowVisible(sheet string, row int, visible bool) error {
 //    visible, err := f.GetRowVisible("Sheet1", 2)
 //
 func (f *File) GetRowVisible(sheet string, row int) (bool, error) {
-	if row < 1 {
+	if row < 1 || row > TotalRows {
 		return false, newInvalidRowNumberError(row)
 	}
 
@@ -406,12 +621,17 @@ func (f *File) GetRowOutlineLevel(sheet string, row int) (uint8, error) {
 //
 //    err := f.RemoveRow("Sheet1", 3)
 //
+// The subsequent rows will be shifted up, and the formula references, merged
+// cells, defined names and data validations will be updated accordingly. The
+// references to the removed row will be turned into #REF!, and the range
+// references and merged cells which contain the removed row will be shrunk.
+//
 // Use this method with caution, which will affect changes in references such
-// as formulas, charts, and so on. If there is any referenced value of the
-// worksheet, it will cause a file error when you open it. The excelize only
-// partially updates these references currently.
+// as charts, and so on. If there is any referenced value of the worksheet, it
+// will cause a file error when you open it. The excelize only partially
+// updates these references currently.
 func (f *File) RemoveRow(sheet string, row int) error {
-	if row < 1 {
+	if row < 1 || row > TotalRows {
 		return newInvalidRowNumberError(row)
 	}
 
@@ -443,20 +663,43 @@ func (f *File) RemoveRow(sheet string, row int) error {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) InsertRow(sheet string, row int) error {
-	if row < 1 {
+	return f.InsertRows(sheet, row, 1)
+}
+
+// InsertRows provides a function to insert multiple rows before given Excel
+// row number starting from 1 and the number of rows. The cells, formula
+// references, merged cells, defined names and data validations will be
+// shifted down accordingly. For example, create 5 new rows before row 3 in
+// Sheet1:
+//
+//    err := f.InsertRows("Sheet1", 3, 5)
+//
+// Use this method with caution, which will affect changes in references such
+// as charts, and so on. If there is any referenced value of the worksheet, it
+// will cause a file error when you open it. The excelize only partially
+// updates these references currently.
+func (f *File) InsertRows(sheet string, row, n int) error {
+	if row < 1 || row > TotalRows {
 		return newInvalidRowNumberError(row)
 	}
-	return f.adjustHelper(sheet, rows, row, 1)
+	if n < 1 || row+n-1 > TotalRows {
+		return fmt.Errorf("invalid number of rows %d", n)
+	}
+	return f.adjustHelper(sheet, rows, row, n)
 }
 
 // DuplicateRow inserts a copy of specified row (by its Excel row number) below
 //
 //    err := f.DuplicateRow("Sheet1", 2)
 //
+// The cell values, styles and formulas will be copied, and the relative
+// references in the formulas will be adjusted for the new position. The
+// merged cells within the row will be replicated.
+//
 // Use this method with caution, which will affect changes in references such
-// as formulas, charts, and so on. If there is any referenced value of the
-// worksheet, it will cause a file error when you open it. The excelize only
-// partially updates these references currently.
+// as charts, and so on. If there is any referenced value of the worksheet, it
+// will cause a file error when you open it. The excelize only partially
+// updates these references currently.
 func (f *File) DuplicateRow(sheet string, row int) error {
 	return f.DuplicateRowTo(sheet, row, row+1)
 }
@@ -466,10 +709,14 @@ func (f *File) DuplicateRow(sheet string, row int) error {
 //
 //    err := f.DuplicateRowTo("Sheet1", 2, 7)
 //
+// The cell values, styles and formulas will be copied, and the relative
+// references in the formulas will be adjusted for the new position. The
+// merged cells within the row will be replicated.
+//
 // Use this method with caution, which will affect changes in references such
-// as formulas, charts, and so on. If there is any referenced value of the
-// worksheet, it will cause a file error when you open it. The excelize only
-// partially updates these references currently.
+// as charts, and so on. If there is any referenced value of the worksheet, it
+// will cause a file error when you open it. The excelize only partially
+// updates these references currently.
 func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 	if row < 1 {
 		return newInvalidRowNumberError(row)
@@ -486,9 +733,8 @@ func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 	var ok bool
 	var rowCopy xlsxRow
 
-	for i, r := range xlsx.SheetData.Row {
+	for _, r := range xlsx.SheetData.Row {
 		if r.R == row {
-			rowCopy = xlsx.SheetData.Row[i]
 			ok = true
 			break
 		}
@@ -500,7 +746,25 @@ func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 	if err := f.adjustHelper(sheet, rows, row2, 1); err != nil {
 		return err
 	}
+	// Copy the source row after adjusting, the source row will be shifted
+	// down if it's below the target position.
+	srcRow := row
+	if row >= row2 {
+		srcRow++
+	}
+	for i, r := range xlsx.SheetData.Row {
+		if r.R == srcRow {
+			rowCopy = deepcopy.Copy(xlsx.SheetData.Row[i]).(xlsxRow)
+			break
+		}
+	}
+	for i := range rowCopy.C {
+		f.copyCellFormula(xlsx, &rowCopy.C[i], 0, row2-srcRow)
+	}
 
+	if len(xlsx.SheetData.Row) < row2 {
+		prepareSheetXML(xlsx, 0, row2)
+	}
 	idx2 := -1
 	for i, r := range xlsx.SheetData.Row {
 		if r.R == row2 {
@@ -512,7 +776,6 @@ func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 		return nil
 	}
 
-	rowCopy.C = append(make([]xlsxC, 0, len(rowCopy.C)), rowCopy.C...)
 	f.ajustSingleRowDimensions(&rowCopy, row2)
 
 	if idx2 != -1 {
@@ -523,6 +786,28 @@ func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 	return f.duplicateMergeCells(sheet, xlsx, row, row2)
 }
 
+// copyCellFormula provides a function to adjust the relative references in
+// the formula of the copied cell by given the source worksheet, column and
+// row offset. The shared formula will be converted to the normal formula in
+// the copied cell, and the area of the array formula will be shifted.
+func (f *File) copyCellFormula(xlsx *xlsxWorksheet, c *xlsxC, colOffset, rowOffset int) {
+	if c.F == nil {
+		return
+	}
+	if c.F.T == STCellFormulaTypeShared {
+		if formula := getSharedForumula(xlsx, c.F.Si, c.R); formula != "" {
+			c.F = &xlsxF{Content: shiftFormula(formula, colOffset, rowOffset)}
+			return
+		}
+		c.F = nil
+		return
+	}
+	c.F.Content = shiftFormula(c.F.Content, colOffset, rowOffset)
+	if c.F.Ref != "" {
+		c.F.Ref = shiftFormula(c.F.Ref, colOffset, rowOffset)
+	}
+}
+
 // duplicateMergeCells merge cells in the destination row if there are single
 // row merged cells in the copied row.
 func (f *File) duplicateMergeCells(sheet string, xlsx *xlsxWorksheet, row, row2 int) error {
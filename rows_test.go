@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -90,6 +91,79 @@ func TestRowsError(t *testing.T) {
 	assert.EqualError(t, err, "sheet SheetN is not exist")
 }
 
+func TestGetRowsRange(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 10; row++ {
+		if row == 5 {
+			continue
+		}
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{row, fmt.Sprintf("R%d", row)}))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "D6", 1.5))
+	style, err := f.NewStyle(`{"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D6", "D6", style))
+	rows, err := f.GetRowsRange("Sheet1", 4, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"4", "R4"}, nil, {"6", "R6", "", "1.50"}, {"7", "R7"}}, rows)
+	all, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, all[3:7], rows)
+	// Test get rows range exceeds the last row of the worksheet.
+	rows, err = f.GetRowsRange("Sheet1", 9, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"9", "R9"}, {"10", "R10"}}, rows)
+	rows, err = f.GetRowsRange("Sheet1", 11, 20)
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	// Test get rows range with the rows without the row number.
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row><c><v>1</v></c></row><row><c><v>2</v></c><c><v>3</v></c></row></sheetData></worksheet>`)
+	f.Sheet = make(map[string]*xlsxWorksheet)
+	rows, err = f.GetRowsRange("Sheet1", 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"2", "3"}}, rows)
+	// Test get rows range with invalid row range.
+	_, err = f.GetRowsRange("Sheet1", 0, 1)
+	assert.EqualError(t, err, newInvalidRowNumberError(0).Error())
+	_, err = f.GetRowsRange("Sheet1", 1, TotalRows+1)
+	assert.EqualError(t, err, newInvalidRowNumberError(TotalRows+1).Error())
+	_, err = f.GetRowsRange("Sheet1", 3, 2)
+	assert.EqualError(t, err, "invalid row range 3 to 2")
+	_, err = f.GetRowsRange("SheetN", 1, 2)
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get rows range with invalid row number and cell reference.
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="A"></row></sheetData></worksheet>`)
+	_, err = f.GetRowsRange("Sheet1", 1, 2)
+	assert.EqualError(t, err, `strconv.Atoi: parsing "A": invalid syntax`)
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="-"></c></row></sheetData></worksheet>`)
+	_, err = f.GetRowsRange("Sheet1", 1, 2)
+	assert.EqualError(t, err, `cannot convert cell "-" to coordinates: invalid cell name "-"`)
+}
+
+func TestGetRowsWithStyle(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(`{"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"A", 1.5, true}))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", CellError("#N/A")))
+	rows, err := f.GetRowsWithStyle("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]CellInfo{
+		{{Value: "A", RawValue: "A", Type: "str"}, {Value: "1.50", RawValue: "1.5", StyleID: style}, {Value: "1", RawValue: "1", Type: "b"}},
+		nil,
+		{{}, {}, {Value: "#N/A", RawValue: "#N/A", Type: "e"}},
+	}, rows)
+	// Test get rows with style on not exists worksheet.
+	_, err = f.GetRowsWithStyle("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get rows with style with invalid cell reference.
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="-"></c></row></sheetData></worksheet>`)
+	f.Sheet = make(map[string]*xlsxWorksheet)
+	_, err = f.GetRowsWithStyle("Sheet1")
+	assert.EqualError(t, err, `cannot convert cell "-" to coordinates: invalid cell name "-"`)
+}
+
 func TestRowHeight(t *testing.T) {
 	xlsx := NewFile()
 	sheet1 := xlsx.GetSheetName(1)
@@ -186,11 +260,14 @@ func TestRowVisibility(t *testing.T) {
 	assert.Equal(t, false, visiable)
 	assert.NoError(t, err)
 	assert.EqualError(t, f.SetRowVisible("Sheet3", 0, true), "invalid row number 0")
+	assert.EqualError(t, f.SetRowVisible("Sheet3", TotalRows+1, true), "invalid row number 1048577")
 	assert.EqualError(t, f.SetRowVisible("SheetN", 2, false), "sheet SheetN is not exist")
 
 	visible, err := f.GetRowVisible("Sheet3", 0)
 	assert.Equal(t, false, visible)
 	assert.EqualError(t, err, "invalid row number 0")
+	_, err = f.GetRowVisible("Sheet3", TotalRows+1)
+	assert.EqualError(t, err, "invalid row number 1048577")
 	_, err = f.GetRowVisible("SheetN", 1)
 	assert.EqualError(t, err, "sheet SheetN is not exist")
 
@@ -258,6 +335,87 @@ func TestRemoveRow(t *testing.T) {
 	assert.EqualError(t, f.RemoveRow("SheetN", 1), `sheet SheetN is not exist`)
 }
 
+func TestInsertRows(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", 1))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A5", "SUM(A1:A4)+$A$3"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A3*2"))
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "C4"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$3:$A$5"}))
+	dvRange := NewDataValidation(true)
+	dvRange.Sqref = "A3:A4 B6"
+	assert.NoError(t, dvRange.SetSqrefDropList("$E$3:$E$5", true))
+	assert.NoError(t, f.AddDataValidation("Sheet1", dvRange))
+
+	assert.NoError(t, f.InsertRows("Sheet1", 3, 2))
+	val, err := f.GetCellValue("Sheet1", "A5")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", val)
+	for cell, expected := range map[string]string{"Sheet1!A7": "SUM(A1:A6)+$A$5", "Sheet2!A1": "Sheet1!A5*2"} {
+		parts := strings.Split(cell, "!")
+		formula, err := f.GetCellFormula(parts[0], parts[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B2:C6", mergeCells[0].GetStartAxis()+":"+mergeCells[0].GetEndAxis())
+	assert.Equal(t, "Sheet1!$A$5:$A$7", f.GetDefinedName()[0].RefersTo)
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A5:A6 B8", xlsx.DataValidations.DataValidation[0].Sqref)
+	assert.Equal(t, "<formula1>$E$5:$E$7</formula1>", xlsx.DataValidations.DataValidation[0].Formula1)
+
+	assert.EqualError(t, f.InsertRows("Sheet1", 0, 1), "invalid row number 0")
+	assert.EqualError(t, f.InsertRows("Sheet1", 1, 0), "invalid number of rows 0")
+	assert.EqualError(t, f.InsertRows("Sheet1", TotalRows, 2), "invalid number of rows 2")
+	assert.EqualError(t, f.InsertRows("SheetN", 1, 1), "sheet SheetN is not exist")
+}
+
+func TestRemoveRowReferences(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	for cell, formula := range map[string]string{
+		"B1": "A3*2",
+		"B2": "SUM(A2:A5)+SUM(A3:A3)",
+		"B6": "A4+$A$5",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!A3+Sheet1!A4"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C2", "D4"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C3", "C3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "E3", "F3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "E5", "E6"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$3"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "Sheet1!$A$1:$A$5"}))
+
+	assert.NoError(t, f.RemoveRow("Sheet1", 3))
+	for cell, expected := range map[string]string{
+		"Sheet1!B1": "#REF!*2",
+		"Sheet1!B2": "SUM(A2:A4)+SUM(#REF!)",
+		"Sheet1!B5": "A3+$A$4",
+		"Sheet2!A1": "Sheet1!#REF!+Sheet1!A3",
+	} {
+		parts := strings.Split(cell, "!")
+		formula, err := f.GetCellFormula(parts[0], parts[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	var refs []string
+	for _, mergeCell := range mergeCells {
+		refs = append(refs, mergeCell.GetStartAxis()+":"+mergeCell.GetEndAxis())
+	}
+	assert.Equal(t, []string{"C2:D3", "E4:E5"}, refs)
+	assert.Equal(t, "Sheet1!#REF!", f.GetDefinedName()[0].RefersTo)
+	assert.Equal(t, "Sheet1!$A$1:$A$4", f.GetDefinedName()[1].RefersTo)
+
+	assert.EqualError(t, f.RemoveRow("Sheet1", TotalRows+1), "invalid row number 1048577")
+}
+
 func TestInsertRow(t *testing.T) {
 	xlsx := NewFile()
 	sheet1 := xlsx.GetSheetName(1)
@@ -807,6 +965,53 @@ func TestDuplicateRowTo(t *testing.T) {
 	assert.EqualError(t, f.DuplicateRowTo("SheetN", 1, 2), "sheet SheetN is not exist")
 }
 
+func TestDuplicateRowFormulas(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Item", 2, 3}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "B2*C2+$E$1+Sheet1!B$2"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "D2", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "E2", "F2"))
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	xlsx.SheetData.Row[1].C = append(xlsx.SheetData.Row[1].C,
+		xlsxC{R: "G2", F: &xlsxF{T: STCellFormulaTypeShared, Ref: "G2:H2", Si: "0", Content: "A2&B2"}},
+		xlsxC{R: "H2", F: &xlsxF{T: STCellFormulaTypeShared, Si: "0"}},
+	)
+
+	assert.NoError(t, f.DuplicateRowTo("Sheet1", 2, 5))
+	for cell, expected := range map[string]string{"D2": "B2*C2+$E$1+Sheet1!B$2", "D5": "B5*C5+$E$1+Sheet1!B$2", "G5": "A5&B5", "H5": "B5&C5"} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	styleID, err := f.GetCellStyle("Sheet1", "D5")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	val, err := f.GetCellValue("Sheet1", "A5")
+	assert.NoError(t, err)
+	assert.Equal(t, "Item", val)
+
+	// Test duplicate row above the source row.
+	assert.NoError(t, f.DuplicateRowTo("Sheet1", 5, 1))
+	formula, err := f.GetCellFormula("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "B1*C1+$E$2+Sheet1!B$3", formula)
+	formula, err = f.GetCellFormula("Sheet1", "D6")
+	assert.NoError(t, err)
+	assert.Equal(t, "B6*C6+$E$2+Sheet1!B$3", formula)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	var refs []string
+	for _, mergeCell := range mergeCells {
+		refs = append(refs, mergeCell.GetStartAxis()+":"+mergeCell.GetEndAxis())
+	}
+	assert.ElementsMatch(t, []string{"E1:F1", "E3:F3", "E6:F6"}, refs)
+
+	assert.Equal(t, "B1+$A$3+Sheet1!#REF!", shiftFormula("A2+$A$3+Sheet1!A1:B$1", 1, -1))
+}
+
 func TestDuplicateMergeCells(t *testing.T) {
 	f := File{}
 	xlsx := &xlsxWorksheet{MergeCells: &xlsxMergeCells{
@@ -22,6 +22,7 @@ import (
 	"path"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -119,6 +120,14 @@ func (f *File) workSheetWriter() {
 				f.Sheet[p].SheetData.Row[k].C = trimCell(v.C)
 			}
 			output, _ := xml.Marshal(sheet)
+			if len(sheet.Unknown) > 0 {
+				var buf bytes.Buffer
+				buf.WriteString(`<worksheet xmlns="` + NameSpaceSpreadSheet + `">`)
+				sheet.writeUnknownElements(&buf, "")
+				bulkAppendFields(&buf, sheet, 1, 39)
+				buf.WriteString(`</worksheet>`)
+				output = buf.Bytes()
+			}
 			f.saveFileList(p, replaceRelationshipsBytes(replaceRelationshipsNameSpaceBytes(output)))
 			ok := f.checked[p]
 			if ok {
@@ -129,11 +138,12 @@ func (f *File) workSheetWriter() {
 	}
 }
 
-// trimCell provides a function to trim blank cells which created by fillColumns.
+// trimCell provides a function to trim blank cells which created by
+// fillColumns, the empty cells created by EnsureCells will be kept.
 func trimCell(column []xlsxC) []xlsxC {
 	rowFull := true
 	for i := range column {
-		rowFull = column[i].hasValue() && rowFull
+		rowFull = (column[i].hasValue() || column[i].ensured) && rowFull
 	}
 	if rowFull {
 		return column
@@ -141,7 +151,7 @@ func trimCell(column []xlsxC) []xlsxC {
 	col := make([]xlsxC, len(column))
 	i := 0
 	for _, c := range column {
-		if c.hasValue() {
+		if c.hasValue() || c.ensured {
 			col[i] = c
 			i++
 		}
@@ -278,6 +288,245 @@ func (f *File) GetActiveSheetIndex() int {
 	return 0
 }
 
+// WorkbookViewOptions directly maps the settings of the workbook view.
+// ActiveSheet specifies the index of the active worksheet, WindowWidth and
+// WindowHeight specifies the size of the workbook window in twips, TabRatio
+// specifies the ratio of the width of the sheet tabs to the width of the
+// horizontal scroll bar in the range from 0 to 1000, ShowHorizontalScroll,
+// ShowVerticalScroll and ShowSheetTabs specifies whether the scroll bars and
+// the sheet tabs are displayed. The zero value fields will be ignored.
+type WorkbookViewOptions struct {
+	ActiveSheet          int
+	WindowWidth          int
+	WindowHeight         int
+	TabRatio             *int
+	ShowHorizontalScroll *bool
+	ShowVerticalScroll   *bool
+	ShowSheetTabs        *bool
+}
+
+// SetWorkbookView provides a function to set the first view of the workbook,
+// such as the active worksheet, the size of the window, and whether the
+// scroll bars and the sheet tabs are displayed. For example, activate the
+// second worksheet and hide the sheet tabs:
+//
+//    showSheetTabs := false
+//    err := f.SetWorkbookView(excelize.WorkbookViewOptions{
+//        ActiveSheet:   2,
+//        ShowSheetTabs: &showSheetTabs,
+//    })
+//
+func (f *File) SetWorkbookView(opts WorkbookViewOptions) error {
+	wb := f.workbookReader()
+	if opts.ActiveSheet != 0 {
+		var ok bool
+		for _, sheet := range wb.Sheets.Sheet {
+			if sheet.SheetID == opts.ActiveSheet {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("sheet index %d is not exist", opts.ActiveSheet)
+		}
+	}
+	if opts.TabRatio != nil && (*opts.TabRatio < 0 || *opts.TabRatio > 1000) {
+		return fmt.Errorf("tab ratio %d out of range", *opts.TabRatio)
+	}
+	if opts.WindowWidth < 0 || opts.WindowHeight < 0 {
+		return errors.New("the size of the window must not be negative")
+	}
+	if opts.ActiveSheet != 0 {
+		f.SetActiveSheet(opts.ActiveSheet)
+	}
+	if wb.BookViews == nil {
+		wb.BookViews = &xlsxBookViews{}
+	}
+	if len(wb.BookViews.WorkBookView) == 0 {
+		wb.BookViews.WorkBookView = append(wb.BookViews.WorkBookView, xlsxWorkBookView{})
+	}
+	view := &wb.BookViews.WorkBookView[0]
+	if opts.WindowWidth > 0 {
+		view.WindowWidth = opts.WindowWidth
+	}
+	if opts.WindowHeight > 0 {
+		view.WindowHeight = opts.WindowHeight
+	}
+	if opts.TabRatio != nil {
+		view.TabRatio = intPtr(*opts.TabRatio)
+	}
+	if opts.ShowHorizontalScroll != nil {
+		view.ShowHorizontalScroll = boolPtr(*opts.ShowHorizontalScroll)
+	}
+	if opts.ShowVerticalScroll != nil {
+		view.ShowVerticalScroll = boolPtr(*opts.ShowVerticalScroll)
+	}
+	if opts.ShowSheetTabs != nil {
+		view.ShowSheetTabs = boolPtr(*opts.ShowSheetTabs)
+	}
+	return nil
+}
+
+// GetWorkbookView provides a function to get the settings of the first view
+// of the workbook. The default values will be returned if the attributes of
+// the workbook view are omitted.
+func (f *File) GetWorkbookView() WorkbookViewOptions {
+	opts := WorkbookViewOptions{
+		ActiveSheet: f.GetActiveSheetIndex(),
+		TabRatio:    intPtr(600),
+	}
+	var view xlsxWorkBookView
+	if wb := f.workbookReader(); wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
+		view = wb.BookViews.WorkBookView[0]
+	}
+	opts.WindowWidth, opts.WindowHeight = view.WindowWidth, view.WindowHeight
+	if view.TabRatio != nil {
+		opts.TabRatio = intPtr(*view.TabRatio)
+	}
+	opts.ShowHorizontalScroll = boolPtr(defaultTrue(view.ShowHorizontalScroll))
+	opts.ShowVerticalScroll = boolPtr(defaultTrue(view.ShowVerticalScroll))
+	opts.ShowSheetTabs = boolPtr(defaultTrue(view.ShowSheetTabs))
+	return opts
+}
+
+// defaultCalcID is the calculation engine version of Excel 2016 and later,
+// which will be used as the default calculation id of the workbook.
+const defaultCalcID = 191029
+
+// CalcPropsOptions directly maps the settings of the workbook calculation
+// properties. CalcID specifies the version of the calculation engine which
+// last calculated the workbook. When the workbook is opened by a spreadsheet
+// application with a newer calculation engine, the application will
+// recalculate all formulas and prompt to save the changes on close, so set it
+// to the version of the target application if the cached results of the
+// formulas are trustworthy. The common calculation ids are 124519 for Excel
+// 2007, 145621 for Excel 2010, 152511 for Excel 2013, 162913 for Excel 2016
+// and 191029 for Excel 2019 and later. CalcMode specifies the calculation
+// mode, the available values are auto, autoNoTable and manual.
+// FullCalcOnLoad specifies whether to recalculate all formulas when the
+// workbook is opened. Iterate, IterateCount and IterateDelta specifies the
+// iterative calculation of the circular references. The nil value fields
+// will be ignored.
+type CalcPropsOptions struct {
+	CalcID         *int
+	CalcMode       *string
+	FullCalcOnLoad *bool
+	Iterate        *bool
+	IterateCount   *int
+	IterateDelta   *float64
+}
+
+// SetCalcProps provides a function to set the calculation properties of the
+// workbook. The calculation id will be set to 191029 if it isn't specified
+// and the workbook hasn't a valid one. For example, let Excel 2016 trust the
+// cached results of the formulas:
+//
+//    calcID := 162913
+//    err := f.SetCalcProps(excelize.CalcPropsOptions{CalcID: &calcID})
+//
+func (f *File) SetCalcProps(opts CalcPropsOptions) error {
+	if opts.CalcID != nil && *opts.CalcID <= 0 {
+		return fmt.Errorf("invalid calculation id %d", *opts.CalcID)
+	}
+	if opts.CalcMode != nil && inStrSlice([]string{"auto", "autoNoTable", "manual"}, *opts.CalcMode) == -1 {
+		return fmt.Errorf("invalid calculation mode %s", *opts.CalcMode)
+	}
+	if opts.IterateCount != nil && (*opts.IterateCount < 1 || *opts.IterateCount > 32767) {
+		return fmt.Errorf("iterate count %d out of range", *opts.IterateCount)
+	}
+	if opts.IterateDelta != nil && *opts.IterateDelta < 0 {
+		return errors.New("the iterate delta must not be negative")
+	}
+	wb := f.workbookReader()
+	if wb.CalcPr == nil {
+		wb.CalcPr = &xlsxCalcPr{}
+	}
+	if opts.CalcID != nil {
+		wb.CalcPr.CalcID = strconv.Itoa(*opts.CalcID)
+	}
+	if calcID, err := strconv.Atoi(wb.CalcPr.CalcID); err != nil || calcID <= 0 {
+		wb.CalcPr.CalcID = strconv.Itoa(defaultCalcID)
+	}
+	if opts.CalcMode != nil {
+		wb.CalcPr.CalcMode = *opts.CalcMode
+	}
+	if opts.FullCalcOnLoad != nil {
+		wb.CalcPr.FullCalcOnLoad = *opts.FullCalcOnLoad
+	}
+	if opts.Iterate != nil {
+		wb.CalcPr.Iterate = *opts.Iterate
+	}
+	if opts.IterateCount != nil {
+		wb.CalcPr.IterateCount = *opts.IterateCount
+	}
+	if opts.IterateDelta != nil {
+		wb.CalcPr.IterateDelta = *opts.IterateDelta
+	}
+	return nil
+}
+
+// GetCalcProps provides a function to get the calculation properties of the
+// workbook. The default values will be returned if the attributes of the
+// calculation properties are omitted.
+func (f *File) GetCalcProps() CalcPropsOptions {
+	var calcPr xlsxCalcPr
+	if wb := f.workbookReader(); wb.CalcPr != nil {
+		calcPr = *wb.CalcPr
+	}
+	calcID, _ := strconv.Atoi(calcPr.CalcID)
+	opts := CalcPropsOptions{
+		CalcID:         intPtr(calcID),
+		CalcMode:       stringPtr("auto"),
+		FullCalcOnLoad: boolPtr(calcPr.FullCalcOnLoad),
+		Iterate:        boolPtr(calcPr.Iterate),
+		IterateCount:   intPtr(100),
+		IterateDelta:   float64Ptr(0.001),
+	}
+	if calcPr.CalcMode != "" {
+		opts.CalcMode = stringPtr(calcPr.CalcMode)
+	}
+	if calcPr.IterateCount > 0 {
+		opts.IterateCount = intPtr(calcPr.IterateCount)
+	}
+	if calcPr.IterateDelta > 0 {
+		opts.IterateDelta = float64Ptr(calcPr.IterateDelta)
+	}
+	return opts
+}
+
+// SetWorkbookDateSystem provides a function to set the date system of the
+// workbook, the 1904 date system will be used if is1904 is true, otherwise
+// the 1900 date system will be used. The time values set by SetCellValue and
+// the stream writer, the date and time number formats, and the date
+// functions of the formula calculation will use the date system of the
+// workbook. Note that the existing date serial numbers in the workbook will
+// not be converted, so the dates will be shifted by 1462 days after changing
+// the date system. For example, use the 1904 date system for the workbook:
+//
+//    err := f.SetWorkbookDateSystem(true)
+//
+func (f *File) SetWorkbookDateSystem(is1904 bool) error {
+	wb := f.workbookReader()
+	if wb.WorkbookPr == nil {
+		wb.WorkbookPr = new(xlsxWorkbookPr)
+	}
+	wb.WorkbookPr.Date1904 = is1904
+	return nil
+}
+
+// GetWorkbookDateSystem provides a function to get the date system of the
+// workbook, it returns true if the workbook uses the 1904 date system.
+func (f *File) GetWorkbookDateSystem() bool {
+	return f.isDate1904()
+}
+
+// isDate1904 provides a function to check if the workbook uses the 1904 date
+// system.
+func (f *File) isDate1904() bool {
+	wb := f.workbookReader()
+	return wb.WorkbookPr != nil && wb.WorkbookPr.Date1904
+}
+
 // SetSheetName provides a function to set the worksheet name by given old and
 // new worksheet names. Maximum 31 characters are allowed in sheet title and
 // this function only changes the name of the sheet and will not update the
@@ -423,6 +672,7 @@ func (f *File) DeleteSheet(name string) {
 			f.deleteCalcChain(sheet.SheetID, "") // Delete CalcChain
 			delete(f.sheetMap, sheetName)
 			delete(f.XLSX, sheetXML)
+			delete(f.lazyWorksheets, sheetXML)
 			delete(f.XLSX, rels)
 			delete(f.Relationships, rels)
 			delete(f.Sheet, sheetXML)
@@ -465,8 +715,11 @@ func (f *File) deleteSheetFromContentTypes(target string) {
 }
 
 // CopySheet provides a function to duplicate a worksheet by gave source and
-// target worksheet index. Note that currently doesn't support duplicate
-// workbooks that contain tables, charts or pictures. For Example:
+// target worksheet index. The cell values, styles, data validations,
+// conditional formats, hyperlinks, comments and the pictures and charts of the
+// worksheet are duplicated, and the duplicated parts are independent of the
+// source worksheet. Note that currently doesn't support duplicate tables,
+// pivot tables, slicers and form controls. For Example:
 //
 //    // Sheet1 already exists...
 //    index := f.NewSheet("Sheet2")
@@ -487,24 +740,313 @@ func (f *File) copySheet(from, to int) error {
 	if err != nil {
 		return err
 	}
+	fromName, toName := f.GetSheetName(from), f.GetSheetName(to)
 	worksheet := deepcopy.Copy(sheet).(*xlsxWorksheet)
-	path := "xl/worksheets/sheet" + strconv.Itoa(to) + ".xml"
-	if len(worksheet.SheetViews.SheetView) > 0 {
+	if worksheet.SheetViews != nil && len(worksheet.SheetViews.SheetView) > 0 {
 		worksheet.SheetViews.SheetView[0].TabSelected = false
 	}
-	worksheet.Drawing = nil
-	worksheet.TableParts = nil
-	worksheet.PageSetUp = nil
+	resetSheetPartRefs(worksheet)
+	path := f.sheetMap[trimSheetName(toName)]
 	f.Sheet[path] = worksheet
-	toRels := "xl/worksheets/_rels/sheet" + strconv.Itoa(to) + ".xml.rels"
-	fromRels := "xl/worksheets/_rels/sheet" + strconv.Itoa(from) + ".xml.rels"
-	_, ok := f.XLSX[fromRels]
-	if ok {
-		f.XLSX[toRels] = f.XLSX[fromRels]
+	toRels := "xl/worksheets/_rels/" + strings.TrimPrefix(path, "xl/worksheets/") + ".rels"
+	delete(f.Relationships, toRels)
+	delete(f.XLSX, toRels)
+	return f.copySheetParts(f, fromName, toName, sheet, worksheet)
+}
+
+// resetSheetPartRefs provides a function to remove the references to the
+// parts related by the relationships of the duplicated worksheet, these
+// parts will be recreated with the new relationship IDs. Note that the
+// drawing of the header and footer pictures in the DrawingHF element is not
+// supported, only the legacy VML drawing of them will be copied.
+func resetSheetPartRefs(ws *xlsxWorksheet) {
+	ws.Drawing, ws.LegacyDrawing, ws.LegacyDrawingHF, ws.DrawingHF = nil, nil, nil, nil
+	ws.Picture, ws.OleObjects, ws.Controls, ws.TableParts = nil, nil, nil, nil
+	if ws.PageSetUp != nil {
+		ws.PageSetUp.RID = ""
+	}
+}
+
+// copySheetParts provides a function to copy the hyperlinks, drawing and
+// comments of the worksheet sheetName of the workbook src to the worksheet
+// newName of the workbook, the relationship IDs of the parts will be remapped
+// for the duplicated worksheet ws.
+func (f *File) copySheetParts(src *File, sheetName, newName string, srcWs, ws *xlsxWorksheet) error {
+	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(newName)], "xl/worksheets/") + ".rels"
+	srcRels := "xl/worksheets/_rels/" + strings.TrimPrefix(src.sheetMap[trimSheetName(sheetName)], "xl/worksheets/") + ".rels"
+	if ws.Hyperlinks != nil {
+		for i, link := range ws.Hyperlinks.Hyperlink {
+			if link.RID == "" {
+				continue
+			}
+			ws.Hyperlinks.Hyperlink[i].RID = ""
+			if rel := src.getDrawingRelationships(srcRels, link.RID); rel != nil {
+				rID := f.addRels(sheetRels, SourceRelationshipHyperLink, rel.Target, rel.TargetMode)
+				ws.Hyperlinks.Hyperlink[i].RID = "rId" + strconv.Itoa(rID)
+			}
+		}
+	}
+	if srcWs.Drawing != nil {
+		target := src.getSheetRelationshipsTargetByID(sheetName, srcWs.Drawing.RID)
+		if target != "" {
+			f.importDrawing(src, strings.Replace(target, "..", "xl", 1), sheetName, newName, sheetRels, ws)
+		}
+	}
+	if srcWs.LegacyDrawingHF != nil {
+		target := src.getSheetRelationshipsTargetByID(sheetName, srcWs.LegacyDrawingHF.RID)
+		if target != "" {
+			f.copyHeaderFooterDrawing(src, strings.Replace(target, "..", "xl", 1), sheetRels, ws)
+		}
+	}
+	comments, err := src.GetComments(sheetName)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		opts := &CommentOption{Author: comment.Author, Text: comment.Text, Runs: comment.Runs}
+		// The run of the author name will be created by adding the comment.
+		if len(opts.Runs) > 0 && opts.Runs[0].Text == comment.Author {
+			opts.Runs = opts.Runs[1:]
+		}
+		if err = f.AddComment(newName, comment.Ref, opts); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// copyHeaderFooterDrawing provides a function to copy the VML drawing of the
+// header and footer pictures by given path of the drawing in the workbook src
+// to the duplicated worksheet ws, the pictures will be added into the
+// workbook if they are imported from another workbook.
+func (f *File) copyHeaderFooterDrawing(src *File, drawingVML, sheetRels string, ws *xlsxWorksheet) {
+	content := src.XLSX[drawingVML]
+	if vml := src.VMLDrawing[drawingVML]; vml != nil {
+		content, _ = xml.Marshal(vml)
+	}
+	if content == nil {
+		return
+	}
+	vmlID := f.countComments() + 1
+	if id := f.countVMLDrawings() + 1; id > vmlID {
+		vmlID = id
+	}
+	newDrawingVML := "xl/drawings/vmlDrawing" + strconv.Itoa(vmlID) + ".vml"
+	f.XLSX[newDrawingVML] = content
+	f.vmlDrawingReader(newDrawingVML, vmlID)
+	srcDrawingRels := strings.Replace(drawingVML, "xl/drawings/", "xl/drawings/_rels/", 1) + ".rels"
+	if rels := src.relsReader(srcDrawingRels); rels != nil {
+		drawingRels := &xlsxRelationships{}
+		for _, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipImage && src != f {
+				media := strings.Replace(rel.Target, "..", "xl", 1)
+				rel.Target = ".." + strings.TrimPrefix(f.addMedia(src.XLSX[media], path.Ext(media)), "xl")
+			}
+			drawingRels.Relationships = append(drawingRels.Relationships, rel)
+		}
+		f.Relationships["xl/drawings/_rels/vmlDrawing"+strconv.Itoa(vmlID)+".vml.rels"] = drawingRels
+	}
+	rID := f.addRels(sheetRels, SourceRelationshipDrawingVML, "../drawings/vmlDrawing"+strconv.Itoa(vmlID)+".vml", "")
+	ws.LegacyDrawingHF = &xlsxLegacyDrawingHF{RID: "rId" + strconv.Itoa(rID)}
+	f.setContentTypePartVMLExtensions()
+	f.setContentTypePartImageExtensions()
+}
+
+// ImportSheet provides a function to copy the worksheet sheetName of the
+// workbook src into the workbook as a new worksheet named newName. The cell
+// values, styles, shared strings, comments, hyperlinks and the pictures and
+// charts of the worksheet are imported, and the style IDs are remapped to the
+// styles of the workbook. The source worksheet name will be used if newName is
+// empty, and an error will be returned if the workbook already contains a
+// worksheet with the same name. Note that currently doesn't support import
+// tables, pivot tables, slicers and form controls. For example, import Sheet1
+// of Book1.xlsx as Sheet2:
+//
+//    src, err := excelize.OpenFile("Book1.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    err = f.ImportSheet(src, "Sheet1", "Sheet2")
+//
+func (f *File) ImportSheet(src *File, sheetName, newName string) error {
+	if src == nil {
+		return errors.New("the source workbook can not be nil")
+	}
+	srcWs, err := src.workSheetReader(sheetName)
+	if err != nil {
+		return err
+	}
+	if newName == "" {
+		newName = sheetName
+	}
+	newName = trimSheetName(newName)
+	if f.GetSheetIndex(newName) != 0 {
+		return fmt.Errorf("sheet %s already exists", newName)
+	}
+	ws := deepcopy.Copy(srcWs).(*xlsxWorksheet)
+	if ws.SheetViews != nil && len(ws.SheetViews.SheetView) > 0 {
+		ws.SheetViews.SheetView[0].TabSelected = false
+	}
+	if err = f.importSheetStyles(src, ws); err != nil {
+		return err
+	}
+	importSharedStrings(src.sharedStringsReader(), ws)
+	resetSheetPartRefs(ws)
+	f.NewSheet(newName)
+	f.Sheet[f.sheetMap[newName]] = ws
+	return f.copySheetParts(src, sheetName, newName, srcWs, ws)
+}
+
+// importSheetStyles provides a function to remap the cell, row and column
+// style IDs and the conditional formatting differential style IDs of the
+// worksheet imported from the workbook src to the styles of the workbook.
+func (f *File) importSheetStyles(src *File, ws *xlsxWorksheet) error {
+	styles := map[int]int{0: 0}
+	styleID := func(ID int) (int, error) {
+		if newID, ok := styles[ID]; ok {
+			return newID, nil
+		}
+		definition, err := src.GetStyleDefinition(ID)
+		if err != nil {
+			return 0, err
+		}
+		newID, err := f.NewStyle(definition)
+		if err != nil {
+			return 0, err
+		}
+		styles[ID] = newID
+		return newID, err
+	}
+	var err error
+	if ws.Cols != nil {
+		for i := range ws.Cols.Col {
+			if ws.Cols.Col[i].Style, err = styleID(ws.Cols.Col[i].Style); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[i]
+		if row.S, err = styleID(row.S); err != nil {
+			return err
+		}
+		for j := range row.C {
+			if row.C[j].S, err = styleID(row.C[j].S); err != nil {
+				return err
+			}
+		}
+	}
+	srcStyles, s := src.stylesReader(), f.stylesReader()
+	dxfs := make(map[int]int)
+	for _, cf := range ws.ConditionalFormatting {
+		for _, rule := range cf.CfRule {
+			if rule.DxfID == nil {
+				continue
+			}
+			if srcStyles.Dxfs == nil || *rule.DxfID < 0 || *rule.DxfID >= len(srcStyles.Dxfs.Dxfs) {
+				rule.DxfID = nil
+				continue
+			}
+			newID, ok := dxfs[*rule.DxfID]
+			if !ok {
+				if s.Dxfs == nil {
+					s.Dxfs = &xlsxDxfs{}
+				}
+				s.Dxfs.Dxfs = append(s.Dxfs.Dxfs, &xlsxDxf{Dxf: srcStyles.Dxfs.Dxfs[*rule.DxfID].Dxf})
+				s.Dxfs.Count = len(s.Dxfs.Dxfs)
+				newID = s.Dxfs.Count - 1
+				dxfs[*rule.DxfID] = newID
+			}
+			rule.DxfID = intPtr(newID)
+		}
 	}
 	return err
 }
 
+// importSharedStrings provides a function to convert the shared string cells
+// of the worksheet imported from another workbook to the inline string cells
+// by given shared string table of the source workbook, the rich text runs of
+// the shared strings will be kept.
+func importSharedStrings(sst *xlsxSST, ws *xlsxWorksheet) {
+	for i := range ws.SheetData.Row {
+		for j := range ws.SheetData.Row[i].C {
+			c := &ws.SheetData.Row[i].C[j]
+			if c.T != "s" {
+				continue
+			}
+			idx, err := strconv.Atoi(c.V)
+			if err != nil || idx < 0 || idx >= len(sst.SI) {
+				continue
+			}
+			si := deepcopy.Copy(sst.SI[idx]).(xlsxSI)
+			c.T, c.V, c.IS = "inlineStr", "", &si
+		}
+	}
+}
+
+// importDrawing provides a function to copy the drawing part drawingXML of
+// the workbook src, including its pictures and charts, into the workbook and
+// reference it from the worksheet imported as newName. The references to the
+// source worksheet in the chart formulas are renamed to the new worksheet.
+func (f *File) importDrawing(src *File, drawingXML, sheetName, newName, sheetRels string, ws *xlsxWorksheet) {
+	content := src.readXML(drawingXML)
+	if wsDr, ok := src.Drawings[drawingXML]; ok && wsDr != nil {
+		content, _ = xml.Marshal(wsDr)
+	}
+	if len(content) == 0 {
+		return
+	}
+	drawingID := f.countDrawings() + 1
+	newDrawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	srcDrawingRels := strings.Replace(drawingXML, "xl/drawings/", "xl/drawings/_rels/", 1) + ".rels"
+	if rels := src.relsReader(srcDrawingRels); rels != nil {
+		drawingRels := &xlsxRelationships{}
+		for _, rel := range rels.Relationships {
+			target := strings.Replace(rel.Target, "..", "xl", 1)
+			switch rel.Type {
+			case SourceRelationshipImage:
+				if file := src.readXML(target); len(file) > 0 {
+					rel.Target = ".." + strings.TrimPrefix(f.addMedia(file, path.Ext(target)), "xl")
+				}
+			case SourceRelationshipChart:
+				if chart := src.readXML(target); len(chart) > 0 {
+					chartID := f.countCharts() + 1
+					f.saveFileList("xl/charts/chart"+strconv.Itoa(chartID)+".xml", renameChartSheetRefs(chart, sheetName, newName))
+					f.addContentTypePart(chartID, "chart")
+					rel.Target = "../charts/chart" + strconv.Itoa(chartID) + ".xml"
+				}
+			}
+			drawingRels.Relationships = append(drawingRels.Relationships, rel)
+		}
+		f.Relationships["xl/drawings/_rels/drawing"+strconv.Itoa(drawingID)+".xml.rels"] = drawingRels
+	}
+	f.saveFileList(newDrawingXML, content)
+	f.addContentTypePart(drawingID, "drawings")
+	rID := f.addRels(sheetRels, SourceRelationshipDrawingML, "../drawings/drawing"+strconv.Itoa(drawingID)+".xml", "")
+	ws.Drawing = &xlsxDrawing{RID: "rId" + strconv.Itoa(rID)}
+}
+
+// chartFormulaRe defined the regular expression to match the formulas in the
+// chart part.
+var chartFormulaRe = regexp.MustCompile(`<(\w+:)?f>[^<]*</(\w+:)?f>`)
+
+// renameChartSheetRefs provides a function to rename the references to the
+// worksheet sheetName in the formulas of the chart part to newName.
+func renameChartSheetRefs(chart []byte, sheetName, newName string) []byte {
+	quote := func(name string) string {
+		return "'" + strings.Replace(name, "'", "''", -1) + "'!"
+	}
+	oldRef := regexp.MustCompile(`(^|[>(,\s])(` + regexp.QuoteMeta(sheetName) + `!|` + regexp.QuoteMeta(quote(sheetName)) + `)`)
+	newRef := newName + "!"
+	if !regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`).MatchString(newName) {
+		newRef = quote(newName)
+	}
+	return chartFormulaRe.ReplaceAllFunc(chart, func(formula []byte) []byte {
+		return oldRef.ReplaceAll(formula, []byte("${1}"+strings.Replace(newRef, "$", "$$", -1)))
+	})
+}
+
 // SetSheetVisible provides a function to set worksheet visible by given worksheet
 // name. A workbook must contain at least one visible worksheet. If the given
 // worksheet has been activated, this setting will be invalidated. Sheet state
@@ -619,7 +1161,14 @@ func parseFormatPanesSet(formatSet string) (*formatPanes, error) {
 // attribute are defined by the W3C XML Schema double datatype.
 //
 // top_left_cell: Location of the top left visible cell in the bottom right pane
-// (when in Left-To-Right mode).
+// (when in Left-To-Right mode). If it's empty, the cell will be calculated by
+// the split positions, the row header width and the column header height are
+// included in the position of the split panes.
+//
+// If the active_pane is empty, the bottom right pane will be active when both
+// vertical and horizontal splits are applied, otherwise the bottom left or top
+// right pane will be active. The panes will be removed if both the x_split and
+// y_split are zero, and the negative split position is invalid.
 //
 // sqref (Sequence of References): Range of the selection. Can be non-contiguous
 // set of ranges.
@@ -649,21 +1198,11 @@ func (f *File) SetPanes(sheet, panes string) error {
 	if err != nil {
 		return err
 	}
-	p := &xlsxPane{
-		ActivePane:  fs.ActivePane,
-		TopLeftCell: fs.TopLeftCell,
-		XSplit:      float64(fs.XSplit),
-		YSplit:      float64(fs.YSplit),
-	}
-	if fs.Freeze {
-		p.State = "frozen"
+	p, err := f.newSheetViewPane(sheet, fs)
+	if err != nil {
+		return err
 	}
 	xlsx.SheetViews.SheetView[len(xlsx.SheetViews.SheetView)-1].Pane = p
-	if !(fs.Freeze) && !(fs.Split) {
-		if len(xlsx.SheetViews.SheetView) > 0 {
-			xlsx.SheetViews.SheetView[len(xlsx.SheetViews.SheetView)-1].Pane = nil
-		}
-	}
 	s := []*xlsxSelection{}
 	for _, p := range fs.Panes {
 		s = append(s, &xlsxSelection{
@@ -676,6 +1215,79 @@ func (f *File) SetPanes(sheet, panes string) error {
 	return err
 }
 
+// newSheetViewPane provides a function to create the pane of the worksheet
+// view by given worksheet name and panes settings. It returns nil if neither
+// freeze nor split panes are applied.
+func (f *File) newSheetViewPane(sheet string, fs *formatPanes) (*xlsxPane, error) {
+	if fs.XSplit < 0 || fs.YSplit < 0 || (fs.Freeze && (fs.XSplit >= TotalColumns || fs.YSplit >= TotalRows)) {
+		return nil, fmt.Errorf("invalid split position %d, %d of the panes", fs.XSplit, fs.YSplit)
+	}
+	if (!fs.Freeze && !fs.Split) || (fs.XSplit == 0 && fs.YSplit == 0) {
+		return nil, nil
+	}
+	p := &xlsxPane{
+		ActivePane:  fs.ActivePane,
+		TopLeftCell: fs.TopLeftCell,
+		XSplit:      float64(fs.XSplit),
+		YSplit:      float64(fs.YSplit),
+	}
+	if p.ActivePane == "" {
+		p.ActivePane = getActivePane(fs.XSplit > 0, fs.YSplit > 0)
+	}
+	if fs.Freeze {
+		p.State = "frozen"
+		if p.TopLeftCell == "" {
+			p.TopLeftCell, _ = CoordinatesToCellName(fs.XSplit+1, fs.YSplit+1)
+		}
+		return p, nil
+	}
+	p.State = "split"
+	if p.TopLeftCell == "" {
+		p.TopLeftCell = f.getSplitPanesTopLeftCell(sheet, fs.XSplit, fs.YSplit)
+	}
+	return p, nil
+}
+
+// getActivePane provides a function to get the default active pane by given
+// whether the vertical and horizontal splits are applied.
+func getActivePane(xSplit, ySplit bool) string {
+	switch {
+	case xSplit && ySplit:
+		return "bottomRight"
+	case ySplit:
+		return "bottomLeft"
+	case xSplit:
+		return "topRight"
+	}
+	return ""
+}
+
+// getSplitPanesTopLeftCell provides a function to calculate the top left
+// visible cell in the bottom right pane of the split panes by given worksheet
+// name and split positions in twips. The split positions include the width
+// of the row header (390 twips) and the height of the column header (300
+// twips), and the column or row which is crossed by the split bar over its
+// half will be the first visible one in the pane.
+func (f *File) getSplitPanesTopLeftCell(sheet string, xSplit, ySplit int) string {
+	col, row := 1, 1
+	for pos, x := xSplit-390, 0; col < TotalColumns; col++ {
+		width := f.getColWidth(sheet, col) * 15
+		if x+width/2 > pos {
+			break
+		}
+		x += width
+	}
+	for pos, y := ySplit-300, 0; row < TotalRows; row++ {
+		height := f.getRowHeight(sheet, row-1) * 15
+		if y+height/2 > pos {
+			break
+		}
+		y += height
+	}
+	cell, _ := CoordinatesToCellName(col, row)
+	return cell
+}
+
 // GetSheetVisible provides a function to get worksheet visible by given worksheet
 // name. For example, get visible state of Sheet1:
 //
@@ -694,6 +1306,33 @@ func (f *File) GetSheetVisible(name string) bool {
 	return visible
 }
 
+// GetSheetList provides a function to get the name, index and visibility
+// state of all worksheets and chartsheets in the order of the workbook. The
+// state will be visible, hidden or veryHidden. For example, get the names of
+// the visible sheets:
+//
+//    for _, sheet := range f.GetSheetList() {
+//        if sheet.State == "visible" {
+//            fmt.Println(sheet.Index, sheet.Name)
+//        }
+//    }
+//
+func (f *File) GetSheetList() []SheetInfo {
+	var sheets []SheetInfo
+	wb := f.workbookReader()
+	if wb == nil {
+		return sheets
+	}
+	for _, sheet := range wb.Sheets.Sheet {
+		state := sheet.State
+		if state == "" {
+			state = "visible"
+		}
+		sheets = append(sheets, SheetInfo{Name: sheet.Name, Index: sheet.SheetID, State: state})
+	}
+	return sheets
+}
+
 // SearchSheet provides a function to get coordinates by given worksheet name,
 // cell value, and regular expression. The function doesn't support searching
 // on the calculated result, formatted numbers and conditional lookup
@@ -1006,6 +1645,110 @@ func (f *File) UnprotectSheet(sheet string) error {
 	return err
 }
 
+// GetSheetDimension provides a function to get the used range of the
+// worksheet by given worksheet name, such as "A1:D10". The dimension stored in
+// the worksheet will be returned if it exists, otherwise the used range will
+// be calculated by scanning the cells which have the value, formula or
+// formatting. The "A1" will be returned for an empty worksheet. Use the
+// UpdateSheetDimension to recalculate the stored dimension before getting it
+// if the worksheet has been changed.
+func (f *File) GetSheetDimension(sheet string) (string, error) {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return "", err
+	}
+	if xlsx.Dimension != nil && xlsx.Dimension.Ref != "" {
+		return xlsx.Dimension.Ref, err
+	}
+	return f.calcSheetDimension(xlsx)
+}
+
+// UpdateSheetDimension provides a function to recalculate the used range of
+// the worksheet by scanning the cells which have the value, formula or
+// formatting, and update the dimension stored in the worksheet by given
+// worksheet name.
+func (f *File) UpdateSheetDimension(sheet string) error {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	ref, err := f.calcSheetDimension(xlsx)
+	if err != nil {
+		return err
+	}
+	xlsx.Dimension = &xlsxDimension{Ref: ref}
+	return err
+}
+
+// calcSheetDimension provides a function to calculate the used range of the
+// worksheet by given worksheet.
+func (f *File) calcSheetDimension(xlsx *xlsxWorksheet) (string, error) {
+	coordinates := []int{0, 0, 0, 0}
+	for _, row := range xlsx.SheetData.Row {
+		for _, c := range row.C {
+			if !c.hasValue() {
+				continue
+			}
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return "", err
+			}
+			if coordinates[0] == 0 || col < coordinates[0] {
+				coordinates[0] = col
+			}
+			if coordinates[1] == 0 || r < coordinates[1] {
+				coordinates[1] = r
+			}
+			if col > coordinates[2] {
+				coordinates[2] = col
+			}
+			if r > coordinates[3] {
+				coordinates[3] = r
+			}
+		}
+	}
+	if coordinates[0] == 0 {
+		return "A1", nil
+	}
+	if coordinates[0] == coordinates[2] && coordinates[1] == coordinates[3] {
+		return CoordinatesToCellName(coordinates[0], coordinates[1])
+	}
+	return f.coordinatesToAreaRef(coordinates)
+}
+
+// ProtectWorkbook provides a function to prevent other users from changing
+// the structure or the windows of the workbook, such as adding, deleting,
+// hiding and moving the worksheets. The password will be hashed with the
+// legacy password hash algorithm of Excel. For example, protect the
+// structure of the workbook with a password:
+//
+//    err := f.ProtectWorkbook(excelize.WorkbookProtectionOptions{
+//        Password:      "password",
+//        LockStructure: true,
+//    })
+//
+func (f *File) ProtectWorkbook(opts WorkbookProtectionOptions) error {
+	if !opts.LockStructure && !opts.LockWindows {
+		return errors.New("at least one of the structure and the windows of the workbook should be locked")
+	}
+	wb := f.workbookReader()
+	wb.WorkbookProtection = &xlsxWorkbookProtection{
+		LockStructure: opts.LockStructure,
+		LockWindows:   opts.LockWindows,
+	}
+	if opts.Password != "" {
+		wb.WorkbookProtection.WorkbookPassword = genSheetPasswd(opts.Password)
+	}
+	return nil
+}
+
+// UnprotectWorkbook provides a function to remove the protection of the
+// workbook.
+func (f *File) UnprotectWorkbook() error {
+	f.workbookReader().WorkbookProtection = nil
+	return nil
+}
+
 // trimSheetName provides a function to trim invaild characters by given worksheet
 // name.
 func trimSheetName(name string) string {
@@ -1049,6 +1792,8 @@ type (
 	FitToHeight int
 	// FitToWidth specified number of horizontal pages to fit on
 	FitToWidth int
+	// PageLayoutScale defines the print scaling percentage of the worksheet
+	PageLayoutScale int
 )
 
 const (
@@ -1120,11 +1865,41 @@ func (p *FitToWidth) getPageLayout(ps *xlsxPageSetUp) {
 	*p = FitToWidth(ps.FitToWidth)
 }
 
+// setPageLayout provides a method to set the print scaling for the
+// worksheet.
+func (p PageLayoutScale) setPageLayout(ps *xlsxPageSetUp) {
+	ps.Scale = int(p)
+}
+
+// getPageLayout provides a method to get the print scaling for the
+// worksheet.
+func (p *PageLayoutScale) getPageLayout(ps *xlsxPageSetUp) {
+	// Excel default: 100
+	if ps == nil || ps.Scale == 0 {
+		*p = 100
+		return
+	}
+	*p = PageLayoutScale(ps.Scale)
+}
+
 // SetPageLayout provides a function to sets worksheet page layout.
 //
 // Available options:
 //   PageLayoutOrientation(string)
 //   PageLayoutPaperSize(int)
+//   FitToHeight(int)
+//   FitToWidth(int)
+//   PageLayoutScale(int)
+//
+// The FitToHeight and FitToWidth options scale the worksheet to fit the given
+// number of pages tall and wide when printing, and enable the fit to page
+// print option of the worksheet. The PageLayoutScale option specifies the
+// print scaling percentage between 10 and 400, and disables the fit to page
+// print option. The fit to page options and the scaling option can't be set
+// at the same time. For example, fit the worksheet to 1 page wide by 2 pages
+// tall:
+//
+//    err := f.SetPageLayout("Sheet1", excelize.FitToWidth(1), excelize.FitToHeight(2))
 //
 // The following shows the paper size sorted by excelize index number:
 //
@@ -1252,6 +2027,21 @@ func (f *File) SetPageLayout(sheet string, opts ...PageLayoutOption) error {
 	if err != nil {
 		return err
 	}
+	var fitToPage, scale bool
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case FitToHeight, FitToWidth:
+			fitToPage = true
+		case PageLayoutScale:
+			if opt < 10 || opt > 400 {
+				return fmt.Errorf("invalid page layout scale %d", opt)
+			}
+			scale = true
+		}
+	}
+	if fitToPage && scale {
+		return errors.New("the fit to page and scale options of the page layout can not be set at the same time")
+	}
 	ps := s.PageSetUp
 	if ps == nil {
 		ps = new(xlsxPageSetUp)
@@ -1261,6 +2051,12 @@ func (f *File) SetPageLayout(sheet string, opts ...PageLayoutOption) error {
 	for _, opt := range opts {
 		opt.setPageLayout(ps)
 	}
+	if fitToPage && s.SheetPr == nil {
+		s.SheetPr = new(xlsxSheetPr)
+	}
+	if (fitToPage || scale) && s.SheetPr != nil {
+		FitToPage(fitToPage).setSheetPrOption(s.SheetPr)
+	}
 	return err
 }
 
@@ -1271,6 +2067,7 @@ func (f *File) SetPageLayout(sheet string, opts ...PageLayoutOption) error {
 //   PageLayoutPaperSize(int)
 //   FitToHeight(int)
 //   FitToWidth(int)
+//   PageLayoutScale(int)
 func (f *File) GetPageLayout(sheet string, opts ...PageLayoutOptionPtr) error {
 	s, err := f.workSheetReader(sheet)
 	if err != nil {
@@ -1295,11 +2092,14 @@ func (f *File) GetPageLayout(sheet string, opts ...PageLayoutOptionPtr) error {
 //        Scope:    "Sheet2",
 //    })
 //
+// Set the Hidden to true to hide the defined name in the user interface.
+// The other attributes of the existing defined names will be kept.
 func (f *File) SetDefinedName(definedName *DefinedName) error {
 	wb := f.workbookReader()
 	d := xlsxDefinedName{
 		Name:    definedName.Name,
 		Comment: definedName.Comment,
+		Hidden:  definedName.Hidden,
 		Data:    definedName.RefersTo,
 	}
 	if definedName.Scope != "" {
@@ -1363,6 +2163,7 @@ func (f *File) GetDefinedName() []DefinedName {
 			definedName := DefinedName{
 				Name:     dn.Name,
 				Comment:  dn.Comment,
+				Hidden:   dn.Hidden,
 				RefersTo: dn.Data,
 				Scope:    "Workbook",
 			}
@@ -1413,6 +2214,76 @@ func (f *File) GroupSheets(sheets []string) error {
 	return nil
 }
 
+// SetSelectedSheets provides a function to select multiple worksheets by
+// given worksheet indexes, the tabs of the given worksheets will be selected
+// and the tabs of the others will be deselected. The index is the same as
+// the function SetActiveSheet, and the active worksheet will be set to the
+// first given worksheet if it isn't selected. Note that at least one visible
+// worksheet should be selected. For example, select Sheet1 and Sheet3:
+//
+//    err := f.SetSelectedSheets([]int{1, 3})
+//
+func (f *File) SetSelectedSheets(indices []int) error {
+	if len(indices) == 0 {
+		return errors.New("at least one worksheet should be selected")
+	}
+	selected, states := map[int]bool{}, map[int]string{}
+	for _, sheet := range f.workbookReader().Sheets.Sheet {
+		states[sheet.SheetID] = sheet.State
+	}
+	sheetMap := f.GetSheetMap()
+	for _, idx := range indices {
+		name, ok := sheetMap[idx]
+		if !ok {
+			return fmt.Errorf("invalid worksheet index %d", idx)
+		}
+		if states[idx] != "" && states[idx] != "visible" {
+			return fmt.Errorf("hidden sheet %s can not be selected", name)
+		}
+		if _, err := f.workSheetReader(name); err != nil {
+			return err
+		}
+		selected[idx] = true
+	}
+	if !selected[f.GetActiveSheetIndex()] {
+		f.SetActiveSheet(indices[0])
+	}
+	for idx, name := range sheetMap {
+		ws, err := f.workSheetReader(name)
+		if err != nil {
+			// Chartsheet
+			continue
+		}
+		if ws.SheetViews == nil {
+			ws.SheetViews = &xlsxSheetViews{}
+		}
+		if len(ws.SheetViews.SheetView) == 0 {
+			ws.SheetViews.SheetView = append(ws.SheetViews.SheetView, xlsxSheetView{WorkbookViewID: 0})
+		}
+		for i := range ws.SheetViews.SheetView {
+			ws.SheetViews.SheetView[i].TabSelected = selected[idx]
+		}
+	}
+	return nil
+}
+
+// GetSelectedSheets provides a function to get the indexes of the selected
+// worksheets in the order of the worksheets in the workbook. The index is the
+// same as the function SetActiveSheet.
+func (f *File) GetSelectedSheets() []int {
+	var indices []int
+	for _, sheet := range f.workbookReader().Sheets.Sheet {
+		ws, err := f.workSheetReader(sheet.Name)
+		if err != nil || ws.SheetViews == nil || len(ws.SheetViews.SheetView) == 0 {
+			continue
+		}
+		if ws.SheetViews.SheetView[0].TabSelected {
+			indices = append(indices, sheet.SheetID)
+		}
+	}
+	return indices
+}
+
 // UngroupSheets provides a function to ungroup worksheets.
 func (f *File) UngroupSheets() error {
 	activeSheet := f.GetActiveSheetIndex()
@@ -1435,110 +2306,106 @@ func (f *File) UngroupSheets() error {
 // InsertPageBreak create a page break to determine where the printed page
 // ends and where begins the next one by given worksheet name and axis, so the
 // content before the page break will be printed on one page and after the
-// page break on another.
-func (f *File) InsertPageBreak(sheet, cell string) (err error) {
-	var ws *xlsxWorksheet
-	var row, col int
-	var rowBrk, colBrk = -1, -1
-	if ws, err = f.workSheetReader(sheet); err != nil {
-		return
-	}
-	if col, row, err = CellNameToCoordinates(cell); err != nil {
-		return
-	}
-	col--
-	row--
-	if col == row && col == 0 {
-		return
-	}
-	if ws.RowBreaks == nil {
-		ws.RowBreaks = &xlsxBreaks{}
+// page break on another. The row break will be inserted above the row of the
+// cell and the column break will be inserted on the left of the column of the
+// cell, the duplicate page breaks will be ignored. Note that the row breaks
+// and the column breaks are limited to 1026 respectively. For example, insert
+// a row break above the row 20 and a column break on the left of the column
+// C:
+//
+//    err := f.InsertPageBreak("Sheet1", "C20")
+//
+func (f *File) InsertPageBreak(sheet, cell string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
 	}
-	if ws.ColBreaks == nil {
-		ws.ColBreaks = &xlsxBreaks{}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
 	}
-
-	for idx, brk := range ws.RowBreaks.Brk {
-		if brk.ID == row {
-			rowBrk = idx
+	if row > 1 {
+		if ws.RowBreaks, err = insertPageBreak(ws.RowBreaks, row-1, TotalColumns-1); err != nil {
+			return err
 		}
 	}
-	for idx, brk := range ws.ColBreaks.Brk {
-		if brk.ID == col {
-			colBrk = idx
-		}
+	if col > 1 {
+		ws.ColBreaks, err = insertPageBreak(ws.ColBreaks, col-1, TotalRows-1)
 	}
+	return err
+}
 
-	if row != 0 && rowBrk == -1 {
-		ws.RowBreaks.Brk = append(ws.RowBreaks.Brk, &xlsxBrk{
-			ID:  row,
-			Max: 16383,
-			Man: true,
-		})
-		ws.RowBreaks.ManualBreakCount++
+// RemovePageBreak remove a page break by given worksheet name and axis, the
+// row break above the row of the cell and the column break on the left of
+// the column of the cell will be removed if exists. For example:
+//
+//    err := f.RemovePageBreak("Sheet1", "C20")
+//
+func (f *File) RemovePageBreak(sheet, cell string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
 	}
-	if col != 0 && colBrk == -1 {
-		ws.ColBreaks.Brk = append(ws.ColBreaks.Brk, &xlsxBrk{
-			ID:  col,
-			Max: 1048575,
-			Man: true,
-		})
-		ws.ColBreaks.ManualBreakCount++
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
 	}
-	ws.RowBreaks.Count = len(ws.RowBreaks.Brk)
-	ws.ColBreaks.Count = len(ws.ColBreaks.Brk)
-	return
+	ws.RowBreaks = removePageBreak(ws.RowBreaks, row-1)
+	ws.ColBreaks = removePageBreak(ws.ColBreaks, col-1)
+	return err
 }
 
-// RemovePageBreak remove a page break by given worksheet name and axis.
-func (f *File) RemovePageBreak(sheet, cell string) (err error) {
-	var ws *xlsxWorksheet
-	var row, col int
-	if ws, err = f.workSheetReader(sheet); err != nil {
-		return
+// insertPageBreak provides a function to insert a manual page break into the
+// page breaks in ascending order by given page breaks, break ID and the max
+// index of the break. The page breaks will be created if it doesn't exist.
+func insertPageBreak(brks *xlsxBreaks, ID, max int) (*xlsxBreaks, error) {
+	if brks == nil {
+		brks = &xlsxBreaks{}
 	}
-	if col, row, err = CellNameToCoordinates(cell); err != nil {
-		return
+	for _, brk := range brks.Brk {
+		if brk.ID == ID {
+			return brks, nil
+		}
 	}
-	col--
-	row--
-	if col == row && col == 0 {
-		return
+	idx := sort.Search(len(brks.Brk), func(i int) bool { return brks.Brk[i].ID > ID })
+	if len(brks.Brk) >= maxPageBreaks {
+		return brks, fmt.Errorf("the number of page breaks exceeds the limit %d", maxPageBreaks)
 	}
-	removeBrk := func(ID int, brks []*xlsxBrk) []*xlsxBrk {
-		for i, brk := range brks {
-			if brk.ID == ID {
-				brks = append(brks[:i], brks[i+1:]...)
-			}
-		}
+	brks.Brk = append(brks.Brk, nil)
+	copy(brks.Brk[idx+1:], brks.Brk[idx:])
+	brks.Brk[idx] = &xlsxBrk{ID: ID, Max: max, Man: true}
+	brks.updateCount()
+	return brks, nil
+}
+
+// removePageBreak provides a function to remove the page break by given page
+// breaks and break ID. The page breaks will be removed if it's empty.
+func removePageBreak(brks *xlsxBreaks, ID int) *xlsxBreaks {
+	if brks == nil || ID == 0 {
 		return brks
 	}
-	if ws.RowBreaks == nil || ws.ColBreaks == nil {
-		return
-	}
-	rowBrks := len(ws.RowBreaks.Brk)
-	colBrks := len(ws.ColBreaks.Brk)
-	if rowBrks > 0 && rowBrks == colBrks {
-		ws.RowBreaks.Brk = removeBrk(row, ws.RowBreaks.Brk)
-		ws.ColBreaks.Brk = removeBrk(col, ws.ColBreaks.Brk)
-		ws.RowBreaks.Count = len(ws.RowBreaks.Brk)
-		ws.ColBreaks.Count = len(ws.ColBreaks.Brk)
-		ws.RowBreaks.ManualBreakCount--
-		ws.ColBreaks.ManualBreakCount--
-		return
+	for i := 0; i < len(brks.Brk); i++ {
+		if brks.Brk[i].ID == ID {
+			brks.Brk = append(brks.Brk[:i], brks.Brk[i+1:]...)
+			i--
+		}
 	}
-	if rowBrks > 0 && rowBrks > colBrks {
-		ws.RowBreaks.Brk = removeBrk(row, ws.RowBreaks.Brk)
-		ws.RowBreaks.Count = len(ws.RowBreaks.Brk)
-		ws.RowBreaks.ManualBreakCount--
-		return
+	if len(brks.Brk) == 0 {
+		return nil
 	}
-	if colBrks > 0 && colBrks > rowBrks {
-		ws.ColBreaks.Brk = removeBrk(col, ws.ColBreaks.Brk)
-		ws.ColBreaks.Count = len(ws.ColBreaks.Brk)
-		ws.ColBreaks.ManualBreakCount--
+	brks.updateCount()
+	return brks
+}
+
+// updateCount provides a function to update the count of the page breaks and
+// the count of the manual page breaks.
+func (brks *xlsxBreaks) updateCount() {
+	brks.Count, brks.ManualBreakCount = len(brks.Brk), 0
+	for _, brk := range brks.Brk {
+		if brk.Man {
+			brks.ManualBreakCount++
+		}
 	}
-	return
 }
 
 // relsReader provides a function to get the pointer to the structure
@@ -9,6 +9,12 @@
 
 package excelize
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // SheetPrOption is an option of a view of a worksheet. See SetSheetPrOptions().
 type SheetPrOption interface {
 	setSheetPrOption(view *xlsxSheetPr)
@@ -33,6 +39,8 @@ type (
 	AutoPageBreaks bool
 	// OutlineSummaryBelow is an outlinePr, within SheetPr option
 	OutlineSummaryBelow bool
+	// OutlineSummaryRight is an outlinePr, within SheetPr option
+	OutlineSummaryRight bool
 )
 
 // setSheetPrOption implements the SheetPrOption interface.
@@ -40,7 +48,7 @@ func (o OutlineSummaryBelow) setSheetPrOption(pr *xlsxSheetPr) {
 	if pr.OutlinePr == nil {
 		pr.OutlinePr = new(xlsxOutlinePr)
 	}
-	pr.OutlinePr.SummaryBelow = bool(o)
+	pr.OutlinePr.SummaryBelow = boolPtr(bool(o))
 }
 
 // getSheetPrOption implements the SheetPrOptionPtr interface.
@@ -50,7 +58,25 @@ func (o *OutlineSummaryBelow) getSheetPrOption(pr *xlsxSheetPr) {
 		*o = true
 		return
 	}
-	*o = OutlineSummaryBelow(defaultTrue(&pr.OutlinePr.SummaryBelow))
+	*o = OutlineSummaryBelow(defaultTrue(pr.OutlinePr.SummaryBelow))
+}
+
+// setSheetPrOption implements the SheetPrOption interface.
+func (o OutlineSummaryRight) setSheetPrOption(pr *xlsxSheetPr) {
+	if pr.OutlinePr == nil {
+		pr.OutlinePr = new(xlsxOutlinePr)
+	}
+	pr.OutlinePr.SummaryRight = boolPtr(bool(o))
+}
+
+// getSheetPrOption implements the SheetPrOptionPtr interface.
+func (o *OutlineSummaryRight) getSheetPrOption(pr *xlsxSheetPr) {
+	// Excel default: true
+	if pr == nil || pr.OutlinePr == nil {
+		*o = true
+		return
+	}
+	*o = OutlineSummaryRight(defaultTrue(pr.OutlinePr.SummaryRight))
 }
 
 // setSheetPrOption implements the SheetPrOption interface and specifies a
@@ -153,6 +179,7 @@ func (o *AutoPageBreaks) getSheetPrOption(pr *xlsxSheetPr) {
 //   FitToPage(bool)
 //   AutoPageBreaks(bool)
 //   OutlineSummaryBelow(bool)
+//   OutlineSummaryRight(bool)
 func (f *File) SetSheetPrOptions(name string, opts ...SheetPrOption) error {
 	sheet, err := f.workSheetReader(name)
 	if err != nil {
@@ -179,6 +206,7 @@ func (f *File) SetSheetPrOptions(name string, opts ...SheetPrOption) error {
 //   FitToPage(bool)
 //   AutoPageBreaks(bool)
 //   OutlineSummaryBelow(bool)
+//   OutlineSummaryRight(bool)
 func (f *File) GetSheetPrOptions(name string, opts ...SheetPrOptionPtr) error {
 	sheet, err := f.workSheetReader(name)
 	if err != nil {
@@ -192,6 +220,79 @@ func (f *File) GetSheetPrOptions(name string, opts ...SheetPrOptionPtr) error {
 	return err
 }
 
+// SetOutlineProps provides a function to set the direction of the summary
+// rows and columns of the outline grouping by given worksheet name. The
+// summary rows will be placed below the detail rows if summaryBelow is true,
+// otherwise above them, and the summary columns will be placed on the right
+// of the detail columns if summaryRight is true, otherwise on the left. Both
+// of them are true by default in Excel. For example, place the summary rows
+// above the grouped rows in Sheet1:
+//
+//    err := f.SetOutlineProps("Sheet1", false, true)
+//
+func (f *File) SetOutlineProps(sheet string, summaryBelow, summaryRight bool) error {
+	return f.SetSheetPrOptions(sheet, OutlineSummaryBelow(summaryBelow), OutlineSummaryRight(summaryRight))
+}
+
+// GetOutlineProps provides a function to get the direction of the summary
+// rows and columns of the outline grouping by given worksheet name.
+func (f *File) GetOutlineProps(sheet string) (bool, bool, error) {
+	var (
+		summaryBelow OutlineSummaryBelow
+		summaryRight OutlineSummaryRight
+	)
+	err := f.GetSheetPrOptions(sheet, &summaryBelow, &summaryRight)
+	return bool(summaryBelow), bool(summaryRight), err
+}
+
+// codeNameExp defined the regular expression of the legal code name of the
+// worksheet, which must be a valid VBA identifier.
+var codeNameExp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,30}$`)
+
+// SetSheetCodeName provides a function to set the code name of the worksheet
+// by given worksheet name and code name. The code name is used to reference
+// the worksheet in the VBA code, it will be kept after the worksheet has been
+// renamed. The code name must be unique in the workbook, start with a letter
+// and contain only letters, numbers and underscores, and the length of the
+// code name can't exceed 31 characters. For example, set the code name of
+// Sheet1 to "DataSheet":
+//
+//    err := f.SetSheetCodeName("Sheet1", "DataSheet")
+//
+func (f *File) SetSheetCodeName(sheet, codeName string) error {
+	if !codeNameExp.MatchString(codeName) {
+		return fmt.Errorf("invalid code name %q", codeName)
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	for _, name := range f.GetSheetMap() {
+		if strings.EqualFold(name, sheet) {
+			continue
+		}
+		if other, err := f.GetSheetCodeName(name); err == nil && strings.EqualFold(other, codeName) {
+			return fmt.Errorf("code name %s is already used by the sheet %s", codeName, name)
+		}
+	}
+	if ws.SheetPr == nil {
+		ws.SheetPr = new(xlsxSheetPr)
+	}
+	ws.SheetPr.CodeName = codeName
+	return err
+}
+
+// GetSheetCodeName provides a function to get the code name of the worksheet
+// by given worksheet name, an empty string will be returned if the code name
+// of the worksheet has not been set.
+func (f *File) GetSheetCodeName(sheet string) (string, error) {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil || ws.SheetPr == nil {
+		return "", err
+	}
+	return ws.SheetPr.CodeName, err
+}
+
 type (
 	// PageMarginBottom specifies the bottom margin for the page.
 	PageMarginBottom float64
@@ -17,6 +17,7 @@ var _ = []excelize.SheetPrOption{
 	excelize.FitToPage(true),
 	excelize.AutoPageBreaks(true),
 	excelize.OutlineSummaryBelow(true),
+	excelize.OutlineSummaryRight(true),
 }
 
 var _ = []excelize.SheetPrOptionPtr{
@@ -26,6 +27,7 @@ var _ = []excelize.SheetPrOptionPtr{
 	(*excelize.FitToPage)(nil),
 	(*excelize.AutoPageBreaks)(nil),
 	(*excelize.OutlineSummaryBelow)(nil),
+	(*excelize.OutlineSummaryRight)(nil),
 }
 
 func ExampleFile_SetSheetPrOptions() {
@@ -39,6 +41,7 @@ func ExampleFile_SetSheetPrOptions() {
 		excelize.FitToPage(true),
 		excelize.AutoPageBreaks(true),
 		excelize.OutlineSummaryBelow(false),
+		excelize.OutlineSummaryRight(false),
 	); err != nil {
 		fmt.Println(err)
 	}
@@ -56,6 +59,7 @@ func ExampleFile_GetSheetPrOptions() {
 		fitToPage                         excelize.FitToPage
 		autoPageBreaks                    excelize.AutoPageBreaks
 		outlineSummaryBelow               excelize.OutlineSummaryBelow
+		outlineSummaryRight               excelize.OutlineSummaryRight
 	)
 
 	if err := f.GetSheetPrOptions(sheet,
@@ -65,6 +69,7 @@ func ExampleFile_GetSheetPrOptions() {
 		&fitToPage,
 		&autoPageBreaks,
 		&outlineSummaryBelow,
+		&outlineSummaryRight,
 	); err != nil {
 		fmt.Println(err)
 	}
@@ -75,6 +80,7 @@ func ExampleFile_GetSheetPrOptions() {
 	fmt.Println("- fitToPage:", fitToPage)
 	fmt.Println("- autoPageBreaks:", autoPageBreaks)
 	fmt.Println("- outlineSummaryBelow:", outlineSummaryBelow)
+	fmt.Println("- outlineSummaryRight:", outlineSummaryRight)
 	// Output:
 	// Defaults:
 	// - codeName: ""
@@ -83,6 +89,7 @@ func ExampleFile_GetSheetPrOptions() {
 	// - fitToPage: false
 	// - autoPageBreaks: false
 	// - outlineSummaryBelow: true
+	// - outlineSummaryRight: true
 }
 
 func TestSheetPrOptions(t *testing.T) {
@@ -98,6 +105,7 @@ func TestSheetPrOptions(t *testing.T) {
 		{new(excelize.FitToPage), excelize.FitToPage(true)},
 		{new(excelize.AutoPageBreaks), excelize.AutoPageBreaks(true)},
 		{new(excelize.OutlineSummaryBelow), excelize.OutlineSummaryBelow(false)},
+		{new(excelize.OutlineSummaryRight), excelize.OutlineSummaryRight(false)},
 	}
 
 	for i, test := range testData {
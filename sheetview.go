@@ -165,10 +165,11 @@ func (f *File) getSheetView(sheetName string, viewIndex int) (*xlsxSheetView, er
 //    ShowFormulas(bool)
 //    ShowGridLines(bool)
 //    ShowRowColHeaders(bool)
+//    ShowZeros(bool)
 //    ZoomScale(float64)
 //    TopLeftCell(string)
 //
-// Example:
+// The zoom scale must be in the range from 10 to 400. Example:
 //
 //    err = f.SetSheetViewOptions("Sheet1", -1, ShowGridLines(false))
 //
@@ -177,7 +178,18 @@ func (f *File) SetSheetViewOptions(name string, viewIndex int, opts ...SheetView
 	if err != nil {
 		return err
 	}
+	return setSheetViewOptions(view, opts)
+}
 
+// setSheetViewOptions provides a function to set the sheet view options by
+// given sheet view. The options will not be applied if any zoom scale is out
+// of the range from 10 to 400.
+func setSheetViewOptions(view *xlsxSheetView, opts []SheetViewOption) error {
+	for _, opt := range opts {
+		if zoomScale, ok := opt.(ZoomScale); ok && (zoomScale < 10 || zoomScale > 400) {
+			return fmt.Errorf("zoom scale %g out of range", zoomScale)
+		}
+	}
 	for _, opt := range opts {
 		opt.setSheetViewOption(view)
 	}
@@ -213,3 +225,155 @@ func (f *File) GetSheetViewOptions(name string, viewIndex int, opts ...SheetView
 	}
 	return nil
 }
+
+// SheetViewPanes directly maps the settings of the freeze or split panes of
+// a worksheet view. The fields have the same meaning as the settings of
+// SetPanes.
+type SheetViewPanes struct {
+	Freeze      bool
+	Split       bool
+	XSplit      int
+	YSplit      int
+	TopLeftCell string
+	ActivePane  string
+}
+
+// SheetViewOptions directly maps the settings of a worksheet view. The nil
+// fields will be ignored by SetSheetView, so that the unrelated attributes of
+// the view are kept.
+type SheetViewOptions struct {
+	Panes             *SheetViewPanes
+	ZoomScale         *float64
+	ShowGridLines     *bool
+	ShowRowColHeaders *bool
+	RightToLeft       *bool
+	TopLeftCell       *string
+	ActiveCell        *string
+}
+
+// SetSheetView provides a function to set the panes, zoom scale, gridlines,
+// headings, right to left display mode, top left visible cell and active cell
+// of the last view of the worksheet in a single call. Only the specified
+// settings will be changed, and the panes will be removed if neither freeze
+// nor split is applied. For example, freeze the first row, hide gridlines,
+// zoom to 120% and set the active cell on Sheet1!B5:
+//
+//    zoomScale, showGridLines, activeCell := 120.0, false, "B5"
+//    err := f.SetSheetView("Sheet1", excelize.SheetViewOptions{
+//        Panes:         &excelize.SheetViewPanes{Freeze: true, YSplit: 1},
+//        ZoomScale:     &zoomScale,
+//        ShowGridLines: &showGridLines,
+//        ActiveCell:    &activeCell,
+//    })
+//
+func (f *File) SetSheetView(sheet string, opts SheetViewOptions) error {
+	view, err := f.getSheetView(sheet, -1)
+	if err != nil {
+		return err
+	}
+	if opts.ZoomScale != nil && (*opts.ZoomScale < 10 || *opts.ZoomScale > 400) {
+		return fmt.Errorf("zoom scale %g out of range", *opts.ZoomScale)
+	}
+	for _, cell := range []*string{opts.TopLeftCell, opts.ActiveCell} {
+		if cell != nil && *cell != "" {
+			if _, _, err = CellNameToCoordinates(*cell); err != nil {
+				return err
+			}
+		}
+	}
+	pane := view.Pane
+	if opts.Panes != nil {
+		if pane, err = f.newSheetViewPane(sheet, &formatPanes{
+			Freeze:      opts.Panes.Freeze,
+			Split:       opts.Panes.Split,
+			XSplit:      opts.Panes.XSplit,
+			YSplit:      opts.Panes.YSplit,
+			TopLeftCell: opts.Panes.TopLeftCell,
+			ActivePane:  opts.Panes.ActivePane,
+		}); err != nil {
+			return err
+		}
+	}
+	if opts.ZoomScale != nil {
+		view.ZoomScale = *opts.ZoomScale
+	}
+	if opts.ShowGridLines != nil {
+		view.ShowGridLines = boolPtr(*opts.ShowGridLines)
+	}
+	if opts.ShowRowColHeaders != nil {
+		view.ShowRowColHeaders = boolPtr(*opts.ShowRowColHeaders)
+	}
+	if opts.RightToLeft != nil {
+		view.RightToLeft = *opts.RightToLeft
+	}
+	if opts.TopLeftCell != nil {
+		view.TopLeftCell = *opts.TopLeftCell
+	}
+	if opts.Panes == nil && opts.ActiveCell == nil {
+		return err
+	}
+	activeCell := getSheetViewActiveCell(view)
+	if opts.ActiveCell != nil {
+		activeCell = *opts.ActiveCell
+	}
+	view.Pane, view.Selection = pane, nil
+	if activeCell != "" {
+		var activePane string
+		if pane != nil {
+			activePane = pane.ActivePane
+		}
+		view.Selection = []*xlsxSelection{{
+			ActiveCell: activeCell,
+			Pane:       activePane,
+			SQRef:      activeCell,
+		}}
+	}
+	return err
+}
+
+// getSheetViewActiveCell provides a function to get the active cell in the
+// active pane of the given worksheet view.
+func getSheetViewActiveCell(view *xlsxSheetView) string {
+	var activePane string
+	if view.Pane != nil {
+		activePane = view.Pane.ActivePane
+	}
+	for _, selection := range view.Selection {
+		if selection != nil && selection.Pane == activePane {
+			return selection.ActiveCell
+		}
+	}
+	return ""
+}
+
+// GetSheetView provides a function to get the settings of the last view of
+// the worksheet, the defaults will be returned for the settings which are not
+// specified in the view. The Panes will be nil if the view has no panes.
+func (f *File) GetSheetView(sheet string) (SheetViewOptions, error) {
+	var opts SheetViewOptions
+	view, err := f.getSheetView(sheet, -1)
+	if err != nil {
+		return opts, err
+	}
+	if view.Pane != nil {
+		opts.Panes = &SheetViewPanes{
+			Freeze:      view.Pane.State == "frozen",
+			Split:       view.Pane.State == "split",
+			XSplit:      int(view.Pane.XSplit),
+			YSplit:      int(view.Pane.YSplit),
+			TopLeftCell: view.Pane.TopLeftCell,
+			ActivePane:  view.Pane.ActivePane,
+		}
+	}
+	zoomScale := view.ZoomScale
+	if zoomScale == 0 {
+		zoomScale = 100 // Excel default: 100
+	}
+	opts.ZoomScale = float64Ptr(zoomScale)
+	opts.ShowGridLines = boolPtr(defaultTrue(view.ShowGridLines))
+	opts.ShowRowColHeaders = boolPtr(defaultTrue(view.ShowRowColHeaders))
+	opts.RightToLeft = boolPtr(view.RightToLeft)
+	opts.TopLeftCell = stringPtr(view.TopLeftCell)
+	opts.ActiveCell = stringPtr(getSheetViewActiveCell(view))
+	return opts, err
+}
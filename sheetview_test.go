@@ -79,6 +79,7 @@ func ExampleFile_SetSheetViewOptions() {
 	// Output:
 	// Default:
 	// - zoomScale: 80
+	// zoom scale 500 out of range
 	// Used out of range value:
 	// - zoomScale: 80
 	// Used correct value:
@@ -181,4 +182,8 @@ func TestSheetViewOptionsErrors(t *testing.T) {
 	assert.NoError(t, f.SetSheetViewOptions(sheet, -1))
 	assert.Error(t, f.SetSheetViewOptions(sheet, 1))
 	assert.Error(t, f.SetSheetViewOptions(sheet, -2))
+	assert.EqualError(t, f.SetSheetViewOptions(sheet, 0, excelize.ShowGridLines(false), excelize.ZoomScale(5)), "zoom scale 5 out of range")
+	var showGridLines excelize.ShowGridLines
+	assert.NoError(t, f.GetSheetViewOptions(sheet, 0, &showGridLines))
+	assert.True(t, bool(showGridLines))
 }
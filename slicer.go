@@ -0,0 +1,403 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SlicerOptions directly maps the settings of the slicer. Name specifies the
+// column name of the table which the slicer is based on, Sheet and Cell
+// specifies the worksheet and the top left cell where the slicer will be
+// placed, TableName specifies the name of the table, Caption specifies the
+// header of the slicer and the column name will be used if it is empty,
+// Width and Height specifies the size of the slicer in pixels.
+type SlicerOptions struct {
+	Name      string
+	Sheet     string
+	Cell      string
+	TableName string
+	Caption   string
+	Width     int
+	Height    int
+}
+
+// slicerCacheNameRe defined the characters which can't be used in the name
+// of the slicer cache.
+var slicerCacheNameRe = regexp.MustCompile(`[^\p{L}\p{N}_.]`)
+
+// AddSlicer provides the method to add the slicer by given settings of the
+// slicer. The slicer is an interactive filter of the table, which displays
+// the unique values of the table column as buttons. Currently, only the
+// slicers of the table are supported, and the slicer can be placed on any
+// worksheet of the workbook. Note that the slicer requires Microsoft Excel
+// 2013 or later. For example, add a slicer for the column "Region" of the
+// table "Table1" at the cell F1 on Sheet1:
+//
+//    err := f.AddSlicer(excelize.SlicerOptions{
+//        Name:      "Region",
+//        Sheet:     "Sheet1",
+//        Cell:      "F1",
+//        TableName: "Table1",
+//    })
+//
+func (f *File) AddSlicer(opts SlicerOptions) error {
+	if opts.Name == "" || opts.TableName == "" {
+		return errors.New("parameter is required")
+	}
+	col, row, err := CellNameToCoordinates(opts.Cell)
+	if err != nil {
+		return err
+	}
+	xlsx, err := f.workSheetReader(opts.Sheet)
+	if err != nil {
+		return err
+	}
+	table, err := f.getTableByName(opts.TableName)
+	if err != nil {
+		return err
+	}
+	var column *xlsxTableColumn
+	if table.TableColumns != nil {
+		for _, tableColumn := range table.TableColumns.TableColumn {
+			if strings.EqualFold(tableColumn.Name, opts.Name) {
+				column = tableColumn
+				break
+			}
+		}
+	}
+	if column == nil {
+		return fmt.Errorf("column %s is not exist in the table %s", opts.Name, opts.TableName)
+	}
+	if opts.Caption == "" {
+		opts.Caption = column.Name
+	}
+	if opts.Width <= 0 {
+		opts.Width = 192
+	}
+	if opts.Height <= 0 {
+		opts.Height = 260
+	}
+	name, cacheName := f.getSlicerNames(column.Name)
+	if err = f.addSlicerCache(cacheName, column.Name, table.ID, column.ID); err != nil {
+		return err
+	}
+	if err = f.addSheetSlicer(xlsx, opts.Sheet, xlsxSlicer{
+		Name:      name,
+		Cache:     cacheName,
+		Caption:   opts.Caption,
+		RowHeight: 241300,
+	}); err != nil {
+		return err
+	}
+	return f.addDrawingSlicer(xlsx, opts.Sheet, name, col, row, opts.Width, opts.Height)
+}
+
+// getTableByName provides a function to get the table by given table name,
+// the table name is case-insensitive.
+func (f *File) getTableByName(name string) (*xlsxTable, error) {
+	var paths []string
+	for path := range f.XLSX {
+		if strings.HasPrefix(path, "xl/tables/table") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		table, err := f.tableReader(path)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(table.Name, name) {
+			return table, nil
+		}
+	}
+	return nil, fmt.Errorf("table %s is not exist", name)
+}
+
+// getTableSlicerCache provides a function to get the name of the slicer
+// cache based on the table by given table ID, an empty string will be
+// returned if the table isn't used by any slicer cache.
+func (f *File) getTableSlicerCache(tableID int) (string, error) {
+	var paths []string
+	for path := range f.XLSX {
+		if strings.HasPrefix(path, "xl/slicerCaches/slicerCache") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		cache := new(xlsxSlicerCacheDefinition)
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+			Decode(cache); err != nil && err != io.EOF {
+			return "", err
+		}
+		if cache.ExtLst == nil {
+			continue
+		}
+		decodeExtLst := new(decodeWorksheetExt)
+		if err := f.xmlNewDecoder(bytes.NewReader([]byte("<extLst>" + cache.ExtLst.Ext + "</extLst>"))).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return "", err
+		}
+		for _, ext := range decodeExtLst.Ext {
+			if ext.URI != ExtURISlicerCacheDefinition {
+				continue
+			}
+			tableSlicerCache := new(decodeTableSlicerCache)
+			if err := f.xmlNewDecoder(bytes.NewReader([]byte(ext.Content))).
+				Decode(tableSlicerCache); err != nil && err != io.EOF {
+				return "", err
+			}
+			if tableSlicerCache.TableID == tableID {
+				return cache.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// getSlicerNames provides a function to get the unique name of the slicer
+// and the slicer cache by given source name. The name of the slicer cache
+// is also used as the defined name of the workbook.
+func (f *File) getSlicerNames(sourceName string) (string, string) {
+	slicerNames, cacheNames := make(map[string]bool), make(map[string]bool)
+	for path := range f.XLSX {
+		if !strings.HasPrefix(path, "xl/slicers/slicer") {
+			continue
+		}
+		slicers := new(xlsxSlicers)
+		if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+			Decode(slicers); err != nil && err != io.EOF {
+			continue
+		}
+		for _, slicer := range slicers.Slicer {
+			slicerNames[strings.ToLower(slicer.Name)] = true
+			cacheNames[strings.ToLower(slicer.Cache)] = true
+		}
+	}
+	if wb := f.workbookReader(); wb.DefinedNames != nil {
+		for _, definedName := range wb.DefinedNames.DefinedName {
+			cacheNames[strings.ToLower(definedName.Name)] = true
+		}
+	}
+	name := sourceName
+	for i := 1; slicerNames[strings.ToLower(name)]; i++ {
+		name = sourceName + " " + strconv.Itoa(i)
+	}
+	prefix := "Slicer_" + slicerCacheNameRe.ReplaceAllString(sourceName, "_")
+	cacheName := prefix
+	for i := 1; cacheNames[strings.ToLower(cacheName)]; i++ {
+		cacheName = prefix + strconv.Itoa(i)
+	}
+	return name, cacheName
+}
+
+// addSlicerCache provides a function to add the slicer cache part of the
+// table column, and reference it in the workbook by given name of the slicer
+// cache, source name, table ID and column ID.
+func (f *File) addSlicerCache(name, sourceName string, tableID, columnID int) error {
+	cacheID := f.countSlicerCaches() + 1
+	tableSlicerCache, _ := xml.Marshal(xlsxTableSlicerCache{TableID: tableID, Column: columnID})
+	cache, _ := xml.Marshal(xlsxSlicerCacheDefinition{
+		XMLNSX:     NameSpaceSpreadSheet,
+		XMLNSX15:   NameSpaceSpreadSheetX15,
+		Name:       name,
+		SourceName: sourceName,
+		ExtLst: &xlsxExtLst{
+			Ext: `<x:ext uri="` + ExtURISlicerCacheDefinition + `">` + string(tableSlicerCache) + `</x:ext>`,
+		},
+	})
+	f.saveFileList("xl/slicerCaches/slicerCache"+strconv.Itoa(cacheID)+".xml", cache)
+	f.addContentTypePart(cacheID, "slicerCache")
+	rID := f.addRels("xl/_rels/workbook.xml.rels", SourceRelationshipSlicerCache, fmt.Sprintf("slicerCaches/slicerCache%d.xml", cacheID), "")
+	wb := f.workbookReader()
+	if wb.DefinedNames == nil {
+		wb.DefinedNames = &xlsxDefinedNames{}
+	}
+	wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, xlsxDefinedName{
+		Name: name,
+		Data: formulaErrorNA,
+	})
+	extLst, err := f.addSlicerList(wb.ExtLst, ExtURISlicerCachesListX15, "x15:slicerCaches", "x14:slicerCache", rID)
+	if err != nil {
+		return err
+	}
+	wb.ExtLst = extLst
+	return err
+}
+
+// addSheetSlicer provides a function to add the slicer to the slicers part
+// of the worksheet by given worksheet name and slicer. The slicers part will
+// be created if the worksheet doesn't have it.
+func (f *File) addSheetSlicer(xlsx *xlsxWorksheet, sheet string, slicer xlsxSlicer) error {
+	_, _, rIDs, err := f.decodeSlicerExtLst(xlsx.ExtLst, ExtURISlicerListX15)
+	if err != nil {
+		return err
+	}
+	slicers, slicerXML := new(xlsxSlicers), ""
+	if len(rIDs) > 0 {
+		slicerXML = strings.Replace(f.getSheetRelationshipsTargetByID(sheet, rIDs[0]), "..", "xl", -1)
+	}
+	if _, ok := f.XLSX[slicerXML]; ok {
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(slicerXML)))).
+			Decode(slicers); err != nil && err != io.EOF {
+			return err
+		}
+	} else {
+		slicerID := f.countSlicers() + 1
+		slicerXML = "xl/slicers/slicer" + strconv.Itoa(slicerID) + ".xml"
+		f.addContentTypePart(slicerID, "slicer")
+		sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+		rID := f.addRels(sheetRels, SourceRelationshipSlicer, "../slicers/slicer"+strconv.Itoa(slicerID)+".xml", "")
+		if xlsx.ExtLst, err = f.addSlicerList(xlsx.ExtLst, ExtURISlicerListX15, "x14:slicerList", "x14:slicer", rID); err != nil {
+			return err
+		}
+	}
+	slicers.Slicer = append(slicers.Slicer, slicer)
+	output, _ := xml.Marshal(slicers)
+	f.saveFileList(slicerXML, output)
+	return err
+}
+
+// decodeSlicerExtLst provides a function to decode the extension list, and get
+// the index of the extension and the relationship IDs in the slicer list by
+// given extension list and URI of the extension. The index will be -1 if the
+// extension doesn't exist.
+func (f *File) decodeSlicerExtLst(extLst *xlsxExtLst, uri string) (*decodeWorksheetExt, int, []string, error) {
+	var (
+		decodeExtLst = new(decodeWorksheetExt)
+		idx          = -1
+		rIDs         []string
+		err          error
+	)
+	if extLst != nil {
+		if err = f.xmlNewDecoder(bytes.NewReader([]byte("<extLst>" + extLst.Ext + "</extLst>"))).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return decodeExtLst, idx, rIDs, err
+		}
+	}
+	for i, ext := range decodeExtLst.Ext {
+		if ext.URI != uri {
+			continue
+		}
+		slicerList := new(decodeSlicerList)
+		idx = i
+		if err = f.xmlNewDecoder(bytes.NewReader([]byte(ext.Content))).
+			Decode(slicerList); err != nil && err != io.EOF {
+			return decodeExtLst, idx, rIDs, err
+		}
+		for _, slicer := range slicerList.Slicer {
+			rIDs = append(rIDs, slicer.RID)
+		}
+	}
+	return decodeExtLst, idx, rIDs, nil
+}
+
+// addSlicerList provides a function to add the relationship ID of the slicers
+// part or the slicer cache part to the slicer list in the extension list by
+// given extension list, URI of the extension, element name of the slicer
+// list, element name of the list item and relationship index.
+func (f *File) addSlicerList(extLst *xlsxExtLst, uri, list, item string, rID int) (*xlsxExtLst, error) {
+	decodeExtLst, idx, rIDs, err := f.decodeSlicerExtLst(extLst, uri)
+	if err != nil {
+		return extLst, err
+	}
+	content := `<` + list + ` xmlns:x14="` + NameSpaceSpreadSheetX14 + `">`
+	for _, ID := range append(rIDs, "rId"+strconv.Itoa(rID)) {
+		content += `<` + item + ` r:id="` + ID + `"/>`
+	}
+	content += `</` + list + `>`
+	if idx == -1 {
+		decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxWorksheetExt{URI: uri, Content: content})
+	} else {
+		decodeExtLst.Ext[idx].Content = content
+	}
+	extLstBytes, err := xml.Marshal(decodeExtLst)
+	if err != nil {
+		return extLst, err
+	}
+	return &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}, err
+}
+
+// addDrawingSlicer provides a function to add the graphic frame of the
+// slicer to the drawing of the worksheet by given worksheet name, slicer
+// name, top left cell coordinates and size of the slicer.
+func (f *File) addDrawingSlicer(xlsx *xlsxWorksheet, sheet, name string, col, row, width, height int) error {
+	drawingID := f.countDrawings() + 1
+	drawingXML := "xl/drawings/drawing" + strconv.Itoa(drawingID) + ".xml"
+	drawingID, drawingXML = f.prepareDrawing(xlsx, drawingID, sheet, drawingXML)
+	colStart, rowStart, _, _, colEnd, rowEnd, x2, y2 := f.positionObjectPixels(sheet, col-1, row-1, 0, 0, width, height)
+	content, cNvPrID := f.drawingParser(drawingXML)
+	graphicFrame := xlsxGraphicFrame{
+		NvGraphicFramePr: xlsxNvGraphicFramePr{
+			CNvPr: &xlsxCNvPr{
+				ID:   cNvPrID,
+				Name: name,
+			},
+		},
+		Graphic: &xlsxGraphic{
+			GraphicData: &xlsxGraphicData{
+				URI: NameSpaceDrawingMLSlicer,
+				Slicer: &xlsxGraphicSlicer{
+					Sle:  NameSpaceDrawingMLSlicer,
+					Name: name,
+				},
+			},
+		},
+	}
+	graphic, _ := xml.Marshal(graphicFrame)
+	content.TwoCellAnchor = append(content.TwoCellAnchor, &xdrCellAnchor{
+		EditAs: "oneCell",
+		From:   &xlsxFrom{Col: colStart, Row: rowStart},
+		To:     &xlsxTo{Col: colEnd, ColOff: x2 * EMU, Row: rowEnd, RowOff: y2 * EMU},
+		GraphicFrame: `<mc:AlternateContent xmlns:mc="` + SourceRelationshipCompatibility +
+			`"><mc:Choice xmlns:sle15="` + NameSpaceDrawingMLSlicerX15 + `" Requires="sle15">` +
+			string(graphic) + `</mc:Choice></mc:AlternateContent>`,
+		ClientData: &xdrClientData{FLocksWithSheet: true, FPrintsWithSheet: true},
+	})
+	f.Drawings[drawingXML] = content
+	f.addContentTypePart(drawingID, "drawings")
+	return nil
+}
+
+// countSlicers provides a function to get slicers files count storage in the
+// folder xl/slicers.
+func (f *File) countSlicers() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/slicers/slicer") {
+			count++
+		}
+	}
+	return count
+}
+
+// countSlicerCaches provides a function to get slicer caches files count
+// storage in the folder xl/slicerCaches.
+func (f *File) countSlicerCaches() int {
+	count := 0
+	for k := range f.XLSX {
+		if strings.Contains(k, "xl/slicerCaches/slicerCache") {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,64 @@
+package excelize
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSlicer(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Region", "Unit Price", "Sales"},
+		{"East", 10, 100},
+		{"West", 20, 200},
+		{"North", 30, 300},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(idx+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "C4", `{"table_name":"Table1"}`))
+	assert.NoError(t, f.AddSlicer(SlicerOptions{Name: "Region", Sheet: "Sheet1", Cell: "E1", TableName: "Table1"}))
+	assert.NoError(t, f.AddSlicer(SlicerOptions{Name: "unit price", Sheet: "Sheet1", Cell: "H1", TableName: "table1", Caption: "Price", Width: 200, Height: 200}))
+	assert.NoError(t, f.AddSlicer(SlicerOptions{Name: "Region", Sheet: "Sheet1", Cell: "K1", TableName: "Table1"}))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddSlicer(SlicerOptions{Name: "Sales", Sheet: "Sheet2", Cell: "A1", TableName: "Table1"}))
+
+	// Test add slicer with invalid settings.
+	assert.EqualError(t, f.AddSlicer(SlicerOptions{Sheet: "Sheet1", Cell: "E1", TableName: "Table1"}), "parameter is required")
+	assert.EqualError(t, f.AddSlicer(SlicerOptions{Name: "Region", Sheet: "Sheet1", Cell: "E", TableName: "Table1"}), `cannot convert cell "E" to coordinates: invalid cell name "E"`)
+	assert.EqualError(t, f.AddSlicer(SlicerOptions{Name: "Region", Sheet: "SheetN", Cell: "E1", TableName: "Table1"}), "sheet SheetN is not exist")
+	assert.EqualError(t, f.AddSlicer(SlicerOptions{Name: "Region", Sheet: "Sheet1", Cell: "E1", TableName: "TableN"}), "table TableN is not exist")
+	assert.EqualError(t, f.AddSlicer(SlicerOptions{Name: "Date", Sheet: "Sheet1", Cell: "E1", TableName: "Table1"}), "column Date is not exist in the table Table1")
+
+	// Test the slicers parts and relationships after reopen the workbook.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<slicerCacheDefinition xmlns="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" xmlns:x="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main" name="Slicer_Unit_Price" sourceName="Unit Price"><extLst><x:ext uri="{2F2917AC-EB37-4324-AD4E-5DD8C200BD13}"><x15:tableSlicerCache tableId="1" column="2"></x15:tableSlicerCache></x:ext></extLst></slicerCacheDefinition>`, string(f.XLSX["xl/slicerCaches/slicerCache2.xml"]))
+	assert.Contains(t, string(f.XLSX["xl/slicerCaches/slicerCache3.xml"]), `name="Slicer_Region1" sourceName="Region"`)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<slicers xmlns="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><slicer name="Region" cache="Slicer_Region" caption="Region" rowHeight="241300"></slicer><slicer name="Unit Price" cache="Slicer_Unit_Price" caption="Price" rowHeight="241300"></slicer><slicer name="Region 1" cache="Slicer_Region1" caption="Region" rowHeight="241300"></slicer></slicers>`, string(f.XLSX["xl/slicers/slicer1.xml"]))
+	assert.Contains(t, string(f.XLSX["xl/slicers/slicer2.xml"]), `<slicer name="Sales" cache="Slicer_Sales" caption="Sales" rowHeight="241300"></slicer>`)
+	contentTypes := string(f.XLSX["[Content_Types].xml"])
+	assert.Contains(t, contentTypes, `<Override PartName="/xl/slicers/slicer2.xml" ContentType="application/vnd.ms-excel.slicer+xml"></Override>`)
+	assert.Contains(t, contentTypes, `<Override PartName="/xl/slicerCaches/slicerCache4.xml" ContentType="application/vnd.ms-excel.slicerCache+xml"></Override>`)
+	workbook := string(f.XLSX["xl/workbook.xml"])
+	assert.Contains(t, workbook, `<definedName name="Slicer_Region1">#N/A</definedName>`)
+	assert.Contains(t, workbook, `<ext uri="{46BE6895-7355-4a93-B00E-2C351335B9C9}"><x15:slicerCaches xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><x14:slicerCache r:id="rId4"/><x14:slicerCache r:id="rId5"/><x14:slicerCache r:id="rId6"/><x14:slicerCache r:id="rId8"/></x15:slicerCaches></ext>`)
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, `<ext uri="{3A4CF648-6AED-40f4-86FF-DC5316D8AED3}"><x14:slicerList xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><x14:slicer r:id="rId2"/></x14:slicerList></ext>`, xlsx.ExtLst.Ext)
+	assert.Equal(t, "../slicers/slicer1.xml", f.getSheetRelationshipsTargetByID("Sheet1", "rId2"))
+	drawing := string(f.XLSX["xl/drawings/drawing1.xml"])
+	assert.Equal(t, 3, strings.Count(drawing, `<mc:Choice xmlns:sle15="http://schemas.microsoft.com/office/drawing/2012/slicer" Requires="sle15">`))
+	assert.Contains(t, drawing, `<a:graphicData uri="http://schemas.microsoft.com/office/drawing/2010/slicer"><sle:slicer xmlns:sle="http://schemas.microsoft.com/office/drawing/2010/slicer" name="Region 1"></sle:slicer></a:graphicData>`)
+
+	// Test add slicer on the worksheet with existing slicers after reopen the
+	// workbook.
+	assert.NoError(t, f.AddSlicer(SlicerOptions{Name: "Sales", Sheet: "Sheet1", Cell: "N1", TableName: "Table1"}))
+	assert.Contains(t, string(f.XLSX["xl/slicers/slicer1.xml"]), `<slicer name="Sales 1" cache="Slicer_Sales1" caption="Sales" rowHeight="241300"></slicer></slicers>`)
+	assert.Equal(t, 2, f.countSlicers())
+	assert.Equal(t, 5, f.countSlicerCaches())
+}
@@ -12,11 +12,13 @@ package excelize
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -24,12 +26,16 @@ import (
 
 // StreamWriter defined the type of stream writer.
 type StreamWriter struct {
-	File       *File
-	Sheet      string
-	SheetID    int
-	worksheet  *xlsxWorksheet
-	rawData    bufferedWriter
-	tableParts string
+	File         *File
+	Sheet        string
+	SheetID      int
+	worksheet    *xlsxWorksheet
+	rawData      bufferedWriter
+	declaration  string
+	rows         int
+	sheetWritten bool
+	tableParts   string
+	colStyles    map[int]int
 }
 
 // NewStreamWriter return stream writer struct by given worksheet name for
@@ -83,12 +89,119 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	if err != nil {
 		return nil, err
 	}
-	sw.rawData.WriteString(XMLHeader + `<worksheet` + templateNamespaceIDMap)
-	bulkAppendFields(&sw.rawData, sw.worksheet, 1, 5)
-	sw.rawData.WriteString(`<sheetData>`)
 	return sw, err
 }
 
+// XMLDeclaration directly maps the settings of the XML declaration of the
+// worksheet part. The Encoding specifies the encoding label in the
+// declaration, only the UTF-8 encoding is supported, and the Standalone
+// specifies the standalone document declaration which could be "yes", "no"
+// or empty to omit it. Set BOM to true to emit the UTF-8 byte order mark
+// before the declaration.
+type XMLDeclaration struct {
+	Encoding   string
+	Standalone string
+	BOM        bool
+}
+
+// SetXMLDeclaration sets the XML declaration of the worksheet part for the
+// StreamWriter, the default declaration is:
+//
+//    <?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+//
+// Note that SetXMLDeclaration must be called before the SetRow. For example,
+// emit the byte order mark and omit the standalone document declaration:
+//
+//    err := sw.SetXMLDeclaration(excelize.XMLDeclaration{Encoding: "UTF-8", BOM: true})
+//
+func (sw *StreamWriter) SetXMLDeclaration(decl XMLDeclaration) error {
+	if sw.sheetWritten {
+		return errors.New("must call the SetXMLDeclaration function before the SetRow function")
+	}
+	if decl.Encoding == "" {
+		decl.Encoding = "UTF-8"
+	}
+	if !strings.EqualFold(decl.Encoding, "UTF-8") {
+		return fmt.Errorf("unsupported encoding %s, only UTF-8 is supported", decl.Encoding)
+	}
+	declaration := `<?xml version="1.0" encoding="` + decl.Encoding + `"`
+	switch decl.Standalone {
+	case "":
+	case "yes", "no":
+		declaration += ` standalone="` + decl.Standalone + `"`
+	default:
+		return fmt.Errorf("invalid standalone document declaration %s", decl.Standalone)
+	}
+	sw.declaration = declaration + "?>\n"
+	if decl.BOM {
+		sw.declaration = "\xef\xbb\xbf" + sw.declaration
+	}
+	return nil
+}
+
+// SetSheetViewOptions sets sheet view options of the worksheet for the
+// StreamWriter. The viewIndex may be negative and if so is counted backward
+// (-1 is the last view). Note that SetSheetViewOptions must be called before
+// the SetRow. For example, hide the gridlines and set the zoom scale of the
+// worksheet:
+//
+//    err := sw.SetSheetViewOptions(0, excelize.ShowGridLines(false), excelize.ZoomScale(80))
+//
+// See File.SetSheetViewOptions for details on the available options.
+func (sw *StreamWriter) SetSheetViewOptions(viewIndex int, opts ...SheetViewOption) error {
+	if sw.sheetWritten {
+		return errors.New("must call the SetSheetViewOptions function before the SetRow function")
+	}
+	view, err := sw.File.getSheetView(sw.Sheet, viewIndex)
+	if err != nil {
+		return err
+	}
+	return setSheetViewOptions(view, opts)
+}
+
+// SetColStyles sets the default styles of the columns for the StreamWriter
+// by given map of the column number to the style ID. The column style will be
+// applied to the cells in the column which are written without the style, and
+// the empty cells in the column. Note that SetColStyles must be called before
+// the SetRow. For example, set the bold font style for the column A and the
+// currency number format for the column B:
+//
+//    bold, err := f.NewStyle(`{"font":{"bold":true}}`)
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    currency, err := f.NewStyle(`{"number_format":164}`)
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    err = sw.SetColStyles(map[int]int{1: bold, 2: currency})
+//
+func (sw *StreamWriter) SetColStyles(styles map[int]int) error {
+	if sw.sheetWritten {
+		return errors.New("must call the SetColStyles function before the SetRow function")
+	}
+	s := sw.File.stylesReader()
+	for col, styleID := range styles {
+		if col < 1 || col > TotalColumns {
+			return fmt.Errorf("invalid column number %d", col)
+		}
+		if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+			return fmt.Errorf("invalid style ID %d", styleID)
+		}
+	}
+	if sw.colStyles == nil {
+		sw.colStyles = make(map[int]int, len(styles))
+	}
+	for col, styleID := range styles {
+		setColStyle(sw.worksheet, col, col, styleID)
+		sw.colStyles[col] = styleID
+	}
+	if sw.worksheet.Cols != nil {
+		sw.worksheet.Cols.Col = coalesceCols(sw.worksheet.Cols.Col)
+	}
+	return nil
+}
+
 // AddTable creates an Excel table for the StreamWriter using the given
 // coordinate area and format set. For example, create a table of A1:D5:
 //
@@ -141,7 +254,7 @@ func (sw *StreamWriter) AddTable(hcell, vcell, format string) error {
 		}
 	}
 
-	tableID := sw.File.countTables() + 1
+	tableID := sw.File.getNextTableID()
 
 	name := formatSet.TableName
 	if name == "" {
@@ -253,24 +366,49 @@ func getRowElement(token xml.Token, hrow int) (startElement xml.StartElement, ok
 }
 
 // Cell can be used directly in StreamWriter.SetRow to specify a style and
-// a value.
+// a value. The RawNumber specifies the preformatted numeric string which
+// will be written as the numeric cell value verbatim, such as the high
+// precision decimal "3.14159265358979323846", and the Value will be ignored
+// if the RawNumber is not empty. The string Value is always written as text,
+// use the style with the text number format "@" to prevent the text, such as
+// the UUID or long digit strings, being converted when editing in Excel:
+//
+//    text, err := f.NewStyle(`{"number_format":49}`)
+//    err = sw.SetRow("A1", []interface{}{excelize.Cell{StyleID: text, Value: "550e8400-e29b-41d4-a716-446655440000"}})
+//
 type Cell struct {
-	StyleID int
-	Value   interface{}
+	StyleID   int
+	Value     interface{}
+	RawNumber string
 }
 
+// rawNumberExp defined the regular expression of the preformatted numeric
+// string of the cell.
+var rawNumberExp = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+
 // SetRow writes an array to stream rows by giving a worksheet name, starting
 // coordinate and a pointer to an array of values. Note that you must call the
 // 'Flush' method to end the streaming writing process.
 //
 // As a special case, if Cell is used as a value, then the Cell.StyleID will be
-// applied to that cell.
+// applied to that cell, otherwise the column style set by SetColStyles will be
+// applied. The rows must be written in ascending order, and the
+// row which has already been written or skipped can't be written again. The
+// CellError value will be written as the error value of the cell, for example:
+//
+//    err := sw.SetRow("A1", []interface{}{1, excelize.CellError("#DIV/0!")})
+//
 func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
 		return err
 	}
-
+	if row <= sw.rows {
+		return fmt.Errorf("row %d has already been written", row)
+	}
+	sw.rows = row
+	sw.writeSheetData()
+	date1904 := sw.File.isDate1904()
 	fmt.Fprintf(&sw.rawData, `<row r="%d">`, row)
 	for i, val := range values {
 		axis, err := CoordinatesToCellName(col+i, row)
@@ -278,14 +416,28 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 			return err
 		}
 		c := xlsxC{R: axis}
+		var rawNumber string
 		if v, ok := val.(Cell); ok {
-			c.S = v.StyleID
-			val = v.Value
+			c.S, val, rawNumber = v.StyleID, v.Value, v.RawNumber
 		} else if v, ok := val.(*Cell); ok && v != nil {
-			c.S = v.StyleID
-			val = v.Value
+			c.S, val, rawNumber = v.StyleID, v.Value, v.RawNumber
+		}
+		if c.S == 0 {
+			c.S = sw.colStyles[col+i]
+		}
+		switch v := val.(type) {
+		case float32:
+			val = float32(sw.File.roundSignificantDigits(float64(v), 32))
+		case float64:
+			val = sw.File.roundSignificantDigits(v, 64)
 		}
-		if err = setCellValFunc(&c, val); err != nil {
+		if rawNumber != "" {
+			if !rawNumberExp.MatchString(rawNumber) {
+				sw.rawData.WriteString(`</row>`)
+				return fmt.Errorf("invalid raw number %q in cell %s", rawNumber, axis)
+			}
+			c.V = rawNumber
+		} else if err = setCellValFunc(&c, val, date1904); err != nil {
 			sw.rawData.WriteString(`</row>`)
 			return err
 		}
@@ -295,8 +447,83 @@ func (sw *StreamWriter) SetRow(axis string, values []interface{}) error {
 	return sw.rawData.Sync()
 }
 
-// setCellValFunc provides a function to set value of a cell.
-func setCellValFunc(c *xlsxC, val interface{}) (err error) {
+// SkipRows skips the given number of rows after the last row written or
+// skipped by the StreamWriter, the next row could be written after the
+// skipped rows. The skipped rows will not be written in the worksheet, so
+// that they will be blank rows without the custom height and style. For
+// example, write the data in the row 1 and 4, and leave the row 2 and 3
+// blank:
+//
+//    err := sw.SetRow("A1", []interface{}{"Section 1"})
+//    err = sw.SkipRows(2)
+//    err = sw.SetRow("A4", []interface{}{"Section 2"})
+//
+func (sw *StreamWriter) SkipRows(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid number of rows to skip %d", n)
+	}
+	if sw.rows+n > TotalRows {
+		return newInvalidRowNumberError(sw.rows + n)
+	}
+	sw.rows += n
+	return nil
+}
+
+// InsertPageBreak create a page break to determine where the printed page
+// ends and where begins the next one by given cell reference, the content
+// before the page break will be printed on one page and after the page break
+// on another. The row break will be inserted above the row of the cell and
+// the column break will be inserted on the left of the column of the cell,
+// and the duplicate page breaks will be ignored. Note that the row breaks
+// and the column breaks are limited to 1026 respectively. For example,
+// insert a row break above the row 20 while streaming:
+//
+//    err := sw.InsertPageBreak("A20")
+//
+func (sw *StreamWriter) InsertPageBreak(cell string) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	if row > 1 {
+		if sw.worksheet.RowBreaks, err = insertPageBreak(sw.worksheet.RowBreaks, row-1, TotalColumns-1); err != nil {
+			return err
+		}
+	}
+	if col > 1 {
+		sw.worksheet.ColBreaks, err = insertPageBreak(sw.worksheet.ColBreaks, col-1, TotalRows-1)
+	}
+	return err
+}
+
+// Spill writes the rows in the in-memory buffer of the StreamWriter to the
+// temp file immediately, rather than waiting for the buffer to grow large
+// enough. The temp file will be created if needed, and the subsequent rows
+// will be spilled to the same temp file. This could be used to reduce the
+// memory usage before entering a memory-pressured phase.
+func (sw *StreamWriter) Spill() error {
+	return sw.rawData.Spill()
+}
+
+// SetRowWithEndCell writes an array to stream rows like SetRow, and returns
+// the reference of the last cell written in the row. An empty string will be
+// returned if the array is empty. For example, write 3 values from B5 and get
+// the ending cell reference "D5":
+//
+//    endCell, err := sw.SetRowWithEndCell("B5", []interface{}{1, 2, 3})
+//
+func (sw *StreamWriter) SetRowWithEndCell(axis string, values []interface{}) (string, error) {
+	if err := sw.SetRow(axis, values); err != nil || len(values) == 0 {
+		return "", err
+	}
+	col, row, _ := CellNameToCoordinates(axis)
+	return CoordinatesToCellName(col+len(values)-1, row)
+}
+
+// setCellValFunc provides a function to set value of a cell, the time value
+// will be converted to the serial number in the given date system.
+func setCellValFunc(c *xlsxC, val interface{}, date1904 bool) (err error) {
+	c.XMLSpace = xml.Attr{}
 	switch val := val.(type) {
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		err = setCellIntFunc(c, val)
@@ -311,9 +538,14 @@ func setCellValFunc(c *xlsxC, val interface{}) (err error) {
 	case time.Duration:
 		c.T, c.V = setCellDuration(val)
 	case time.Time:
-		c.T, c.V, _, err = setCellTime(val)
+		c.T, c.V, _, err = setCellTime(val, date1904)
 	case bool:
 		c.T, c.V = setCellBool(val)
+	case CellError:
+		var t, v string
+		if t, v, err = setCellError(val); err == nil {
+			c.T, c.V = t, v
+		}
 	case nil:
 		c.T, c.V, c.XMLSpace = setCellStr("")
 	default:
@@ -373,9 +605,12 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
+	sw.writeSheetData()
 	sw.rawData.WriteString(`</sheetData>`)
+	sw.worksheet.writeUnknownElements(&sw.rawData, "sheetData")
 	bulkAppendFields(&sw.rawData, sw.worksheet, 7, 37)
 	sw.rawData.WriteString(sw.tableParts)
+	sw.worksheet.writeUnknownElements(&sw.rawData, "tableParts")
 	bulkAppendFields(&sw.rawData, sw.worksheet, 39, 39)
 	sw.rawData.WriteString(`</worksheet>`)
 	if err := sw.rawData.Flush(); err != nil {
@@ -395,14 +630,45 @@ func (sw *StreamWriter) Flush() error {
 	return nil
 }
 
+// writeSheetData provides a function to write the worksheet fields before
+// the sheetData element, and the start tag of the sheetData element once. The
+// XML declaration and the start tag of the worksheet will be written first.
+func (sw *StreamWriter) writeSheetData() {
+	if !sw.sheetWritten {
+		if sw.declaration == "" {
+			sw.declaration = XMLHeader
+		}
+		sw.rawData.WriteString(sw.declaration + `<worksheet` + templateNamespaceIDMap)
+		sw.worksheet.writeUnknownElements(&sw.rawData, "")
+		bulkAppendFields(&sw.rawData, sw.worksheet, 1, 5)
+		sw.rawData.WriteString(`<sheetData>`)
+		sw.sheetWritten = true
+	}
+}
+
 // bulkAppendFields bulk-appends fields in a worksheet by specified field
-// names order range.
+// names order range. The fields will be encoded with the element names in the
+// struct tags, since some types are shared by different elements, such as
+// the rowBreaks and colBreaks. The unknown elements of the worksheet will be
+// written after the fields before them.
 func bulkAppendFields(w io.Writer, ws *xlsxWorksheet, from, to int) {
 	s := reflect.ValueOf(ws).Elem()
 	enc := xml.NewEncoder(w)
 	for i := 0; i < s.NumField(); i++ {
 		if from <= i && i <= to {
-			enc.Encode(s.Field(i).Interface())
+			name := strings.Split(s.Type().Field(i).Tag.Get("xml"), ",")[0]
+			enc.EncodeElement(s.Field(i).Interface(), xml.StartElement{Name: xml.Name{Local: name}})
+			ws.writeUnknownElements(w, name)
+		}
+	}
+}
+
+// writeUnknownElements provides a function to write the raw XML of the
+// unknown elements of the worksheet after the element with the given name.
+func (ws *xlsxWorksheet) writeUnknownElements(w io.Writer, after string) {
+	for _, element := range ws.Unknown {
+		if element.After == after {
+			_, _ = io.WriteString(w, element.Content)
 		}
 	}
 }
@@ -490,6 +756,17 @@ func (bw *bufferedWriter) Sync() (err error) {
 	return bw.Flush()
 }
 
+// Spill will write the in-memory buffer to a temp file immediately, and the
+// temp file will be created if it doesn't exist. Any error will be returned.
+func (bw *bufferedWriter) Spill() (err error) {
+	if bw.tmp == nil {
+		if bw.tmp, err = ioutil.TempFile(os.TempDir(), "excelize-"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
 // Flush the entire in-memory buffer to the temp file, if a temp file is being
 // used.
 func (bw *bufferedWriter) Flush() error {
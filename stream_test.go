@@ -1,6 +1,7 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -142,6 +143,60 @@ func TestNewStreamWriter(t *testing.T) {
 	assert.EqualError(t, err, "sheet SheetN is not exist")
 }
 
+func TestStreamSetSheetViewOptions(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetSheetViewOptions(0, ShowGridLines(false), ShowRowColHeaders(false), ShowZeros(false), ZoomScale(80)))
+	assert.EqualError(t, streamWriter.SetSheetViewOptions(0, ZoomScale(401)), "zoom scale 401 out of range")
+	assert.EqualError(t, streamWriter.SetSheetViewOptions(1), "view index 1 out of range")
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{0}))
+	assert.EqualError(t, streamWriter.SetSheetViewOptions(0, ShowGridLines(true)), "must call the SetSheetViewOptions function before the SetRow function")
+	assert.NoError(t, streamWriter.Flush())
+	assert.Contains(t, string(file.XLSX["xl/worksheets/sheet1.xml"]), `<sheetView showGridLines="false" showRowColHeaders="false" showZeros="false" tabSelected="true" zoomScale="80" workbookViewId="0"></sheetView>`)
+
+	// Test set sheet view options on the worksheet without rows.
+	file = NewFile()
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetSheetViewOptions(-1, ShowGridLines(false)))
+	assert.NoError(t, streamWriter.Flush())
+	assert.Contains(t, string(file.XLSX["xl/worksheets/sheet1.xml"]), `<sheetView showGridLines="false" tabSelected="true" workbookViewId="0"></sheetView></sheetViews><sheetFormatPr defaultRowHeight="15"></sheetFormatPr><sheetData></sheetData>`)
+}
+
+func TestStreamSetXMLDeclaration(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Data"}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.True(t, bytes.HasPrefix(file.XLSX["xl/worksheets/sheet1.xml"], []byte(XMLHeader+`<worksheet`)))
+
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetXMLDeclaration(XMLDeclaration{Encoding: "utf-8", Standalone: "no", BOM: true}))
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Data"}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.True(t, bytes.HasPrefix(file.XLSX["xl/worksheets/sheet1.xml"], []byte("\xef\xbb\xbf<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"no\"?>\n<worksheet")))
+	val, err := file.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Data", val)
+
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetXMLDeclaration(XMLDeclaration{}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.True(t, bytes.HasPrefix(file.XLSX["xl/worksheets/sheet1.xml"], []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<worksheet")))
+
+	// Test set XML declaration with invalid settings.
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, streamWriter.SetXMLDeclaration(XMLDeclaration{Encoding: "UTF-16"}), "unsupported encoding UTF-16, only UTF-8 is supported")
+	assert.EqualError(t, streamWriter.SetXMLDeclaration(XMLDeclaration{Standalone: "true"}), "invalid standalone document declaration true")
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Data"}))
+	assert.EqualError(t, streamWriter.SetXMLDeclaration(XMLDeclaration{}), "must call the SetXMLDeclaration function before the SetRow function")
+}
+
 func TestSetRow(t *testing.T) {
 	// Test error exceptions
 	file := NewFile()
@@ -150,25 +205,216 @@ func TestSetRow(t *testing.T) {
 	assert.EqualError(t, streamWriter.SetRow("A", []interface{}{}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 }
 
+func TestStreamWriterXMLSpace(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Hello", " Hello", "Hello\t", 1}))
+	assert.NoError(t, streamWriter.Flush())
+	sheetXML := string(file.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, `<c r="A1" t="str"><v>Hello</v></c>`)
+	assert.Contains(t, sheetXML, `<c xml:space="preserve" r="B1" t="str"><v> Hello</v></c>`)
+	assert.Contains(t, sheetXML, `<c xml:space="preserve" r="C1" t="str"><v>Hello&#x9;</v></c>`)
+	assert.Contains(t, sheetXML, `<c r="D1"><v>1</v></c>`)
+
+	// Test write the cell with the control characters.
+	file = NewFile()
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"a\x00b\x0bc"}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.Contains(t, string(file.XLSX["xl/worksheets/sheet1.xml"]), `<c r="A1" t="str"><v>a_x0000_b_x000B_c</v></c>`)
+	val, err := file.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "a\x00b\x0bc", val)
+}
+
+func TestSkipRows(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Section 1"}))
+	assert.NoError(t, streamWriter.SkipRows(2))
+	assert.EqualError(t, streamWriter.SetRow("A3", []interface{}{"Section 2"}), "row 3 has already been written")
+	assert.NoError(t, streamWriter.SetRow("A4", []interface{}{"Section 2"}))
+	assert.EqualError(t, streamWriter.SetRow("A4", []interface{}{"Section 2"}), "row 4 has already been written")
+	assert.NoError(t, streamWriter.SkipRows(0))
+	assert.EqualError(t, streamWriter.SkipRows(-1), "invalid number of rows to skip -1")
+	assert.EqualError(t, streamWriter.SkipRows(TotalRows), "invalid row number 1048580")
+	assert.NoError(t, streamWriter.Flush())
+	assert.Contains(t, string(file.XLSX["xl/worksheets/sheet1.xml"]), `<sheetData><row r="1"><c r="A1" t="str"><v>Section 1</v></c></row><row r="4"><c r="A4" t="str"><v>Section 2</v></c></row></sheetData>`)
+	rows, err := file.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Section 1"}, nil, nil, {"Section 2"}}, rows)
+}
+
+func TestStreamSpill(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Data"}))
+	assert.NoError(t, streamWriter.Spill())
+	assert.NotNil(t, streamWriter.rawData.tmp)
+	assert.Zero(t, streamWriter.rawData.buf.Len())
+	tmp := streamWriter.rawData.tmp.Name()
+	// Test spill again after the temp file has been created.
+	assert.NoError(t, streamWriter.Spill())
+	assert.NoError(t, streamWriter.SetRow("A2", []interface{}{"Data"}))
+	assert.NoError(t, streamWriter.Spill())
+	assert.Equal(t, tmp, streamWriter.rawData.tmp.Name())
+	assert.NoError(t, streamWriter.Flush())
+	_, err = os.Stat(tmp)
+	assert.True(t, os.IsNotExist(err))
+	rows, err := file.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Data"}, {"Data"}}, rows)
+
+	// Test spill with the closed temp file.
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.Spill())
+	assert.NoError(t, streamWriter.rawData.Close())
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Data"}))
+	assert.Error(t, streamWriter.Spill())
+}
+
+func TestStreamRawNumber(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	styleID, err := file.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{
+		Cell{RawNumber: "3.14159265358979323846"},
+		&Cell{StyleID: styleID, RawNumber: "-1.5E+300", Value: "ignored"},
+		Cell{RawNumber: ".5"},
+	}))
+	for i, raw := range []string{"abc", "1.2.3", "NaN", "Inf", "0x1p-2", "1e", "-"} {
+		cell, err := CoordinatesToCellName(1, i+2)
+		assert.NoError(t, err)
+		assert.EqualError(t, streamWriter.SetRow(cell, []interface{}{Cell{RawNumber: raw}}), fmt.Sprintf("invalid raw number %q in cell %s", raw, cell))
+	}
+	assert.NoError(t, streamWriter.Flush())
+	sheetXML := string(file.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, fmt.Sprintf(`<c r="A1"><v>3.14159265358979323846</v></c><c r="B1" s="%d"><v>-1.5E+300</v></c><c r="C1"><v>.5</v></c>`, styleID))
+	val, err := file.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.14159265358979323846", val)
+}
+
+func TestStreamCellError(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{CellError("#N/A"), Cell{Value: CellError("#REF!")}}))
+	assert.EqualError(t, streamWriter.SetRow("A2", []interface{}{CellError("#ERR")}), "unsupported cell error value #ERR")
+	assert.NoError(t, streamWriter.Flush())
+	sheetXML := string(file.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, `<c r="A1" t="e"><v>#N/A</v></c><c r="B1" t="e"><v>#REF!</v></c>`)
+}
+
+func TestSetRowWithEndCell(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	endCell, err := streamWriter.SetRowWithEndCell("B5", []interface{}{1, "2", nil})
+	assert.NoError(t, err)
+	assert.Equal(t, "D5", endCell)
+	endCell, err = streamWriter.SetRowWithEndCell("Z6", make([]interface{}, 27))
+	assert.NoError(t, err)
+	assert.Equal(t, "AZ6", endCell)
+	endCell, err = streamWriter.SetRowWithEndCell("A7", []interface{}{})
+	assert.NoError(t, err)
+	assert.Empty(t, endCell)
+	// Test set row with invalid cell coordinates.
+	_, err = streamWriter.SetRowWithEndCell("A", []interface{}{1})
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.NoError(t, streamWriter.Flush())
+}
+
+func TestStreamInsertPageBreak(t *testing.T) {
+	file := NewFile()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Section 1"}))
+	assert.NoError(t, streamWriter.InsertPageBreak("A1"))
+	assert.NoError(t, streamWriter.InsertPageBreak("C20"))
+	assert.NoError(t, streamWriter.InsertPageBreak("A10"))
+	assert.NoError(t, streamWriter.InsertPageBreak("B10"))
+	// Test insert the duplicate page breaks.
+	assert.NoError(t, streamWriter.InsertPageBreak("A20"))
+	assert.NoError(t, streamWriter.InsertPageBreak("C1"))
+	assert.NoError(t, streamWriter.SetRow("A20", []interface{}{"Section 2"}))
+	assert.EqualError(t, streamWriter.InsertPageBreak("A"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.NoError(t, streamWriter.Flush())
+	sheetXML := string(file.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, `</sheetData>`+
+		`<rowBreaks count="2" manualBreakCount="2"><brk id="9" max="16383" man="true"></brk><brk id="19" max="16383" man="true"></brk></rowBreaks>`+
+		`<colBreaks count="2" manualBreakCount="2"><brk id="1" max="1048575" man="true"></brk><brk id="2" max="1048575" man="true"></brk></colBreaks></worksheet>`)
+	rows, err := file.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 20)
+
+	// Test insert page breaks exceeds the limit.
+	streamWriter, err = file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for row := 2; row <= maxPageBreaks+1; row++ {
+		cell, err := CoordinatesToCellName(1, row)
+		assert.NoError(t, err)
+		assert.NoError(t, streamWriter.InsertPageBreak(cell))
+	}
+	assert.NoError(t, streamWriter.InsertPageBreak("A2"))
+	assert.NoError(t, streamWriter.InsertPageBreak("B2"))
+	assert.EqualError(t, streamWriter.InsertPageBreak("A2000"), "the number of page breaks exceeds the limit 1026")
+	assert.NoError(t, streamWriter.Flush())
+}
+
+func TestStreamSetColStyles(t *testing.T) {
+	file := NewFile()
+	bold, err := file.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	currency, err := file.NewStyle(`{"number_format":164}`)
+	assert.NoError(t, err)
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, streamWriter.SetColStyles(map[int]int{0: bold}), "invalid column number 0")
+	assert.EqualError(t, streamWriter.SetColStyles(map[int]int{TotalColumns + 1: bold}), "invalid column number 16385")
+	assert.EqualError(t, streamWriter.SetColStyles(map[int]int{1: 100}), "invalid style ID 100")
+	assert.NoError(t, streamWriter.SetColStyles(map[int]int{1: bold, 3: currency}))
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Name", "Note", 1.5, Cell{StyleID: bold, Value: 2}}))
+	assert.NoError(t, streamWriter.SetRow("B2", []interface{}{"Note", Cell{StyleID: bold, Value: 3}}))
+	assert.EqualError(t, streamWriter.SetColStyles(map[int]int{1: bold}), "must call the SetColStyles function before the SetRow function")
+	assert.NoError(t, streamWriter.Flush())
+	sheetXML := string(file.XLSX["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheetXML, fmt.Sprintf(`<cols><col max="1" min="1" style="%d" width="9"></col><col max="3" min="3" style="%d" width="9"></col></cols><sheetData>`, bold, currency))
+	for cell, expected := range map[string]int{"A1": bold, "B1": 0, "C1": currency, "D1": bold, "B2": 0, "C2": bold, "A3": bold, "C3": currency} {
+		styleID, err := file.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+}
+
 func TestSetCellValFunc(t *testing.T) {
 	c := &xlsxC{}
-	assert.NoError(t, setCellValFunc(c, 128))
-	assert.NoError(t, setCellValFunc(c, int8(-128)))
-	assert.NoError(t, setCellValFunc(c, int16(-32768)))
-	assert.NoError(t, setCellValFunc(c, int32(-2147483648)))
-	assert.NoError(t, setCellValFunc(c, int64(-9223372036854775808)))
-	assert.NoError(t, setCellValFunc(c, uint(128)))
-	assert.NoError(t, setCellValFunc(c, uint8(255)))
-	assert.NoError(t, setCellValFunc(c, uint16(65535)))
-	assert.NoError(t, setCellValFunc(c, uint32(4294967295)))
-	assert.NoError(t, setCellValFunc(c, uint64(18446744073709551615)))
-	assert.NoError(t, setCellValFunc(c, float32(100.1588)))
-	assert.NoError(t, setCellValFunc(c, float64(100.1588)))
-	assert.NoError(t, setCellValFunc(c, " Hello"))
-	assert.NoError(t, setCellValFunc(c, []byte(" Hello")))
-	assert.NoError(t, setCellValFunc(c, time.Now().UTC()))
-	assert.NoError(t, setCellValFunc(c, time.Duration(1e13)))
-	assert.NoError(t, setCellValFunc(c, true))
-	assert.NoError(t, setCellValFunc(c, nil))
-	assert.NoError(t, setCellValFunc(c, complex64(5+10i)))
+	assert.NoError(t, setCellValFunc(c, 128, false))
+	assert.NoError(t, setCellValFunc(c, int8(-128), false))
+	assert.NoError(t, setCellValFunc(c, int16(-32768), false))
+	assert.NoError(t, setCellValFunc(c, int32(-2147483648), false))
+	assert.NoError(t, setCellValFunc(c, int64(-9223372036854775808), false))
+	assert.NoError(t, setCellValFunc(c, uint(128), false))
+	assert.NoError(t, setCellValFunc(c, uint8(255), false))
+	assert.NoError(t, setCellValFunc(c, uint16(65535), false))
+	assert.NoError(t, setCellValFunc(c, uint32(4294967295), false))
+	assert.NoError(t, setCellValFunc(c, uint64(18446744073709551615), false))
+	assert.NoError(t, setCellValFunc(c, float32(100.1588), false))
+	assert.NoError(t, setCellValFunc(c, float64(100.1588), false))
+	assert.NoError(t, setCellValFunc(c, " Hello", false))
+	assert.NoError(t, setCellValFunc(c, []byte(" Hello"), false))
+	assert.Equal(t, "preserve", c.XMLSpace.Value)
+	assert.NoError(t, setCellValFunc(c, time.Now().UTC(), false))
+	assert.NoError(t, setCellValFunc(c, time.Duration(1e13), false))
+	assert.NoError(t, setCellValFunc(c, true, false))
+	assert.Empty(t, c.XMLSpace.Value)
+	assert.NoError(t, setCellValFunc(c, nil, false))
+	assert.NoError(t, setCellValFunc(c, complex64(5+10i), false))
 }
@@ -13,12 +13,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // Excel styles can reference number formats that are built-in, all of which
@@ -749,9 +754,53 @@ var currencyNumFmt = map[int]string{
 	634: "[$ZWR]\\ #,##0.00",
 }
 
+var (
+	// numFmtConditionRe defined the regular expression of the condition in
+	// the number format code section, such as "[<=9999999]".
+	numFmtConditionRe = regexp.MustCompile(`\[(<=|>=|<>|<|>|=)(-?[0-9.]+)\]`)
+	// numFmtFractionRe defined the regular expression of the fraction
+	// placeholders in the number format code section, such as "# ?/?".
+	numFmtFractionRe = regexp.MustCompile(`(?:([#0?,]+)(\s+))?([#0?]+)/([#0?]+|[1-9][0-9]*)`)
+	// numFmtLocaleRe defined the regular expression of the currency and
+	// locale tag in the number format code section, such as "[$-380A]".
+	numFmtLocaleRe = regexp.MustCompile(`\[\$[^\]]*\]`)
+	// numFmtColorRe defined the regular expression of the color in the
+	// number format code section, such as "[Red]" and "[Color10]".
+	numFmtColorRe = regexp.MustCompile(`(?i)\[(black|blue|cyan|green|magenta|red|white|yellow|color\s*([0-9]+))\]`)
+	// styleRGBColorRe defined the regular expression of the RGB color code
+	// in the style settings, such as "A020F0" and "#A020F0".
+	styleRGBColorRe = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+)
+
+// numFmtColors defined the RGB color code of the color names which used in
+// the number format code.
+var numFmtColors = map[string]string{
+	"black":   "000000",
+	"blue":    "0000FF",
+	"cyan":    "00FFFF",
+	"green":   "00FF00",
+	"magenta": "FF00FF",
+	"red":     "FF0000",
+	"white":   "FFFFFF",
+	"yellow":  "FFFF00",
+}
+
+// indexedColorMapping defined the RGB color code of the legacy indexed color
+// palette.
+var indexedColorMapping = []string{
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"800000", "008000", "000080", "808000", "800080", "008080", "C0C0C0", "808080",
+	"9999FF", "993366", "FFFFCC", "CCFFFF", "660066", "FF8080", "0066CC", "CCCCFF",
+	"000080", "FF00FF", "FFFF00", "00FFFF", "800080", "800000", "008080", "0000FF",
+	"00CCFF", "CCFFFF", "CCFFCC", "FFFF99", "99CCFF", "FF99CC", "CC99FF", "FFCC99",
+	"3366FF", "33CCCC", "99CC00", "FFCC00", "FF9900", "FF6600", "666699", "969696",
+	"003366", "339966", "003300", "333300", "993300", "993366", "333399", "333333",
+}
+
 // builtInNumFmtFunc defined the format conversion functions map. Partial format
 // code doesn't support currently and will return original string.
-var builtInNumFmtFunc = map[int]func(i int, v string) string{
+var builtInNumFmtFunc = map[int]func(i int, v string, date1904 bool) string{
 	0:  formatToString,
 	1:  formatToInt,
 	2:  formatToFloat,
@@ -760,8 +809,8 @@ var builtInNumFmtFunc = map[int]func(i int, v string) string{
 	9:  formatToC,
 	10: formatToD,
 	11: formatToE,
-	12: formatToString, // Doesn't support currently
-	13: formatToString, // Doesn't support currently
+	12: formatToE,
+	13: formatToE,
 	14: parseTime,
 	15: parseTime,
 	16: parseTime,
@@ -805,9 +854,46 @@ var validType = map[string]string{
 	"2_color_scale": "2_color_scale",
 	"3_color_scale": "3_color_scale",
 	"data_bar":      "dataBar",
+	"icon_set":      "iconSet",
 	"formula":       "expression",
 }
 
+// iconSetStyles defined the list of valid icon set styles and the number of
+// icons in each of them.
+var iconSetStyles = map[string]int{
+	"3Arrows":         3,
+	"3ArrowsGray":     3,
+	"3Flags":          3,
+	"3TrafficLights1": 3,
+	"3TrafficLights2": 3,
+	"3Signs":          3,
+	"3Symbols":        3,
+	"3Symbols2":       3,
+	"4Arrows":         4,
+	"4ArrowsGray":     4,
+	"4RedToBlack":     4,
+	"4Rating":         4,
+	"4TrafficLights":  4,
+	"5Arrows":         5,
+	"5ArrowsGray":     5,
+	"5Rating":         5,
+	"5Quarters":       5,
+}
+
+// dataBarDirections defined the list of valid data bar directions.
+var dataBarDirections = map[string]string{
+	"context":       "context",
+	"left_to_right": "leftToRight",
+	"right_to_left": "rightToLeft",
+}
+
+// dataBarAxisPositions defined the list of valid data bar axis positions.
+var dataBarAxisPositions = map[string]string{
+	"automatic": "automatic",
+	"middle":    "middle",
+	"none":      "none",
+}
+
 // criteriaType defined the list of valid criteria types.
 var criteriaType = map[string]string{
 	"between":                  "between",
@@ -843,14 +929,14 @@ var criteriaType = map[string]string{
 
 // formatToString provides a function to return original string by given
 // built-in number formats code and cell string.
-func formatToString(i int, v string) string {
+func formatToString(i int, v string, date1904 bool) string {
 	return v
 }
 
 // formatToInt provides a function to convert original string to integer
 // format as string type by given built-in number formats code and cell
 // string.
-func formatToInt(i int, v string) string {
+func formatToInt(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -861,7 +947,7 @@ func formatToInt(i int, v string) string {
 // formatToFloat provides a function to convert original string to float
 // format as string type by given built-in number formats code and cell
 // string.
-func formatToFloat(i int, v string) string {
+func formatToFloat(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -871,7 +957,7 @@ func formatToFloat(i int, v string) string {
 
 // formatToA provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToA(i int, v string) string {
+func formatToA(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -886,20 +972,20 @@ func formatToA(i int, v string) string {
 
 // formatToB provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToB(i int, v string) string {
+func formatToB(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
 	}
 	if f < 0 {
-		return fmt.Sprintf("(%.2f)", f)
+		return fmt.Sprintf("(%.2f)", math.Abs(f))
 	}
 	return fmt.Sprintf("%.2f", f)
 }
 
 // formatToC provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToC(i int, v string) string {
+func formatToC(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -910,7 +996,7 @@ func formatToC(i int, v string) string {
 
 // formatToD provides a function to convert original string to special format
 // as string type by given built-in number formats code and cell string.
-func formatToD(i int, v string) string {
+func formatToD(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
@@ -919,14 +1005,15 @@ func formatToD(i int, v string) string {
 	return fmt.Sprintf("%.2f%%", f)
 }
 
-// formatToE provides a function to convert original string to special format
-// as string type by given built-in number formats code and cell string.
-func formatToE(i int, v string) string {
+// formatToE provides a function to convert original string to scientific or
+// fraction format as string type by given built-in number formats code and
+// cell string.
+func formatToE(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
 	}
-	return fmt.Sprintf("%.e", f)
+	return applyNumFmtCode(f, builtInNumFmt[i], date1904)
 }
 
 // parseTime provides a function to returns a string parsed using time.Time.
@@ -940,14 +1027,17 @@ func formatToE(i int, v string) string {
 // arbitrary characters unused in Excel Date formats, and then at the end,
 // turn them to what they should actually be. Based off:
 // http://www.ozgrid.com/Excel/CustomFormats.htm
-func parseTime(i int, v string) string {
+func parseTime(i int, v string, date1904 bool) string {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
 		return v
 	}
-	val := timeFromExcelTime(f, false)
-	format := builtInNumFmt[i]
+	return formatExcelTime(timeFromExcelTime(f, date1904), builtInNumFmt[i])
+}
 
+// formatExcelTime provides a function to format the time by given Excel date
+// and time format code.
+func formatExcelTime(val time.Time, format string) string {
 	replacements := []struct{ xltime, gotime string }{
 		{"yyyy", "2006"},
 		{"yy", "06"},
@@ -962,6 +1052,7 @@ func parseTime(i int, v string) string {
 		{":mm", ":04"},
 		{"mm", "01"},
 		{"am/pm", "pm"},
+		{"AM/PM", "PM"},
 		{"m/", "1/"},
 		{"%%%%", "January"},
 		{"&&&&", "Monday"},
@@ -997,6 +1088,498 @@ func is12HourTime(format string) bool {
 	return strings.Contains(format, "am/pm") || strings.Contains(format, "AM/PM") || strings.Contains(format, "a/p") || strings.Contains(format, "A/P")
 }
 
+// splitNumFmtCode provides a function to split the number format code into
+// sections by semicolon, the semicolons in quoted text are ignored.
+func splitNumFmtCode(code string) []string {
+	var (
+		sections []string
+		section  strings.Builder
+		quoted   bool
+	)
+	runes := []rune(code)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '"':
+			quoted = !quoted
+		case r == '\\' && !quoted && i+1 < len(runes):
+			section.WriteRune(r)
+			i++
+		case r == ';' && !quoted:
+			sections = append(sections, section.String())
+			section.Reset()
+			continue
+		}
+		section.WriteRune(runes[i])
+	}
+	return append(sections, section.String())
+}
+
+// isDateTimeNumFmt checks if the number format code section contains date or
+// time placeholders.
+func isDateTimeNumFmt(section string) bool {
+	var quoted bool
+	runes := []rune(section)
+	for i := 0; i < len(runes); i++ {
+		switch r := unicode.ToLower(runes[i]); {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '\\' || r == '_' || r == '*':
+			i++
+		case r == '[':
+			j := i
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if elapsed := strings.ToLower(string(runes[i:j])); elapsed == "[h" || elapsed == "[hh" || elapsed == "[m" || elapsed == "[mm" || elapsed == "[s" || elapsed == "[ss" {
+				return true
+			}
+			i = j
+		case strings.ContainsRune("ydhms", r):
+			return true
+		}
+	}
+	return false
+}
+
+// applyNumFmtCode provides a function to format the number by given number
+// format code, such as "#,##0.00", "0%", "0.00E+00" and "yyyy-mm-dd". The
+// sections of the code for positive, negative and zero are supported, only
+// the commonly used placeholders are supported currently.
+func applyNumFmtCode(value float64, code string, date1904 bool) string {
+	section, value, negative := selectNumFmtSection(value, splitNumFmtCode(code))
+	switch trimmed := strings.TrimSpace(section); {
+	case strings.EqualFold(trimmed, "general"), trimmed == "@":
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case isDateTimeNumFmt(section):
+		section = numFmtColorRe.ReplaceAllString(numFmtLocaleRe.ReplaceAllString(section, ""), "")
+		format := strings.ToLower(strings.NewReplacer("\"", "", "\\", "").Replace(section))
+		format = strings.Replace(format, "am/pm", "AM/PM", -1)
+		if !strings.Contains(section, "AM/PM") {
+			format = strings.Replace(format, "AM/PM", "am/pm", -1)
+		}
+		return formatExcelTime(timeFromExcelTime(value, date1904), format)
+	}
+	var sign string
+	if negative {
+		sign = "-"
+	}
+	if loc := numFmtFractionRe.FindStringSubmatchIndex(section); loc != nil {
+		var intPattern, separator string
+		if loc[2] != -1 {
+			intPattern, separator = section[loc[2]:loc[3]], section[loc[4]:loc[5]]
+		}
+		return sign + formatNumFmtLiteral(section[:loc[0]]) +
+			formatNumFmtFraction(math.Abs(value), intPattern, separator, section[loc[6]:loc[7]], section[loc[8]:loc[9]]) +
+			formatNumFmtLiteral(section[loc[1]:])
+	}
+	if tokens := parseNumFmtDigitTemplate(section); tokens != nil {
+		return sign + formatNumFmtDigitTemplate(math.Abs(value), tokens)
+	}
+	var (
+		prefix, pattern, suffix strings.Builder
+		state, percent          int // state of the pattern: 0 before, 1 in, 2 after
+	)
+	runes := []rune(section)
+	for i := 0; i < len(runes); i++ {
+		r, lit := runes[i], &prefix
+		if state > 0 {
+			lit = &suffix
+		}
+		switch {
+		case state < 2 && strings.ContainsRune("0#?", r),
+			state == 1 && (r == '.' || r == ','),
+			state == 0 && r == '.' && i+1 < len(runes) && strings.ContainsRune("0#?", runes[i+1]):
+			state = 1
+			pattern.WriteRune(r)
+			continue
+		case state == 1 && (r == 'E' || r == 'e') && i+1 < len(runes) && (runes[i+1] == '+' || runes[i+1] == '-'):
+			pattern.WriteString("E" + string(runes[i+1]))
+			i++
+			continue
+		}
+		if state == 1 {
+			state, lit = 2, &suffix
+		}
+		switch r {
+		case '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			lit.WriteString(string(runes[i+1 : j]))
+			i = j
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				lit.WriteRune(runes[i])
+			}
+		case '_':
+			i++
+			lit.WriteRune(' ')
+		case '*':
+			i++
+		case '[':
+			j := i
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if content := string(runes[i+1 : j]); strings.HasPrefix(content, "$") {
+				lit.WriteString(strings.SplitN(content[1:], "-", 2)[0])
+			}
+			i = j
+		case '%':
+			percent++
+			lit.WriteRune(r)
+		default:
+			lit.WriteRune(r)
+		}
+	}
+	value = math.Abs(value) * math.Pow(100, float64(percent))
+	if pattern.Len() == 0 {
+		return sign + prefix.String() + suffix.String()
+	}
+	return sign + prefix.String() + formatNumFmtPattern(value, pattern.String()) + suffix.String()
+}
+
+// selectNumFmtSection provides a function to select the section of the
+// number format code which will be applied to the value, the conditions in
+// the sections such as "[<=9999999]" are supported. The returned value and
+// whether to show the minus sign are adjusted for the selected section.
+func selectNumFmtSection(value float64, sections []string) (string, float64, bool) {
+	conditional := numFmtConditionRe.MatchString(sections[0]) ||
+		(len(sections) > 1 && numFmtConditionRe.MatchString(sections[1]))
+	if conditional {
+		for i := 0; i < len(sections) && i < 3; i++ {
+			matches := numFmtConditionRe.FindStringSubmatch(sections[i])
+			if matches == nil {
+				return sections[i], value, value < 0
+			}
+			if matchNumFmtCondition(value, matches[1], matches[2]) {
+				return numFmtConditionRe.ReplaceAllString(sections[i], ""), value, value < 0
+			}
+		}
+		return numFmtConditionRe.ReplaceAllString(sections[0], ""), value, value < 0
+	}
+	switch {
+	case value < 0 && len(sections) > 1:
+		return sections[1], math.Abs(value), false
+	case value == 0 && len(sections) > 2:
+		return sections[2], value, false
+	}
+	return sections[0], value, value < 0
+}
+
+// getNumFmtColor provides a function to get the RGB color code of the color
+// in the number format code section which will be applied to the value, such
+// as "#FF0000" for "[Red]". Returns empty string if the section doesn't
+// contain color.
+func getNumFmtColor(value float64, code string) string {
+	section, _, _ := selectNumFmtSection(value, splitNumFmtCode(code))
+	matches := numFmtColorRe.FindStringSubmatch(section)
+	if matches == nil {
+		return ""
+	}
+	if matches[2] != "" {
+		idx, _ := strconv.Atoi(matches[2])
+		if idx < 1 || idx > 56 {
+			return ""
+		}
+		return "#" + indexedColorMapping[idx+7]
+	}
+	return "#" + numFmtColors[strings.ToLower(matches[1])]
+}
+
+// matchNumFmtCondition checks if the value matches the condition of the
+// number format code section by given operator and operand.
+func matchNumFmtCondition(value float64, operator, operand string) bool {
+	num, err := strconv.ParseFloat(operand, 64)
+	if err != nil {
+		return false
+	}
+	switch operator {
+	case "<":
+		return value < num
+	case "<=":
+		return value <= num
+	case ">":
+		return value > num
+	case ">=":
+		return value >= num
+	case "<>":
+		return value != num
+	}
+	return value == num
+}
+
+// formatNumFmtLiteral provides a function to get the literal text of the
+// number format code which doesn't contain any digit placeholder.
+func formatNumFmtLiteral(code string) string {
+	var literal strings.Builder
+	runes := []rune(code)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			literal.WriteString(string(runes[i+1 : j]))
+			i = j
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				literal.WriteRune(runes[i])
+			}
+		case '_':
+			i++
+			literal.WriteRune(' ')
+		case '*':
+			i++
+		case '[':
+			j := i
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if j < len(runes) {
+				if content := string(runes[i+1 : j]); strings.HasPrefix(content, "$") {
+					literal.WriteString(strings.SplitN(content[1:], "-", 2)[0])
+				}
+			}
+			i = j
+		default:
+			literal.WriteRune(r)
+		}
+	}
+	return literal.String()
+}
+
+// formatNumFmtFraction provides a function to format the non-negative number
+// as fraction by given integer, numerator and denominator placeholders, such
+// as "# ?/?", "# ??/??" and "?/4". The denominator will be fixed if it is a
+// number, otherwise the closest fraction with the limited digits of the
+// denominator will be used.
+func formatNumFmtFraction(value float64, intPattern, separator, numPattern, denPattern string) string {
+	integer, fraction := 0.0, value
+	if intPattern != "" {
+		integer = math.Floor(value)
+		fraction = value - integer
+	}
+	var num, den int
+	if d, err := strconv.Atoi(denPattern); err == nil {
+		num, den = int(math.Round(fraction*float64(d))), d
+	} else {
+		num, den = approximateFraction(fraction, int(math.Pow(10, float64(len(denPattern))))-1)
+	}
+	if intPattern != "" && num >= den {
+		integer, num = integer+1, 0
+	}
+	if intPattern != "" && num == 0 {
+		intStr := formatNumFmtPattern(integer, intPattern)
+		if intStr == "" {
+			intStr = "0"
+		}
+		return intStr + strings.Repeat(" ", len(separator)+len(numPattern)+1+len(denPattern))
+	}
+	var intStr string
+	if intPattern != "" {
+		intStr = formatNumFmtPattern(integer, intPattern) + separator
+	}
+	numStr, denStr := strconv.Itoa(num), strconv.Itoa(den)
+	for len(numStr) < len(numPattern) && numPattern[0] != '#' {
+		if numPattern[0] == '?' {
+			numStr = " " + numStr
+			continue
+		}
+		numStr = "0" + numStr
+	}
+	for len(denStr) < len(denPattern) && denPattern[0] != '#' {
+		if denPattern[0] == '?' {
+			denStr += " "
+			continue
+		}
+		denStr = "0" + denStr
+	}
+	return intStr + numStr + "/" + denStr
+}
+
+// approximateFraction provides a function to get the closest fraction of the
+// number by given maximum denominator.
+func approximateFraction(value float64, maxDen int) (int, int) {
+	num, den, diff := int(math.Round(value)), 1, math.Abs(value-math.Round(value))
+	for d := 2; d <= maxDen && diff > 0; d++ {
+		n := int(math.Round(value * float64(d)))
+		if delta := math.Abs(value - float64(n)/float64(d)); delta < diff-1e-12 {
+			num, den, diff = n, d, delta
+		}
+	}
+	return num, den
+}
+
+// parseNumFmtDigitTemplate provides a function to parse the number format
+// code section which contains several groups of integer digit placeholders
+// separated by literal text, such as "000-00-0000" and "(###) ###-####".
+// Returns nil if the section isn't a digit template.
+func parseNumFmtDigitTemplate(section string) []string {
+	var (
+		tokens  []string
+		groups  int
+		literal bool
+	)
+	runes := []rune(section)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case strings.ContainsRune("0#?", r):
+			if literal || groups == 0 {
+				groups++
+			}
+			literal = false
+			tokens = append(tokens, string(r))
+			continue
+		case strings.ContainsRune(".,%/eE@", r):
+			return nil
+		case r == '"' || r == '[':
+			closing, j := '"', i+1
+			if r == '[' {
+				closing = ']'
+			}
+			for j < len(runes) && runes[j] != closing {
+				j++
+			}
+			if j == len(runes) {
+				j--
+			}
+			tokens = append(tokens, formatNumFmtLiteral(string(runes[i:j+1])))
+			i = j
+		case r == '\\' || r == '_' || r == '*':
+			if i+1 < len(runes) {
+				tokens = append(tokens, formatNumFmtLiteral(string(runes[i:i+2])))
+			}
+			i++
+		default:
+			tokens = append(tokens, string(r))
+		}
+		literal = groups > 0
+	}
+	if groups < 2 {
+		return nil
+	}
+	return tokens
+}
+
+// formatNumFmtDigitTemplate provides a function to fill the digits of the
+// non-negative number into the digit placeholders of the template from right
+// to left, the remaining digits will be placed before the first placeholder.
+func formatNumFmtDigitTemplate(value float64, tokens []string) string {
+	digits := strconv.FormatFloat(math.Round(value), 'f', 0, 64)
+	if digits == "0" {
+		digits = ""
+	}
+	first := -1
+	for i, token := range tokens {
+		if token == "0" || token == "#" || token == "?" {
+			first = i
+			break
+		}
+	}
+	result := make([]string, len(tokens))
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := tokens[i]
+		if token != "0" && token != "#" && token != "?" {
+			result[i] = token
+			continue
+		}
+		switch {
+		case i == first && len(digits) > 0:
+			result[i], digits = digits, ""
+		case len(digits) > 0:
+			result[i], digits = digits[len(digits)-1:], digits[:len(digits)-1]
+		case token == "0":
+			result[i] = "0"
+		case token == "?":
+			result[i] = " "
+		}
+	}
+	return strings.Join(result, "")
+}
+
+// formatNumFmtPattern provides a function to format the non-negative number
+// by given digit placeholders pattern of the number format code, such as
+// "#,##0.00" or "0.00E+00".
+func formatNumFmtPattern(value float64, pattern string) string {
+	if idx := strings.Index(pattern, "E"); idx != -1 {
+		mantissa, exponentPattern := pattern[:idx], pattern[idx+2:]
+		intDigits := len(strings.SplitN(strings.Replace(mantissa, ",", "", -1), ".", 2)[0])
+		if intDigits < 1 {
+			intDigits = 1
+		}
+		exponent := 0
+		if value != 0 {
+			exponent = int(math.Floor(math.Log10(value)))
+			exponent -= ((exponent % intDigits) + intDigits) % intDigits
+		}
+		m := value / math.Pow(10, float64(exponent))
+		if decimals := len(strings.SplitN(mantissa+".", ".", 3)[1]); m != 0 && roundPrecision(m, decimals) >= math.Pow(10, float64(intDigits)) {
+			m, exponent = m/math.Pow(10, float64(intDigits)), exponent+intDigits
+		}
+		sign := ""
+		if exponent < 0 {
+			sign = "-"
+		} else if pattern[idx+1] == '+' {
+			sign = "+"
+		}
+		digits := strconv.Itoa(int(math.Abs(float64(exponent))))
+		for len(digits) < len(exponentPattern) {
+			digits = "0" + digits
+		}
+		return formatNumFmtPattern(m, mantissa) + "E" + sign + digits
+	}
+	parts := strings.SplitN(pattern, ".", 2)
+	intPattern, fracPattern := parts[0], ""
+	if len(parts) > 1 {
+		fracPattern = strings.Replace(parts[1], ",", "", -1)
+	}
+	for strings.HasSuffix(intPattern, ",") {
+		value, intPattern = value/1000, intPattern[:len(intPattern)-1]
+	}
+	thousands := strings.Contains(intPattern, ",")
+	intPattern = strings.Replace(intPattern, ",", "", -1)
+	decimals := len(fracPattern)
+	minFrac := strings.Count(fracPattern, "0") + strings.Count(fracPattern, "?")
+	digits := strings.SplitN(strconv.FormatFloat(roundPrecision(value, decimals), 'f', decimals, 64), ".", 2)
+	intStr, fracStr := digits[0], ""
+	if len(digits) > 1 {
+		fracStr = digits[1]
+	}
+	for len(fracStr) > minFrac && strings.HasSuffix(fracStr, "0") {
+		fracStr = fracStr[:len(fracStr)-1]
+	}
+	minInt := strings.Count(intPattern, "0")
+	if intStr == "0" && minInt == 0 {
+		intStr = ""
+	}
+	for len(intStr) < minInt {
+		intStr = "0" + intStr
+	}
+	if thousands {
+		for i := len(intStr) - 3; i > 0; i -= 3 {
+			intStr = intStr[:i] + "," + intStr[i:]
+		}
+	}
+	if len(parts) > 1 {
+		return intStr + "." + fracStr
+	}
+	return intStr
+}
+
+// roundPrecision provides a function to round the number half away from zero
+// by given decimal places.
+func roundPrecision(value float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(value*p) / p
+}
+
 // stylesReader provides a function to get the pointer to the structure after
 // deserialization of xl/styles.xml.
 func (f *File) stylesReader() *xlsxStyleSheet {
@@ -1066,6 +1649,14 @@ func parseFormatStyleSet(style string) (*Style, error) {
 //     3     | - - - - - - | 5     | -----------
 //     1     | ----------- | 6     | ===========
 //
+// The border type must be one of left, right, top, bottom, diagonalUp and
+// diagonalDown, and the border color must be a RGB color code such as
+// "A020F0" or "#A020F0". The diagonalUp and diagonalDown borders share the
+// same diagonal line settings of the cell, so they should use the same style
+// and color. For example, create a style with crossed-out diagonal borders:
+//
+//    style, err := f.NewStyle(`{"border":[{"type":"diagonalUp","color":"#000000","style":1},{"type":"diagonalDown","color":"#000000","style":1}]}`)
+//
 // The following shows the shading styles sorted by excelize index number:
 //
 //     Index | Style           | Index | Style
@@ -1074,6 +1665,16 @@ func parseFormatStyleSet(style string) (*Style, error) {
 //     1     | Vertical        | 4     | From corner
 //     2     | Diagonal Up     | 5     | From center
 //
+// The gradient fill uses two or more colors as the gradient stops. Set the
+// angle field to create a linear gradient fill with the given angle in
+// degrees between 0 and 360, which overrides the shading style. The stops
+// field specifies the position of each color stop, the positions must be
+// between 0 and 1 in ascending order, and the stops will be spaced evenly if
+// the positions are not specified. For example, create a linear gradient fill
+// with three colors:
+//
+//    style, err := f.NewStyle(`{"fill":{"type":"gradient","color":["#FF0000","#FFFF00","#0000FF"],"angle":90,"stops":[0,0.3,1]}}`)
+//
 // The following shows the patterns styles sorted by excelize index number:
 //
 //     Index | Style           | Index | Style
@@ -1894,6 +2495,24 @@ func parseFormatStyleSet(style string) (*Style, error) {
 //
 // Cell Sheet1!A6 in the Excel Application: martes, 04 de Julio de 2017
 //
+// The colors of the font, fill and border could also reference to the theme
+// color with tint instead of the RGB color code, the theme index should be
+// in the range of 0 to 11 and the tint should be in the range of -1 to 1. For
+// example, set the font color as the accent 1 theme color darker 25%:
+//
+//    style, err := f.NewStyle(`{"font":{"theme_color":{"theme":4,"tint":-0.25}}}`)
+//
+// The indent of the alignment specifies the number of indent levels of the
+// text in the range of 0 to 250, and the text rotation specifies the degrees
+// of the text rotated in the range of 0 to 180, the value 0 to 90 rotates the
+// text counterclockwise and the value 91 to 180 rotates the text clockwise by
+// the value minus 90 degrees, and the value 255 specifies the vertical text.
+// For example, indent the text by 2 levels and rotate the header text 90
+// degrees:
+//
+//    indent, err := f.NewStyle(`{"alignment":{"horizontal":"left","indent":2}}`)
+//    rotate, err := f.NewStyle(`{"alignment":{"text_rotation":90}}`)
+//
 func (f *File) NewStyle(style interface{}) (int, error) {
 	var fs *Style
 	var err error
@@ -1907,6 +2526,18 @@ func (f *File) NewStyle(style interface{}) (int, error) {
 	case *Style:
 		fs = v
 	}
+	if err = validateStyleThemeColor(fs); err != nil {
+		return cellXfsID, err
+	}
+	if err = validateStyleAlignment(fs); err != nil {
+		return cellXfsID, err
+	}
+	if err = validateStyleBorders(fs); err != nil {
+		return cellXfsID, err
+	}
+	if err = validateStyleFill(fs); err != nil {
+		return cellXfsID, err
+	}
 	s := f.stylesReader()
 	numFmtID := setNumFmt(s, fs)
 
@@ -1942,6 +2573,18 @@ func (f *File) NewConditionalStyle(style string) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	if err = validateStyleThemeColor(fs); err != nil {
+		return 0, err
+	}
+	if err = validateStyleAlignment(fs); err != nil {
+		return 0, err
+	}
+	if err = validateStyleBorders(fs); err != nil {
+		return 0, err
+	}
+	if err = validateStyleFill(fs); err != nil {
+		return 0, err
+	}
 	dxf := dxf{
 		Fill: setFills(fs, false),
 	}
@@ -1969,23 +2612,82 @@ func (f *File) NewConditionalStyle(style string) (int, error) {
 // Documents generated by excelize start with Calibri.
 func (f *File) GetDefaultFont() string {
 	font := f.readDefaultFont()
+	if font == nil || font.Name == nil || font.Name.Val == nil {
+		return ""
+	}
 	return *font.Name.Val
 }
 
-// SetDefaultFont changes the default font in the workbook.
-func (f *File) SetDefaultFont(fontName string) {
+// SetDefaultFont changes the default font in the workbook. The font of the
+// normal cell style and the minor font of the theme will be updated, so that
+// the cells without custom font will use the given font. For example, set
+// the default font of the workbook to Arial:
+//
+//    err := f.SetDefaultFont("Arial")
+//
+func (f *File) SetDefaultFont(fontName string) error {
+	if fontName == "" {
+		return errors.New("the font name can not be empty")
+	}
 	font := f.readDefaultFont()
-	font.Name.Val = stringPtr(fontName)
+	if font == nil {
+		return errors.New("the default font is not exist")
+	}
+	font.Name = &attrValString{Val: stringPtr(fontName)}
 	s := f.stylesReader()
-	s.Fonts.Font[0] = font
-	custom := true
-	s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
+	if s.CellStyles != nil && len(s.CellStyles.CellStyle) > 0 {
+		custom := true
+		s.CellStyles.CellStyle[0].CustomBuiltIn = &custom
+	}
+	if f.Theme != nil {
+		minorFont := f.Theme.ThemeElements.FontScheme.MinorFont.Children
+		for idx := range minorFont {
+			if minorFont[idx].XMLName.Local == "latin" {
+				minorFont[idx].Typeface = fontName
+				minorFont[idx].Panose = ""
+				f.themeUpdated = true
+			}
+		}
+	}
+	return nil
+}
+
+// GetDefaultFontSize provides the default font size currently set in the
+// workbook. Documents generated by excelize start with 11.
+func (f *File) GetDefaultFontSize() float64 {
+	font := f.readDefaultFont()
+	if font == nil || font.Sz == nil || font.Sz.Val == nil {
+		return 0
+	}
+	return *font.Sz.Val
 }
 
-// readDefaultFont provides an unmarshalled font value.
+// SetDefaultFontSize changes the default font size in the workbook. The size
+// should be greater than 0 and not exceed 409.
+func (f *File) SetDefaultFontSize(size float64) error {
+	if size <= 0 || size > 409 {
+		return fmt.Errorf("invalid font size %v", size)
+	}
+	font := f.readDefaultFont()
+	if font == nil {
+		return errors.New("the default font is not exist")
+	}
+	font.Sz = &attrValFloat{Val: float64Ptr(size)}
+	return nil
+}
+
+// readDefaultFont provides an unmarshalled font value which referenced by
+// the first cell style format record.
 func (f *File) readDefaultFont() *xlsxFont {
 	s := f.stylesReader()
-	return s.Fonts.Font[0]
+	fontID := 0
+	if s.CellStyleXfs != nil && len(s.CellStyleXfs.Xf) > 0 {
+		fontID = s.CellStyleXfs.Xf[0].FontID
+	}
+	if s.Fonts == nil || fontID >= len(s.Fonts.Font) {
+		return nil
+	}
+	return s.Fonts.Font[fontID]
 }
 
 // setFont provides a function to add font style by given cell format
@@ -2000,7 +2702,7 @@ func (f *File) setFont(style *Style) *xlsxFont {
 	}
 	fnt := xlsxFont{
 		Sz:     &attrValFloat{Val: float64Ptr(style.Font.Size)},
-		Color:  &xlsxColor{RGB: getPaletteColor(style.Font.Color)},
+		Color:  newStyleColor(style.Font.Color, style.Font.ThemeColor),
 		Name:   &attrValString{Val: stringPtr(style.Font.Family)},
 		Family: &attrValInt{Val: intPtr(2)},
 	}
@@ -2117,31 +2819,172 @@ func setLangNumFmt(styleSheet *xlsxStyleSheet, style *Style) int {
 	return nf.NumFmtID
 }
 
+// styleFillPatterns defined the pattern types of the cell fill by given
+// pattern index.
+var styleFillPatterns = []string{
+	"none",
+	"solid",
+	"mediumGray",
+	"darkGray",
+	"lightGray",
+	"darkHorizontal",
+	"darkVertical",
+	"darkDown",
+	"darkUp",
+	"darkGrid",
+	"darkTrellis",
+	"lightHorizontal",
+	"lightVertical",
+	"lightDown",
+	"lightUp",
+	"lightGrid",
+	"lightTrellis",
+	"gray125",
+	"gray0625",
+}
+
+// styleBorders defined the border line styles by given style index.
+var styleBorders = []string{
+	"none",
+	"thin",
+	"medium",
+	"dashed",
+	"dotted",
+	"thick",
+	"double",
+	"hair",
+	"mediumDashed",
+	"dashDot",
+	"mediumDashDot",
+	"dashDotDot",
+	"mediumDashDotDot",
+	"slantDashDot",
+}
+
+// validateStyleThemeColor provides a function to validate the theme colors
+// of the font, fill and border in the style settings.
+func validateStyleThemeColor(style *Style) error {
+	colors := style.Fill.ThemeColor
+	if style.Font != nil {
+		colors = append(colors, style.Font.ThemeColor)
+	}
+	for _, border := range style.Border {
+		colors = append(colors, border.ThemeColor)
+	}
+	for _, color := range colors {
+		if color == nil {
+			continue
+		}
+		if color.Theme < 0 || color.Theme > 11 {
+			return fmt.Errorf("invalid theme color index %d", color.Theme)
+		}
+		if color.Tint < -1 || color.Tint > 1 {
+			return fmt.Errorf("invalid theme color tint %v", color.Tint)
+		}
+	}
+	return nil
+}
+
+// validateStyleAlignment provides a function to validate the indent and text
+// rotation of the alignment in the style.
+func validateStyleAlignment(style *Style) error {
+	if style.Alignment == nil {
+		return nil
+	}
+	if style.Alignment.Indent < 0 || style.Alignment.Indent > 250 {
+		return fmt.Errorf("invalid alignment indent %d", style.Alignment.Indent)
+	}
+	if rotation := style.Alignment.TextRotation; (rotation < 0 || rotation > 180) && rotation != 255 {
+		return fmt.Errorf("invalid alignment text rotation %d", rotation)
+	}
+	return nil
+}
+
+// validateStyleBorders provides a function to validate the type, line style
+// and color of the borders in the style.
+func validateStyleBorders(style *Style) error {
+	for _, border := range style.Border {
+		switch border.Type {
+		case "left", "right", "top", "bottom", "diagonalUp", "diagonalDown":
+		default:
+			return fmt.Errorf("invalid border type %s", border.Type)
+		}
+		if border.Style < 0 || border.Style >= len(styleBorders) {
+			return fmt.Errorf("invalid border style %d", border.Style)
+		}
+		if border.Color != "" && !styleRGBColorRe.MatchString(border.Color) {
+			return fmt.Errorf("invalid border color %s", border.Color)
+		}
+	}
+	return nil
+}
+
+// validateStyleFill provides a function to validate the colors, angle and
+// stop positions of the gradient fill in the style.
+func validateStyleFill(style *Style) error {
+	if style.Fill.Type != "gradient" {
+		return nil
+	}
+	for idx, color := range style.Fill.Color {
+		if idx < len(style.Fill.ThemeColor) && style.Fill.ThemeColor[idx] != nil {
+			continue
+		}
+		if color != "" && !styleRGBColorRe.MatchString(color) {
+			return fmt.Errorf("invalid gradient fill color %s", color)
+		}
+	}
+	if angle := style.Fill.Angle; angle != nil && (*angle < 0 || *angle > 360) {
+		return fmt.Errorf("invalid gradient fill angle %v", *angle)
+	}
+	if len(style.Fill.Stops) == 0 {
+		return nil
+	}
+	if count := countFillColors(style); len(style.Fill.Stops) != count {
+		return fmt.Errorf("the number of gradient fill stops %d doesn't match the number of colors %d", len(style.Fill.Stops), count)
+	}
+	for idx, position := range style.Fill.Stops {
+		if position < 0 || position > 1 || (idx > 0 && position < style.Fill.Stops[idx-1]) {
+			return fmt.Errorf("invalid gradient fill stop position %v", position)
+		}
+	}
+	return nil
+}
+
+// newStyleColor provides a function to create the color of the style by
+// given RGB color code and theme color, the theme color will be used if it
+// has been specified.
+func newStyleColor(color string, themeColor *StyleColor) *xlsxColor {
+	if themeColor != nil {
+		return &xlsxColor{Theme: intPtr(themeColor.Theme), Tint: themeColor.Tint}
+	}
+	return &xlsxColor{RGB: getPaletteColor(color)}
+}
+
+// newFillColor provides a function to create the color of the fill by given
+// color index.
+func newFillColor(style *Style, index int) *xlsxColor {
+	var color string
+	if index < len(style.Fill.Color) {
+		color = style.Fill.Color[index]
+	}
+	if index < len(style.Fill.ThemeColor) {
+		return newStyleColor(color, style.Fill.ThemeColor[index])
+	}
+	return newStyleColor(color, nil)
+}
+
+// countFillColors provides a function to get the number of the fill colors
+// in the style settings.
+func countFillColors(style *Style) int {
+	if len(style.Fill.ThemeColor) > len(style.Fill.Color) {
+		return len(style.Fill.ThemeColor)
+	}
+	return len(style.Fill.Color)
+}
+
 // setFills provides a function to add fill elements in the styles.xml by
 // given cell format settings.
 func setFills(style *Style, fg bool) *xlsxFill {
-	var patterns = []string{
-		"none",
-		"solid",
-		"mediumGray",
-		"darkGray",
-		"lightGray",
-		"darkHorizontal",
-		"darkVertical",
-		"darkDown",
-		"darkUp",
-		"darkGrid",
-		"darkTrellis",
-		"lightHorizontal",
-		"lightVertical",
-		"lightDown",
-		"lightUp",
-		"lightGrid",
-		"lightTrellis",
-		"gray125",
-		"gray0625",
-	}
-
 	var variants = []float64{
 		90,
 		0,
@@ -2152,16 +2995,19 @@ func setFills(style *Style, fg bool) *xlsxFill {
 	var fill xlsxFill
 	switch style.Fill.Type {
 	case "gradient":
-		if len(style.Fill.Color) != 2 {
+		count := countFillColors(style)
+		if count < 2 {
 			break
 		}
 		var gradient xlsxGradientFill
-		switch style.Fill.Shading {
-		case 0, 1, 2, 3:
+		switch {
+		case style.Fill.Angle != nil:
+			gradient.Degree = *style.Fill.Angle
+		case style.Fill.Shading >= 0 && style.Fill.Shading <= 3:
 			gradient.Degree = variants[style.Fill.Shading]
-		case 4:
+		case style.Fill.Shading == 4:
 			gradient.Type = "path"
-		case 5:
+		case style.Fill.Shading == 5:
 			gradient.Type = "path"
 			gradient.Bottom = 0.5
 			gradient.Left = 0.5
@@ -2171,10 +3017,13 @@ func setFills(style *Style, fg bool) *xlsxFill {
 			break
 		}
 		var stops []*xlsxGradientFillStop
-		for index, color := range style.Fill.Color {
+		for index := 0; index < count; index++ {
 			var stop xlsxGradientFillStop
-			stop.Position = float64(index)
-			stop.Color.RGB = getPaletteColor(color)
+			stop.Position = float64(index) / float64(count-1)
+			if len(style.Fill.Stops) == count {
+				stop.Position = style.Fill.Stops[index]
+			}
+			stop.Color = *newFillColor(style, index)
 			stops = append(stops, &stop)
 		}
 		gradient.Stop = stops
@@ -2183,15 +3032,15 @@ func setFills(style *Style, fg bool) *xlsxFill {
 		if style.Fill.Pattern > 18 || style.Fill.Pattern < 0 {
 			break
 		}
-		if len(style.Fill.Color) < 1 {
+		if countFillColors(style) < 1 {
 			break
 		}
 		var pattern xlsxPatternFill
-		pattern.PatternType = patterns[style.Fill.Pattern]
+		pattern.PatternType = styleFillPatterns[style.Fill.Pattern]
 		if fg {
-			pattern.FgColor.RGB = getPaletteColor(style.Fill.Color[0])
+			pattern.FgColor = *newFillColor(style, 0)
 		} else {
-			pattern.BgColor.RGB = getPaletteColor(style.Fill.Color[0])
+			pattern.BgColor = *newFillColor(style, 0)
 		}
 		fill.PatternFill = &pattern
 	default:
@@ -2234,47 +3083,29 @@ func setProtection(style *Style) *xlsxProtection {
 // setBorders provides a function to add border elements in the styles.xml by
 // given borders format settings.
 func setBorders(style *Style) *xlsxBorder {
-	var styles = []string{
-		"none",
-		"thin",
-		"medium",
-		"dashed",
-		"dotted",
-		"thick",
-		"double",
-		"hair",
-		"mediumDashed",
-		"dashDot",
-		"mediumDashDot",
-		"dashDotDot",
-		"mediumDashDotDot",
-		"slantDashDot",
-	}
-
 	var border xlsxBorder
 	for _, v := range style.Border {
 		if 0 <= v.Style && v.Style < 14 {
-			var color xlsxColor
-			color.RGB = getPaletteColor(v.Color)
+			color := *newStyleColor(v.Color, v.ThemeColor)
 			switch v.Type {
 			case "left":
-				border.Left.Style = styles[v.Style]
+				border.Left.Style = styleBorders[v.Style]
 				border.Left.Color = &color
 			case "right":
-				border.Right.Style = styles[v.Style]
+				border.Right.Style = styleBorders[v.Style]
 				border.Right.Color = &color
 			case "top":
-				border.Top.Style = styles[v.Style]
+				border.Top.Style = styleBorders[v.Style]
 				border.Top.Color = &color
 			case "bottom":
-				border.Bottom.Style = styles[v.Style]
+				border.Bottom.Style = styleBorders[v.Style]
 				border.Bottom.Color = &color
 			case "diagonalUp":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = styleBorders[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalUp = true
 			case "diagonalDown":
-				border.Diagonal.Style = styles[v.Style]
+				border.Diagonal.Style = styleBorders[v.Style]
 				border.Diagonal.Color = &color
 				border.DiagonalDown = true
 			}
@@ -2317,11 +3148,322 @@ func (f *File) GetCellStyle(sheet, axis string) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	cellData, col, row, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return 0, err
 	}
-	return f.prepareCellStyle(xlsx, col, cellData.S), err
+	return f.prepareCellStyle(xlsx, col, row, cellData.S), err
+}
+
+// SetCellProtection provides a function to set the protection properties of
+// the cell by given worksheet name, cell coordinates, locked and hidden. The
+// locked cell can't be edited and the formula of the hidden cell will not be
+// displayed when the worksheet is protected. The other formatting of the cell
+// will be kept, and the existing style will be reused if the same formatting
+// already exists. For example, allow the cell A1 on Sheet1 to be edited after
+// protecting the worksheet:
+//
+//    err := f.SetCellProtection("Sheet1", "A1", false, false)
+//
+func (f *File) SetCellProtection(sheet, axis string, locked, hidden bool) error {
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return err
+	}
+	s := f.stylesReader()
+	var xf xlsxXf
+	if s.CellXfs != nil && styleID < len(s.CellXfs.Xf) {
+		xf = s.CellXfs.Xf[styleID]
+	}
+	if xf.Alignment != nil {
+		alignment := *xf.Alignment
+		xf.Alignment = &alignment
+	}
+	xf.ApplyProtection, xf.Protection = true, &xlsxProtection{Hidden: hidden, Locked: locked}
+	if s.CellXfs == nil {
+		s.CellXfs = &xlsxCellXfs{}
+	}
+	styleID = -1
+	for idx, cellXf := range s.CellXfs.Xf {
+		if reflect.DeepEqual(cellXf, xf) {
+			styleID = idx
+			break
+		}
+	}
+	if styleID == -1 {
+		s.CellXfs.Xf = append(s.CellXfs.Xf, xf)
+		s.CellXfs.Count = len(s.CellXfs.Xf)
+		styleID = s.CellXfs.Count - 1
+	}
+	return f.SetCellStyle(sheet, axis, axis, styleID)
+}
+
+// GetCellProtection provides a function to get the protection properties of
+// the cell by given worksheet name and cell coordinates. The cell is locked
+// and not hidden by default.
+func (f *File) GetCellProtection(sheet, axis string) (locked, hidden bool, err error) {
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return
+	}
+	locked = true
+	s := f.stylesReader()
+	if s.CellXfs == nil || styleID >= len(s.CellXfs.Xf) {
+		return
+	}
+	if xf := s.CellXfs.Xf[styleID]; xf.ApplyProtection && xf.Protection != nil {
+		locked, hidden = xf.Protection.Locked, xf.Protection.Hidden
+	}
+	return
+}
+
+// GetCellAlignment provides a function to get the alignment settings of the
+// cell by given worksheet name and cell coordinates, such as the horizontal
+// and vertical alignment, wrap text and shrink to fit. The default horizontal
+// alignment "general" and vertical alignment "bottom" will be returned if the
+// style of the cell doesn't specify them. For example, check if the text of
+// the cell A1 on Sheet1 is wrapped:
+//
+//    alignment, err := f.GetCellAlignment("Sheet1", "A1")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    fmt.Println(alignment.WrapText)
+//
+func (f *File) GetCellAlignment(sheet, axis string) (Alignment, error) {
+	alignment := Alignment{Horizontal: "general", Vertical: "bottom"}
+	styleID, err := f.GetCellStyle(sheet, axis)
+	if err != nil {
+		return alignment, err
+	}
+	s := f.stylesReader()
+	if s.CellXfs == nil || styleID >= len(s.CellXfs.Xf) {
+		return alignment, err
+	}
+	if xf := s.CellXfs.Xf[styleID]; xf.Alignment != nil {
+		alignment = Alignment{
+			Horizontal:      xf.Alignment.Horizontal,
+			Indent:          xf.Alignment.Indent,
+			JustifyLastLine: xf.Alignment.JustifyLastLine,
+			ReadingOrder:    xf.Alignment.ReadingOrder,
+			RelativeIndent:  xf.Alignment.RelativeIndent,
+			ShrinkToFit:     xf.Alignment.ShrinkToFit,
+			TextRotation:    xf.Alignment.TextRotation,
+			Vertical:        xf.Alignment.Vertical,
+			WrapText:        xf.Alignment.WrapText,
+		}
+		if alignment.Horizontal == "" {
+			alignment.Horizontal = "general"
+		}
+		if alignment.Vertical == "" {
+			alignment.Vertical = "bottom"
+		}
+	}
+	return alignment, err
+}
+
+// GetStyleDefinition provides a function to get the style definition by
+// given style index, the font, fill, border, alignment, protection and number
+// format of the style will be returned as the JSON string which could be used
+// in the function NewStyle. For example, copy the style of cell A1 to cell B1
+// on Sheet1:
+//
+//    styleID, err := f.GetCellStyle("Sheet1", "A1")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    definition, err := f.GetStyleDefinition(styleID)
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    style, err := f.NewStyle(definition)
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    err = f.SetCellStyle("Sheet1", "B1", "B1", style)
+//
+func (f *File) GetStyleDefinition(styleID int) (string, error) {
+	s := f.stylesReader()
+	if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return "", fmt.Errorf("invalid style ID %d", styleID)
+	}
+	xf := s.CellXfs.Xf[styleID]
+	style := Style{NumFmt: xf.NumFmtID}
+	if xf.NumFmtID != 0 && s.NumFmts != nil {
+		if _, ok := builtInNumFmt[xf.NumFmtID]; !ok {
+			for _, numFmt := range s.NumFmts.NumFmt {
+				if numFmt.NumFmtID == xf.NumFmtID {
+					style.NumFmt, style.CustomNumFmt = 0, stringPtr(numFmt.FormatCode)
+					break
+				}
+			}
+		}
+	}
+	if xf.FontID != 0 && s.Fonts != nil && xf.FontID < len(s.Fonts.Font) {
+		style.Font = getFontDefinition(s.Fonts.Font[xf.FontID])
+	}
+	if xf.FillID != 0 && s.Fills != nil && xf.FillID < len(s.Fills.Fill) {
+		style.Fill = getFillDefinition(s.Fills.Fill[xf.FillID])
+	}
+	if xf.BorderID != 0 && s.Borders != nil && xf.BorderID < len(s.Borders.Border) {
+		style.Border = getBorderDefinition(s.Borders.Border[xf.BorderID])
+	}
+	if xf.ApplyAlignment && xf.Alignment != nil {
+		style.Alignment = &Alignment{
+			Horizontal:      xf.Alignment.Horizontal,
+			Indent:          xf.Alignment.Indent,
+			JustifyLastLine: xf.Alignment.JustifyLastLine,
+			ReadingOrder:    xf.Alignment.ReadingOrder,
+			RelativeIndent:  xf.Alignment.RelativeIndent,
+			ShrinkToFit:     xf.Alignment.ShrinkToFit,
+			TextRotation:    xf.Alignment.TextRotation,
+			Vertical:        xf.Alignment.Vertical,
+			WrapText:        xf.Alignment.WrapText,
+		}
+	}
+	if xf.ApplyProtection && xf.Protection != nil {
+		style.Protection = &Protection{
+			Hidden: xf.Protection.Hidden,
+			Locked: xf.Protection.Locked,
+		}
+	}
+	definition, err := json.Marshal(style)
+	return string(definition), err
+}
+
+// getPaletteColorDefinition provides a function to convert the ARGB color
+// of the style to the RGB color code which used in the style definition.
+func getPaletteColorDefinition(color *xlsxColor) string {
+	if color == nil || color.RGB == "" {
+		return ""
+	}
+	if len(color.RGB) == 8 {
+		return "#" + color.RGB[2:]
+	}
+	return "#" + color.RGB
+}
+
+// getThemeColorDefinition provides a function to get the theme color of the
+// style definition by given color, returns nil if the color doesn't
+// reference to the theme color.
+func getThemeColorDefinition(color *xlsxColor) *StyleColor {
+	if color == nil || color.Theme == nil {
+		return nil
+	}
+	return &StyleColor{Theme: *color.Theme, Tint: color.Tint}
+}
+
+// getFontDefinition provides a function to get the font settings of the
+// style definition by given font.
+func getFontDefinition(fnt *xlsxFont) *Font {
+	font := Font{
+		Bold:       fnt.B != nil,
+		Italic:     fnt.I != nil,
+		Strike:     fnt.Strike != nil,
+		Color:      getPaletteColorDefinition(fnt.Color),
+		ThemeColor: getThemeColorDefinition(fnt.Color),
+	}
+	if fnt.U != nil && fnt.U.Val != nil {
+		font.Underline = *fnt.U.Val
+	}
+	if fnt.Name != nil && fnt.Name.Val != nil {
+		font.Family = *fnt.Name.Val
+	}
+	if fnt.Sz != nil && fnt.Sz.Val != nil {
+		font.Size = *fnt.Sz.Val
+	}
+	return &font
+}
+
+// getFillDefinition provides a function to get the fill settings of the style
+// definition by given fill.
+func getFillDefinition(fill *xlsxFill) Fill {
+	var definition Fill
+	if fill.GradientFill != nil {
+		definition.Type = "gradient"
+		switch {
+		case fill.GradientFill.Type == "path" && fill.GradientFill.Left == 0.5:
+			definition.Shading = 5
+		case fill.GradientFill.Type == "path":
+			definition.Shading = 4
+		case fill.GradientFill.Degree == 0:
+			definition.Shading = 1
+		case fill.GradientFill.Degree == 45:
+			definition.Shading = 2
+		case fill.GradientFill.Degree == 135:
+			definition.Shading = 3
+		case fill.GradientFill.Degree != 90:
+			definition.Angle = float64Ptr(fill.GradientFill.Degree)
+		}
+		var themeColor, customStops bool
+		count := len(fill.GradientFill.Stop)
+		for idx, stop := range fill.GradientFill.Stop {
+			definition.Color = append(definition.Color, getPaletteColorDefinition(&stop.Color))
+			definition.ThemeColor = append(definition.ThemeColor, getThemeColorDefinition(&stop.Color))
+			definition.Stops = append(definition.Stops, stop.Position)
+			themeColor = themeColor || stop.Color.Theme != nil
+			customStops = customStops || count < 2 || stop.Position != float64(idx)/float64(count-1)
+		}
+		if !themeColor {
+			definition.ThemeColor = nil
+		}
+		if !customStops {
+			definition.Stops = nil
+		}
+		return definition
+	}
+	if fill.PatternFill != nil {
+		definition.Type = "pattern"
+		for idx, pattern := range styleFillPatterns {
+			if pattern == fill.PatternFill.PatternType {
+				definition.Pattern = idx
+				break
+			}
+		}
+		color := &fill.PatternFill.FgColor
+		if color.RGB == "" && color.Theme == nil {
+			color = &fill.PatternFill.BgColor
+		}
+		definition.Color = []string{getPaletteColorDefinition(color)}
+		if themeColor := getThemeColorDefinition(color); themeColor != nil {
+			definition.ThemeColor = []*StyleColor{themeColor}
+		}
+	}
+	return definition
+}
+
+// getBorderDefinition provides a function to get the border settings of the
+// style definition by given border.
+func getBorderDefinition(border *xlsxBorder) []Border {
+	var borders []Border
+	for _, line := range []struct {
+		typ  string
+		line xlsxLine
+		ok   bool
+	}{
+		{"left", border.Left, true},
+		{"right", border.Right, true},
+		{"top", border.Top, true},
+		{"bottom", border.Bottom, true},
+		{"diagonalUp", border.Diagonal, border.DiagonalUp},
+		{"diagonalDown", border.Diagonal, border.DiagonalDown},
+	} {
+		if !line.ok || line.line.Style == "" {
+			continue
+		}
+		for idx, style := range styleBorders {
+			if style == line.line.Style {
+				borders = append(borders, Border{
+					Type:       line.typ,
+					Color:      getPaletteColorDefinition(line.line.Color),
+					ThemeColor: getThemeColorDefinition(line.line.Color),
+					Style:      idx,
+				})
+				break
+			}
+		}
+	}
+	return borders
 }
 
 // SetCellStyle provides a function to add style attribute for cells by given
@@ -2356,7 +3498,7 @@ func (f *File) GetCellStyle(sheet, axis string) (int, error) {
 //
 // Set alignment style for cell H9 on Sheet1:
 //
-//    style, err := f.NewStyle(`{"alignment":{"horizontal":"center","ident":1,"justify_last_line":true,"reading_order":0,"relative_indent":1,"shrink_to_fit":true,"text_rotation":45,"vertical":"","wrap_text":true}}`)
+//    style, err := f.NewStyle(`{"alignment":{"horizontal":"center","indent":1,"justify_last_line":true,"reading_order":0,"relative_indent":1,"shrink_to_fit":true,"text_rotation":45,"vertical":"","wrap_text":true}}`)
 //    if err != nil {
 //        fmt.Println(err)
 //    }
@@ -2389,6 +3531,13 @@ func (f *File) GetCellStyle(sheet, axis string) (int, error) {
 //    }
 //    err = f.SetCellStyle("Sheet1", "H9", "H9", style)
 //
+// When the range extends to the last row of the worksheet or spans the entire
+// rows, the style will be set as the column or row formatting instead of
+// creating every cell in the range. For example, set style for the columns A:Z
+// on Sheet1:
+//
+//    err = f.SetCellStyle("Sheet1", "A1", "Z1048576", style)
+//
 func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 	hcol, hrow, err := CellNameToCoordinates(hcell)
 	if err != nil {
@@ -2419,6 +3568,14 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 	if err != nil {
 		return err
 	}
+	if vrow == TotalRows {
+		setCellStyleCols(xlsx, hcol, vcol, hrow, styleID)
+		return err
+	}
+	if hcol == 1 && vcol == TotalColumns {
+		setCellStyleRows(xlsx, hrow, vrow, styleID)
+		return err
+	}
 	prepareSheetXML(xlsx, vcol, vrow)
 	makeContiguousColumns(xlsx, hrow, vrow, vcol)
 
@@ -2430,6 +3587,163 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 	return err
 }
 
+// CopyStyle provides a function to copy the styles of the source cell range
+// to one or more destination cell ranges in the same worksheet like the
+// format painter of Excel, the values and formulas of the destination cells
+// will be kept. The destination ranges are separated by spaces or commas. The
+// style pattern of the source cell range will be repeated across each
+// destination cell range if the sizes of the ranges are different. The row
+// formatting will be copied if both of the source and destination ranges span
+// the entire rows. For example, apply the formatting of the banded rows A1:C2
+// to the ranges A3:C100 and E3:G100 in Sheet1:
+//
+//    err := f.CopyStyle("Sheet1", "A1:C2", "A3:C100 E3:G100")
+//
+func (f *File) CopyStyle(sheet, srcRange, destRange string) error {
+	refs := append([]string{srcRange}, strings.Fields(strings.Replace(destRange, ",", " ", -1))...)
+	if len(refs) == 1 {
+		return fmt.Errorf("invalid area %q", destRange)
+	}
+	coordinates := make([][]int, len(refs))
+	for i, ref := range refs {
+		cells := strings.Split(ref, ":")
+		if len(cells) == 1 {
+			cells = append(cells, cells[0])
+		}
+		if len(cells) != 2 {
+			return fmt.Errorf("invalid area %q", ref)
+		}
+		coords, err := areaRangeToCoordinates(cells[0], cells[1])
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(coords)
+		coordinates[i] = coords
+	}
+	src := coordinates[0]
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	// Read the styles of the source cells and rows first, which allows the
+	// source and destination areas overlap.
+	styles, rowStyles := make([][]int, src[3]-src[1]+1), make([]*int, src[3]-src[1]+1)
+	for r := range styles {
+		styles[r] = make([]int, src[2]-src[0]+1)
+		row := src[1] + r
+		var rowData *xlsxRow
+		if row <= len(xlsx.SheetData.Row) {
+			rowData = &xlsx.SheetData.Row[row-1]
+			if rowData.CustomFormat {
+				rowStyles[r] = intPtr(rowData.S)
+			}
+		}
+		for c := range styles[r] {
+			col := src[0] + c
+			var style int
+			if rowData != nil && col <= len(rowData.C) {
+				style = rowData.C[col-1].S
+			}
+			if style == 0 && rowStyles[r] != nil {
+				style = *rowStyles[r]
+			}
+			styles[r][c] = f.prepareCellStyle(xlsx, col, row, style)
+		}
+	}
+	for _, dest := range coordinates[1:] {
+		if src[0] == 1 && src[2] == TotalColumns && dest[0] == 1 && dest[2] == TotalColumns {
+			copyRowStyles(xlsx, dest, styles, rowStyles)
+			continue
+		}
+		prepareSheetXML(xlsx, dest[2], dest[3])
+		makeContiguousColumns(xlsx, dest[1], dest[3], dest[2])
+		for row := dest[1]; row <= dest[3]; row++ {
+			for col := dest[0]; col <= dest[2]; col++ {
+				xlsx.SheetData.Row[row-1].C[col-1].S = styles[(row-dest[1])%len(styles)][(col-dest[0])%len(styles[0])]
+			}
+		}
+	}
+	return err
+}
+
+// copyRowStyles provides a function to copy the row formatting and the styles
+// of the existing cells to the entire rows of the destination range by given
+// styles of the source cells and rows, the cells will not be created in the
+// destination rows.
+func copyRowStyles(xlsx *xlsxWorksheet, dest []int, styles [][]int, rowStyles []*int) {
+	prepareSheetXML(xlsx, 0, dest[3])
+	for row := dest[1]; row <= dest[3]; row++ {
+		idx, rowData := (row-dest[1])%len(styles), &xlsx.SheetData.Row[row-1]
+		rowData.S, rowData.CustomFormat = 0, rowStyles[idx] != nil
+		if rowData.CustomFormat {
+			rowData.S = *rowStyles[idx]
+		}
+		for col := range rowData.C {
+			rowData.C[col].S = styles[idx][col]
+		}
+	}
+}
+
+// setCellStyleCols provides a function to set the style of the columns
+// hcol:vcol from the row hrow to the last row of the worksheet by the column
+// formatting instead of creating every cell in the columns. The existing cells
+// in the columns will be updated, and the cells in the rows which have a
+// custom row style will be created, since the row style has higher precedence
+// than the column style in Excel. The cells above the row hrow will be created
+// with their original styles to keep them unchanged by the column formatting.
+func setCellStyleCols(xlsx *xlsxWorksheet, hcol, vcol, hrow, styleID int) {
+	if hrow > 1 {
+		prepareSheetXML(xlsx, vcol, hrow-1)
+		makeContiguousColumns(xlsx, 1, hrow-1, vcol)
+		colStyles := make([]int, vcol-hcol+1)
+		if xlsx.Cols != nil {
+			for _, c := range xlsx.Cols.Col {
+				for col := c.Min; col <= c.Max; col++ {
+					if col >= hcol && col <= vcol {
+						colStyles[col-hcol] = c.Style
+					}
+				}
+			}
+		}
+		for r := 0; r < hrow-1; r++ {
+			rowData := &xlsx.SheetData.Row[r]
+			for c := hcol - 1; c < vcol; c++ {
+				if rowData.C[c].S != 0 {
+					continue
+				}
+				rowData.C[c].S = colStyles[c-hcol+1]
+				if rowData.CustomFormat {
+					rowData.C[c].S = rowData.S
+				}
+			}
+		}
+	}
+	setColStyle(xlsx, hcol, vcol, styleID)
+	for r := hrow - 1; r < len(xlsx.SheetData.Row); r++ {
+		rowData := &xlsx.SheetData.Row[r]
+		if rowData.CustomFormat {
+			fillColumns(rowData, vcol, r+1)
+		}
+		for c := hcol - 1; c < vcol && c < len(rowData.C); c++ {
+			rowData.C[c].S = styleID
+		}
+	}
+}
+
+// setCellStyleRows provides a function to set the style of the entire rows
+// hrow:vrow by the row formatting instead of creating every cell in the rows.
+// The existing cells in the rows will be updated.
+func setCellStyleRows(xlsx *xlsxWorksheet, hrow, vrow, styleID int) {
+	prepareSheetXML(xlsx, 0, vrow)
+	for r := hrow - 1; r < vrow; r++ {
+		rowData := &xlsx.SheetData.Row[r]
+		rowData.S, rowData.CustomFormat = styleID, true
+		for c := range rowData.C {
+			rowData.C[c].S = styleID
+		}
+	}
+}
+
 // SetConditionalFormat provides a function to create conditional formatting
 // rule for cell value. Conditional formatting is a feature of Excel which
 // allows you to apply a format to a cell or a range of cells based on certain
@@ -2482,6 +3796,20 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //                   | min_value
 //                   | max_value
 //                   | bar_color
+//                   | min_length
+//                   | max_length
+//                   | bar_only
+//                   | bar_solid
+//                   | bar_direction
+//                   | bar_border_color
+//                   | bar_negative_color
+//                   | bar_negative_border_color
+//                   | bar_axis_position
+//                   | bar_axis_color
+//     icon_set      | icon_style
+//                   | reverse_icons
+//                   | icons_only
+//                   | icons
 //     formula       | criteria
 //
 // The criteria parameter is used to set the criteria by which the cell data
@@ -2601,6 +3929,15 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 // type: data_bar - The data_bar type is used to specify Excel's "Data Bar"
 // style conditional format.
 //
+// type: formula - The formula type is used to specify a conditional format
+// rule based on a formula, such as "Use a formula to determine which cells to
+// format". The formula is specified in the criteria parameter, it should be
+// written relative to the top left cell of the range, and the relative and
+// absolute references will be kept as written:
+//
+//    // Highlight the cells where the value in column A is greater than column B.
+//    f.SetConditionalFormat("Sheet1", "A1:B10", fmt.Sprintf(`[{"type":"formula","criteria":"$A1>$B1","format":%d}]`, format))
+//
 // min_type - The min_type and max_type properties are available when the conditional formatting type is 2_color_scale, 3_color_scale or data_bar. The mid_type is available for 3_color_scale. The properties are used as follows:
 //
 //    // Data Bars: Gradient Fill.
@@ -2641,6 +3978,70 @@ func (f *File) SetCellStyle(sheet, hcell, vcell string, styleID int) error {
 //
 // bar_color - Used for data_bar. Same as min_color, see above.
 //
+// min_length - The min_length and max_length properties are used for data_bar
+// to set the minimum and maximum length of the data bar as a percentage of the
+// cell width.
+//
+// bar_only - Used for data_bar. Hide the cell values and show the data bars
+// only.
+//
+// The following properties are used to set the extended data bar options
+// which was introduced in Excel 2010:
+//
+// bar_solid - Used for data_bar. Use the solid fill instead of the gradient
+// fill for the data bars.
+//
+// bar_direction - Used for data_bar. Set the direction of the data bars, the
+// available values are context, left_to_right and right_to_left.
+//
+// bar_border_color - Used for data_bar. Set the border color of the data bars.
+//
+// bar_negative_color - Used for data_bar. Set the fill color of the data bars
+// for the negative values, the default color is red.
+//
+// bar_negative_border_color - Used for data_bar. Set the border color of the
+// data bars for the negative values.
+//
+// bar_axis_position - Used for data_bar. Set the position of the axis between
+// the positive and negative values, the available values are automatic,
+// middle and none.
+//
+// bar_axis_color - Used for data_bar. Set the color of the axis.
+//
+//    // Data Bars: Solid Fill with the negative values and the axis.
+//    f.SetConditionalFormat("Sheet1", "L1:L10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_solid":true,"bar_border_color":"#638EC6","bar_negative_color":"#FF0000","bar_axis_position":"middle"}]`)
+//
+// type: icon_set - The icon_set type is used to specify Excel's "Icon Sets"
+// style conditional format:
+//
+//    // Icon Sets: 3 Arrows.
+//    f.SetConditionalFormat("Sheet1", "M1:M10", `[{"type":"icon_set","criteria":"=","icon_style":"3Arrows"}]`)
+//
+// icon_style - Used for icon_set. The default icon style is 3TrafficLights1,
+// and an error will be returned for the unsupported icon style. The available
+// icon styles are:
+//
+//    3Arrows         | 4Arrows         | 5Arrows
+//    3ArrowsGray     | 4ArrowsGray     | 5ArrowsGray
+//    3Flags          | 4RedToBlack     | 5Rating
+//    3TrafficLights1 | 4Rating         | 5Quarters
+//    3TrafficLights2 | 4TrafficLights  |
+//    3Signs          |                 |
+//    3Symbols        |                 |
+//    3Symbols2       |                 |
+//
+// reverse_icons - Used for icon_set. Reverse the order of the icons.
+//
+// icons_only - Used for icon_set. Hide the cell values and show the icons
+// only.
+//
+// icons - Used for icon_set. Set the custom thresholds of the icons except the
+// first one, the default thresholds are the evenly divided percentages. The
+// criteria could be >= or >, and the type could be num, percent, percentile or
+// formula:
+//
+//    f.SetConditionalFormat("Sheet1", "N1:N10", `[{"type":"icon_set","criteria":"=","icon_style":"3TrafficLights1","icons":[{"criteria":">=","type":"num","value":"50"},{"criteria":">","type":"num","value":"90"}]}]`)
+//
 func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 	var format []*formatConditional
 	err := json.Unmarshal([]byte(formatSet), &format)
@@ -2656,14 +4057,21 @@ func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 		"2_color_scale":   drawCondFmtColorScale,
 		"3_color_scale":   drawCondFmtColorScale,
 		"dataBar":         drawCondFmtDataBar,
+		"iconSet":         drawCondFmtIconSet,
 		"expression":      drawConfFmtExp,
 	}
+	for _, v := range format {
+		if _, ok := iconSetStyles[v.IconStyle]; validType[v.Type] == "iconSet" && v.IconStyle != "" && !ok {
+			return fmt.Errorf("unsupported icon style %s", v.IconStyle)
+		}
+	}
 
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
 	cfRule := []*xlsxCfRule{}
+	x14CfRule := []*xlsxX14CfRule{}
 	for p, v := range format {
 		var vt, ct string
 		var ok bool
@@ -2675,7 +4083,13 @@ func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 			if ok || vt == "expression" {
 				drawfunc, ok := drawContFmtFunc[vt]
 				if ok {
-					cfRule = append(cfRule, drawfunc(p, ct, v))
+					rule := drawfunc(p, ct, v)
+					if vt == "dataBar" {
+						if x14Rule := drawCondFmtDataBarExt(rule, v); x14Rule != nil {
+							x14CfRule = append(x14CfRule, x14Rule)
+						}
+					}
+					cfRule = append(cfRule, rule)
 				}
 			}
 		}
@@ -2685,23 +4099,116 @@ func (f *File) SetConditionalFormat(sheet, area, formatSet string) error {
 		SQRef:  area,
 		CfRule: cfRule,
 	})
+	if len(x14CfRule) > 0 {
+		var condFmtBytes []byte
+		if condFmtBytes, err = xml.Marshal(&xlsxX14ConditionalFormatting{
+			XMLNSXM: NameSpaceSpreadSheetExcel2006Main,
+			CfRule:  x14CfRule,
+			Sqref:   area,
+		}); err != nil {
+			return err
+		}
+		err = f.updateX14ConditionalFormatting(xlsx, "", condFmtBytes)
+	}
 	return err
 }
 
 // UnsetConditionalFormat provides a function to unset the conditional format
-// by given worksheet name and range.
+// by given worksheet name and range. The conditional formatting will be
+// removed if its ranges are equal to the given ones, otherwise the cells of
+// the given ranges will be removed from the ranges of it. Note that the
+// differential formatting records used by the removed rules will be kept in
+// the styles, since they may be referenced by the other conditional formats.
+// For example, unset the conditional format on D1:D10 of Sheet1:
+//
+//    err := f.UnsetConditionalFormat("Sheet1", "D1:D10")
+//
 func (f *File) UnsetConditionalFormat(sheet, area string) error {
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	for i, cf := range ws.ConditionalFormatting {
-		if cf.SQRef == area {
-			ws.ConditionalFormatting = append(ws.ConditionalFormatting[:i], ws.ConditionalFormatting[i+1:]...)
-			return nil
+	condFmts := ws.ConditionalFormatting[:0]
+	for _, cf := range ws.ConditionalFormatting {
+		if cf.SQRef, err = subtractSqref(cf.SQRef, area); err != nil {
+			return err
+		}
+		if cf.SQRef != "" {
+			condFmts = append(condFmts, cf)
 		}
 	}
-	return nil
+	ws.ConditionalFormatting = condFmts
+	return f.updateX14ConditionalFormatting(ws, area, nil)
+}
+
+// updateX14ConditionalFormatting provides a function to update the extended
+// conditional formatting rules in the extension list of the worksheet. The
+// rules in the given range will be removed if the range isn't empty, and the
+// given serialized conditionalFormatting element will be appended.
+func (f *File) updateX14ConditionalFormatting(ws *xlsxWorksheet, area string, condFmt []byte) error {
+	var (
+		idx            = -1
+		content        string
+		decodeExtLst   = new(decodeWorksheetExt)
+		decodeCondFmts *decodeX14ConditionalFormattings
+		extLstBytes    []byte
+		err            error
+	)
+	if ws.ExtLst != nil {
+		if err = f.xmlNewDecoder(bytes.NewReader([]byte("<extLst>" + ws.ExtLst.Ext + "</extLst>"))).
+			Decode(decodeExtLst); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	for i, ext := range decodeExtLst.Ext {
+		if ext.URI != ExtURIConditionalFormattings {
+			continue
+		}
+		idx, decodeCondFmts = i, new(decodeX14ConditionalFormattings)
+		if err = f.xmlNewDecoder(bytes.NewReader([]byte(ext.Content))).
+			Decode(decodeCondFmts); err != nil && err != io.EOF {
+			return err
+		}
+		for _, cf := range decodeCondFmts.ConditionalFormatting {
+			if area != "" {
+				sqref, err := subtractSqref(cf.Sqref, area)
+				if err != nil {
+					return err
+				}
+				if sqref == "" {
+					continue
+				}
+				cf.Content = strings.Replace(cf.Content, ">"+cf.Sqref+"<", ">"+sqref+"<", 1)
+			}
+			content += fmt.Sprintf(`<x14:conditionalFormatting xmlns:xm="%s">%s</x14:conditionalFormatting>`,
+				NameSpaceSpreadSheetExcel2006Main, cf.Content)
+		}
+	}
+	content += string(condFmt)
+	switch {
+	case idx == -1 && content == "":
+		return err
+	case idx == -1:
+		decodeExtLst.Ext = append(decodeExtLst.Ext, &xlsxWorksheetExt{
+			URI:     ExtURIConditionalFormattings,
+			Content: "<x14:conditionalFormattings>" + content + "</x14:conditionalFormattings>",
+		})
+	case content == "":
+		decodeExtLst.Ext = append(decodeExtLst.Ext[:idx], decodeExtLst.Ext[idx+1:]...)
+	default:
+		decodeExtLst.Ext[idx].Content = "<x14:conditionalFormattings>" + content + "</x14:conditionalFormattings>"
+	}
+	if len(decodeExtLst.Ext) == 0 {
+		ws.ExtLst = nil
+		return err
+	}
+	if extLstBytes, err = xml.Marshal(decodeExtLst); err != nil {
+		return err
+	}
+	ws.ExtLst = &xlsxExtLst{
+		Ext: strings.TrimSuffix(strings.TrimPrefix(string(extLstBytes), "<extLst>"), "</extLst>"),
+	}
+	return err
 }
 
 // drawCondFmtCellIs provides a function to create conditional formatting rule
@@ -2809,14 +4316,123 @@ func drawCondFmtColorScale(p int, ct string, format *formatConditional) *xlsxCfR
 // drawCondFmtDataBar provides a function to create conditional formatting
 // rule for data bar by given priority, criteria type and format settings.
 func drawCondFmtDataBar(p int, ct string, format *formatConditional) *xlsxCfRule {
-	return &xlsxCfRule{
+	c := &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
 		DataBar: &xlsxDataBar{
-			Cfvo:  []*xlsxCfvo{{Type: format.MinType}, {Type: format.MaxType}},
+			Cfvo: []*xlsxCfvo{
+				{Type: format.MinType, Val: format.MinValue},
+				{Type: format.MaxType, Val: format.MaxValue},
+			},
 			Color: []*xlsxColor{{RGB: getPaletteColor(format.BarColor)}},
 		},
 	}
+	if minLength, err := strconv.Atoi(format.MinLength); err == nil {
+		c.DataBar.MinLength = minLength
+	}
+	if maxLength, err := strconv.Atoi(format.MaxLength); err == nil {
+		c.DataBar.MaxLength = maxLength
+	}
+	if format.BarOnly {
+		c.DataBar.ShowValue = boolPtr(false)
+	}
+	return c
+}
+
+// drawCondFmtDataBarExt provides a function to create the extended
+// conditional formatting rule for data bar which was introduced in Excel 2010
+// by given format settings, the rule will be linked to the data bar rule by
+// the GUID. It returns nil if none of the extended properties are specified.
+func drawCondFmtDataBarExt(c *xlsxCfRule, format *formatConditional) *xlsxX14CfRule {
+	if !format.BarSolid && format.BarDirection == "" && format.BarBorderColor == "" &&
+		format.BarNegativeColor == "" && format.BarNegativeBorderColor == "" &&
+		format.BarAxisPosition == "" && format.BarAxisColor == "" {
+		return nil
+	}
+	cfvoTypes := map[string]string{"min": "autoMin", "max": "autoMax"}
+	dataBar := &xlsxX14DataBar{
+		MaxLength:                            100,
+		Gradient:                             !format.BarSolid,
+		Direction:                            dataBarDirections[format.BarDirection],
+		NegativeBarBorderColorSameAsPositive: format.BarNegativeBorderColor == "",
+		AxisPosition:                         dataBarAxisPositions[format.BarAxisPosition],
+		NegativeFillColor:                    &xlsxColor{RGB: "FFFF0000"},
+		AxisColor:                            &xlsxColor{RGB: "FF000000"},
+	}
+	for _, cfvo := range c.DataBar.Cfvo {
+		x14Cfvo := &xlsxX14Cfvo{Type: cfvo.Type, F: cfvo.Val}
+		if cfvoType, ok := cfvoTypes[cfvo.Type]; ok {
+			x14Cfvo.Type, x14Cfvo.F = cfvoType, ""
+		}
+		dataBar.Cfvo = append(dataBar.Cfvo, x14Cfvo)
+	}
+	if c.DataBar.MinLength != 0 {
+		dataBar.MinLength = c.DataBar.MinLength
+	}
+	if c.DataBar.MaxLength != 0 {
+		dataBar.MaxLength = c.DataBar.MaxLength
+	}
+	if format.BarBorderColor != "" {
+		dataBar.Border = true
+		dataBar.BorderColor = &xlsxColor{RGB: getPaletteColor(format.BarBorderColor)}
+	}
+	if format.BarNegativeColor != "" {
+		dataBar.NegativeFillColor = &xlsxColor{RGB: getPaletteColor(format.BarNegativeColor)}
+	}
+	if format.BarNegativeBorderColor != "" {
+		dataBar.NegativeBorderColor = &xlsxColor{RGB: getPaletteColor(format.BarNegativeBorderColor)}
+	}
+	if format.BarAxisColor != "" {
+		dataBar.AxisColor = &xlsxColor{RGB: getPaletteColor(format.BarAxisColor)}
+	}
+	GUID := genGUID()
+	c.ExtLst = &xlsxExtLst{
+		Ext: fmt.Sprintf(`<ext uri="%s" xmlns:x14="%s"><x14:id>%s</x14:id></ext>`,
+			ExtURIConditionalFormattingRuleID, NameSpaceSpreadSheetX14, GUID),
+	}
+	return &xlsxX14CfRule{Type: c.Type, ID: GUID, DataBar: dataBar}
+}
+
+// drawCondFmtIconSet provides a function to create conditional formatting
+// rule for icon set by given priority, criteria type and format settings.
+func drawCondFmtIconSet(p int, ct string, format *formatConditional) *xlsxCfRule {
+	iconStyle := format.IconStyle
+	if iconStyle == "" {
+		iconStyle = "3TrafficLights1"
+	}
+	count := iconSetStyles[iconStyle]
+	c := &xlsxCfRule{
+		Priority: p + 1,
+		Type:     validType[format.Type],
+		IconSet: &xlsxIconSet{
+			Cfvo:    []*xlsxCfvo{{Type: "percent", Val: "0"}},
+			IconSet: iconStyle,
+			Reverse: format.ReverseIcons,
+		},
+	}
+	if format.IconsOnly {
+		c.IconSet.ShowValue = boolPtr(false)
+	}
+	for i := 1; i < count; i++ {
+		cfvo := &xlsxCfvo{
+			Type: "percent",
+			Val:  strconv.FormatFloat(math.Round(float64(i)*100/float64(count)), 'f', -1, 64),
+		}
+		if i <= len(format.Icons) && format.Icons[i-1] != nil {
+			icon := format.Icons[i-1]
+			if icon.Type != "" {
+				cfvo.Type = icon.Type
+			}
+			if icon.Value != "" {
+				cfvo.Val = icon.Value
+			}
+			if icon.Criteria == ">" {
+				cfvo.Gte = boolPtr(false)
+			}
+		}
+		c.IconSet.Cfvo = append(c.IconSet.Cfvo, cfvo)
+	}
+	return c
 }
 
 // drawConfFmtExp provides a function to create conditional formatting rule
@@ -2825,7 +4441,7 @@ func drawConfFmtExp(p int, ct string, format *formatConditional) *xlsxCfRule {
 	return &xlsxCfRule{
 		Priority: p + 1,
 		Type:     validType[format.Type],
-		Formula:  []string{format.Criteria},
+		Formula:  []string{strings.TrimPrefix(format.Criteria, "=")},
 		DxfID:    &format.Format,
 	}
 }
@@ -2852,6 +4468,17 @@ func (f *File) themeReader() *xlsxTheme {
 	return &theme
 }
 
+// themeWriter provides a function to save xl/theme/theme1.xml after serialize
+// structure. The theme part will be kept as is unless it has been updated,
+// since the theme structure doesn't map all elements of the theme.
+func (f *File) themeWriter() {
+	if f.themeUpdated && f.Theme != nil && f.Theme.XMLName.Local == "theme" {
+		f.Theme.XMLNSa = NameSpaceDrawingML
+		output, _ := xml.Marshal(f.Theme)
+		f.saveFileList("xl/theme/theme1.xml", replaceRelationshipsNameSpaceBytes(output))
+	}
+}
+
 // ThemeColor applied the color with tint value.
 func ThemeColor(baseColor string, tint float64) string {
 	if tint == 0 {
@@ -3,6 +3,7 @@ package excelize
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -168,6 +169,119 @@ func TestSetConditionalFormat(t *testing.T) {
 	}
 }
 
+func TestSetConditionalFormatFormula(t *testing.T) {
+	f := NewFile()
+	format, err := f.NewConditionalStyle(`{"font":{"color":"#9A0511"},"fill":{"type":"pattern","color":["#FEC7CE"],"pattern":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:B10", fmt.Sprintf(`[{"type":"formula","criteria":"$A1>$B1","format":%d}]`, format)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", fmt.Sprintf(`[{"type":"formula","criteria":"=AND(C1>$D$1,C1<>\"\")","format":%d}]`, format)))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook with the formula-type rules.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	cf := xlsx.ConditionalFormatting
+	assert.Len(t, cf, 2)
+	for i, expected := range []struct {
+		sqref, formula string
+	}{{"A1:B10", "$A1>$B1"}, {"C1:C10", `AND(C1>$D$1,C1<>"")`}} {
+		assert.Equal(t, expected.sqref, cf[i].SQRef)
+		if assert.Len(t, cf[i].CfRule, 1) {
+			assert.Equal(t, "expression", cf[i].CfRule[0].Type)
+			assert.Equal(t, []string{expected.formula}, cf[i].CfRule[0].Formula)
+			assert.Equal(t, format, *cf[i].CfRule[0].DxfID)
+		}
+	}
+}
+
+func TestSetConditionalFormatDataBar(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_only":true,"min_length":"5","max_length":"95"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"data_bar","criteria":"=","min_type":"num","min_value":"-10","max_type":"percent","max_value":"90","bar_color":"#638EC6","bar_solid":true,"bar_direction":"right_to_left","bar_border_color":"#0070C0","bar_negative_color":"#FF5050","bar_negative_border_color":"#C00000","bar_axis_position":"middle","bar_axis_color":"#7F7F7F"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_solid":true}]`))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 3)
+	dataBar := ws.ConditionalFormatting[0].CfRule[0].DataBar
+	assert.Equal(t, boolPtr(false), dataBar.ShowValue)
+	assert.Equal(t, 5, dataBar.MinLength)
+	assert.Equal(t, 95, dataBar.MaxLength)
+	assert.Nil(t, ws.ConditionalFormatting[0].CfRule[0].ExtLst)
+	assert.Equal(t, []*xlsxCfvo{{Type: "num", Val: "-10"}, {Type: "percent", Val: "90"}}, ws.ConditionalFormatting[1].CfRule[0].DataBar.Cfvo)
+	assert.Contains(t, ws.ConditionalFormatting[1].CfRule[0].ExtLst.Ext, ExtURIConditionalFormattingRuleID)
+	assert.Contains(t, ws.ExtLst.Ext, ExtURIConditionalFormattings)
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:dataBar maxLength="100" minLength="0" border="true" gradient="false" direction="rightToLeft" negativeBarBorderColorSameAsPositive="false" axisPosition="middle"><x14:cfvo type="num"><xm:f>-10</xm:f></x14:cfvo><x14:cfvo type="percent"><xm:f>90</xm:f></x14:cfvo><x14:borderColor rgb="FF0070C0"></x14:borderColor><x14:negativeFillColor rgb="FFFF5050"></x14:negativeFillColor><x14:negativeBorderColor rgb="FFC00000"></x14:negativeBorderColor><x14:axisColor rgb="FF7F7F7F"></x14:axisColor></x14:dataBar>`)
+	assert.Contains(t, ws.ExtLst.Ext, `<x14:cfvo type="autoMin"></x14:cfvo><x14:cfvo type="autoMax"></x14:cfvo>`)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	// Test reopen the workbook and unset the extended data bar rules.
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 3)
+	assert.Equal(t, 2, strings.Count(ws.ExtLst.Ext, "<x14:conditionalFormatting "))
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "B1:B10"))
+	assert.Equal(t, 1, strings.Count(ws.ExtLst.Ext, "<x14:conditionalFormatting "))
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:sqref>C1:C10</xm:sqref>")
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "C1:C10"))
+	assert.Nil(t, ws.ExtLst)
+	// Test unset conditional format with unsupport charset extension list.
+	ws.ConditionalFormatting = []*xlsxConditionalFormatting{{SQRef: "A1:A10"}}
+	ws.ExtLst = &xlsxExtLst{Ext: string(MacintoshCyrillicCharset)}
+	assert.EqualError(t, f.UnsetConditionalFormat("Sheet1", "A1:A10"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestSetConditionalFormatIconSet(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"icon_set","criteria":"=","icon_style":"4Rating"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10", `[{"type":"icon_set","criteria":"=","icon_style":"3Arrows","reverse_icons":true,"icons_only":true,"icons":[{"criteria":">=","type":"num","value":"50"},{"criteria":">","type":"num","value":"90"}]}]`))
+	// Test set icon set with the default icon style.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C1:C10", `[{"type":"icon_set","criteria":"="}]`))
+	// Test set icon set with invalid icon style.
+	assert.EqualError(t, f.SetConditionalFormat("Sheet1", "D1:D10", `[{"type":"icon_set","criteria":"=","icon_style":"unknown"}]`), "unsupported icon style unknown")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 3)
+	for i, expected := range []*xlsxIconSet{{
+		Cfvo: []*xlsxCfvo{
+			{Type: "percent", Val: "0"},
+			{Type: "percent", Val: "25"},
+			{Type: "percent", Val: "50"},
+			{Type: "percent", Val: "75"},
+		},
+		IconSet: "4Rating",
+	}, {
+		Cfvo: []*xlsxCfvo{
+			{Type: "percent", Val: "0"},
+			{Type: "num", Val: "50"},
+			{Gte: boolPtr(false), Type: "num", Val: "90"},
+		},
+		IconSet:   "3Arrows",
+		ShowValue: boolPtr(false),
+		Reverse:   true,
+	}, {
+		Cfvo: []*xlsxCfvo{
+			{Type: "percent", Val: "0"},
+			{Type: "percent", Val: "33"},
+			{Type: "percent", Val: "67"},
+		},
+		IconSet: "3TrafficLights1",
+	}} {
+		assert.Equal(t, "iconSet", ws.ConditionalFormatting[i].CfRule[0].Type)
+		assert.Equal(t, expected, ws.ConditionalFormatting[i].CfRule[0].IconSet)
+	}
+}
+
 func TestUnsetConditionalFormat(t *testing.T) {
 	f := NewFile()
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 7))
@@ -176,10 +290,48 @@ func TestUnsetConditionalFormat(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"6"}]`, format)))
 	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "A1:A10"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, ws.ConditionalFormatting)
+	// Test unset conditional format on one of multiple ranges.
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10 C1:C10", fmt.Sprintf(`[{"type":"cell","criteria":">","format":%d,"value":"6"}]`, format)))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_border_color":"#638EC6"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B1:B10 A1:A10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_border_color":"#638EC6"}]`))
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "a1:a10"))
+	assert.Len(t, ws.ConditionalFormatting, 2)
+	assert.Equal(t, "C1:C10", ws.ConditionalFormatting[0].SQRef)
+	assert.Equal(t, "B1:B10", ws.ConditionalFormatting[1].SQRef)
+	assert.Equal(t, 1, strings.Count(ws.ExtLst.Ext, "<x14:conditionalFormatting "))
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:sqref>B1:B10</xm:sqref>")
+	// Test the differential formatting records are kept.
+	assert.Len(t, f.Styles.Dxfs.Dxfs, 1)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f2, err := OpenReader(buf)
+	assert.NoError(t, err)
+	ws, err = f2.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, ws.ConditionalFormatting, 2)
 	// Test unset conditional format on not exists worksheet.
 	assert.EqualError(t, f.UnsetConditionalFormat("SheetN", "A1:A10"), "sheet SheetN is not exist")
 	// Save xlsx file by the given path.
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestUnsetConditionalFormat.xlsx")))
+	// Test unset conditional format by the whole multiple ranges.
+	f = NewFile()
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "A1:A5 C1:C5", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_border_color":"#638EC6"}]`))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "E1:E10", `[{"type":"data_bar","criteria":"=","min_type":"min","max_type":"max","bar_color":"#638EC6","bar_border_color":"#638EC6"}]`))
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "A1:A5 C1:C5"))
+	assert.Len(t, ws.ConditionalFormatting, 1)
+	assert.Equal(t, 1, strings.Count(ws.ExtLst.Ext, "<x14:conditionalFormatting "))
+	assert.NotContains(t, ws.ExtLst.Ext, "A1:A5")
+	// Test unset conditional format on a sub range.
+	assert.NoError(t, f.UnsetConditionalFormat("Sheet1", "E3:E4"))
+	assert.Equal(t, "E1:E2 E5:E10", ws.ConditionalFormatting[0].SQRef)
+	assert.Contains(t, ws.ExtLst.Ext, "<xm:sqref>E1:E2 E5:E10</xm:sqref>")
+	// Test unset conditional format with invalid range.
+	assert.EqualError(t, f.UnsetConditionalFormat("Sheet1", "E"), `cannot convert cell "E" to coordinates: invalid cell name "E"`)
 }
 
 func TestNewStyle(t *testing.T) {
@@ -195,6 +347,32 @@ func TestNewStyle(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewStyleAlignment(t *testing.T) {
+	f := NewFile()
+	for _, alignment := range []Alignment{{Horizontal: "left", Indent: 2}, {Indent: 250}, {TextRotation: 90}, {TextRotation: 180}, {TextRotation: 255}} {
+		styleID, err := f.NewStyle(&Style{Alignment: &alignment})
+		assert.NoError(t, err)
+		xf := f.Styles.CellXfs.Xf[styleID]
+		assert.True(t, xf.ApplyAlignment)
+		assert.Equal(t, alignment.Indent, xf.Alignment.Indent)
+		assert.Equal(t, alignment.TextRotation, xf.Alignment.TextRotation)
+	}
+	styleID, err := f.NewStyle(`{"alignment":{"horizontal":"left","indent":2,"text_rotation":90}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxAlignment{Horizontal: "left", Indent: 2, TextRotation: 90}, f.Styles.CellXfs.Xf[styleID].Alignment)
+	// Test create style with invalid indent and text rotation.
+	for _, alignment := range []string{`{"indent":-1}`, `{"indent":251}`} {
+		_, err = f.NewStyle(`{"alignment":` + alignment + `}`)
+		assert.EqualError(t, err, strings.Replace(strings.Trim(alignment, "{}"), `"indent":`, "invalid alignment indent ", 1))
+		_, err = f.NewConditionalStyle(`{"alignment":` + alignment + `}`)
+		assert.Error(t, err)
+	}
+	for _, rotation := range []int{-1, 181, 254, 256} {
+		_, err = f.NewStyle(&Style{Alignment: &Alignment{TextRotation: rotation}})
+		assert.EqualError(t, err, fmt.Sprintf("invalid alignment text rotation %d", rotation))
+	}
+}
+
 func TestGetDefaultFont(t *testing.T) {
 	f := NewFile()
 	s := f.GetDefaultFont()
@@ -203,11 +381,37 @@ func TestGetDefaultFont(t *testing.T) {
 
 func TestSetDefaultFont(t *testing.T) {
 	f := NewFile()
-	f.SetDefaultFont("Ariel")
+	assert.NoError(t, f.SetDefaultFont("Ariel"))
 	styles := f.stylesReader()
 	s := f.GetDefaultFont()
 	assert.Equal(t, s, "Ariel", "Default font should change to Ariel")
 	assert.Equal(t, *styles.CellStyles.CellStyle[0].CustomBuiltIn, true)
+	assert.EqualError(t, f.SetDefaultFont(""), "the font name can not be empty")
+
+	// Test set default font and size and read back after saving the workbook.
+	assert.NoError(t, f.SetDefaultFont("Arial"))
+	assert.NoError(t, f.SetDefaultFontSize(12))
+	assert.EqualError(t, f.SetDefaultFontSize(0), "invalid font size 0")
+	assert.EqualError(t, f.SetDefaultFontSize(410), "invalid font size 410")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "Arial", f.GetDefaultFont())
+	assert.Equal(t, 12.0, f.GetDefaultFontSize())
+	assert.Equal(t, "Office Theme", f.Theme.Name)
+	for _, el := range f.Theme.ThemeElements.FontScheme.MinorFont.Children {
+		if el.XMLName.Local == "latin" {
+			assert.Equal(t, "Arial", el.Typeface)
+		}
+	}
+
+	// Test set default font without font in the style sheet.
+	f.Styles.Fonts = nil
+	assert.Equal(t, "", f.GetDefaultFont())
+	assert.Equal(t, 0.0, f.GetDefaultFontSize())
+	assert.EqualError(t, f.SetDefaultFont("Arial"), "the default font is not exist")
+	assert.EqualError(t, f.SetDefaultFontSize(11), "the default font is not exist")
 }
 
 func TestStylesReader(t *testing.T) {
@@ -225,8 +429,536 @@ func TestThemeReader(t *testing.T) {
 	assert.EqualValues(t, new(xlsxTheme), f.themeReader())
 }
 
+func TestThemeWriter(t *testing.T) {
+	f := NewFile()
+	// Test the theme part without update will be kept as is.
+	theme := []byte(`<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="Office Theme"><a:themeElements><a:unknown/></a:themeElements></a:theme>`)
+	f.XLSX["xl/theme/theme1.xml"] = theme
+	f.Theme = f.themeReader()
+	_, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Equal(t, theme, f.XLSX["xl/theme/theme1.xml"])
+	// Test the theme part will be saved after update.
+	f = NewFile()
+	assert.NoError(t, f.SetDefaultFont("Arial"))
+	_, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Contains(t, string(f.XLSX["xl/theme/theme1.xml"]), `typeface="Arial"></latin>`)
+}
+
 func TestSetCellStyle(t *testing.T) {
 	f := NewFile()
 	// Test set cell style on not exists worksheet.
 	assert.EqualError(t, f.SetCellStyle("SheetN", "A1", "A2", 1), "sheet SheetN is not exist")
 }
+
+func TestSetCellProtection(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(`{"font":{"bold":true},"alignment":{"horizontal":"center"}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", styleID))
+	locked, hidden, err := f.GetCellProtection("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	assert.False(t, hidden)
+
+	assert.NoError(t, f.SetCellProtection("Sheet1", "A1", false, true))
+	assert.NoError(t, f.SetCellProtection("Sheet1", "B1", false, true))
+	locked, hidden, err = f.GetCellProtection("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.False(t, locked)
+	assert.True(t, hidden)
+	s := f.stylesReader()
+	assert.Len(t, s.CellXfs.Xf, styleID+2)
+	assert.Equal(t, s.CellXfs.Count, len(s.CellXfs.Xf))
+	protectedStyleID, err := f.GetCellStyle("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID+1, protectedStyleID)
+	definition, err := f.GetStyleDefinition(protectedStyleID)
+	assert.NoError(t, err)
+	assert.Contains(t, definition, `"font":{"bold":true`)
+	assert.Contains(t, definition, `"alignment":{"horizontal":"center"`)
+	assert.Contains(t, definition, `"protection":{"hidden":true,"locked":false}`)
+	// Test the original style is not changed.
+	definition, err = f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	assert.NotContains(t, definition, `"protection"`)
+
+	// Test set cell protection on the cell without style.
+	assert.NoError(t, f.SetCellProtection("Sheet1", "C1", false, false))
+	locked, _, err = f.GetCellProtection("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	// Test set and get cell protection with invalid cell coordinates.
+	assert.EqualError(t, f.SetCellProtection("Sheet1", "A", false, false), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, _, err = f.GetCellProtection("Sheet1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.SetCellProtection("SheetN", "A1", false, false), "sheet SheetN is not exist")
+}
+
+func TestGetCellAlignment(t *testing.T) {
+	f := NewFile()
+	// Test get the default alignment of the cell without style.
+	alignment, err := f.GetCellAlignment("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, Alignment{Horizontal: "general", Vertical: "bottom"}, alignment)
+	styleID, err := f.NewStyle(`{"alignment":{"horizontal":"center","vertical":"top","wrap_text":true,"indent":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", styleID))
+	alignment, err = f.GetCellAlignment("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, Alignment{Horizontal: "center", Vertical: "top", WrapText: true, Indent: 1}, alignment)
+	// Test get the alignment with default values omitted in the style.
+	styleID, err = f.NewStyle(`{"alignment":{"shrink_to_fit":true},"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetColStyle("Sheet1", "B", styleID))
+	alignment, err = f.GetCellAlignment("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, Alignment{Horizontal: "general", Vertical: "bottom", ShrinkToFit: true}, alignment)
+	// Test get the alignment with invalid style ID.
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row[0].C[0].S = 100
+	alignment, err = f.GetCellAlignment("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, Alignment{Horizontal: "general", Vertical: "bottom"}, alignment)
+	// Test get the alignment with invalid cell coordinates and not exists worksheet.
+	_, err = f.GetCellAlignment("Sheet1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = f.GetCellAlignment("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
+func TestNewStyleDiagonalBorder(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(`{"border":[{"type":"diagonalUp","color":"#A020F0","style":1},{"type":"diagonalDown","color":"#A020F0","style":1}]}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", styleID))
+	s := f.stylesReader()
+	border := s.Borders.Border[s.CellXfs.Xf[styleID].BorderID]
+	assert.True(t, border.DiagonalUp)
+	assert.True(t, border.DiagonalDown)
+	assert.Equal(t, "thin", border.Diagonal.Style)
+	assert.Equal(t, &xlsxColor{RGB: "FFA020F0"}, border.Diagonal.Color)
+	// Test read the diagonal borders back from the style definition.
+	definition, err := f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	style, err := parseFormatStyleSet(definition)
+	assert.NoError(t, err)
+	assert.Equal(t, []Border{
+		{Type: "diagonalUp", Color: "#A020F0", Style: 1},
+		{Type: "diagonalDown", Color: "#A020F0", Style: 1},
+	}, style.Border)
+	// Test create the style with only one diagonal border.
+	styleID, err = f.NewStyle(`{"border":[{"type":"diagonalUp","color":"000000","style":2}]}`)
+	assert.NoError(t, err)
+	border = s.Borders.Border[s.CellXfs.Xf[styleID].BorderID]
+	assert.True(t, border.DiagonalUp)
+	assert.False(t, border.DiagonalDown)
+	assert.Equal(t, "medium", border.Diagonal.Style)
+	// Test create the style with invalid border type, style and color.
+	_, err = f.NewStyle(`{"border":[{"type":"diagonal","color":"000000","style":1}]}`)
+	assert.EqualError(t, err, "invalid border type diagonal")
+	_, err = f.NewStyle(`{"border":[{"type":"diagonalUp","color":"000000","style":14}]}`)
+	assert.EqualError(t, err, "invalid border style 14")
+	_, err = f.NewStyle(`{"border":[{"type":"diagonalDown","color":"red","style":1}]}`)
+	assert.EqualError(t, err, "invalid border color red")
+	_, err = f.NewConditionalStyle(`{"border":[{"type":"diagonalDown","color":"#0000FFFF","style":1}]}`)
+	assert.EqualError(t, err, "invalid border color #0000FFFF")
+}
+
+func TestNewStyleGradientFill(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(`{"fill":{"type":"gradient","color":["#FF0000","#0000FF"],"angle":90}}`)
+	assert.NoError(t, err)
+	s := f.stylesReader()
+	assert.Equal(t, &xlsxGradientFill{Degree: 90, Stop: []*xlsxGradientFillStop{
+		{Position: 0, Color: xlsxColor{RGB: "FFFF0000"}},
+		{Position: 1, Color: xlsxColor{RGB: "FF0000FF"}},
+	}}, s.Fills.Fill[s.CellXfs.Xf[styleID].FillID].GradientFill)
+	// Test create the linear gradient fill with multiple stops.
+	styleID, err = f.NewStyle(`{"fill":{"type":"gradient","color":["#FF0000","#FFFF00","#0000FF"],"angle":30,"stops":[0,0.25,1]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, &xlsxGradientFill{Degree: 30, Stop: []*xlsxGradientFillStop{
+		{Position: 0, Color: xlsxColor{RGB: "FFFF0000"}},
+		{Position: 0.25, Color: xlsxColor{RGB: "FFFFFF00"}},
+		{Position: 1, Color: xlsxColor{RGB: "FF0000FF"}},
+	}}, s.Fills.Fill[s.CellXfs.Xf[styleID].FillID].GradientFill)
+	definition, err := f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	style, err := parseFormatStyleSet(definition)
+	assert.NoError(t, err)
+	assert.Equal(t, Fill{Type: "gradient", Color: []string{"#FF0000", "#FFFF00", "#0000FF"}, Angle: float64Ptr(30), Stops: []float64{0, 0.25, 1}}, style.Fill)
+	// Test create the gradient fill with evenly spaced stops.
+	styleID, err = f.NewStyle(`{"fill":{"type":"gradient","color":["#FF0000","#FFFF00","#0000FF"],"shading":1}}`)
+	assert.NoError(t, err)
+	stops := s.Fills.Fill[s.CellXfs.Xf[styleID].FillID].GradientFill.Stop
+	assert.Len(t, stops, 3)
+	assert.Equal(t, 0.5, stops[1].Position)
+	definition, err = f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	style, err = parseFormatStyleSet(definition)
+	assert.NoError(t, err)
+	assert.Nil(t, style.Fill.Angle)
+	assert.Nil(t, style.Fill.Stops)
+	// Test create the gradient fill with invalid colors, angle and stops.
+	for style, expected := range map[string]string{
+		`{"fill":{"type":"gradient","color":["#FF0000","blue"]}}`:                                  "invalid gradient fill color blue",
+		`{"fill":{"type":"gradient","color":["#FF0000","#0000FF"],"angle":-1}}`:                    "invalid gradient fill angle -1",
+		`{"fill":{"type":"gradient","color":["#FF0000","#0000FF"],"angle":361}}`:                   "invalid gradient fill angle 361",
+		`{"fill":{"type":"gradient","color":["#FF0000","#0000FF"],"stops":[0]}}`:                   "the number of gradient fill stops 1 doesn't match the number of colors 2",
+		`{"fill":{"type":"gradient","color":["#FF0000","#0000FF"],"stops":[0,1.5]}}`:               "invalid gradient fill stop position 1.5",
+		`{"fill":{"type":"gradient","color":["#FF0000","#0000FF","#00FF00"],"stops":[0,0.6,0.4]}}`: "invalid gradient fill stop position 0.4",
+	} {
+		_, err = f.NewStyle(style)
+		assert.EqualError(t, err, expected, style)
+	}
+	_, err = f.NewConditionalStyle(`{"fill":{"type":"gradient","color":["#FF0000","#0000FF"],"stops":[-0.5,1]}}`)
+	assert.EqualError(t, err, "invalid gradient fill stop position -0.5")
+}
+
+func TestCopyStyle(t *testing.T) {
+	f := NewFile()
+	style1, err := f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+	assert.NoError(t, err)
+	style2, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	style3, err := f.NewStyle(`{"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", style1))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "A2", style2))
+	assert.NoError(t, f.SetColStyle("Sheet1", "B", style3))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D5", "value"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E6", "=1+1"))
+
+	// Test repeat the style pattern of the source range across the destination.
+	assert.NoError(t, f.CopyStyle("Sheet1", "A1:B2", "E5:D9"))
+	for cell, expected := range map[string]int{
+		"D5": style1, "E5": style1, "D6": style2, "E6": style3,
+		"D7": style1, "E7": style1, "D8": style2, "E8": style3,
+		"D9": style1, "E9": style1, "F5": 0, "D10": 0,
+	} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test the values and formulas of the destination will be kept.
+	val, err := f.GetCellValue("Sheet1", "D5")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", val)
+	formula, err := f.GetCellFormula("Sheet1", "E6")
+	assert.NoError(t, err)
+	assert.Equal(t, "=1+1", formula)
+
+	// Test copy the style of the single cell and the overlapped ranges.
+	assert.NoError(t, f.CopyStyle("Sheet1", "A2", "G1:H2"))
+	assert.NoError(t, f.CopyStyle("Sheet1", "A1:A2", "A2:A3"))
+	for cell, expected := range map[string]int{"G1": style2, "H2": style2, "A2": style1, "A3": style2} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test copy the style of the cells beyond the worksheet data.
+	assert.NoError(t, f.CopyStyle("Sheet1", "Z100", "A1"))
+	styleID, err := f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Zero(t, styleID)
+
+	// Test copy the style to multiple destination ranges.
+	assert.NoError(t, f.CopyStyle("Sheet1", "A3", "J1:J2 K5,L6"))
+	for cell, expected := range map[string]int{"J1": style2, "J2": style2, "K5": style2, "L6": style2, "K6": 0} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+
+	// Test copy the row formatting of the entire rows.
+	f = NewFile()
+	style1, err = f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+	assert.NoError(t, err)
+	style2, err = f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "XFD1", style1))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style2))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "C3"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C4", "C4"))
+	assert.NoError(t, f.CopyStyle("Sheet1", "A1:XFD2", "A3:XFD5"))
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, xlsx.SheetData.Row, 5)
+	for r, expected := range []struct {
+		style        int
+		customFormat bool
+	}{{style1, true}, {0, false}, {style1, true}} {
+		row := xlsx.SheetData.Row[r+2]
+		assert.Equal(t, expected.style, row.S, r+3)
+		assert.Equal(t, expected.customFormat, row.CustomFormat, r+3)
+		assert.True(t, len(row.C) <= 3, r+3)
+	}
+	for cell, expected := range map[string]int{"C3": style1, "C4": 0} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test copy the style of the cell in the row with the row formatting.
+	assert.NoError(t, f.CopyStyle("Sheet1", "B1:C1", "E7:F7"))
+	for cell, expected := range map[string]int{"E7": style2, "F7": style1} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+
+	// Test copy style with invalid cell range.
+	assert.EqualError(t, f.CopyStyle("Sheet1", "A1", ""), `invalid area ""`)
+	assert.EqualError(t, f.CopyStyle("Sheet1", "A1:B2:C3", "D1"), `invalid area "A1:B2:C3"`)
+	assert.EqualError(t, f.CopyStyle("Sheet1", "A1", "D1:E2:F3"), `invalid area "D1:E2:F3"`)
+	assert.EqualError(t, f.CopyStyle("Sheet1", "A", "D1"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.CopyStyle("Sheet1", "A1", "D"), `cannot convert cell "D" to coordinates: invalid cell name "D"`)
+	// Test copy style on not exists worksheet.
+	assert.EqualError(t, f.CopyStyle("SheetN", "A1", "D1"), "sheet SheetN is not exist")
+}
+
+func TestSetCellStyleEntireColsRows(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "B2"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A5", "XFD5", style))
+	// Test set style for the entire columns.
+	assert.NoError(t, f.SetCellStyle("Sheet1", "Z1048576", "A1", style))
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, xlsx.Cols.Col, 26)
+	for _, col := range xlsx.Cols.Col {
+		assert.Equal(t, style, col.Style)
+	}
+	assert.Len(t, xlsx.SheetData.Row, 5)
+	assert.Len(t, xlsx.SheetData.Row[4].C, 26)
+	for _, cell := range []string{"B2", "A5", "Z5"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, style, styleID, cell)
+	}
+	// Test set style for the entire rows.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "C3"))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "XFD3", style))
+	xlsx, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Nil(t, xlsx.Cols)
+	assert.Len(t, xlsx.SheetData.Row, 3)
+	assert.Equal(t, 0, xlsx.SheetData.Row[0].S)
+	for _, row := range xlsx.SheetData.Row[1:] {
+		assert.Equal(t, style, row.S)
+		assert.True(t, row.CustomFormat)
+	}
+	assert.Len(t, xlsx.SheetData.Row[2].C, 3)
+	styleID, err := f.GetCellStyle("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	// Test the cells written in the entire rows keep the style of the rows.
+	for _, cell := range []string{"B2", "Z2", "XFD3"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, style, styleID, cell)
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", "B2"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "Z2", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D3", true))
+	assert.Equal(t, style, xlsx.SheetData.Row[1].C[1].S)
+	assert.Equal(t, style, xlsx.SheetData.Row[1].C[25].S)
+	assert.Equal(t, style, xlsx.SheetData.Row[2].C[3].S)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	xlsx, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, style, xlsx.SheetData.Row[1].C[25].S)
+
+	// Test set style for the entire columns below the header row.
+	f = NewFile()
+	style, err = f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", "B3"))
+	assert.NoError(t, f.SetColStyle("Sheet1", "B", style))
+	header, err := f.NewStyle(`{"font":{"bold":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "C1048576", header))
+	xlsx, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, xlsx.SheetData.Row, 3)
+	// The cells above the range are created to keep their original styles.
+	for col, expected := range []int{0, style, 0} {
+		assert.Equal(t, expected, xlsx.SheetData.Row[0].C[col].S, col)
+	}
+	for cell, expected := range map[string]int{"A2": header, "B3": header, "C100": header} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+}
+
+func BenchmarkSetCellStyle(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		f := NewFile()
+		style, err := f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+		if err != nil {
+			b.Error(err)
+		}
+		if err := f.SetCellStyle("Sheet1", "A1", "CV1000", style); err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func TestGetStyleDefinition(t *testing.T) {
+	f := NewFile()
+	for _, style := range []string{
+		`{"font":{"bold":true,"italic":true,"underline":"single","family":"Arial","size":12,"strike":true,"color":"#777777"}}`,
+		`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1},"number_format":14}`,
+		`{"fill":{"type":"gradient","color":["#FFFFFF","#E0EBF5"],"shading":3}}`,
+		`{"fill":{"type":"gradient","color":["#FFFFFF","#E0EBF5"],"shading":5}}`,
+		`{"fill":{"type":"gradient","color":["#FF0000","#FFFF00","#0000FF"],"angle":60,"stops":[0,0.2,1]}}`,
+		`{"border":[{"type":"left","color":"0000FF","style":3},{"type":"top","color":"00FF00","style":4},{"type":"diagonalDown","color":"A020F0","style":7}]}`,
+		`{"alignment":{"horizontal":"center","vertical":"top","wrap_text":true},"protection":{"hidden":true,"locked":true}}`,
+		`{"custom_number_format":"[$-380A]dddd\\,\\ dd\" de \"mmmm\" de \"yyyy;@"}`,
+		`{"font":{"theme_color":{"theme":4,"tint":-0.25}},"fill":{"type":"pattern","theme_color":[{"theme":5,"tint":0.4}],"pattern":1}}`,
+		`{"fill":{"type":"gradient","color":["#FFFFFF"],"theme_color":[null,{"theme":9}],"shading":1}}`,
+		`{"border":[{"type":"left","theme_color":{"theme":1,"tint":0.5},"style":1}]}`,
+	} {
+		styleID, err := f.NewStyle(style)
+		assert.NoError(t, err)
+		definition, err := f.GetStyleDefinition(styleID)
+		assert.NoError(t, err)
+		newStyleID, err := f.NewStyle(definition)
+		assert.NoError(t, err)
+		s := f.stylesReader()
+		xf, newXf := s.CellXfs.Xf[styleID], s.CellXfs.Xf[newStyleID]
+		assert.Equal(t, s.Fonts.Font[xf.FontID], s.Fonts.Font[newXf.FontID], style)
+		assert.Equal(t, s.Fills.Fill[xf.FillID], s.Fills.Fill[newXf.FillID], style)
+		assert.Equal(t, s.Borders.Border[xf.BorderID], s.Borders.Border[newXf.BorderID], style)
+		assert.Equal(t, xf.Alignment, newXf.Alignment, style)
+		assert.Equal(t, xf.Protection, newXf.Protection, style)
+		assert.Equal(t, getNumFmtCode(s, xf.NumFmtID), getNumFmtCode(s, newXf.NumFmtID), style)
+	}
+	// Test get style definition with invalid style ID.
+	_, err := f.GetStyleDefinition(-1)
+	assert.EqualError(t, err, "invalid style ID -1")
+	_, err = f.GetStyleDefinition(100)
+	assert.EqualError(t, err, "invalid style ID 100")
+}
+
+func getNumFmtCode(s *xlsxStyleSheet, numFmtID int) string {
+	if s.NumFmts != nil {
+		for _, numFmt := range s.NumFmts.NumFmt {
+			if numFmt.NumFmtID == numFmtID {
+				return numFmt.FormatCode
+			}
+		}
+	}
+	return builtInNumFmt[numFmtID]
+}
+
+func TestNewStyleThemeColor(t *testing.T) {
+	f := NewFile()
+	styleID, err := f.NewStyle(`{"font":{"theme_color":{"theme":4,"tint":-0.25}},"fill":{"type":"pattern","theme_color":[{"theme":5}],"pattern":1},"border":[{"type":"top","theme_color":{"theme":1},"style":1}]}`)
+	assert.NoError(t, err)
+	s := f.stylesReader()
+	xf := s.CellXfs.Xf[styleID]
+	assert.Equal(t, &xlsxColor{Theme: intPtr(4), Tint: -0.25}, s.Fonts.Font[xf.FontID].Color)
+	assert.Equal(t, xlsxColor{Theme: intPtr(5)}, s.Fills.Fill[xf.FillID].PatternFill.FgColor)
+	assert.Equal(t, &xlsxColor{Theme: intPtr(1)}, s.Borders.Border[xf.BorderID].Top.Color)
+	definition, err := f.GetStyleDefinition(styleID)
+	assert.NoError(t, err)
+	assert.Contains(t, definition, `"theme_color":{"theme":4,"tint":-0.25}`)
+
+	_, err = f.NewConditionalStyle(`{"font":{"theme_color":{"theme":4}}}`)
+	assert.NoError(t, err)
+	// Test create style with invalid theme color.
+	_, err = f.NewStyle(`{"font":{"theme_color":{"theme":12}}}`)
+	assert.EqualError(t, err, "invalid theme color index 12")
+	_, err = f.NewStyle(&Style{Fill: Fill{Type: "pattern", ThemeColor: []*StyleColor{{Theme: -1}}}})
+	assert.EqualError(t, err, "invalid theme color index -1")
+	_, err = f.NewStyle(`{"border":[{"type":"top","theme_color":{"theme":1,"tint":1.5},"style":1}]}`)
+	assert.EqualError(t, err, "invalid theme color tint 1.5")
+	_, err = f.NewConditionalStyle(`{"font":{"theme_color":{"theme":12}}}`)
+	assert.EqualError(t, err, "invalid theme color index 12")
+}
+
+func TestApplyNumFmtCode(t *testing.T) {
+	for _, c := range []struct {
+		value    float64
+		code     string
+		expected string
+	}{
+		// Scientific
+		{1234.5, "0.00E+00", "1.23E+03"},
+		{-0.00012, "0.00E+00", "-1.20E-04"},
+		{0, "0.00E+00", "0.00E+00"},
+		{1e300, "0.00E+00", "1.00E+300"},
+		{12345, "##0.0E+0", "12.3E+3"},
+		// Fraction
+		{1.5, "# ?/?", "1 1/2"},
+		{0.5, "# ?/?", " 1/2"},
+		{5, "# ?/?", "5    "},
+		{0, "# ?/?", "0    "},
+		{-2.25, "# ?/?", "-2 1/4"},
+		{0.999, "# ?/?", "1    "},
+		{3.14159, "# ???/???", "3  16/113"},
+		{1.75, "?/?", "7/4"},
+		{0.3, "# ?/4", " 1/4"},
+		{1.3, "?/8", "10/8"},
+		{0, "?/?", "0/1"},
+		// Special
+		{5551234, "[<=9999999]###-####;(###) ###-####", "555-1234"},
+		{5551234567, "[<=9999999]###-####;(###) ###-####", "(555) 123-4567"},
+		{123456789, "000-00-0000", "123-45-6789"},
+		{12345, "00000-0000", "00001-2345"},
+		{1234, "00000", "01234"},
+		{-5, "#,##0;(#,##0)", "(5)"},
+		// Color
+		{-5, "#,##0;[Red](#,##0)", "(5)"},
+		{44000, "[Blue]yyyy-mm-dd", "2020-06-18"},
+	} {
+		assert.Equal(t, c.expected, applyNumFmtCode(c.value, c.code, false), c.code)
+	}
+}
+
+func TestGetCellValueNumFmt(t *testing.T) {
+	f := NewFile()
+	for i, c := range []struct {
+		numFmt   string
+		value    float64
+		expected string
+	}{
+		{`{"number_format":11}`, -1234.5, "-1.23E+03"},
+		{`{"number_format":12}`, 2.5, "2 1/2"},
+		{`{"number_format":13}`, 0.1, "  1/10"},
+		{`{"number_format":48}`, 0.000123, "123.0E-6"},
+		{`{"custom_number_format":"[<=9999999]###-####;(###) ###-####"}`, 8005551212, "(800) 555-1212"},
+	} {
+		axis, err := CoordinatesToCellName(1, i+1)
+		assert.NoError(t, err)
+		style, err := f.NewStyle(c.numFmt)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellValue("Sheet1", axis, c.value))
+		assert.NoError(t, f.SetCellStyle("Sheet1", axis, axis, style))
+		val, err := f.GetCellValue("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, val, c.numFmt)
+	}
+}
+
+func TestGetNumFmtColor(t *testing.T) {
+	assert.Equal(t, "", getNumFmtColor(5, "#,##0;[Red](#,##0)"))
+	assert.Equal(t, "#FF0000", getNumFmtColor(-5, "#,##0;[Red](#,##0)"))
+	assert.Equal(t, "#FF0000", getNumFmtColor(-5, "#,##0;[red](#,##0)"))
+	assert.Equal(t, "#0000FF", getNumFmtColor(0, "#,##0;[Red](#,##0);[Blue]0"))
+	assert.Equal(t, "#FFFF00", getNumFmtColor(100, "[Yellow][>50]0;[Green]0"))
+	assert.Equal(t, "#00FF00", getNumFmtColor(10, "[Yellow][>50]0;[Green]0"))
+	assert.Equal(t, "#000000", getNumFmtColor(1, "[Color1]0"))
+	assert.Equal(t, "#333333", getNumFmtColor(1, "[Color56]0"))
+	assert.Equal(t, "", getNumFmtColor(1, "[Color57]0"))
+}
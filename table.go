@@ -10,12 +10,15 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // parseFormatTableSet provides a function to parse the format settings of the
@@ -75,7 +78,7 @@ func (f *File) AddTable(sheet, hcell, vcell, format string) error {
 		vrow, hrow = hrow, vrow
 	}
 
-	tableID := f.countTables() + 1
+	tableID := f.getNextTableID()
 	sheetRelationshipsTableXML := "../tables/table" + strconv.Itoa(tableID) + ".xml"
 	tableXML := strings.Replace(sheetRelationshipsTableXML, "..", "xl", -1)
 	// Add first table for given sheet.
@@ -90,16 +93,23 @@ func (f *File) AddTable(sheet, hcell, vcell, format string) error {
 	return err
 }
 
-// countTables provides a function to get table files count storage in the
-// folder xl/tables.
-func (f *File) countTables() int {
-	count := 0
-	for k := range f.XLSX {
-		if strings.Contains(k, "xl/tables/table") {
-			count++
+// getNextTableID provides a function to get the unused ID of the table, the
+// ID of the table is unique in the workbook and also used as the number of
+// the table part in the folder xl/tables.
+func (f *File) getNextTableID() int {
+	tableID := 0
+	for path := range f.XLSX {
+		if !strings.HasPrefix(path, "xl/tables/table") {
+			continue
+		}
+		if ID, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "xl/tables/table"), ".xml")); err == nil && ID > tableID {
+			tableID = ID
+		}
+		if table, err := f.tableReader(path); err == nil && table.ID > tableID {
+			tableID = table.ID
 		}
 	}
-	return count
+	return tableID + 1
 }
 
 // addSheetTable provides a function to add tablePart element to
@@ -177,11 +187,425 @@ func (f *File) addTable(sheet, tableXML string, x1, y1, x2, y2, i int, formatSet
 			ShowColumnStripes: formatSet.ShowColumnStripes,
 		},
 	}
+	if formatSet.ShowTotalsRow {
+		if err = f.addTableTotalsRow(sheet, &t, formatSet.TotalsRow); err != nil {
+			return err
+		}
+	}
 	table, _ := xml.Marshal(t)
 	f.saveFileList(tableXML, table)
 	return nil
 }
 
+// SetTableTotalsRow provides the method to show or hide the totals row of the
+// table by given table name and format set, the table name is
+// case-insensitive. The totals row will be added below the last row of the
+// table, and the existing totals row will be replaced. For example, show the
+// totals row of the table "Table1" with the sum of the column "Score":
+//
+//    err := f.SetTableTotalsRow("Table1", `{"show_totals_row":true,"totals_row":[{"column":"Name","label":"Total"},{"column":"Score","function":"sum"}]}`)
+//
+// Hide the totals row of the table "Table1":
+//
+//    err := f.SetTableTotalsRow("Table1", `{"show_totals_row":false}`)
+//
+// The same options could be used in the format set of AddTable. The label
+// "Total" in the first column and the sum in the last column will be used if
+// the columns of the totals row are not specified. The function of the column
+// in the totals row could be one of the following:
+//
+//    average
+//    count
+//    countNums
+//    max
+//    min
+//    none
+//    stdDev
+//    sum
+//    var
+//
+func (f *File) SetTableTotalsRow(name, format string) error {
+	formatSet, err := parseFormatTableSet(format)
+	if err != nil {
+		return err
+	}
+	sheet, _, tableXML, table, err := f.getTablePart(name)
+	if err != nil {
+		return err
+	}
+	if table.TotalsRowCount > 0 {
+		if err = f.deleteTableTotalsRow(sheet, table); err != nil {
+			return err
+		}
+	}
+	if formatSet.ShowTotalsRow {
+		if err = f.addTableTotalsRow(sheet, table, formatSet.TotalsRow); err != nil {
+			return err
+		}
+	}
+	output, _ := xml.Marshal(table)
+	f.saveFileList(tableXML, output)
+	return err
+}
+
+// tableTotalsRowFunctions defined the functions of the totals row of the
+// table and the function number of SUBTOTAL which ignores hidden rows.
+var tableTotalsRowFunctions = map[string]int{
+	"average":   101,
+	"count":     103,
+	"countNums": 102,
+	"max":       104,
+	"min":       105,
+	"none":      0,
+	"stdDev":    107,
+	"sum":       109,
+	"var":       110,
+}
+
+// addTableTotalsRow provides a function to add the totals row below the last
+// row of the table by given worksheet name, table and the settings of the
+// columns in the totals row.
+func (f *File) addTableTotalsRow(sheet string, table *xlsxTable, totalsRow []formatTableTotalsRow) error {
+	coordinates, err := f.areaRefToCoordinates(table.Ref)
+	if err != nil {
+		return err
+	}
+	if table.TableColumns == nil || len(table.TableColumns.TableColumn) == 0 {
+		return fmt.Errorf("table %s has no columns", table.Name)
+	}
+	columns := table.TableColumns.TableColumn
+	if len(totalsRow) == 0 {
+		totalsRow = []formatTableTotalsRow{{Column: columns[0].Name, Label: "Total"}}
+		if len(columns) > 1 {
+			totalsRow = append(totalsRow, formatTableTotalsRow{Column: columns[len(columns)-1].Name, Function: "sum"})
+		}
+	}
+	for _, column := range columns {
+		column.TotalsRowFunction, column.TotalsRowLabel = "", ""
+	}
+	row := coordinates[3] + 1
+	for _, opts := range totalsRow {
+		idx := -1
+		for i, column := range columns {
+			if strings.EqualFold(column.Name, opts.Column) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return fmt.Errorf("column %s is not exist in the table %s", opts.Column, table.Name)
+		}
+		column := columns[idx]
+		cell, err := CoordinatesToCellName(coordinates[0]+idx, row)
+		if err != nil {
+			return err
+		}
+		if opts.Function == "" || opts.Function == "none" {
+			column.TotalsRowLabel = opts.Label
+			if err = f.SetCellStr(sheet, cell, opts.Label); err != nil {
+				return err
+			}
+			continue
+		}
+		var function string
+		for name := range tableTotalsRowFunctions {
+			if strings.EqualFold(name, opts.Function) {
+				function = name
+			}
+		}
+		if function == "" {
+			return fmt.Errorf("unsupported totals row function %s", opts.Function)
+		}
+		column.TotalsRowFunction = function
+		if err = f.SetCellFormula(sheet, cell, fmt.Sprintf("SUBTOTAL(%d,%s[%s])",
+			tableTotalsRowFunctions[function], table.Name, escapeStructuredReference(column.Name))); err != nil {
+			return err
+		}
+	}
+	if table.AutoFilter != nil {
+		table.AutoFilter.Ref = table.Ref
+	}
+	coordinates[3] = row
+	table.Ref, _ = f.coordinatesToAreaRef(coordinates)
+	table.TotalsRowCount, table.TotalsRowShown = 1, true
+	return err
+}
+
+// deleteTableTotalsRow provides a function to delete the totals row of the
+// table and clear the cells in the totals row by given worksheet name and
+// table.
+func (f *File) deleteTableTotalsRow(sheet string, table *xlsxTable) error {
+	coordinates, err := f.areaRefToCoordinates(table.Ref)
+	if err != nil {
+		return err
+	}
+	for col := coordinates[0]; col <= coordinates[2]; col++ {
+		cell, err := CoordinatesToCellName(col, coordinates[3])
+		if err != nil {
+			return err
+		}
+		if err = f.SetCellFormula(sheet, cell, ""); err != nil {
+			return err
+		}
+		if err = f.SetCellStr(sheet, cell, ""); err != nil {
+			return err
+		}
+	}
+	if table.TableColumns != nil {
+		for _, column := range table.TableColumns.TableColumn {
+			column.TotalsRowFunction, column.TotalsRowLabel = "", ""
+		}
+	}
+	coordinates[3] -= table.TotalsRowCount
+	table.Ref, _ = f.coordinatesToAreaRef(coordinates)
+	if table.AutoFilter != nil {
+		table.AutoFilter.Ref = table.Ref
+	}
+	table.TotalsRowCount = 0
+	return err
+}
+
+// escapeStructuredReference provides a function to escape the special
+// characters in the column name of the structured reference.
+func escapeStructuredReference(name string) string {
+	return strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(name)
+}
+
+// GetTables provides the method to get all tables in a worksheet by given
+// worksheet name. For example, get the tables on Sheet1:
+//
+//    tables, err := f.GetTables("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for _, table := range tables {
+//        fmt.Println(table.Name, table.Range, table.Columns)
+//    }
+//
+func (f *File) GetTables(sheet string) ([]Table, error) {
+	tables := []Table{}
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return tables, err
+	}
+	var rels = "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet)], "xl/worksheets/") + ".rels"
+	sheetRels := f.relsReader(rels)
+	if sheetRels == nil {
+		return tables, nil
+	}
+	for _, v := range sheetRels.Relationships {
+		if v.Type != SourceRelationshipTable {
+			continue
+		}
+		t, err := f.tableReader(strings.Replace(v.Target, "..", "xl", 1))
+		if err != nil {
+			return tables, err
+		}
+		table := Table{Name: t.Name, Range: t.Ref, ShowTotalsRow: t.TotalsRowCount > 0}
+		if t.TableStyleInfo != nil {
+			table.TableStyle = t.TableStyleInfo.Name
+			table.ShowFirstColumn = t.TableStyleInfo.ShowFirstColumn
+			table.ShowLastColumn = t.TableStyleInfo.ShowLastColumn
+			table.ShowRowStripes = t.TableStyleInfo.ShowRowStripes
+			table.ShowColumnStripes = t.TableStyleInfo.ShowColumnStripes
+		}
+		if t.TableColumns != nil {
+			for _, column := range t.TableColumns.TableColumn {
+				table.Columns = append(table.Columns, column.Name)
+			}
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// tableReader provides a function to get the pointer to the structure after
+// deserialization of the table by given path, such as xl/tables/table1.xml.
+func (f *File) tableReader(path string) (*xlsxTable, error) {
+	table := new(xlsxTable)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+		Decode(table); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return table, nil
+}
+
+// DeleteTable provides the method to delete table by given table name, the
+// table name is case-insensitive. The cell data in the range of the table
+// will be kept as the plain cells. The table used by the slicers can't be
+// deleted, and an error will be returned. For example, delete the table
+// named "Table1":
+//
+//    err := f.DeleteTable("Table1")
+//
+func (f *File) DeleteTable(name string) error {
+	sheet, rID, tableXML, table, err := f.getTablePart(name)
+	if err != nil {
+		return err
+	}
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	cacheName, err := f.getTableSlicerCache(table.ID)
+	if err != nil {
+		return err
+	}
+	if cacheName != "" {
+		return fmt.Errorf("table %s is used by the slicer cache %s", name, cacheName)
+	}
+	if xlsx.TableParts != nil {
+		for k, v := range xlsx.TableParts.TableParts {
+			if v.RID == rID {
+				xlsx.TableParts.TableParts = append(xlsx.TableParts.TableParts[:k], xlsx.TableParts.TableParts[k+1:]...)
+				break
+			}
+		}
+		xlsx.TableParts.Count = len(xlsx.TableParts.TableParts)
+		if xlsx.TableParts.Count == 0 {
+			xlsx.TableParts = nil
+		}
+	}
+	f.deleteSheetRelationships(sheet, rID)
+	f.deleteTablePart(tableXML)
+	return nil
+}
+
+// ResizeTable provides the method to resize the table by given table name
+// and the new cell range of the table, the table name is case-insensitive.
+// The new range must start at the header row of the table, and the names of
+// the existing columns will be kept if the columns are still covered by the
+// new range. For example, extend the table "Table1" of A1:D5 to A1:E10:
+//
+//    err := f.ResizeTable("Table1", "A1:E10")
+//
+func (f *File) ResizeTable(name, newRange string) error {
+	rng := strings.Split(newRange, ":")
+	if len(rng) != 2 {
+		return fmt.Errorf("invalid area %q", newRange)
+	}
+	coordinates, err := areaRangeToCoordinates(rng[0], rng[1])
+	if err != nil {
+		return err
+	}
+	_ = sortCoordinates(coordinates)
+	sheet, _, tableXML, table, err := f.getTablePart(name)
+	if err != nil {
+		return err
+	}
+	oldCoordinates, err := f.areaRefToCoordinates(table.Ref)
+	if err != nil {
+		return err
+	}
+	if coordinates[1] != oldCoordinates[1] {
+		return fmt.Errorf("the new range %s of the table %s must start at the header row %d", newRange, table.Name, oldCoordinates[1])
+	}
+	// Correct the minimum number of rows, the table at least two lines.
+	if coordinates[1] == coordinates[3] {
+		coordinates[3]++
+	}
+	ref, err := f.coordinatesToAreaRef(coordinates)
+	if err != nil {
+		return err
+	}
+	columns := map[int]*xlsxTableColumn{}
+	names, maxID := map[string]bool{}, 0
+	if table.TableColumns != nil {
+		for i, column := range table.TableColumns.TableColumn {
+			if col := oldCoordinates[0] + i; col >= coordinates[0] && col <= coordinates[2] {
+				columns[col] = column
+				names[strings.ToLower(column.Name)] = true
+			}
+			if column.ID > maxID {
+				maxID = column.ID
+			}
+		}
+	}
+	var tableColumn []*xlsxTableColumn
+	for col := coordinates[0]; col <= coordinates[2]; col++ {
+		if column, ok := columns[col]; ok {
+			tableColumn = append(tableColumn, column)
+			continue
+		}
+		cell, err := CoordinatesToCellName(col, coordinates[1])
+		if err != nil {
+			return err
+		}
+		columnName, _ := f.GetCellValue(sheet, cell)
+		if columnName == "" || names[strings.ToLower(columnName)] {
+			for idx := len(tableColumn) + 1; ; idx++ {
+				if columnName = "Column" + strconv.Itoa(idx); !names[strings.ToLower(columnName)] {
+					break
+				}
+			}
+			_ = f.SetCellStr(sheet, cell, columnName)
+		}
+		names[strings.ToLower(columnName)] = true
+		maxID++
+		tableColumn = append(tableColumn, &xlsxTableColumn{ID: maxID, Name: columnName})
+	}
+	table.Ref = ref
+	table.TableColumns = &xlsxTableColumns{Count: len(tableColumn), TableColumn: tableColumn}
+	if table.AutoFilter != nil {
+		table.AutoFilter.Ref = ref
+		if table.TotalsRowCount > 0 && coordinates[3]-table.TotalsRowCount > coordinates[1] {
+			coordinates[3] -= table.TotalsRowCount
+			table.AutoFilter.Ref, _ = f.coordinatesToAreaRef(coordinates)
+		}
+		filterColumns := table.AutoFilter.FilterColumn[:0]
+		for _, filterColumn := range table.AutoFilter.FilterColumn {
+			filterColumn.ColID += oldCoordinates[0] - coordinates[0]
+			if filterColumn.ColID >= 0 && filterColumn.ColID < len(tableColumn) {
+				filterColumns = append(filterColumns, filterColumn)
+			}
+		}
+		table.AutoFilter.FilterColumn = filterColumns
+	}
+	output, _ := xml.Marshal(table)
+	f.saveFileList(tableXML, output)
+	return nil
+}
+
+// getTablePart provides a function to get the worksheet name, relationship
+// index, part path and the table by given table name, the table name is
+// case-insensitive.
+func (f *File) getTablePart(name string) (string, string, string, *xlsxTable, error) {
+	for _, sheet := range f.workbookReader().Sheets.Sheet {
+		var rels = "xl/worksheets/_rels/" + strings.TrimPrefix(f.sheetMap[trimSheetName(sheet.Name)], "xl/worksheets/") + ".rels"
+		sheetRels := f.relsReader(rels)
+		if sheetRels == nil {
+			continue
+		}
+		for _, v := range sheetRels.Relationships {
+			if v.Type != SourceRelationshipTable {
+				continue
+			}
+			tableXML := strings.Replace(v.Target, "..", "xl", 1)
+			table, err := f.tableReader(tableXML)
+			if err != nil {
+				return "", "", "", nil, err
+			}
+			if strings.EqualFold(table.Name, name) {
+				return sheet.Name, v.ID, tableXML, table, nil
+			}
+		}
+	}
+	return "", "", "", nil, fmt.Errorf("table %s is not exist", name)
+}
+
+// deleteTablePart provides a function to delete the table part and the
+// content type part of the table by given table part path.
+func (f *File) deleteTablePart(tableXML string) {
+	delete(f.XLSX, tableXML)
+	content := f.contentTypesReader()
+	for k, v := range content.Overrides {
+		if v.PartName == "/"+tableXML {
+			content.Overrides = append(content.Overrides[:k], content.Overrides[k+1:]...)
+			break
+		}
+	}
+}
+
 // parseAutoFilterSet provides a function to parse the settings of the auto
 // filter.
 func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
@@ -201,13 +625,37 @@ func parseAutoFilterSet(formatSet string) (*formatAutoFilter, error) {
 //
 //    err := f.AutoFilter("Sheet1", "A1", "D4", `{"column":"B","expression":"x != blanks"}`)
 //
+// Filter data in multiple columns of an autofilter, column B equals "A" or
+// "B", and column C greater than 100:
+//
+//    err := f.AutoFilter("Sheet1", "A1", "D4", `{"columns":[{"column":"B","values":["A","B"]},{"column":"C","custom_filters":[{"operator":">","value":"100"}]}]}`)
+//
 // column defines the filter columns in a autofilter range based on simple
 // criteria
 //
-// It isn't sufficient to just specify the filter condition. You must also
-// hide any rows that don't match the filter condition. Rows are hidden using
-// the SetRowVisible() method. Excelize can't filter rows automatically since
-// this isn't part of the file format.
+// columns defines the filter criteria of multiple columns, each column can be
+// set with one of the following criteria:
+//
+//    expression     - filter expression as described below
+//    values         - list of values to be shown, an empty string value
+//                     matches blank cells
+//    custom_filters - at most two custom filters with operator and value,
+//                     use "and": true to join them with the 'and' operator
+//
+// Note that only the filter criteria will be stored in the worksheet by
+// default, it isn't sufficient to just specify the filter condition. You
+// must also hide any rows that don't match the filter condition. Rows are
+// hidden using the SetRowVisible() method, or set apply_filter_hide to true
+// to hide the rows which don't satisfy the criteria automatically:
+//
+//    err := f.AutoFilter("Sheet1", "A1", "D4", `{"column":"B","expression":"x > 100","apply_filter_hide":true}`)
+//
+// When evaluating the criteria, numeric cell values are compared with the
+// numeric criteria values, and the date criteria value in the format
+// yyyy-mm-dd or yyyy-mm-dd hh:mm:ss are compared as the Excel serial date.
+// The blank cells only satisfy the blanks and not equal criteria. Other
+// values are compared as case insensitive text with the displayed cell
+// value.
 //
 // Setting a filter criteria for a column:
 //
@@ -291,7 +739,184 @@ func (f *File) AutoFilter(sheet, hcell, vcell, format string) error {
 	}
 	ref := cellStart + ":" + cellEnd
 	refRange := vcol - hcol
-	return f.autoFilter(sheet, ref, refRange, hcol, formatSet)
+	if err = f.autoFilter(sheet, ref, refRange, hcol, formatSet); err != nil || !formatSet.ApplyFilterHide {
+		return err
+	}
+	return f.hideFilteredRows(sheet, hcol, hrow, vrow)
+}
+
+// hideFilteredRows provides a function to set the hidden attribute of the
+// rows in the auto filter range by given worksheet name, the first column
+// number, header row number and the last row number of the range.
+func (f *File) hideFilteredRows(sheet string, col, hrow, vrow int) error {
+	xlsx, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	date1904 := f.isDate1904()
+	for row := hrow + 1; row <= vrow; row++ {
+		visible := true
+		for _, filterColumn := range xlsx.AutoFilter.FilterColumn {
+			cell, err := CoordinatesToCellName(col+filterColumn.ColID, row)
+			if err != nil {
+				return err
+			}
+			raw, err := f.getCellStringFunc(sheet, cell, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+				if c.T == "" || c.T == "n" {
+					return c.V, true, nil
+				}
+				val, err := c.getValueFrom(f, f.sharedStringsReader())
+				return val, true, err
+			})
+			if err != nil {
+				return err
+			}
+			formatted, err := f.GetCellValue(sheet, cell)
+			if err != nil {
+				return err
+			}
+			if !matchFilterColumn(filterColumn, raw, formatted, date1904) {
+				visible = false
+				break
+			}
+		}
+		if rowVisible, _ := f.GetRowVisible(sheet, row); !visible || !rowVisible {
+			if err = f.SetRowVisible(sheet, row, visible); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// matchFilterColumn provides a function to check if the cell value satisfies
+// the filter criteria of the column by given raw and formatted cell value.
+// The criteria which can't be evaluated, such as color filter and dynamic
+// filter will be ignored.
+func matchFilterColumn(filterColumn *xlsxFilterColumn, raw, formatted string, date1904 bool) bool {
+	if filterColumn.Filters != nil {
+		for _, filter := range filterColumn.Filters.Filter {
+			if raw == "" {
+				if strings.ToLower(filter.Val) == "blanks" {
+					return true
+				}
+				continue
+			}
+			if strings.EqualFold(filter.Val, formatted) || strings.EqualFold(filter.Val, raw) {
+				return true
+			}
+			if matchWildcard(filter.Val, formatted) {
+				return true
+			}
+		}
+		return raw == "" && filterColumn.Filters.Blank
+	}
+	if filterColumn.CustomFilters != nil {
+		for _, customFilter := range filterColumn.CustomFilters.CustomFilter {
+			matched := matchCustomFilter(customFilter, raw, formatted, date1904)
+			if filterColumn.CustomFilters.And && !matched {
+				return false
+			}
+			if !filterColumn.CustomFilters.And && matched {
+				return true
+			}
+		}
+		return filterColumn.CustomFilters.And
+	}
+	return true
+}
+
+// matchCustomFilter provides a function to check if the cell value satisfies
+// the custom filter by given raw and formatted cell value.
+func matchCustomFilter(customFilter *xlsxCustomFilter, raw, formatted string, date1904 bool) bool {
+	operator := customFilter.Operator
+	if operator == "" {
+		operator = "equal"
+	}
+	if raw == "" {
+		return operator == "notEqual" && strings.TrimSpace(customFilter.Val) != "" ||
+			operator == "equal" && strings.TrimSpace(customFilter.Val) == ""
+	}
+	if strings.TrimSpace(customFilter.Val) == "" {
+		return operator == "notEqual"
+	}
+	num, err := strconv.ParseFloat(raw, 64)
+	criteria, ok := parseFilterCriteriaNumber(customFilter.Val, date1904)
+	if err == nil && ok {
+		switch operator {
+		case "lessThan":
+			return num < criteria
+		case "lessThanOrEqual":
+			return num <= criteria
+		case "greaterThan":
+			return num > criteria
+		case "greaterThanOrEqual":
+			return num >= criteria
+		case "notEqual":
+			return num != criteria
+		}
+		return num == criteria
+	}
+	if ok && operator != "equal" && operator != "notEqual" {
+		// The text value doesn't satisfy the numeric comparison criteria.
+		return false
+	}
+	val, text := strings.ToLower(customFilter.Val), strings.ToLower(formatted)
+	switch operator {
+	case "lessThan":
+		return text < val
+	case "lessThanOrEqual":
+		return text <= val
+	case "greaterThan":
+		return text > val
+	case "greaterThanOrEqual":
+		return text >= val
+	case "notEqual":
+		return !matchWildcard(val, text)
+	}
+	return matchWildcard(val, text)
+}
+
+// parseFilterCriteriaNumber provides a function to convert the criteria value
+// of the filter to number, the date criteria will be converted to the Excel
+// serial date in the given date system.
+func parseFilterCriteriaNumber(val string, date1904 bool) (float64, bool) {
+	if num, err := strconv.ParseFloat(val, 64); err == nil {
+		return num, true
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, val); err == nil {
+			if num, err := timeToExcelTime(t, date1904); err == nil {
+				return num, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// matchWildcard provides a function to check if the text matches the filter
+// value which may contain the '*' and '?' wildcard characters, the wildcard
+// characters can be escaped using '~'.
+func matchWildcard(pattern, text string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; {
+		case c == '~' && i+1 < len(runes):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case c == '*':
+			expr.WriteString(".*")
+		case c == '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	return err == nil && re.MatchString(text)
 }
 
 // autoFilter provides a function to extract the tokens from the filter
@@ -303,72 +928,116 @@ func (f *File) autoFilter(sheet, ref string, refRange, col int, formatSet *forma
 	}
 	if xlsx.SheetPr != nil {
 		xlsx.SheetPr.FilterMode = true
+	} else {
+		xlsx.SheetPr = &xlsxSheetPr{FilterMode: true}
 	}
-	xlsx.SheetPr = &xlsxSheetPr{FilterMode: true}
 	filter := &xlsxAutoFilter{
 		Ref: ref,
 	}
 	xlsx.AutoFilter = filter
-	if formatSet.Column == "" || formatSet.Expression == "" {
-		return nil
+	columns := formatSet.Columns
+	if formatSet.Column != "" && formatSet.Expression != "" {
+		columns = append([]formatAutoFilterColumn{{Column: formatSet.Column, Expression: formatSet.Expression}}, columns...)
 	}
+	colIDs := map[int]bool{}
+	for _, column := range columns {
+		filterColumn, err := f.parseAutoFilterColumn(column, refRange, col)
+		if err != nil {
+			return err
+		}
+		if colIDs[filterColumn.ColID] {
+			return fmt.Errorf("duplicate filter criteria of column '%s'", column.Column)
+		}
+		colIDs[filterColumn.ColID] = true
+		filter.FilterColumn = append(filter.FilterColumn, filterColumn)
+	}
+	return nil
+}
 
-	fsCol, err := ColumnNameToNumber(formatSet.Column)
+// parseAutoFilterColumn provides a function to convert the filter criteria
+// settings of a column to the filterColumn element.
+func (f *File) parseAutoFilterColumn(column formatAutoFilterColumn, refRange, col int) (*xlsxFilterColumn, error) {
+	fsCol, err := ColumnNameToNumber(column.Column)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	offset := fsCol - col
 	if offset < 0 || offset > refRange {
-		return fmt.Errorf("incorrect index of column '%s'", formatSet.Column)
+		return nil, fmt.Errorf("incorrect index of column '%s'", column.Column)
 	}
-
-	filter.FilterColumn = &xlsxFilterColumn{
+	filterColumn := &xlsxFilterColumn{
 		ColID: offset,
 	}
-	re := regexp.MustCompile(`"(?:[^"]|"")*"|\S+`)
-	token := re.FindAllString(formatSet.Expression, -1)
-	if len(token) != 3 && len(token) != 7 {
-		return fmt.Errorf("incorrect number of tokens in criteria '%s'", formatSet.Expression)
-	}
-	expressions, tokens, err := f.parseFilterExpression(formatSet.Expression, token)
-	if err != nil {
-		return err
+	switch {
+	case column.Expression != "":
+		re := regexp.MustCompile(`"(?:[^"]|"")*"|\S+`)
+		token := re.FindAllString(column.Expression, -1)
+		if len(token) != 3 && len(token) != 7 {
+			return nil, fmt.Errorf("incorrect number of tokens in criteria '%s'", column.Expression)
+		}
+		expressions, tokens, err := f.parseFilterExpression(column.Expression, token)
+		if err != nil {
+			return nil, err
+		}
+		f.writeAutoFilter(filterColumn, expressions, tokens)
+	case len(column.Values) > 0:
+		filterColumn.Filters = &xlsxFilters{}
+		for _, val := range column.Values {
+			if val == "" {
+				filterColumn.Filters.Blank = true
+				continue
+			}
+			filterColumn.Filters.Filter = append(filterColumn.Filters.Filter, &xlsxFilter{Val: val})
+		}
+	case len(column.CustomFilters) > 0:
+		if len(column.CustomFilters) > 2 {
+			return nil, fmt.Errorf("the number of custom filters of column '%s' exceeds the limit 2", column.Column)
+		}
+		operators := map[string]int{"<": 1, "==": 2, "=": 2, "<=": 3, ">": 4, "!=": 5, "<>": 5, ">=": 6}
+		for _, customFilter := range column.CustomFilters {
+			operator, ok := operators[customFilter.Operator]
+			if !ok {
+				return nil, fmt.Errorf("unknown operator: %s", customFilter.Operator)
+			}
+			f.writeCustomFilter(filterColumn, operator, customFilter.Value)
+		}
+		filterColumn.CustomFilters.And = column.And && len(column.CustomFilters) == 2
+	default:
+		return nil, fmt.Errorf("missing filter criteria of column '%s'", column.Column)
 	}
-	f.writeAutoFilter(filter, expressions, tokens)
-	xlsx.AutoFilter = filter
-	return nil
+	return filterColumn, nil
 }
 
 // writeAutoFilter provides a function to check for single or double custom
 // filters as default filters and handle them accordingly.
-func (f *File) writeAutoFilter(filter *xlsxAutoFilter, exp []int, tokens []string) {
+func (f *File) writeAutoFilter(filterColumn *xlsxFilterColumn, exp []int, tokens []string) {
 	if len(exp) == 1 && exp[0] == 2 {
 		// Single equality.
 		var filters []*xlsxFilter
 		filters = append(filters, &xlsxFilter{Val: tokens[0]})
-		filter.FilterColumn.Filters = &xlsxFilters{Filter: filters}
+		filterColumn.Filters = &xlsxFilters{Filter: filters}
 	} else if len(exp) == 3 && exp[0] == 2 && exp[1] == 1 && exp[2] == 2 {
 		// Double equality with "or" operator.
 		filters := []*xlsxFilter{}
 		for _, v := range tokens {
 			filters = append(filters, &xlsxFilter{Val: v})
 		}
-		filter.FilterColumn.Filters = &xlsxFilters{Filter: filters}
+		filterColumn.Filters = &xlsxFilters{Filter: filters}
 	} else {
 		// Non default custom filter.
 		expRel := map[int]int{0: 0, 1: 2}
 		andRel := map[int]bool{0: true, 1: false}
 		for k, v := range tokens {
-			f.writeCustomFilter(filter, exp[expRel[k]], v)
+			f.writeCustomFilter(filterColumn, exp[expRel[k]], v)
 			if k == 1 {
-				filter.FilterColumn.CustomFilters.And = andRel[exp[k]]
+				filterColumn.CustomFilters.And = andRel[exp[k]]
 			}
 		}
 	}
 }
 
 // writeCustomFilter provides a function to write the <customFilter> element.
-func (f *File) writeCustomFilter(filter *xlsxAutoFilter, operator int, val string) {
+func (f *File) writeCustomFilter(filterColumn *xlsxFilterColumn, operator int, val string) {
 	operators := map[int]string{
 		1:  "lessThan",
 		2:  "equal",
@@ -382,12 +1051,12 @@ func (f *File) writeCustomFilter(filter *xlsxAutoFilter, operator int, val strin
 		Operator: operators[operator],
 		Val:      val,
 	}
-	if filter.FilterColumn.CustomFilters != nil {
-		filter.FilterColumn.CustomFilters.CustomFilter = append(filter.FilterColumn.CustomFilters.CustomFilter, &customFilter)
+	if filterColumn.CustomFilters != nil {
+		filterColumn.CustomFilters.CustomFilter = append(filterColumn.CustomFilters.CustomFilter, &customFilter)
 	} else {
 		customFilters := []*xlsxCustomFilter{}
 		customFilters = append(customFilters, &customFilter)
-		filter.FilterColumn.CustomFilters = &xlsxCustomFilters{CustomFilter: customFilters}
+		filterColumn.CustomFilters = &xlsxCustomFilters{CustomFilter: customFilters}
 	}
 }
 
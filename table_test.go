@@ -1,9 +1,11 @@
 package excelize
 
 import (
+	"encoding/xml"
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -43,6 +45,221 @@ func TestAddTable(t *testing.T) {
 	assert.EqualError(t, f.addTable("sheet1", "", 1, 1, 0, 0, 0, nil), "invalid cell coordinates [0, 0]")
 }
 
+func TestTableTotalsRow(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Score", "Rank[1]"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Alice", 90, 1}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "C2", `{"table_name":"scores","show_totals_row":true}`))
+	table, err := f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:C3", table.Ref)
+	assert.Equal(t, "A1:C2", table.AutoFilter.Ref)
+	assert.Equal(t, 1, table.TotalsRowCount)
+	assert.Equal(t, "Total", table.TableColumns.TableColumn[0].TotalsRowLabel)
+	assert.Equal(t, "sum", table.TableColumns.TableColumn[2].TotalsRowFunction)
+	val, err := f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Total", val)
+	formula, err := f.GetCellFormula("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUBTOTAL(109,scores[Rank'[1']])", formula)
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, tables[0].ShowTotalsRow)
+
+	// Test replace the totals row with the specified functions.
+	assert.NoError(t, f.SetTableTotalsRow("Scores", `{"show_totals_row":true,"totals_row":[{"column":"Name","label":"Summary"},{"column":"score","function":"average"},{"column":"Rank[1]","function":"countnums"}]}`))
+	table, err = f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:C3", table.Ref)
+	assert.Equal(t, "Summary", table.TableColumns.TableColumn[0].TotalsRowLabel)
+	assert.Equal(t, "average", table.TableColumns.TableColumn[1].TotalsRowFunction)
+	assert.Equal(t, "countNums", table.TableColumns.TableColumn[2].TotalsRowFunction)
+	for cell, expected := range map[string]string{"B3": "SUBTOTAL(101,scores[Score])", "C3": "SUBTOTAL(102,scores[Rank'[1']])"} {
+		formula, err = f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+
+	// Test hide the totals row.
+	assert.NoError(t, f.SetTableTotalsRow("scores", `{"show_totals_row":false}`))
+	table, err = f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:C2", table.Ref)
+	assert.Equal(t, 0, table.TotalsRowCount)
+	assert.Empty(t, table.TableColumns.TableColumn[1].TotalsRowFunction)
+	val, err = f.GetCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Empty(t, val)
+	formula, err = f.GetCellFormula("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+
+	// Test set totals row with invalid settings.
+	assert.EqualError(t, f.SetTableTotalsRow("scores", `{"show_totals_row":true,"totals_row":[{"column":"Level","function":"sum"}]}`), "column Level is not exist in the table scores")
+	assert.EqualError(t, f.SetTableTotalsRow("scores", `{"show_totals_row":true,"totals_row":[{"column":"Score","function":"median"}]}`), "unsupported totals row function median")
+	assert.EqualError(t, f.SetTableTotalsRow("scores", `{x}`), "invalid character 'x' looking for beginning of object key string")
+	assert.EqualError(t, f.SetTableTotalsRow("TableN", `{}`), "table TableN is not exist")
+	assert.EqualError(t, f.AddTable("Sheet1", "E1", "F2", `{"show_totals_row":true,"totals_row":[{"column":"Level"}]}`), "column Level is not exist in the table Table2")
+}
+
+func TestGetTables(t *testing.T) {
+	f := NewFile()
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, tables)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Score"}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B3", `{"table_name":"scores","table_style":"TableStyleMedium2","show_first_column":true}`))
+	assert.NoError(t, f.AddTable("Sheet1", "D1", "E2", ``))
+	tables, err = f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []Table{
+		{Name: "scores", Range: "A1:B3", TableStyle: "TableStyleMedium2", ShowFirstColumn: true, ShowRowStripes: true, Columns: []string{"Name", "Score"}},
+		{Name: "Table2", Range: "D1:E2", ShowRowStripes: true, Columns: []string{"Column1", "Column2"}},
+	}, tables)
+
+	// Test get tables on the worksheet created by the stream writer.
+	f.NewSheet("Sheet2")
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"ID", "Value"}))
+	assert.NoError(t, sw.SetRow("A2", []interface{}{1, 2}))
+	assert.NoError(t, sw.AddTable("A1", "B2", ``))
+	assert.NoError(t, sw.Flush())
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "A1:B2", tables[0].Range)
+	assert.Equal(t, []string{"ID", "Value"}, tables[0].Columns)
+
+	// Test get tables on not exists worksheet.
+	_, err = f.GetTables("SheetN")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+	// Test get tables with unsupported charset table part.
+	f.XLSX["xl/tables/table1.xml"] = MacintoshCyrillicCharset
+	_, err = f.GetTables("Sheet1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestDeleteTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Score"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Alice", 90}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B2", `{"table_name":"scores"}`))
+	assert.NoError(t, f.AddTable("Sheet1", "D1", "E2", ``))
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.AddTable("Sheet2", "A1", "B2", ``))
+	assert.NoError(t, f.DeleteTable("Scores"))
+	// Test the remaining table parts keep the paths and IDs.
+	_, ok := f.XLSX["xl/tables/table1.xml"]
+	assert.False(t, ok)
+	table, err := f.tableReader("xl/tables/table3.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "Table3", table.Name)
+	assert.Equal(t, 3, table.ID)
+	xlsx, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, xlsx.TableParts.Count)
+	// Test add table after the table has been deleted.
+	assert.NoError(t, f.AddTable("Sheet1", "G1", "H2", `{"table_name":"totals"}`))
+	table, err = f.tableReader("xl/tables/table4.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "totals", table.Name)
+	assert.Equal(t, 4, table.ID)
+
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 2)
+	assert.Equal(t, "D1:E2", tables[0].Range)
+	assert.Equal(t, "G1:H2", tables[1].Range)
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "Table3", tables[0].Name)
+	val, err := f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", val)
+
+	// Test delete the last table on the worksheet.
+	assert.NoError(t, f.DeleteTable("Table3"))
+	xlsx, err = f.workSheetReader("Sheet2")
+	assert.NoError(t, err)
+	assert.Nil(t, xlsx.TableParts)
+	tables, err = f.GetTables("Sheet2")
+	assert.NoError(t, err)
+	assert.Empty(t, tables)
+	for _, override := range f.contentTypesReader().Overrides {
+		assert.NotEqual(t, "/xl/tables/table3.xml", override.PartName)
+	}
+
+	// Test delete not exists table.
+	assert.EqualError(t, f.DeleteTable("TableN"), "table TableN is not exist")
+	// Test delete table with unsupported charset table part.
+	f.XLSX["xl/tables/table4.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.DeleteTable("totals"), "XML syntax error on line 1: invalid UTF-8")
+
+	// Test delete the table used by the slicer.
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Score"}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B2", `{"table_name":"T1"}`))
+	assert.NoError(t, f.AddTable("Sheet1", "D1", "E2", `{"table_name":"T2"}`))
+	assert.NoError(t, f.AddSlicer(SlicerOptions{Name: "Name", Sheet: "Sheet1", Cell: "G1", TableName: "T1"}))
+	assert.EqualError(t, f.DeleteTable("T1"), "table T1 is used by the slicer cache Slicer_Name")
+	assert.NoError(t, f.DeleteTable("T2"))
+	table, err = f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "T1", table.Name)
+	assert.Contains(t, string(f.XLSX["xl/slicerCaches/slicerCache1.xml"]), `tableId="1"`)
+	// Test delete table with unsupported charset slicer cache part.
+	f.XLSX["xl/slicerCaches/slicerCache1.xml"] = MacintoshCyrillicCharset
+	assert.EqualError(t, f.DeleteTable("T1"), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestResizeTable(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Score", "Level"}))
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B3", `{"table_name":"scores"}`))
+	// Test extend the table with more rows and columns.
+	assert.NoError(t, f.ResizeTable("Scores", "A1:D10"))
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:D10", tables[0].Range)
+	assert.Equal(t, []string{"Name", "Score", "Level", "Column4"}, tables[0].Columns)
+	val, err := f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Column4", val)
+	table, err := f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1:D10", table.AutoFilter.Ref)
+	assert.Equal(t, 4, table.TableColumns.TableColumn[3].ID)
+
+	// Test shrink the table and keep the names of existing columns.
+	table.AutoFilter.FilterColumn = []*xlsxFilterColumn{{ColID: 0}, {ColID: 2}}
+	output, err := xml.Marshal(table)
+	assert.NoError(t, err)
+	f.saveFileList("xl/tables/table1.xml", output)
+	assert.NoError(t, f.ResizeTable("scores", "C5:B1"))
+	table, err = f.tableReader("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	assert.Equal(t, "B1:C5", table.Ref)
+	assert.Equal(t, 2, table.TableColumns.Count)
+	assert.Equal(t, "Score", table.TableColumns.TableColumn[0].Name)
+	assert.Equal(t, 2, table.TableColumns.TableColumn[0].ID)
+	assert.Len(t, table.AutoFilter.FilterColumn, 1)
+	assert.Equal(t, 1, table.AutoFilter.FilterColumn[0].ColID)
+
+	// Test resize table with invalid range.
+	assert.EqualError(t, f.ResizeTable("scores", "B2:C5"), "the new range B2:C5 of the table scores must start at the header row 1")
+	assert.EqualError(t, f.ResizeTable("scores", "B1"), `invalid area "B1"`)
+	assert.EqualError(t, f.ResizeTable("scores", "B1:C"), `cannot convert cell "C" to coordinates: invalid cell name "C"`)
+	// Test resize not exists table.
+	assert.EqualError(t, f.ResizeTable("TableN", "A1:B2"), "table TableN is not exist")
+}
+
 func TestAutoFilter(t *testing.T) {
 	outFile := filepath.Join("test", "TestAutoFilter%d.xlsx")
 
@@ -114,6 +331,66 @@ func TestAutoFilterError(t *testing.T) {
 	}), `incorrect number of tokens in criteria '-'`)
 }
 
+func TestAutoFilterColumns(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AutoFilter("Sheet1", "A1", "D4", `{"column":"A","expression":"x == 1","columns":[{"column":"B","values":["A","B",""]},{"column":"C","custom_filters":[{"operator":">","value":"100"},{"operator":"<=","value":"200"}],"and":true}]}`))
+	output, err := xml.Marshal(f.Sheet["xl/worksheets/sheet1.xml"].AutoFilter)
+	assert.NoError(t, err)
+	assert.Equal(t, `<autoFilter ref="A1:D4"><filterColumn colId="0"><filters><filter val="1"></filter></filters></filterColumn><filterColumn colId="1"><filters blank="true"><filter val="A"></filter><filter val="B"></filter></filters></filterColumn><filterColumn colId="2"><customFilters and="true"><customFilter operator="greaterThan" val="100"></customFilter><customFilter operator="lessThanOrEqual" val="200"></customFilter></customFilters></filterColumn></autoFilter>`, string(output))
+	assert.True(t, f.Sheet["xl/worksheets/sheet1.xml"].SheetPr.FilterMode)
+
+	for _, c := range []struct {
+		format, err string
+	}{
+		{`{"columns":[{"column":"E","values":["A"]}]}`, "incorrect index of column 'E'"},
+		{`{"columns":[{"column":"B"}]}`, "missing filter criteria of column 'B'"},
+		{`{"columns":[{"column":"B","values":["A"]},{"column":"B","values":["B"]}]}`, "duplicate filter criteria of column 'B'"},
+		{`{"columns":[{"column":"B","custom_filters":[{"operator":"?","value":"1"}]}]}`, "unknown operator: ?"},
+		{`{"columns":[{"column":"B","custom_filters":[{"operator":">","value":"1"},{"operator":">","value":"1"},{"operator":">","value":"1"}]}]}`, "the number of custom filters of column 'B' exceeds the limit 2"},
+		{`{"columns":[{"column":"B","expression":"x -- y"}]}`, "unknown operator: --"},
+	} {
+		assert.EqualError(t, f.AutoFilter("Sheet1", "A1", "D4", c.format), c.err)
+	}
+}
+
+func TestAutoFilterApplyFilterHide(t *testing.T) {
+	f := NewFile()
+	for i, row := range [][]interface{}{
+		{"Name", "Score", "Date"},
+		{"Apple", 120, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Banana", 80, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"apricot", "N/A", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Cherry", nil, nil},
+		{"Durian", 300, time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		cell, _ := CoordinatesToCellName(1, i+1)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	for _, c := range []struct {
+		format  string
+		visible []bool
+	}{
+		{`{"column":"B","expression":"x > 100","apply_filter_hide":true}`, []bool{true, false, false, false, true}},
+		{`{"column":"A","expression":"x == a*","apply_filter_hide":true}`, []bool{true, false, true, false, false}},
+		{`{"column":"B","expression":"x == blanks","apply_filter_hide":true}`, []bool{false, false, false, true, false}},
+		{`{"column":"B","expression":"x == nonblanks","apply_filter_hide":true}`, []bool{true, true, true, false, true}},
+		{`{"column":"B","expression":"x != 80","apply_filter_hide":true}`, []bool{true, false, true, true, true}},
+		{`{"columns":[{"column":"C","custom_filters":[{"operator":">=","value":"2020-01-01"},{"operator":"<","value":"2020-03-01"}],"and":true}],"apply_filter_hide":true}`, []bool{true, true, false, false, false}},
+		{`{"columns":[{"column":"A","values":["banana","Cherry"]},{"column":"B","values":["80",""]}],"apply_filter_hide":true}`, []bool{false, true, false, true, false}},
+		{`{"columns":[{"column":"B","custom_filters":[{"operator":"<","value":"100"},{"operator":"==","value":"n/a"}]}],"apply_filter_hide":true}`, []bool{false, true, true, false, false}},
+		{`{"column":"A","expression":"x > 100"}`, []bool{false, true, true, false, false}},
+	} {
+		assert.NoError(t, f.AutoFilter("Sheet1", "A1", "C6", c.format))
+		for i, visible := range c.visible {
+			rowVisible, err := f.GetRowVisible("Sheet1", i+2)
+			assert.NoError(t, err)
+			assert.Equal(t, visible, rowVisible, fmt.Sprintf("%s row %d", c.format, i+2))
+		}
+	}
+	assert.True(t, matchWildcard("~*a?", "*ab"))
+	assert.False(t, matchWildcard("~*a?", "bab"))
+}
+
 func TestParseFilterTokens(t *testing.T) {
 	f := NewFile()
 	// Test with unknown operator.
@@ -20,7 +20,7 @@ type vmlDrawing struct {
 	XMLNSx      string           `xml:"xmlns:x,attr"`
 	XMLNSmv     string           `xml:"xmlns:mv,attr"`
 	Shapelayout *xlsxShapelayout `xml:"o:shapelayout"`
-	Shapetype   *xlsxShapetype   `xml:"v:shapetype"`
+	Shapetype   []xlsxShapetype  `xml:"v:shapetype"`
 	Shape       []xlsxShape      `xml:"v:shape"`
 }
 
@@ -42,22 +42,30 @@ type xlsxIDmap struct {
 type xlsxShape struct {
 	XMLName     xml.Name `xml:"v:shape"`
 	ID          string   `xml:"id,attr"`
+	Spid        string   `xml:"o:spid,attr,omitempty"`
 	Type        string   `xml:"type,attr"`
 	Style       string   `xml:"style,attr"`
-	Fillcolor   string   `xml:"fillcolor,attr"`
-	Insetmode   string   `xml:"urn:schemas-microsoft-com:office:office insetmode,attr,omitempty"`
+	Filled      string   `xml:"filled,attr,omitempty"`
+	Fillcolor   string   `xml:"fillcolor,attr,omitempty"`
+	Stroked     string   `xml:"stroked,attr,omitempty"`
+	Insetmode   string   `xml:"o:insetmode,attr,omitempty"`
 	Strokecolor string   `xml:"strokecolor,attr,omitempty"`
 	Val         string   `xml:",innerxml"`
 }
 
 // xlsxShapetype directly maps the shapetype element.
 type xlsxShapetype struct {
-	ID        string      `xml:"id,attr"`
-	Coordsize string      `xml:"coordsize,attr"`
-	Spt       int         `xml:"o:spt,attr"`
-	Path      string      `xml:"path,attr"`
-	Stroke    *xlsxStroke `xml:"v:stroke"`
-	VPath     *vPath      `xml:"v:path"`
+	ID             string      `xml:"id,attr"`
+	Coordsize      string      `xml:"coordsize,attr"`
+	Spt            int         `xml:"o:spt,attr"`
+	Preferrelative string      `xml:"o:preferrelative,attr,omitempty"`
+	Path           string      `xml:"path,attr"`
+	Filled         string      `xml:"filled,attr,omitempty"`
+	Stroked        string      `xml:"stroked,attr,omitempty"`
+	Stroke         *xlsxStroke `xml:"v:stroke"`
+	Formulas       *vFormulas  `xml:"v:formulas"`
+	VPath          *vPath      `xml:"v:path"`
+	Lock           *oLock      `xml:"o:lock"`
 }
 
 // xlsxStroke directly maps the stroke element.
@@ -65,10 +73,34 @@ type xlsxStroke struct {
 	Joinstyle string `xml:"joinstyle,attr"`
 }
 
+// vFormulas directly maps the v:formulas element. This element specifies the
+// formulas used to calculate the path of the shape type.
+type vFormulas struct {
+	Formula []vFormula `xml:"v:f"`
+}
+
+// vFormula directly maps the v:f element.
+type vFormula struct {
+	Eqn string `xml:"eqn,attr"`
+}
+
 // vPath directly maps the v:path element.
 type vPath struct {
+	Shadowok        string `xml:"shadowok,attr,omitempty"`
+	Extrusionok     string `xml:"o:extrusionok,attr,omitempty"`
+	Strokeok        string `xml:"strokeok,attr,omitempty"`
+	Fillok          string `xml:"fillok,attr,omitempty"`
 	Gradientshapeok string `xml:"gradientshapeok,attr,omitempty"`
-	Connecttype     string `xml:"o:connecttype,attr"`
+	Connecttype     string `xml:"o:connecttype,attr,omitempty"`
+}
+
+// oLock directly maps the o:lock element. This element specifies the
+// locking properties of the shape or the shape type.
+type oLock struct {
+	Ext         string `xml:"v:ext,attr"`
+	Rotation    string `xml:"rotation,attr,omitempty"`
+	Aspectratio string `xml:"aspectratio,attr,omitempty"`
+	Shapetype   string `xml:"shapetype,attr,omitempty"`
 }
 
 // vFill directly maps the v:fill element. This element must be defined within a
@@ -97,13 +129,23 @@ type vShadow struct {
 // vTextbox directly maps the v:textbox element. This element must be defined
 // within a Shape element.
 type vTextbox struct {
-	Style string   `xml:"style,attr"`
-	Div   *xlsxDiv `xml:"div"`
+	Style       string   `xml:"style,attr"`
+	Singleclick string   `xml:"o:singleclick,attr,omitempty"`
+	Div         *xlsxDiv `xml:"div"`
 }
 
 // xlsxDiv directly maps the div element.
 type xlsxDiv struct {
-	Style string `xml:"style,attr"`
+	Style string       `xml:"style,attr"`
+	Font  *vmlTextFont `xml:"font"`
+}
+
+// vmlTextFont directly maps the font element in the text box of the shape.
+type vmlTextFont struct {
+	Face    string `xml:"face,attr,omitempty"`
+	Size    int    `xml:"size,attr,omitempty"`
+	Color   string `xml:"color,attr,omitempty"`
+	Content string `xml:",chardata"`
 }
 
 // xClientData (Attached Object Data) directly maps the x:ClientData element.
@@ -123,6 +165,24 @@ type xClientData struct {
 	Column        int    `xml:"x:Column"`
 }
 
+// xFormControlClientData directly maps the x:ClientData element of the form
+// control shape. The ObjectType attribute determines the kind of the form
+// control, such as Button, Checkbox and Radio.
+type xFormControlClientData struct {
+	ObjectType  string    `xml:"ObjectType,attr"`
+	Anchor      string    `xml:"x:Anchor"`
+	PrintObject string    `xml:"x:PrintObject,omitempty"`
+	AutoFill    string    `xml:"x:AutoFill"`
+	AutoLine    string    `xml:"x:AutoLine,omitempty"`
+	FmlaMacro   string    `xml:"x:FmlaMacro,omitempty"`
+	TextHAlign  string    `xml:"x:TextHAlign,omitempty"`
+	TextVAlign  string    `xml:"x:TextVAlign,omitempty"`
+	FmlaLink    string    `xml:"x:FmlaLink,omitempty"`
+	Checked     int       `xml:"x:Checked,omitempty"`
+	FirstButton *struct{} `xml:"x:FirstButton"`
+	NoThreeD    *struct{} `xml:"x:NoThreeD"`
+}
+
 // decodeVmlDrawing defines the structure used to parse the file
 // xl/drawings/vmlDrawing%d.vml.
 type decodeVmlDrawing struct {
@@ -131,7 +191,30 @@ type decodeVmlDrawing struct {
 
 // decodeShape defines the structure used to parse the particular shape element.
 type decodeShape struct {
-	Val string `xml:",innerxml"`
+	ID          string `xml:"id,attr"`
+	Spid        string `xml:"urn:schemas-microsoft-com:office:office spid,attr"`
+	Type        string `xml:"type,attr"`
+	Style       string `xml:"style,attr"`
+	Filled      string `xml:"filled,attr"`
+	Fillcolor   string `xml:"fillcolor,attr"`
+	Stroked     string `xml:"stroked,attr"`
+	Insetmode   string `xml:"urn:schemas-microsoft-com:office:office insetmode,attr"`
+	Strokecolor string `xml:"strokecolor,attr"`
+	Val         string `xml:",innerxml"`
+}
+
+// decodeShapeVal defines the structure used to parse the client data of the
+// particular shape element.
+type decodeShapeVal struct {
+	ClientData *decodeXClientData `xml:"ClientData"`
+}
+
+// decodeXClientData defines the structure used to parse the x:ClientData
+// element of the shape.
+type decodeXClientData struct {
+	ObjectType string `xml:"ObjectType,attr"`
+	Row        int    `xml:"Row"`
+	Column     int    `xml:"Column"`
 }
 
 // encodeShape defines the structure used to re-serialization shape element.
@@ -142,3 +225,29 @@ type encodeShape struct {
 	Textbox    *vTextbox    `xml:"v:textbox"`
 	ClientData *xClientData `xml:"x:ClientData"`
 }
+
+// vImagedata directly maps the v:imagedata element. This element specifies
+// the relationship ID of the picture which used in the shape.
+type vImagedata struct {
+	RelID string `xml:"o:relid,attr"`
+	Title string `xml:"o:title,attr,omitempty"`
+}
+
+// encodeHeaderFooterImage defines the structure used to re-serialization
+// picture shape element in the header and footer.
+type encodeHeaderFooterImage struct {
+	XMLName   xml.Name    `xml:"shape"`
+	Imagedata *vImagedata `xml:"v:imagedata"`
+	Lock      *oLock      `xml:"o:lock"`
+}
+
+// encodeFormControl defines the structure used to re-serialization form
+// control shape element.
+type encodeFormControl struct {
+	XMLName    xml.Name                `xml:"shape"`
+	Fill       *vFill                  `xml:"v:fill"`
+	Path       *vPath                  `xml:"v:path"`
+	Lock       *oLock                  `xml:"o:lock"`
+	Textbox    *vTextbox               `xml:"v:textbox"`
+	ClientData *xFormControlClientData `xml:"x:ClientData"`
+}
@@ -315,6 +315,7 @@ type cPlotArea struct {
 	OfPieChart     *cCharts `xml:"ofPieChart"`
 	RadarChart     *cCharts `xml:"radarChart"`
 	ScatterChart   *cCharts `xml:"scatterChart"`
+	StockChart     *cCharts `xml:"stockChart"`
 	Surface3DChart *cCharts `xml:"surface3DChart"`
 	SurfaceChart   *cCharts `xml:"surfaceChart"`
 	CatAx          []*cAxs  `xml:"catAx"`
@@ -336,6 +337,8 @@ type cCharts struct {
 	Ser          *[]cSer        `xml:"ser"`
 	SerLines     *attrValString `xml:"serLines"`
 	DLbls        *cDLbls        `xml:"dLbls"`
+	HiLowLines   *cChartLines   `xml:"hiLowLines"`
+	UpDownBars   *cUpDownBars   `xml:"upDownBars"`
 	Shape        *attrValString `xml:"shape"`
 	HoleSize     *attrValInt    `xml:"holeSize"`
 	Smooth       *attrValBool   `xml:"smooth"`
@@ -351,6 +354,7 @@ type cAxs struct {
 	AxPos          *attrValString `xml:"axPos"`
 	MajorGridlines *cChartLines   `xml:"majorGridlines"`
 	MinorGridlines *cChartLines   `xml:"minorGridlines"`
+	Title          *cTitle        `xml:"title"`
 	NumFmt         *cNumFmt       `xml:"numFmt"`
 	MajorTickMark  *attrValString `xml:"majorTickMark"`
 	MinorTickMark  *attrValString `xml:"minorTickMark"`
@@ -375,9 +379,18 @@ type cChartLines struct {
 	SpPr *cSpPr `xml:"spPr"`
 }
 
+// cUpDownBars directly maps the upDownBars element. This element specifies
+// the up and down bars of the line or stock chart.
+type cUpDownBars struct {
+	GapWidth *attrValInt  `xml:"gapWidth"`
+	UpBars   *cChartLines `xml:"upBars"`
+	DownBars *cChartLines `xml:"downBars"`
+}
+
 // cScaling directly maps the scaling element. This element contains
 // additional axis settings.
 type cScaling struct {
+	LogBase     *attrValFloat  `xml:"logBase"`
 	Orientation *attrValString `xml:"orientation"`
 	Max         *attrValFloat  `xml:"max"`
 	Min         *attrValFloat  `xml:"min"`
@@ -401,6 +414,8 @@ type cSer struct {
 	DLbls            *cDLbls      `xml:"dLbls"`
 	Marker           *cMarker     `xml:"marker"`
 	InvertIfNegative *attrValBool `xml:"invertIfNegative"`
+	Trendline        *cTrendline  `xml:"trendline"`
+	ErrBars          *cErrBars    `xml:"errBars"`
 	Cat              *cCat        `xml:"cat"`
 	Val              *cVal        `xml:"val"`
 	XVal             *cCat        `xml:"xVal"`
@@ -418,6 +433,29 @@ type cMarker struct {
 	SpPr   *cSpPr         `xml:"spPr"`
 }
 
+// cTrendline (Trendline) directly maps the trendline element. This element
+// specifies a trendline of the series.
+type cTrendline struct {
+	Name          string         `xml:"name,omitempty"`
+	TrendlineType *attrValString `xml:"trendlineType"`
+	Order         *attrValInt    `xml:"order"`
+	Period        *attrValInt    `xml:"period"`
+	Forward       *attrValFloat  `xml:"forward"`
+	Backward      *attrValFloat  `xml:"backward"`
+	DispRSqr      *attrValBool   `xml:"dispRSqr"`
+	DispEq        *attrValBool   `xml:"dispEq"`
+}
+
+// cErrBars (Error Bars) directly maps the errBars element. This element
+// specifies the error bars of the series.
+type cErrBars struct {
+	ErrDir     *attrValString `xml:"errDir"`
+	ErrBarType *attrValString `xml:"errBarType"`
+	ErrValType *attrValString `xml:"errValType"`
+	NoEndCap   *attrValBool   `xml:"noEndCap"`
+	Val        *attrValFloat  `xml:"val"`
+}
+
 // cDPt (Data Point) directly maps the dPt element. This element specifies a
 // single data point.
 type cDPt struct {
@@ -480,13 +518,15 @@ type cNumCache struct {
 // entire series or the entire chart. It contains child elements that specify
 // the specific formatting and positioning settings.
 type cDLbls struct {
-	ShowLegendKey   *attrValBool `xml:"showLegendKey"`
-	ShowVal         *attrValBool `xml:"showVal"`
-	ShowCatName     *attrValBool `xml:"showCatName"`
-	ShowSerName     *attrValBool `xml:"showSerName"`
-	ShowPercent     *attrValBool `xml:"showPercent"`
-	ShowBubbleSize  *attrValBool `xml:"showBubbleSize"`
-	ShowLeaderLines *attrValBool `xml:"showLeaderLines"`
+	NumFmt          *cNumFmt       `xml:"numFmt"`
+	DLblPos         *attrValString `xml:"dLblPos"`
+	ShowLegendKey   *attrValBool   `xml:"showLegendKey"`
+	ShowVal         *attrValBool   `xml:"showVal"`
+	ShowCatName     *attrValBool   `xml:"showCatName"`
+	ShowSerName     *attrValBool   `xml:"showSerName"`
+	ShowPercent     *attrValBool   `xml:"showPercent"`
+	ShowBubbleSize  *attrValBool   `xml:"showBubbleSize"`
+	ShowLeaderLines *attrValBool   `xml:"showLeaderLines"`
 }
 
 // cLegend (Legend) directly maps the legend element. This element specifies
@@ -525,10 +565,13 @@ type formatChartAxis struct {
 	MinorGridlines      bool    `json:"minor_grid_lines"`
 	MajorTickMark       string  `json:"major_tick_mark"`
 	MinorTickMark       string  `json:"minor_tick_mark"`
+	MinorUnit           float64 `json:"minor_unit"`
 	MinorUnitType       string  `json:"minor_unit_type"`
 	MajorUnit           float64 `json:"major_unit"`
 	MajorUnitType       string  `json:"major_unit_type"`
+	LogBase             float64 `json:"log_base"`
 	TickLabelSkip       int     `json:"tick_label_skip"`
+	TickMarkSkip        int     `json:"tick_mark_skip"`
 	DisplayUnits        string  `json:"display_units"`
 	DisplayUnitsVisible bool    `json:"display_units_visible"`
 	DateAxis            bool    `json:"date_axis"`
@@ -542,6 +585,8 @@ type formatChartAxis struct {
 		Italic    bool   `json:"italic"`
 		Underline bool   `json:"underline"`
 	} `json:"num_font"`
+	Name       string       `json:"name"`
+	NameFont   Font         `json:"name_font"`
 	NameLayout formatLayout `json:"name_layout"`
 }
 
@@ -597,6 +642,7 @@ type formatChart struct {
 	ShowHiddenData bool   `json:"show_hidden_data"`
 	SetRotation    int    `json:"set_rotation"`
 	SetHoleSize    int    `json:"set_hole_size"`
+	SecondaryAxis  bool   `json:"secondary_axis"`
 	order          int
 }
 
@@ -613,10 +659,15 @@ type formatChartLegend struct {
 
 // formatChartSeries directly maps the format settings of the chart series.
 type formatChartSeries struct {
-	Name       string `json:"name"`
-	Categories string `json:"categories"`
-	Values     string `json:"values"`
-	Line       struct {
+	Name          string                 `json:"name"`
+	Type          string                 `json:"type"`
+	Categories    string                 `json:"categories"`
+	Values        string                 `json:"values"`
+	SecondaryAxis bool                   `json:"secondary_axis"`
+	DataLabels    *formatChartDataLabels `json:"data_labels"`
+	Trendline     *formatChartTrendline  `json:"trendline"`
+	ErrorBars     *formatChartErrorBars  `json:"error_bars"`
+	Line          struct {
 		None  bool    `json:"none"`
 		Color string  `json:"color"`
 		Width float64 `json:"width"`
@@ -636,10 +687,47 @@ type formatChartSeries struct {
 	} `json:"marker"`
 }
 
+// formatChartDataLabels directly maps the format settings of the chart series
+// data labels.
+type formatChartDataLabels struct {
+	ShowVal         bool   `json:"show_val"`
+	ShowCatName     bool   `json:"show_cat_name"`
+	ShowSerName     bool   `json:"show_series_name"`
+	ShowPercent     bool   `json:"show_percent"`
+	ShowBubbleSize  bool   `json:"show_bubble_size"`
+	ShowLeaderLines bool   `json:"show_leader_lines"`
+	NumFmt          string `json:"number_format"`
+	Position        string `json:"position"`
+}
+
+// formatChartTrendline directly maps the format settings of the chart series
+// trendline.
+type formatChartTrendline struct {
+	Type            string  `json:"type"`
+	Name            string  `json:"name"`
+	Order           int     `json:"order"`
+	Period          int     `json:"period"`
+	Forward         float64 `json:"forward"`
+	Backward        float64 `json:"backward"`
+	DisplayEquation bool    `json:"display_equation"`
+	DisplayRSquared bool    `json:"display_r_squared"`
+}
+
+// formatChartErrorBars directly maps the format settings of the chart series
+// error bars.
+type formatChartErrorBars struct {
+	Direction string  `json:"direction"`
+	Type      string  `json:"type"`
+	ValueType string  `json:"value_type"`
+	Value     float64 `json:"value"`
+	NoEndCap  bool    `json:"no_end_cap"`
+}
+
 // formatChartTitle directly maps the format settings of the chart title.
 type formatChartTitle struct {
 	None    bool         `json:"none"`
 	Name    string       `json:"name"`
+	Font    Font         `json:"font"`
 	Overlay bool         `json:"overlay"`
 	Layout  formatLayout `json:"layout"`
 }
@@ -70,16 +70,79 @@ type xlsxPhoneticRun struct {
 	T  string `xml:"t,attr"`
 }
 
-// formatComment directly maps the format settings of the comment.
-type formatComment struct {
-	Author string `json:"author"`
-	Text   string `json:"text"`
+// CommentOption directly maps the settings of the comment. The Text will be
+// ignored if the rich text Runs of the comment has been specified. The Width
+// and Height specifies the size in pixels of the comment box.
+type CommentOption struct {
+	Author string
+	Text   string
+	Runs   []RichTextRun
+	Width  int
+	Height int
+}
+
+// RichTextRun directly maps the settings of the rich text run.
+type RichTextRun struct {
+	Font *Font
+	Text string
 }
 
 // Comment directly maps the comment information.
 type Comment struct {
-	Author   string `json:"author"`
-	AuthorID int    `json:"author_id"`
-	Ref      string `json:"ref"`
-	Text     string `json:"text"`
+	Author   string        `json:"author"`
+	AuthorID int           `json:"author_id"`
+	Ref      string        `json:"ref"`
+	Text     string        `json:"text"`
+	Runs     []RichTextRun `json:"runs"`
+}
+
+// xlsxThreadedComments directly maps the ThreadedComments element. This
+// element specifies the threaded comments of the worksheet, which stored in
+// the xl/threadedComments/threadedComment%d.xml.
+type xlsxThreadedComments struct {
+	XMLName         xml.Name              `xml:"http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments ThreadedComments"`
+	ThreadedComment []xlsxThreadedComment `xml:"threadedComment"`
+}
+
+// xlsxThreadedComment directly maps the threadedComment element. This element
+// specifies a comment or a reply of the thread, the reply references the
+// first comment of the thread by the parentId attribute.
+type xlsxThreadedComment struct {
+	Ref      string `xml:"ref,attr"`
+	DT       string `xml:"dT,attr"`
+	PersonID string `xml:"personId,attr"`
+	ID       string `xml:"id,attr"`
+	ParentID string `xml:"parentId,attr,omitempty"`
+	Text     string `xml:"text"`
+}
+
+// xlsxPersonList directly maps the personList element. This element specifies
+// the authors of the threaded comments in the workbook, which stored in the
+// xl/persons/person.xml.
+type xlsxPersonList struct {
+	XMLName xml.Name     `xml:"http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments personList"`
+	Person  []xlsxPerson `xml:"person"`
+}
+
+// xlsxPerson directly maps the person element.
+type xlsxPerson struct {
+	DisplayName string `xml:"displayName,attr"`
+	ID          string `xml:"id,attr"`
+	UserID      string `xml:"userId,attr,omitempty"`
+	ProviderID  string `xml:"providerId,attr,omitempty"`
+}
+
+// ThreadedComment directly maps the threaded comment settings, the Replies
+// will be added as the replies of the thread in order.
+type ThreadedComment struct {
+	Author  string
+	Text    string
+	Replies []ThreadedCommentReply
+}
+
+// ThreadedCommentReply directly maps the reply settings of the threaded
+// comment.
+type ThreadedCommentReply struct {
+	Author string
+	Text   string
 }
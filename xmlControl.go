@@ -0,0 +1,59 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxControl directly maps the control element in the controls of the
+// worksheet. This element specifies the embedded control, the shapeId
+// attribute references the shape of the control in the VML drawing, and the
+// r:id attribute references the control properties part.
+type xlsxControl struct {
+	XMLName   xml.Name       `xml:"control"`
+	ShapeID   int            `xml:"shapeId,attr"`
+	RID       string         `xml:"r:id,attr"`
+	Name      string         `xml:"name,attr,omitempty"`
+	ControlPr *xlsxControlPr `xml:"controlPr"`
+}
+
+// xlsxControlPr directly maps the controlPr element. This element specifies
+// the properties of the embedded control.
+type xlsxControlPr struct {
+	DefaultSize bool               `xml:"defaultSize,attr"`
+	AutoFill    bool               `xml:"autoFill,attr"`
+	AutoLine    bool               `xml:"autoLine,attr"`
+	AutoPict    bool               `xml:"autoPict,attr"`
+	Macro       string             `xml:"macro,attr,omitempty"`
+	Anchor      *xlsxControlAnchor `xml:"anchor"`
+}
+
+// xlsxControlAnchor directly maps the anchor element of the control
+// properties. This element specifies the position of the control in the
+// worksheet.
+type xlsxControlAnchor struct {
+	MoveWithCells bool     `xml:"moveWithCells,attr,omitempty"`
+	SizeWithCells bool     `xml:"sizeWithCells,attr,omitempty"`
+	From          xlsxFrom `xml:"from"`
+	To            xlsxTo   `xml:"to"`
+}
+
+// xlsxFormControlPr directly maps the formControlPr element in the file
+// xl/ctrlProps/ctrlProp%d.xml. This element specifies the properties of the
+// form control, such as the type of the control, the checked state and the
+// linked cell.
+type xlsxFormControlPr struct {
+	XMLName     xml.Name `xml:"http://schemas.microsoft.com/office/spreadsheetml/2009/9/main formControlPr"`
+	ObjectType  string   `xml:"objectType,attr"`
+	Checked     string   `xml:"checked,attr,omitempty"`
+	FirstButton bool     `xml:"firstButton,attr,omitempty"`
+	FmlaLink    string   `xml:"fmlaLink,attr,omitempty"`
+	LockText    bool     `xml:"lockText,attr,omitempty"`
+	NoThreeD    bool     `xml:"noThreeD,attr,omitempty"`
+}
@@ -74,10 +74,24 @@ type decodeWsDr struct {
 // specifies a two cell anchor placeholder for a group, a shape, or a drawing
 // element. It moves with cells and its extents are in EMU units.
 type decodeTwoCellAnchor struct {
-	From       *decodeFrom       `xml:"from"`
-	To         *decodeTo         `xml:"to"`
-	Pic        *decodePic        `xml:"pic,omitempty"`
-	ClientData *decodeClientData `xml:"clientData"`
+	From         *decodeFrom         `xml:"from"`
+	To           *decodeTo           `xml:"to"`
+	Pic          *decodePic          `xml:"pic,omitempty"`
+	GraphicFrame *decodeGraphicFrame `xml:"graphicFrame"`
+	ClientData   *decodeClientData   `xml:"clientData"`
+}
+
+// decodeGraphicFrame directly maps the graphicFrame element. This element
+// describes a single graphical object frame for a spreadsheet which contains
+// a graphical object, such as the chart.
+type decodeGraphicFrame struct {
+	Graphic struct {
+		GraphicData struct {
+			Chart struct {
+				RID string `xml:"id,attr"`
+			} `xml:"chart"`
+		} `xml:"graphicData"`
+	} `xml:"graphic"`
 }
 
 // decodeCNvPr directly maps the cNvPr (Non-Visual Drawing Properties). This
@@ -117,6 +131,14 @@ type decodeBlip struct {
 	Embed  string `xml:"embed,attr"`
 	Cstate string `xml:"cstate,attr,omitempty"`
 	R      string `xml:"r,attr"`
+	ExtLst struct {
+		Ext []struct {
+			URI     string `xml:"uri,attr"`
+			SVGBlip struct {
+				Embed string `xml:"embed,attr"`
+			} `xml:"svgBlip"`
+		} `xml:"ext"`
+	} `xml:"extLst"`
 }
 
 // decodeStretch directly maps the stretch element. This element specifies
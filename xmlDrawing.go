@@ -26,6 +26,11 @@ const (
 	SourceRelationshipPivotTable                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
 	SourceRelationshipPivotCache                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
 	SourceRelationshipVBAProject                 = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
+	SourceRelationshipCtrlProp                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/ctrlProp"
+	SourceRelationshipSlicer                     = "http://schemas.microsoft.com/office/2007/relationships/slicer"
+	SourceRelationshipSlicerCache                = "http://schemas.microsoft.com/office/2007/relationships/slicerCache"
+	SourceRelationshipThreadedComment            = "http://schemas.microsoft.com/office/2017/10/relationships/threadedComment"
+	SourceRelationshipPerson                     = "http://schemas.microsoft.com/office/2017/10/relationships/person"
 	SourceRelationshipChart201506                = "http://schemas.microsoft.com/office/drawing/2015/06/chart"
 	SourceRelationshipChart20070802              = "http://schemas.microsoft.com/office/drawing/2007/8/2/chart"
 	SourceRelationshipChart2014                  = "http://schemas.microsoft.com/office/drawing/2014/chart"
@@ -33,9 +38,13 @@ const (
 	NameSpaceDrawingML                           = "http://schemas.openxmlformats.org/drawingml/2006/main"
 	NameSpaceDrawingMLChart                      = "http://schemas.openxmlformats.org/drawingml/2006/chart"
 	NameSpaceDrawingMLSpreadSheet                = "http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing"
+	NameSpaceDrawingMLSlicer                     = "http://schemas.microsoft.com/office/drawing/2010/slicer"
+	NameSpaceDrawingMLSlicerX15                  = "http://schemas.microsoft.com/office/drawing/2012/slicer"
+	NameSpaceDrawing2016SVG                      = "http://schemas.microsoft.com/office/drawing/2016/SVG/main"
 	NameSpaceSpreadSheet                         = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
 	NameSpaceSpreadSheetX14                      = "http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"
 	NameSpaceSpreadSheetX15                      = "http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"
+	NameSpaceSpreadSheetThreadedComments         = "http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments"
 	NameSpaceSpreadSheetExcel2006Main            = "http://schemas.microsoft.com/office/excel/2006/main"
 	NameSpaceMacExcel2008Main                    = "http://schemas.microsoft.com/office/mac/excel/2008/main"
 	NameSpaceXML                                 = "http://www.w3.org/XML/1998/namespace"
@@ -48,14 +57,20 @@ const (
 	NameSpaceDublinCore                          = "http://purl.org/dc/elements/1.1/"
 	NameSpaceDublinCoreTerms                     = "http://purl.org/dc/terms/"
 	NameSpaceDublinCoreMetadataIntiative         = "http://purl.org/dc/dcmitype/"
+	ContentTypeControlProperties                 = "application/vnd.ms-excel.controlproperties+xml"
 	ContentTypeDrawing                           = "application/vnd.openxmlformats-officedocument.drawing+xml"
 	ContentTypeDrawingML                         = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
 	ContentTypeMacro                             = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	ContentTypeSheetML                           = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
 	ContentTypeSpreadSheetMLChartsheet           = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
 	ContentTypeSpreadSheetMLComments             = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
+	ContentTypeSpreadSheetMLThreadedComments     = "application/vnd.ms-excel.threadedcomments+xml"
+	ContentTypeSpreadSheetMLPerson               = "application/vnd.ms-excel.person+xml"
 	ContentTypeSpreadSheetMLPivotCacheDefinition = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
 	ContentTypeSpreadSheetMLPivotTable           = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotTable+xml"
 	ContentTypeSpreadSheetMLTable                = "application/vnd.openxmlformats-officedocument.spreadsheetml.table+xml"
+	ContentTypeSlicer                            = "application/vnd.ms-excel.slicer+xml"
+	ContentTypeSlicerCache                       = "application/vnd.ms-excel.slicerCache+xml"
 	ContentTypeSpreadSheetMLWorksheet            = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
 	ContentTypeVBA                               = "application/vnd.ms-office.vbaProject"
 	ContentTypeVML                               = "application/vnd.openxmlformats-officedocument.vmlDrawing"
@@ -63,21 +78,33 @@ const (
 	// ([ISO/IEC29500-1:2016] section 18.2.10) of the worksheet element
 	// ([ISO/IEC29500-1:2016] section 18.3.1.99) is extended by the addition of
 	// new child ext elements ([ISO/IEC29500-1:2016] section 18.2.7)
-	ExtURIConditionalFormattings = "{78C0D931-6437-407D-A8EE-F0AAD7539E65}"
-	ExtURIDataValidations        = "{CCE6A557-97BC-4B89-ADB6-D9C93CAAB3DF}"
-	ExtURISparklineGroups        = "{05C60535-1F16-4fd2-B633-F4F36F0B64E0}"
-	ExtURISlicerListX14          = "{A8765BA9-456A-4DAB-B4F3-ACF838C121DE}"
-	ExtURISlicerCachesListX14    = "{BBE1A952-AA13-448e-AADC-164F8A28A991}"
-	ExtURISlicerListX15          = "{3A4CF648-6AED-40f4-86FF-DC5316D8AED3}"
-	ExtURIProtectedRanges        = "{FC87AEE6-9EDD-4A0A-B7FB-166176984837}"
-	ExtURIIgnoredErrors          = "{01252117-D84E-4E92-8308-4BE1C098FCBB}"
-	ExtURIWebExtensions          = "{F7C9EE02-42E1-4005-9D12-6889AFFD525C}"
-	ExtURITimelineRefs           = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
-	ExtURIDrawingBlip            = "{28A0092B-C50C-407E-A947-70E740481C1C}"
-	ExtURIMacExcelMX             = "{64002731-A6B0-56B0-2670-7721B7C09600}"
+	ExtURIConditionalFormattings      = "{78C0D931-6437-407D-A8EE-F0AAD7539E65}"
+	ExtURIConditionalFormattingRuleID = "{B025F937-C7B1-47D3-B67F-A62EFF666E3E}"
+	ExtURIDataValidations             = "{CCE6A557-97BC-4B89-ADB6-D9C93CAAB3DF}"
+	ExtURISparklineGroups             = "{05C60535-1F16-4fd2-B633-F4F36F0B64E0}"
+	ExtURISlicerListX14               = "{A8765BA9-456A-4DAB-B4F3-ACF838C121DE}"
+	ExtURISlicerCachesListX14         = "{BBE1A952-AA13-448e-AADC-164F8A28A991}"
+	ExtURISlicerListX15               = "{3A4CF648-6AED-40f4-86FF-DC5316D8AED3}"
+	ExtURISlicerCachesListX15         = "{46BE6895-7355-4a93-B00E-2C351335B9C9}"
+	ExtURISlicerCacheDefinition       = "{2F2917AC-EB37-4324-AD4E-5DD8C200BD13}"
+	ExtURIProtectedRanges             = "{FC87AEE6-9EDD-4A0A-B7FB-166176984837}"
+	ExtURIIgnoredErrors               = "{01252117-D84E-4E92-8308-4BE1C098FCBB}"
+	ExtURIWebExtensions               = "{F7C9EE02-42E1-4005-9D12-6889AFFD525C}"
+	ExtURITimelineRefs                = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
+	ExtURIDrawingBlip                 = "{28A0092B-C50C-407E-A947-70E740481C1C}"
+	ExtURIMacExcelMX                  = "{64002731-A6B0-56B0-2670-7721B7C09600}"
+	ExtURISVG                         = "{96DAC541-7B7A-43D3-8B79-37D633B846F1}"
 )
 
-var supportImageTypes = map[string]string{".gif": ".gif", ".jpg": ".jpeg", ".jpeg": ".jpeg", ".png": ".png", ".tif": ".tiff", ".tiff": ".tiff"}
+// Excel specifications and limits
+const (
+	TotalRows      = 1048576
+	TotalColumns   = 16384
+	maxPageBreaks  = 1026
+	maxEnsureCells = 1048576
+)
+
+var supportImageTypes = map[string]string{".gif": ".gif", ".jpg": ".jpeg", ".jpeg": ".jpeg", ".png": ".png", ".svg": ".svg", ".tif": ".tiff", ".tiff": ".tiff"}
 
 // xlsxCNvPr directly maps the cNvPr (Non-Visual Drawing Properties). This
 // element specifies non-visual canvas properties. This allows for additional
@@ -128,9 +155,31 @@ type xlsxPicLocks struct {
 // specifies the existence of an image (binary large image or picture) and
 // contains a reference to the image data.
 type xlsxBlip struct {
-	Embed  string `xml:"r:embed,attr"`
-	Cstate string `xml:"cstate,attr,omitempty"`
-	R      string `xml:"xmlns:r,attr"`
+	Embed  string          `xml:"r:embed,attr"`
+	Cstate string          `xml:"cstate,attr,omitempty"`
+	R      string          `xml:"xmlns:r,attr"`
+	ExtLst *xlsxBlipExtLst `xml:"a:extLst"`
+}
+
+// xlsxBlipExtLst directly maps the extLst element of the blip. This element
+// specifies the extension list of the picture, such as the SVG image.
+type xlsxBlipExtLst struct {
+	Ext []*xlsxBlipExt `xml:"a:ext"`
+}
+
+// xlsxBlipExt directly maps the ext element of the blip extension list.
+type xlsxBlipExt struct {
+	URI     string       `xml:"uri,attr"`
+	SVGBlip *xlsxSVGBlip `xml:"asvg:svgBlip"`
+}
+
+// xlsxSVGBlip directly maps the svgBlip element in the namespace
+// http://schemas.microsoft.com/office/drawing/2016/SVG/main - This element
+// specifies the reference to the SVG image, the embed image of the blip will
+// be used as the fallback of the SVG image.
+type xlsxSVGBlip struct {
+	XMLNSaSVG string `xml:"xmlns:asvg,attr"`
+	Embed     string `xml:"r:embed,attr"`
 }
 
 // xlsxStretch directly maps the stretch element. This element specifies that a
@@ -319,8 +368,9 @@ type xlsxGraphic struct {
 // document. This graphic object is provided entirely by the document authors
 // who choose to persist this data within the document.
 type xlsxGraphicData struct {
-	URI   string     `xml:"uri,attr"`
-	Chart *xlsxChart `xml:"c:chart,omitempty"`
+	URI    string             `xml:"uri,attr"`
+	Chart  *xlsxChart         `xml:"c:chart,omitempty"`
+	Slicer *xlsxGraphicSlicer `xml:"sle:slicer,omitempty"`
 }
 
 // xlsxChart (Chart) directly maps the c:chart element.
@@ -423,6 +473,20 @@ type formatPicture struct {
 	Hyperlink        string  `json:"hyperlink"`
 	HyperlinkType    string  `json:"hyperlink_type"`
 	Positioning      string  `json:"positioning"`
+	Fallback         string  `json:"fallback"`
+}
+
+// Picture directly maps the picture embedded in the worksheet. The Cell is
+// the top-left cell of the picture anchor, and the Range is the cell range
+// covered by the picture for the two cell anchor, it will be empty for the
+// one cell anchor. The Extension is the format of the picture, such as
+// ".png", and the File is the raw content of the picture.
+type Picture struct {
+	Name      string
+	Cell      string
+	Range     string
+	Extension string
+	File      []byte
 }
 
 // formatShape directly maps the format settings of the shape.
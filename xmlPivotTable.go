@@ -58,8 +58,8 @@ type xlsxPivotTableDefinition struct {
 	PageWrap                int                      `xml:"pageWrap,attr,omitempty"`
 	PageOverThenDown        bool                     `xml:"pageOverThenDown,attr,omitempty"`
 	SubtotalHiddenItems     bool                     `xml:"subtotalHiddenItems,attr,omitempty"`
-	RowGrandTotals          bool                     `xml:"rowGrandTotals,attr,omitempty"`
-	ColGrandTotals          bool                     `xml:"colGrandTotals,attr,omitempty"`
+	RowGrandTotals          *bool                    `xml:"rowGrandTotals,attr"`
+	ColGrandTotals          *bool                    `xml:"colGrandTotals,attr"`
 	FieldPrintTitles        bool                     `xml:"fieldPrintTitles,attr,omitempty"`
 	ItemPrintTitles         bool                     `xml:"itemPrintTitles,attr,omitempty"`
 	MergeItem               bool                     `xml:"mergeItem,attr,omitempty"`
@@ -148,7 +148,7 @@ type xlsxPivotField struct {
 	DataSourceSort               bool               `xml:"dataSourceSort,attr,omitempty"`
 	NonAutoSortDefault           bool               `xml:"nonAutoSortDefault,attr,omitempty"`
 	RankBy                       int                `xml:"rankBy,attr,omitempty"`
-	DefaultSubtotal              bool               `xml:"defaultSubtotal,attr,omitempty"`
+	DefaultSubtotal              *bool              `xml:"defaultSubtotal,attr"`
 	SumSubtotal                  bool               `xml:"sumSubtotal,attr,omitempty"`
 	CountASubtotal               bool               `xml:"countASubtotal,attr,omitempty"`
 	AvgSubtotal                  bool               `xml:"avgSubtotal,attr,omitempty"`
@@ -252,8 +252,8 @@ type xlsxPageField struct {
 	Fld    int         `xml:"fld,attr"`
 	Item   int         `xml:"item,attr,omitempty"`
 	Hier   int         `xml:"hier,attr"`
-	Name   string      `xml:"name,attr"`
-	Cap    string      `xml:"cap,attr"`
+	Name   string      `xml:"name,attr,omitempty"`
+	Cap    string      `xml:"cap,attr,omitempty"`
 	ExtLst *xlsxExtLst `xml:"extLst"`
 }
 
@@ -39,6 +39,7 @@ type xlsxSI struct {
 func (x xlsxSI) String() string {
 	if len(x.R) > 0 {
 		var rows strings.Builder
+		rows.WriteString(x.T)
 		for _, s := range x.R {
 			rows.WriteString(s.T)
 		}
@@ -61,7 +62,10 @@ type xlsxR struct {
 // they are directly applied to the run and supersede any formatting from
 // styles.
 type xlsxRPr struct {
-	B      string         `xml:"b,omitempty"`
+	B      *string        `xml:"b"`
+	I      *string        `xml:"i"`
+	Strike *string        `xml:"strike"`
+	U      *attrValString `xml:"u"`
 	Sz     *attrValFloat  `xml:"sz"`
 	Color  *xlsxColor     `xml:"color"`
 	RFont  *attrValString `xml:"rFont"`
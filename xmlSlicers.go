@@ -0,0 +1,84 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX files. Support reads and writes XLSX file generated by
+// Microsoft Excel™ 2007 and later. Support save file without losing original
+// charts of XLSX. This library needs Go version 1.10 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxSlicers directly maps the slicers element in the file
+// xl/slicers/slicer%d.xml. This element specifies the collection of the
+// slicers on a worksheet.
+type xlsxSlicers struct {
+	XMLName xml.Name     `xml:"http://schemas.microsoft.com/office/spreadsheetml/2009/9/main slicers"`
+	Slicer  []xlsxSlicer `xml:"slicer"`
+}
+
+// xlsxSlicer directly maps the slicer element. This element specifies a
+// slicer view on the worksheet, the cache attribute references the name of
+// the slicer cache which the slicer is based on.
+type xlsxSlicer struct {
+	Name        string `xml:"name,attr"`
+	Cache       string `xml:"cache,attr"`
+	Caption     string `xml:"caption,attr,omitempty"`
+	StartItem   int    `xml:"startItem,attr,omitempty"`
+	ColumnCount int    `xml:"columnCount,attr,omitempty"`
+	ShowCaption *bool  `xml:"showCaption,attr"`
+	Level       int    `xml:"level,attr,omitempty"`
+	Style       string `xml:"style,attr,omitempty"`
+	RowHeight   int    `xml:"rowHeight,attr"`
+}
+
+// xlsxSlicerCacheDefinition directly maps the slicerCacheDefinition element
+// in the file xl/slicerCaches/slicerCache%d.xml. This element specifies the
+// slicer cache, the sourceName attribute specifies the name of the table
+// column or the pivot field which the slicer cache is based on.
+type xlsxSlicerCacheDefinition struct {
+	XMLName    xml.Name    `xml:"http://schemas.microsoft.com/office/spreadsheetml/2009/9/main slicerCacheDefinition"`
+	XMLNSX     string      `xml:"xmlns:x,attr"`
+	XMLNSX15   string      `xml:"xmlns:x15,attr"`
+	Name       string      `xml:"name,attr"`
+	SourceName string      `xml:"sourceName,attr"`
+	ExtLst     *xlsxExtLst `xml:"extLst"`
+}
+
+// xlsxTableSlicerCache directly maps the tableSlicerCache element. This
+// element specifies the table and the column of the table which the slicer
+// cache is based on.
+type xlsxTableSlicerCache struct {
+	XMLName        xml.Name `xml:"x15:tableSlicerCache"`
+	TableID        int      `xml:"tableId,attr"`
+	Column         int      `xml:"column,attr"`
+	SortOrder      string   `xml:"sortOrder,attr,omitempty"`
+	CustomListSort *bool    `xml:"customListSort,attr"`
+	CrossFilter    string   `xml:"crossFilter,attr,omitempty"`
+}
+
+// decodeTableSlicerCache directly maps the tableSlicerCache element in the
+// extension list of the slicer cache definition.
+type decodeTableSlicerCache struct {
+	TableID int `xml:"tableId,attr"`
+	Column  int `xml:"column,attr"`
+}
+
+// decodeSlicerList directly maps the slicerList element in the extension
+// list of the worksheet and the slicerCaches element in the extension list
+// of the workbook, these elements reference the slicers parts and the slicer
+// caches parts by relationship ID.
+type decodeSlicerList struct {
+	Slicer []struct {
+		RID string `xml:"id,attr"`
+	} `xml:",any"`
+}
+
+// xlsxGraphicSlicer directly maps the sle:slicer element in the graphic
+// frame of the drawing. This element references the slicer by name.
+type xlsxGraphicSlicer struct {
+	Sle  string `xml:"xmlns:sle,attr"`
+	Name string `xml:"name,attr"`
+}
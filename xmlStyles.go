@@ -326,30 +326,43 @@ type Alignment struct {
 	WrapText        bool   `json:"wrap_text"`
 }
 
+// StyleColor directly maps the theme color settings of the style, the theme
+// specifies the index of the color in the theme color scheme and the tint
+// specifies the lightening or darkening value applied to the color.
+type StyleColor struct {
+	Theme int     `json:"theme"`
+	Tint  float64 `json:"tint"`
+}
+
 // Border directly maps the border settings of the cells.
 type Border struct {
-	Type  string `json:"type"`
-	Color string `json:"color"`
-	Style int    `json:"style"`
+	Type       string      `json:"type"`
+	Color      string      `json:"color"`
+	ThemeColor *StyleColor `json:"theme_color"`
+	Style      int         `json:"style"`
 }
 
 // Font directly maps the font settings of the fonts.
 type Font struct {
-	Bold      bool    `json:"bold"`
-	Italic    bool    `json:"italic"`
-	Underline string  `json:"underline"`
-	Family    string  `json:"family"`
-	Size      float64 `json:"size"`
-	Strike    bool    `json:"strike"`
-	Color     string  `json:"color"`
+	Bold       bool        `json:"bold"`
+	Italic     bool        `json:"italic"`
+	Underline  string      `json:"underline"`
+	Family     string      `json:"family"`
+	Size       float64     `json:"size"`
+	Strike     bool        `json:"strike"`
+	Color      string      `json:"color"`
+	ThemeColor *StyleColor `json:"theme_color"`
 }
 
 // Fill directly maps the fill settings of the cells.
 type Fill struct {
-	Type    string   `json:"type"`
-	Pattern int      `json:"pattern"`
-	Color   []string `json:"color"`
-	Shading int      `json:"shading"`
+	Type       string        `json:"type"`
+	Pattern    int           `json:"pattern"`
+	Color      []string      `json:"color"`
+	ThemeColor []*StyleColor `json:"theme_color"`
+	Shading    int           `json:"shading"`
+	Angle      *float64      `json:"angle"`
+	Stops      []float64     `json:"stops"`
 }
 
 // Protection directly maps the protection settings of the cells.
@@ -364,7 +377,7 @@ type Style struct {
 	Fill          Fill        `json:"fill"`
 	Font          *Font       `json:"font"`
 	Alignment     *Alignment  `json:"alignment"`
-	Protection    *Protection `json:"protection"`
+	Protection    *Protection `json:"protection,omitempty"`
 	NumFmt        int         `json:"number_format"`
 	DecimalPlaces int         `json:"decimal_places"`
 	CustomNumFmt  *string     `json:"custom_number_format"`
@@ -44,9 +44,9 @@ type xlsxTable struct {
 // applied column by column to a table of data in the worksheet. This collection
 // expresses AutoFilter settings.
 type xlsxAutoFilter struct {
-	XMLName      xml.Name          `xml:"autoFilter"`
-	Ref          string            `xml:"ref,attr"`
-	FilterColumn *xlsxFilterColumn `xml:"filterColumn"`
+	XMLName      xml.Name            `xml:"autoFilter"`
+	Ref          string              `xml:"ref,attr"`
+	FilterColumn []*xlsxFilterColumn `xml:"filterColumn"`
 }
 
 // xlsxFilterColumn directly maps the filterColumn element. The filterColumn
@@ -194,14 +194,39 @@ type xlsxTableStyleInfo struct {
 	ShowColumnStripes bool   `xml:"showColumnStripes,attr"`
 }
 
+// Table directly maps the table information of the worksheet. The Range is
+// the cell range covered by the table including the header row, and the
+// Columns are the names of the table columns in order.
+type Table struct {
+	Name              string
+	Range             string
+	TableStyle        string
+	ShowFirstColumn   bool
+	ShowLastColumn    bool
+	ShowRowStripes    bool
+	ShowColumnStripes bool
+	ShowTotalsRow     bool
+	Columns           []string
+}
+
 // formatTable directly maps the format settings of the table.
 type formatTable struct {
-	TableName         string `json:"table_name"`
-	TableStyle        string `json:"table_style"`
-	ShowFirstColumn   bool   `json:"show_first_column"`
-	ShowLastColumn    bool   `json:"show_last_column"`
-	ShowRowStripes    bool   `json:"show_row_stripes"`
-	ShowColumnStripes bool   `json:"show_column_stripes"`
+	TableName         string                 `json:"table_name"`
+	TableStyle        string                 `json:"table_style"`
+	ShowFirstColumn   bool                   `json:"show_first_column"`
+	ShowLastColumn    bool                   `json:"show_last_column"`
+	ShowRowStripes    bool                   `json:"show_row_stripes"`
+	ShowColumnStripes bool                   `json:"show_column_stripes"`
+	ShowTotalsRow     bool                   `json:"show_totals_row"`
+	TotalsRow         []formatTableTotalsRow `json:"totals_row"`
+}
+
+// formatTableTotalsRow directly maps the settings of the column in the
+// totals row of the table.
+type formatTableTotalsRow struct {
+	Column   string `json:"column"`
+	Function string `json:"function"`
+	Label    string `json:"label"`
 }
 
 // formatAutoFilter directly maps the auto filter settings.
@@ -212,4 +237,19 @@ type formatAutoFilter struct {
 		Column string `json:"column"`
 		Value  []int  `json:"value"`
 	} `json:"filter_list"`
+	Columns         []formatAutoFilterColumn `json:"columns"`
+	ApplyFilterHide bool                     `json:"apply_filter_hide"`
+}
+
+// formatAutoFilterColumn directly maps the filter criteria settings of a
+// column in the auto filter.
+type formatAutoFilterColumn struct {
+	Column        string   `json:"column"`
+	Expression    string   `json:"expression"`
+	Values        []string `json:"values"`
+	CustomFilters []struct {
+		Operator string `json:"operator"`
+		Value    string `json:"value"`
+	} `json:"custom_filters"`
+	And bool `json:"and"`
 }
@@ -14,6 +14,10 @@ import "encoding/xml"
 // xlsxTheme directly maps the theme element in the namespace
 // http://schemas.openxmlformats.org/drawingml/2006/main
 type xlsxTheme struct {
+	XMLName           xml.Name              `xml:"http://schemas.openxmlformats.org/drawingml/2006/main theme"`
+	XMLNSa            string                `xml:"xmlns:a,attr"`
+	XMLNSr            string                `xml:"xmlns:r,attr,omitempty"`
+	Name              string                `xml:"name,attr,omitempty"`
 	ThemeElements     xlsxThemeElements     `xml:"themeElements"`
 	ObjectDefaults    xlsxObjectDefaults    `xml:"objectDefaults"`
 	ExtraClrSchemeLst xlsxExtraClrSchemeLst `xml:"extraClrSchemeLst"`
@@ -72,10 +72,12 @@ type xlsxWorkbookProtection struct {
 	RevisionsHashValue     string `xml:"revisionsHashValue,attr,omitempty"`
 	RevisionsSaltValue     string `xml:"revisionsSaltValue,attr,omitempty"`
 	RevisionsSpinCount     int    `xml:"revisionsSpinCount,attr,omitempty"`
+	RevisionsPassword      string `xml:"revisionsPassword,attr,omitempty"`
 	WorkbookAlgorithmName  string `xml:"workbookAlgorithmName,attr,omitempty"`
 	WorkbookHashValue      string `xml:"workbookHashValue,attr,omitempty"`
 	WorkbookSaltValue      string `xml:"workbookSaltValue,attr,omitempty"`
 	WorkbookSpinCount      int    `xml:"workbookSpinCount,attr,omitempty"`
+	WorkbookPassword       string `xml:"workbookPassword,attr,omitempty"`
 }
 
 // xlsxFileVersion directly maps the fileVersion element. This element defines
@@ -129,10 +131,10 @@ type xlsxWorkBookView struct {
 	AutoFilterDateGrouping bool   `xml:"autoFilterDateGrouping,attr,omitempty"`
 	FirstSheet             int    `xml:"firstSheet,attr,omitempty"`
 	Minimized              bool   `xml:"minimized,attr,omitempty"`
-	ShowHorizontalScroll   bool   `xml:"showHorizontalScroll,attr,omitempty"`
-	ShowSheetTabs          bool   `xml:"showSheetTabs,attr,omitempty"`
-	ShowVerticalScroll     bool   `xml:"showVerticalScroll,attr,omitempty"`
-	TabRatio               int    `xml:"tabRatio,attr,omitempty"`
+	ShowHorizontalScroll   *bool  `xml:"showHorizontalScroll,attr"`
+	ShowSheetTabs          *bool  `xml:"showSheetTabs,attr"`
+	ShowVerticalScroll     *bool  `xml:"showVerticalScroll,attr"`
+	TabRatio               *int   `xml:"tabRatio,attr"`
 	Visibility             string `xml:"visibility,attr,omitempty"`
 	WindowHeight           int    `xml:"windowHeight,attr,omitempty"`
 	WindowWidth            int    `xml:"windowWidth,attr,omitempty"`
@@ -289,11 +291,33 @@ type xlsxCustomWorkbookView struct {
 	YWindow              *int    `xml:"yWindow,attr"`
 }
 
+// SheetInfo directly maps the name, index and visibility state of the sheet
+// in the workbook. The State is one of visible, hidden and veryHidden, the
+// very hidden sheet can't be unhidden in the user interface of Excel.
+type SheetInfo struct {
+	Name  string
+	Index int
+	State string
+}
+
 // DefinedName directly maps the name for a cell or cell range on a
-// worksheet.
+// worksheet. The Hidden specifies whether the defined name is hidden in the
+// user interface, which is usually used for the internal calculations.
 type DefinedName struct {
 	Name     string
 	Comment  string
+	Hidden   bool
 	RefersTo string
 	Scope    string
 }
+
+// WorkbookProtectionOptions directly maps the settings of the workbook
+// protection. The LockStructure specifies whether the structure of the
+// workbook is locked, such as adding, deleting, hiding and moving the
+// worksheets, and the LockWindows specifies whether the size and position of
+// the workbook windows are locked.
+type WorkbookProtectionOptions struct {
+	Password      string
+	LockStructure bool
+	LockWindows   bool
+}
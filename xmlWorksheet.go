@@ -54,6 +54,8 @@ type xlsxWorksheet struct {
 	WebPublishItems       *xlsxInnerXML                `xml:"webPublishItems"`
 	TableParts            *xlsxTableParts              `xml:"tableParts"`
 	ExtLst                *xlsxExtLst                  `xml:"extLst"`
+	DecodeUnknown         *xlsxInnerXML                `xml:",any"`
+	Unknown               []xlsxUnknownElement         `xml:"-"`
 }
 
 // xlsxDrawing change r:id to rid in the namespace.
@@ -244,9 +246,11 @@ type xlsxSheetPr struct {
 }
 
 // xlsxOutlinePr maps to the outlinePr element
-// SummaryBelow allows you to adjust the direction of grouper controls
+// SummaryBelow and SummaryRight allows you to adjust the direction of grouper
+// controls
 type xlsxOutlinePr struct {
-	SummaryBelow bool `xml:"summaryBelow,attr"`
+	SummaryBelow *bool `xml:"summaryBelow,attr"`
+	SummaryRight *bool `xml:"summaryRight,attr"`
 }
 
 // xlsxPageSetUpPr directly maps the pageSetupPr element in the namespace
@@ -464,6 +468,9 @@ type xlsxC struct {
 	F  *xlsxF  `xml:"f,omitempty"`      // Formula
 	V  string  `xml:"v,omitempty"`      // Value
 	IS *xlsxSI `xml:"is"`
+	// ensured specifies the empty cell created by EnsureCells, which will
+	// not be trimmed on save.
+	ensured bool
 }
 
 func (c *xlsxC) hasValue() bool {
@@ -565,7 +572,7 @@ type xlsxColorScale struct {
 type xlsxDataBar struct {
 	MaxLength int          `xml:"maxLength,attr,omitempty"`
 	MinLength int          `xml:"minLength,attr,omitempty"`
-	ShowValue bool         `xml:"showValue,attr,omitempty"`
+	ShowValue *bool        `xml:"showValue,attr"`
 	Cfvo      []*xlsxCfvo  `xml:"cfvo"`
 	Color     []*xlsxColor `xml:"color"`
 }
@@ -574,7 +581,7 @@ type xlsxDataBar struct {
 type xlsxIconSet struct {
 	Cfvo      []*xlsxCfvo `xml:"cfvo"`
 	IconSet   string      `xml:"iconSet,attr,omitempty"`
-	ShowValue bool        `xml:"showValue,attr,omitempty"`
+	ShowValue *bool       `xml:"showValue,attr"`
 	Percent   bool        `xml:"percent,attr,omitempty"`
 	Reverse   bool        `xml:"reverse,attr,omitempty"`
 }
@@ -582,12 +589,74 @@ type xlsxIconSet struct {
 // cfvo (Conditional Format Value Object) describes the values of the
 // interpolation points in a gradient scale.
 type xlsxCfvo struct {
-	Gte    bool        `xml:"gte,attr,omitempty"`
+	Gte    *bool       `xml:"gte,attr"`
 	Type   string      `xml:"type,attr,omitempty"`
 	Val    string      `xml:"val,attr,omitempty"`
 	ExtLst *xlsxExtLst `xml:"extLst"`
 }
 
+// decodeX14ConditionalFormattings directly maps the conditionalFormattings
+// element.
+type decodeX14ConditionalFormattings struct {
+	XMLName               xml.Name                          `xml:"conditionalFormattings"`
+	ConditionalFormatting []*decodeX14ConditionalFormatting `xml:"conditionalFormatting"`
+}
+
+// decodeX14ConditionalFormatting directly maps the conditionalFormatting
+// element.
+type decodeX14ConditionalFormatting struct {
+	XMLName xml.Name `xml:"conditionalFormatting"`
+	Sqref   string   `xml:"sqref"`
+	Content string   `xml:",innerxml"`
+}
+
+// xlsxX14ConditionalFormatting directly maps the conditionalFormatting
+// element in the namespace http://schemas.microsoft.com/office/spreadsheetml/2009/9/main
+// - This collection expresses the conditional formatting rules which are
+// introduced in Excel 2010, such as the extended data bar.
+type xlsxX14ConditionalFormatting struct {
+	XMLName xml.Name         `xml:"x14:conditionalFormatting"`
+	XMLNSXM string           `xml:"xmlns:xm,attr"`
+	CfRule  []*xlsxX14CfRule `xml:"x14:cfRule"`
+	Sqref   string           `xml:"xm:sqref"`
+	Content string           `xml:",innerxml"`
+}
+
+// xlsxX14CfRule directly maps the cfRule element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2009/9/main
+type xlsxX14CfRule struct {
+	Type    string          `xml:"type,attr,omitempty"`
+	ID      string          `xml:"id,attr,omitempty"`
+	DataBar *xlsxX14DataBar `xml:"x14:dataBar"`
+}
+
+// xlsxX14DataBar directly maps the dataBar element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2009/9/main - This
+// element specifies the extended properties of the data bar, such as solid
+// fill, border, direction, negative value colors and axis position.
+type xlsxX14DataBar struct {
+	MaxLength                            int            `xml:"maxLength,attr"`
+	MinLength                            int            `xml:"minLength,attr"`
+	Border                               bool           `xml:"border,attr,omitempty"`
+	Gradient                             bool           `xml:"gradient,attr"`
+	Direction                            string         `xml:"direction,attr,omitempty"`
+	NegativeBarColorSameAsPositive       bool           `xml:"negativeBarColorSameAsPositive,attr,omitempty"`
+	NegativeBarBorderColorSameAsPositive bool           `xml:"negativeBarBorderColorSameAsPositive,attr"`
+	AxisPosition                         string         `xml:"axisPosition,attr,omitempty"`
+	Cfvo                                 []*xlsxX14Cfvo `xml:"x14:cfvo"`
+	BorderColor                          *xlsxColor     `xml:"x14:borderColor"`
+	NegativeFillColor                    *xlsxColor     `xml:"x14:negativeFillColor"`
+	NegativeBorderColor                  *xlsxColor     `xml:"x14:negativeBorderColor"`
+	AxisColor                            *xlsxColor     `xml:"x14:axisColor"`
+}
+
+// xlsxX14Cfvo directly maps the cfvo element in the namespace
+// http://schemas.microsoft.com/office/spreadsheetml/2009/9/main
+type xlsxX14Cfvo struct {
+	Type string `xml:"type,attr"`
+	F    string `xml:"xm:f,omitempty"`
+}
+
 // xlsxHyperlinks directly maps the hyperlinks element in the namespace
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main - A hyperlink can
 // be stored in a package as a relationship. Hyperlinks shall be identified by
@@ -686,6 +755,16 @@ type xlsxInnerXML struct {
 	Content string `xml:",innerxml"`
 }
 
+// xlsxUnknownElement directly maps the raw XML of the child element of the
+// worksheet which is not supported by the worksheet model, such as the
+// mc:AlternateContent element. The After specifies the name of the supported
+// element before it, the element will be written after that on save, or at
+// the beginning of the worksheet if it's empty.
+type xlsxUnknownElement struct {
+	After   string
+	Content string
+}
+
 // xlsxWorksheetExt directly maps the ext element in the worksheet.
 type xlsxWorksheetExt struct {
 	XMLName xml.Name `xml:"ext"`
@@ -825,6 +904,29 @@ type formatConditional struct {
 	MaxLength    string `json:"max_length,omitempty"`
 	MultiRange   string `json:"multi_range,omitempty"`
 	BarColor     string `json:"bar_color,omitempty"`
+	// The following options are used to set the extended properties of
+	// the data bar.
+	BarOnly                bool   `json:"bar_only,omitempty"`
+	BarSolid               bool   `json:"bar_solid,omitempty"`
+	BarDirection           string `json:"bar_direction,omitempty"`
+	BarBorderColor         string `json:"bar_border_color,omitempty"`
+	BarNegativeColor       string `json:"bar_negative_color,omitempty"`
+	BarNegativeBorderColor string `json:"bar_negative_border_color,omitempty"`
+	BarAxisPosition        string `json:"bar_axis_position,omitempty"`
+	BarAxisColor           string `json:"bar_axis_color,omitempty"`
+	// The following options are used to set the properties of the icon set.
+	IconStyle    string                   `json:"icon_style,omitempty"`
+	ReverseIcons bool                     `json:"reverse_icons,omitempty"`
+	IconsOnly    bool                     `json:"icons_only,omitempty"`
+	Icons        []*formatConditionalIcon `json:"icons,omitempty"`
+}
+
+// formatConditionalIcon directly maps the threshold settings of the icon in
+// the icon set conditional formatting.
+type formatConditionalIcon struct {
+	Criteria string `json:"criteria"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
 }
 
 // FormatSheetProtection directly maps the settings of worksheet protection.
@@ -861,6 +963,19 @@ type FormatHeaderFooter struct {
 	FirstHeader      string
 }
 
+// HeaderFooterImage directly maps the settings of the picture in the header
+// or footer. Position specifies the section of the header or footer which
+// the picture is placed in, the possible values are left, center and right.
+// IsFooter specifies the picture is placed in the footer instead of the
+// header, Extension specifies the extension name of the picture file, such as
+// ".png", and File specifies the content of the picture file.
+type HeaderFooterImage struct {
+	Position  string
+	IsFooter  bool
+	Extension string
+	File      []byte
+}
+
 // FormatPageMargins directly maps the settings of page margins
 type FormatPageMargins struct {
 	Bottom string